@@ -0,0 +1,87 @@
+package main
+
+import (
+	"datapad/internal/instance"
+	"datapad/internal/notes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// runAdd creates a note from its title, content and tags
+func runAdd(env *environment, args []string) error {
+	fs := env.newFlagSet("add")
+	content := fs.String("content", "", "Content of the note, - to read it from the standard input")
+	tags := fs.String("tags", "", "Comma-separated tags of the note")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("expected the title of the note")
+	}
+
+	text := *content
+	if text == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("error reading the standard input: %w", err)
+		}
+		text = string(data)
+	}
+	return addNote(env, instance.Request{
+		Op:      instance.OpAdd,
+		Title:   strings.Join(fs.Args(), " "),
+		Content: text,
+		Tags:    strings.Split(*tags, ","),
+	})
+}
+
+// runQuick captures a thought as a note, titled as the quick notes of the
+// interface are
+func runQuick(env *environment, args []string) error {
+	fs := env.newFlagSet("quick")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	title, content := notes.QuickNote(strings.Join(fs.Args(), " "))
+	if title == "" {
+		fs.Usage()
+		return errors.New("expected the text to capture")
+	}
+	return addNote(env, instance.Request{Op: instance.OpAdd, Title: title, Content: content})
+}
+
+// addNote hands a new note to the interface when one has the store open, so
+// that it shows the note at once and no save of either side is lost, and
+// writes the store itself otherwise
+func addNote(env *environment, req instance.Request) error {
+	resp, served, err := instance.Submit(env.storagePath, req)
+	if err != nil {
+		return err
+	}
+	if !served {
+		manager, err := env.manager()
+		if err != nil {
+			return err
+		}
+		if resp, err = instance.Apply(manager, req); err != nil {
+			return err
+		}
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+
+	if served {
+		fmt.Printf("Note %s added through the running interface: %s\n", resp.ID, resp.Title)
+	} else {
+		fmt.Printf("Note %s added: %s\n", resp.ID, resp.Title)
+	}
+	if resp.Duplicate {
+		fmt.Fprintln(os.Stderr, "Another note already has this title")
+	}
+	return nil
+}
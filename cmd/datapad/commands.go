@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bufio"
+	"datapad/internal/config"
+	"datapad/internal/notes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// environment holds what every command needs to run
+type environment struct {
+	storagePath string
+	workspace   string // Name of the workspace of storagePath, empty outside workspaces
+	configPath  string // Config file, whose settings win over those of the storage folder
+	config      config.Config
+
+	// Options of the destructive commands
+	dryRun  bool // Report what would change without writing anything
+	yes     bool // Don't ask for confirmation
+	verbose bool // List every note changed
+}
+
+// errCancelled is returned when the user doesn't confirm a change
+var errCancelled = errors.New("cancelled, nothing was changed")
+
+// manager opens the notes store. In a dry run the store is only read, not
+// even created when it doesn't exist.
+func (e *environment) manager() (*notes.NotesManager, error) {
+	var manager *notes.NotesManager
+	if e.dryRun {
+		manager = notes.OpenNotesManager(e.storagePath)
+		manager.DryRun = true
+		if err := manager.CheckStorage(); err != nil {
+			return nil, err
+		}
+		if err := manager.LoadNotes(); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error loading notes: %w", err)
+		}
+	} else {
+		var err error
+		manager, err = notes.NewNotesManager(e.storagePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if e.verbose {
+		manager.OnActivity = func(entry notes.Activity) {
+			fmt.Printf("  %-9s %s  %s\n", entry.Action, entry.NoteID, entry.Title)
+		}
+	}
+	manager.DefaultTags = e.config.Tags.Defaults
+	manager.Snapshots = notes.SnapshotPolicy(e.config.Snapshots)
+	manager.ManualOrder = e.config.View.Sort == config.NoteSortManual
+	manager.TidyContent = e.config.Editor.TrimSpace
+	manager.SortNotes()
+	return manager, nil
+}
+
+// command is a subcommand of the CLI
+type command struct {
+	name        string
+	usage       string
+	summary     string
+	run         func(env *environment, args []string) error
+	destructive bool // Accepts --dry-run, --yes and --verbose
+}
+
+// commands lists the available subcommands, filled in init since the
+// commands themselves print their usage from this list
+var commands []command
+
+func init() {
+	commands = []command{
+		{
+			name:    "open",
+			usage:   "open [--edit] <id|title> | open [--edit] --title <title>",
+			summary: "Start the interface on a note, found by ID or else by title",
+			run:     runOpen,
+		},
+		{
+			name:    "new",
+			usage:   "new [title]",
+			summary: "Start the interface on a new note",
+			run:     runNew,
+		},
+		{
+			name:    "search",
+			usage:   "search [query]",
+			summary: "Start the interface on the notes matching the search, or on the search prompt",
+			run:     runSearch,
+		},
+		{
+			name:    "tag",
+			usage:   "tag [tag]",
+			summary: "Start the interface on the notes with the tag, or on the list of tags",
+			run:     runTag,
+		},
+		{
+			name:    "add",
+			usage:   "add [--content <text>|-] [--tags <tags>] <title>",
+			summary: "Create a note, through the interface when one has the store open",
+			run:     runAdd,
+		},
+		{
+			name:    "quick",
+			usage:   "quick <text>",
+			summary: "Capture a thought as a note, titled as the quick notes of the interface",
+			run:     runQuick,
+		},
+		{
+			name:    "list",
+			usage:   "list [--tags <expression>] [--search <query>] [--archived] [--limit <n>] [--offset <n>]",
+			summary: "List the notes, those whose tags match the expression or matching the search",
+			run:     runList,
+		},
+		{
+			name:    "stats",
+			usage:   "stats",
+			summary: "Print statistics about the notes, such as the time spent editing them",
+			run:     runStats,
+		},
+		{
+			name:    "review",
+			usage:   "review [--limit <n>] [--mark]",
+			summary: "Print the notes that haven't been looked at for the longest time",
+			run:     runReview,
+		},
+		{
+			name:    "export",
+			usage:   "export --site <dir> | --book <file.html|file.pdf> | --json <file> <id> | --launcher <file.desktop|file.command> <id>",
+			summary: "Export the notes to a static HTML site or a single document, or a note as JSON or as a desktop launcher",
+			run:     runExport,
+		},
+		{
+			name:    "images",
+			usage:   "images export <id> <dir>",
+			summary: "Copy the images of a note into a directory, named after their captions",
+			run:     runImages,
+		},
+		{
+			name:    "print",
+			usage:   "print [--pdf <file>] <id>",
+			summary: "Print a note or write it to a PDF file",
+			run:     runPrint,
+		},
+		{
+			name:        "import",
+			usage:       "import --keep <dir> [--skip-trashed] | --notion <zip> | --json <file>",
+			summary:     "Import notes from Google Keep or Notion, or a note exported as JSON",
+			run:         runImport,
+			destructive: true,
+		},
+		{
+			name:        "doctor",
+			usage:       "doctor [--fix]",
+			summary:     "Check the notes store for problems and repair them",
+			run:         runDoctor,
+			destructive: true,
+		},
+		{
+			name:        "snapshot",
+			usage:       "snapshot [--list] [--restore <date> <id>]",
+			summary:     "Take a snapshot of the notes, list the snapshots or restore a note",
+			run:         runSnapshot,
+			destructive: true,
+		},
+		{
+			name:    "share",
+			usage:   "share [--public] <id>",
+			summary: "Share a note as a GitHub gist and print its URL",
+			run:     runShare,
+		},
+		{
+			name:        "unshare",
+			usage:       "unshare <id>",
+			summary:     "Delete the gist a note was shared to",
+			run:         runUnshare,
+			destructive: true,
+		},
+		{
+			name:    "sync",
+			usage:   "sync [--webdav <url>]",
+			summary: "Synchronize the notes with a WebDAV server such as Nextcloud",
+			run:     runSync,
+		},
+		{
+			name:    "workspace",
+			usage:   "workspace list",
+			summary: "List the workspaces of the config file and their storage folders",
+			run:     runWorkspace,
+		},
+		{
+			name:    "settings",
+			usage:   "settings export [<file>] | import <file>",
+			summary: "Copy the settings into the storage folder or a file, or import them into the storage folder",
+			run:     runSettings,
+		},
+		{
+			name:    "passphrase",
+			usage:   "passphrase",
+			summary: "Hash a passphrase to unlock the interface after it locks",
+			run:     runPassphrase,
+		},
+	}
+}
+
+// runCommand runs the subcommand called name
+func runCommand(env *environment, name string, args []string) error {
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		if err := cmd.run(env, args); err != nil {
+			return err
+		}
+		if env.dryRun {
+			fmt.Println("Dry run, nothing was written")
+		}
+		return nil
+	}
+	usage()
+	return fmt.Errorf("unknown command %q", name)
+}
+
+// newFlagSet creates the flag set of a subcommand with its usage message,
+// and the options shared by destructive commands
+func (e *environment) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		for _, cmd := range commands {
+			if cmd.name == name {
+				fmt.Fprintf(os.Stderr, "Usage: datapad %s\n\n%s\n\nOptions:\n", cmd.usage, cmd.summary)
+			}
+		}
+		fs.PrintDefaults()
+	}
+	for _, cmd := range commands {
+		if cmd.name == name && cmd.destructive {
+			fs.BoolVar(&e.dryRun, "dry-run", false, "Print what would change without writing anything")
+			fs.BoolVar(&e.yes, "yes", false, "Don't ask for confirmation")
+			fs.BoolVar(&e.verbose, "verbose", false, "List the ID and title of every note changed")
+		}
+	}
+	return fs
+}
+
+// confirm asks the user to confirm a change, which a dry run or --yes skip.
+// Without a terminal to ask, --yes is required.
+func (e *environment) confirm(question string) error {
+	if e.dryRun || e.yes {
+		return nil
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return errors.New("confirmation required, run again with --yes")
+	}
+	fmt.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return errCancelled
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errCancelled
+	}
+}
+
+// usage prints the help of the CLI
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: datapad [options] [command]\n\n")
+	fmt.Fprintf(os.Stderr, "Without a command, datapad opens the terminal interface.\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-40s %s\n", cmd.usage, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nOptions:\n")
+	flag.PrintDefaults()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+)
+
+// runDoctor checks the notes store and optionally repairs it
+func runDoctor(env *environment, args []string) error {
+	fs := env.newFlagSet("doctor")
+	fix := fs.Bool("fix", false, "Apply the safe repairs")
+	lowercase := fs.Bool("lowercase-tags", false, "Write every tag in lowercase, merging the tags that become the same")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+
+	if *lowercase {
+		if err := env.confirm("Write every tag in lowercase?"); err != nil {
+			return err
+		}
+		changed, err := manager.NormalizeAllTags(true)
+		if err != nil {
+			return fmt.Errorf("error normalizing tags: %w", err)
+		}
+		fmt.Printf("Tags of %d note(s) written in lowercase\n", changed)
+	}
+
+	issues := manager.Verify()
+	if len(issues) == 0 {
+		fmt.Println("No problem found")
+		return nil
+	}
+
+	fixable := 0
+	for _, issue := range issues {
+		marker := " "
+		if issue.Fixable {
+			marker = "*"
+			fixable++
+		}
+		fmt.Printf("%s [%s] %s\n    fix: %s\n", marker, issue.Kind, issue.Message, issue.Fix)
+	}
+	fmt.Printf("\n%d problem(s) found, %d can be repaired automatically (*)\n", len(issues), fixable)
+
+	if !*fix {
+		if fixable > 0 {
+			fmt.Println("Run datapad doctor --fix to repair them")
+		}
+		return nil
+	}
+
+	if err := env.confirm(fmt.Sprintf("Repair %d problem(s)?", fixable)); err != nil {
+		return err
+	}
+	fixed, err := manager.Repair(issues)
+	if err != nil {
+		return fmt.Errorf("error repairing notes: %w", err)
+	}
+	fmt.Printf("%d problem(s) repaired\n", fixed)
+	return nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"datapad/internal/export"
+	"datapad/internal/notes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runExport exports the notes to other formats
+func runExport(env *environment, args []string) error {
+	fs := env.newFlagSet("export")
+	siteDir := fs.String("site", "", "Generate a static HTML site in this directory")
+	templateDir := fs.String("templates", "", "Directory of templates overriding the embedded ones")
+	bookPath := fs.String("book", "", "Write the notes to a single HTML or PDF document with a table of contents")
+	ids := fs.String("ids", "", "Comma-separated IDs of the notes to include in the book, in order")
+	tag := fs.String("tag", "", "Include the notes with this tag in the book")
+	order := fs.String("order", "", "Order of the book: selection, title, created or updated")
+	title := fs.String("title", "", "Title of the book")
+	manifest := fs.String("manifest", "", "Also list the images of the book with their captions and alt text in this .json or .md file")
+	skipEmpty := fs.Bool("skip-empty", false, "Leave out the notes with neither text nor images instead of marking them empty")
+	jsonPath := fs.String("json", "", "Write the note given by ID to this JSON file, - for the standard output")
+	launcherPath := fs.String("launcher", "", "Write a .desktop or .command file opening the note given by ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	chosen := 0
+	for _, path := range []string{*siteDir, *bookPath, *jsonPath, *launcherPath} {
+		if path != "" {
+			chosen++
+		}
+	}
+	if chosen != 1 {
+		fs.Usage()
+		return errors.New("choose what to export, --site, --book, --json or --launcher")
+	}
+	if *jsonPath != "" && fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected the ID of the note to export as JSON")
+	}
+	if *launcherPath != "" && fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected the ID of the note the launcher opens")
+	}
+	if *manifest != "" && *bookPath == "" {
+		return errors.New("--manifest only applies to books, sites always include attachments.json and attachments.md")
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+
+	if *jsonPath != "" {
+		return exportNoteJSON(manager, fs.Arg(0), *jsonPath)
+	}
+	if *launcherPath != "" {
+		return exportLauncher(env, manager, fs.Arg(0), *launcherPath)
+	}
+
+	if *bookPath != "" {
+		selection, err := bookSelection(manager, *ids, *tag)
+		if err != nil {
+			return err
+		}
+		if *skipEmpty {
+			kept := export.NonEmpty(selection)
+			if skipped := len(selection) - len(kept); skipped > 0 {
+				fmt.Printf("%d empty note(s) left out\n", skipped)
+			}
+			selection = kept
+		}
+		err = export.ExportBook(manager, selection, *bookPath, export.BookOptions{
+			Title:      *title,
+			Order:      *order,
+			Manifest:   *manifest,
+			SkipEmpty:  *skipEmpty,
+			Extensions: env.config.Markdown.Extensions,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d note(s) exported to %s\n", len(selection), *bookPath)
+		if *manifest != "" {
+			fmt.Printf("Images listed in %s\n", *manifest)
+		}
+		return nil
+	}
+
+	report, err := export.ExportSite(manager, export.SiteOptions{
+		OutputDir:   *siteDir,
+		TemplateDir: *templateDir,
+		SkipEmpty:   *skipEmpty,
+		Extensions:  env.config.Markdown.Extensions,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Site exported to %s: %d page(s) written, %d up to date, %d image(s) copied\n",
+		*siteDir, report.Written, report.Skipped, report.Images)
+	if report.Removed > 0 {
+		fmt.Printf("%d page(s) of notes no longer exported removed\n", report.Removed)
+	}
+	if report.Empty > 0 {
+		fmt.Printf("%d empty note(s) left out\n", report.Empty)
+	}
+	return nil
+}
+
+// exportNoteJSON writes a note as JSON to a file or, for -, to the standard
+// output
+func exportNoteJSON(manager *notes.NotesManager, id, path string) error {
+	data, err := manager.ExportNoteJSON(id)
+	if err != nil {
+		return err
+	}
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	fmt.Printf("Note exported to %s\n", path)
+	return nil
+}
+
+// exportLauncher writes a desktop launcher opening the note id with this
+// program, in the storage folder in use
+func exportLauncher(env *environment, manager *notes.NotesManager, id, path string) error {
+	note, err := manager.GetNoteByID(id)
+	if err != nil {
+		return err
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to find the datapad program: %w", err)
+	}
+	storagePath, err := filepath.Abs(env.storagePath)
+	if err != nil {
+		return fmt.Errorf("unable to resolve the storage folder: %w", err)
+	}
+
+	launcher := export.Launcher{Executable: executable, StoragePath: storagePath, Note: note}
+	if err := export.WriteLauncher(launcher, path); err != nil {
+		return err
+	}
+	fmt.Printf("Launcher for %s written to %s\n", note.Title, path)
+	return nil
+}
+
+// bookSelection returns the notes given by ID, or those with the tag, or
+// every note that isn't archived
+func bookSelection(manager *notes.NotesManager, ids, tag string) ([]*notes.Note, error) {
+	if ids != "" {
+		selection := []*notes.Note{}
+		for _, id := range strings.Split(ids, ",") {
+			note, err := manager.GetNoteByID(strings.TrimSpace(id))
+			if err != nil {
+				return nil, err
+			}
+			selection = append(selection, note)
+		}
+		return selection, nil
+	}
+
+	candidates := manager.Notes
+	if tag != "" {
+		candidates = manager.FilterByTags([]string{tag})
+	}
+	selection := []*notes.Note{}
+	for _, note := range candidates {
+		if !note.Archived {
+			selection = append(selection, note)
+		}
+	}
+	return selection, nil
+}
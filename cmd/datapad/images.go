@@ -0,0 +1,45 @@
+package main
+
+import (
+	"datapad/internal/export"
+	"errors"
+	"fmt"
+)
+
+// runImages copies the images of a note into a directory
+func runImages(env *environment, args []string) error {
+	fs := env.newFlagSet("images")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 3 || fs.Arg(0) != "export" {
+		fs.Usage()
+		return errors.New("expected export, the ID of the note and a directory")
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+	note, err := manager.GetNoteByID(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	if len(note.Images) == 0 {
+		fmt.Printf("%q has no images\n", note.Title)
+		return nil
+	}
+
+	report, err := export.ExportImages(manager, note, fs.Arg(2))
+	for _, path := range report.Written {
+		fmt.Println(path)
+	}
+	for _, path := range report.Missing {
+		fmt.Printf("Missing from the store, skipped: %s\n", path)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d image(s) of %q exported to %s\n", len(report.Written), note.Title, fs.Arg(2))
+	return nil
+}
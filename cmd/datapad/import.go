@@ -0,0 +1,105 @@
+package main
+
+import (
+	"datapad/internal/importer"
+	"datapad/internal/notes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+)
+
+// runImport imports notes from other applications
+func runImport(env *environment, args []string) error {
+	fs := env.newFlagSet("import")
+	keepDir := fs.String("keep", "", "Import a Google Keep Takeout export from this directory")
+	notionPath := fs.String("notion", "", "Import a Notion Markdown & CSV export (zip file or extracted folder)")
+	skipTrashed := fs.Bool("skip-trashed", false, "Leave out the notes in the Keep trash")
+	jsonPath := fs.String("json", "", "Import a note exported with export --json, - for the standard input")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	chosen := 0
+	for _, path := range []string{*keepDir, *notionPath, *jsonPath} {
+		if path != "" {
+			chosen++
+		}
+	}
+	if chosen != 1 {
+		fs.Usage()
+		return errors.New("choose one source to import, --keep, --notion or --json")
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+
+	if *jsonPath != "" {
+		return importNoteJSON(manager, *jsonPath)
+	}
+
+	var report importer.Report
+	if *keepDir != "" {
+		report, err = importer.ImportKeep(manager, *keepDir, importer.KeepOptions{
+			SkipTrashed: *skipTrashed,
+		})
+	} else {
+		report, err = importer.ImportNotion(manager, *notionPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	printImportReport(report)
+	return nil
+}
+
+// importNoteJSON imports a note written by export --json, read from a file
+// or, for -, from the standard input
+func importNoteJSON(manager *notes.NotesManager, path string) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	note, err := manager.ImportNoteJSON(data)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Note imported: %s (%s)\n", note.Title, note.ID)
+	return nil
+}
+
+// printImportReport prints the summary of an import
+func printImportReport(report importer.Report) {
+	fmt.Printf("%d note(s) imported (%d archived, %d from the trash), %d skipped, %d image(s) copied\n",
+		report.Imported, report.Archived, report.Trashed, report.Skipped, report.Images)
+	printRemap(report.Remapped)
+	if len(report.Warnings) > 0 {
+		fmt.Printf("\n%d warning(s):\n", len(report.Warnings))
+		for _, warning := range report.Warnings {
+			fmt.Printf("  - %s\n", warning)
+		}
+	}
+}
+
+// printRemap lists the imported notes that got a new ID, theirs being taken
+func printRemap(remap notes.IDRemap) {
+	if len(remap) == 0 {
+		return
+	}
+	fmt.Printf("\n%d note(s) got a new ID, theirs being taken:\n", len(remap))
+	for _, old := range slices.Sorted(maps.Keys(remap)) {
+		fmt.Printf("  %s → %s\n", old, remap[old])
+	}
+}
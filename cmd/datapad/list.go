@@ -0,0 +1,85 @@
+package main
+
+import (
+	"datapad/internal/notes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// runList prints the notes, optionally those matching a tag expression and a
+// search query
+func runList(env *environment, args []string) error {
+	fs := env.newFlagSet("list")
+	tags := fs.String("tags", "", `Tag expression such as "work AND (urgent OR review) AND NOT done"`)
+	search := fs.String("search", "", `Search query such as "tag:work modified:<7d meeting"`)
+	archived := fs.Bool("archived", false, "Include archived notes")
+	limit := fs.Int("limit", 0, "Number of notes to print, all of them when zero")
+	offset := fs.Int("offset", 0, "Number of notes to skip before printing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return errors.New("unexpected arguments")
+	}
+	if *limit < 0 {
+		return errors.New("--limit can't be negative")
+	}
+	if *offset < 0 {
+		return errors.New("--offset can't be negative")
+	}
+
+	expr, err := notes.ParseTagExpr(*tags)
+	if err != nil {
+		var exprErr *notes.TagExprError
+		if errors.As(err, &exprErr) {
+			fmt.Fprintf(os.Stderr, "  %s\n  %s^\n", *tags, strings.Repeat(" ", exprErr.Pos))
+		}
+		return fmt.Errorf("invalid tag expression: %w", err)
+	}
+	query, err := notes.ParseQuery(*search, time.Now())
+	if err != nil {
+		var queryErr *notes.QueryError
+		if errors.As(err, &queryErr) {
+			fmt.Fprintf(os.Stderr, "  %s\n  %s^\n", *search, strings.Repeat(" ", queryErr.Pos))
+		}
+		return fmt.Errorf("invalid search: %w", err)
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+
+	listed := []*notes.Note{}
+	for _, result := range manager.SearchQuery(query) {
+		note := result.Note
+		if !expr.Match(note.Tags) || (note.Archived && !*archived) {
+			continue
+		}
+		listed = append(listed, note)
+	}
+	page, total := notes.Page(listed, *offset, *limit)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, note := range page {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", note.ID, note.Title, strings.Join(note.Tags, ", "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	// The range goes to stderr so that the listing stays easy to parse
+	if *limit > 0 || *offset > 0 {
+		if len(page) == 0 {
+			fmt.Fprintf(os.Stderr, "No notes past %d, %d in all\n", *offset, total)
+		} else {
+			fmt.Fprintf(os.Stderr, "Notes %d-%d of %d\n", *offset+1, *offset+len(page), total)
+		}
+	}
+	return nil
+}
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"datapad/internal/config"
+	"datapad/internal/expand"
+	"datapad/internal/notes"
 	"datapad/internal/tui"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -11,22 +15,136 @@ import (
 func main() {
 	// Define command line options
 	var storagePath string
+	var configPath string
+	var workspace string
 	flag.StringVar(&storagePath, "storage", "", "Path to notes storage folder (optional)")
+	flag.StringVar(&configPath, "config", "", "Path to the config file (optional)")
+	flag.StringVar(&workspace, "workspace", "", "Name of the workspace to open, from the config file (optional)")
+	flag.Usage = usage
 	flag.Parse()
 
-	// If no path is provided, use a default folder in the home directory
-	if storagePath == "" {
-		homeDir, err := os.UserHomeDir()
+	// Load the user configuration. Paths given as --flag=~/path aren't
+	// expanded by the shell.
+	configPath = expand.Path(configPath)
+	if configPath == "" {
+		path, err := config.DefaultPath()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: unable to determine home directory: %v\n", err)
-			os.Exit(1)
+			exit(err)
 		}
-		storagePath = filepath.Join(homeDir, ".datapad")
+		configPath = path
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		exit(err)
+	}
+
+	storagePath, err = resolveWorkspace(cfg, workspace, storagePath)
+	if err != nil {
+		exit(err)
+	}
+	workspace = cfg.WorkspaceFor(storagePath)
+
+	// The settings kept in the storage folder lie beneath the config file,
+	// which is read again on top of them
+	cfg, err = config.LoadWithSettings(configPath, filepath.Join(storagePath, config.SettingsFile))
+	if err != nil {
+		exit(err)
+	}
+
+	// Run a subcommand when one is given
+	if flag.NArg() > 0 {
+		env := &environment{storagePath: storagePath, workspace: workspace, configPath: configPath, config: cfg}
+		if err := runCommand(env, flag.Arg(0), flag.Args()[1:]); err != nil {
+			exit(err)
+		}
+		return
 	}
 
 	// Launch the TUI application
-	if err := tui.App(storagePath); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	if err := tui.App(storagePath, workspace, configPath, cfg, tui.Start{}); err != nil {
+		exit(err)
+	}
+}
+
+// exitCodes maps the errors of the notes package to the exit status of the
+// program, any other error exiting with 1
+var exitCodes = []struct {
+	err  error
+	code int
+}{
+	{notes.ErrNoteNotFound, 3},
+	{notes.ErrImageNotFound, 4},
+	{notes.ErrDuplicateID, 5},
+	{notes.ErrStorageCorrupt, 6},
+	{notes.ErrReadOnly, 7},
+	{notes.ErrStorageNotDir, 8},
+	{notes.ErrStoragePermission, 8},
+}
+
+// exitCode returns the exit status of the program failing with err
+func exitCode(err error) int {
+	for _, c := range exitCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return 1
+}
+
+// exit prints an error and exits, explaining what to do when the storage
+// folder can't be used
+func exit(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	switch {
+	case errors.Is(err, notes.ErrStorageNotDir), errors.Is(err, notes.ErrStoragePermission):
+		fmt.Fprintln(os.Stderr, "Use -storage or DATAPAD_HOME to choose another storage folder")
+	case errors.Is(err, notes.ErrStorageCorrupt):
+		fmt.Fprintln(os.Stderr, "Fix notes.json, or replace it with the copy kept by a snapshot in the snapshots folder")
+	case errors.Is(err, notes.ErrReadOnly):
+		fmt.Fprintln(os.Stderr, "Make the storage folder writable, or use -storage to choose another one")
+	}
+	os.Exit(exitCode(err))
+}
+
+// resolveWorkspace returns the storage folder of the workspace given with
+// --workspace, or else the one resolveStoragePath finds
+func resolveWorkspace(cfg config.Config, workspace, flagPath string) (string, error) {
+	if workspace == "" {
+		return resolveStoragePath(flagPath)
+	}
+	if flagPath != "" {
+		return "", errors.New("use either -storage or -workspace")
+	}
+	return cfg.WorkspacePath(workspace)
+}
+
+// resolveStoragePath returns the storage folder, by order of precedence:
+//  1. the --storage flag
+//  2. the DATAPAD_HOME environment variable
+//  3. $XDG_DATA_HOME/datapad, unless ~/.datapad already exists so that
+//     existing notes aren't left behind
+//  4. ~/.datapad
+//
+// A leading ~ and environment variables are expanded in the first two.
+func resolveStoragePath(flagPath string) (string, error) {
+	if flagPath != "" {
+		return expand.Path(flagPath), nil
+	}
+	if home := os.Getenv("DATAPAD_HOME"); home != "" {
+		return expand.Path(home), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory: %w", err)
+	}
+	legacy := filepath.Join(homeDir, ".datapad")
+
+	// The XDG spec requires an absolute path, a relative one is ignored
+	if dataHome := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dataHome) {
+		if _, err := os.Stat(legacy); os.IsNotExist(err) {
+			return filepath.Join(dataHome, "datapad"), nil
+		}
 	}
+	return legacy, nil
 }
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"datapad/internal/notes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveStoragePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		flag     string
+		env      string // DATAPAD_HOME
+		dataHome string // XDG_DATA_HOME
+		legacy   bool   // ~/.datapad exists
+		want     string
+	}{
+		{"flag first", "/flag", "/env", "/xdg", true, "/flag"},
+		{"flag expanded", "~/notes", "/env", "", false, "HOME/notes"},
+		{"DATAPAD_HOME", "", "/env", "/xdg", false, "/env"},
+		{"DATAPAD_HOME expanded", "", "$XDG_DATA_HOME/mine", "/xdg", false, "/xdg/mine"},
+		{"XDG_DATA_HOME", "", "", "/xdg", false, "/xdg/datapad"},
+		{"existing ~/.datapad before XDG_DATA_HOME", "", "", "/xdg", true, "HOME/.datapad"},
+		{"relative XDG_DATA_HOME ignored", "", "", "xdg", false, "HOME/.datapad"},
+		{"default", "", "", "", false, "HOME/.datapad"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("DATAPAD_HOME", tt.env)
+			t.Setenv("XDG_DATA_HOME", tt.dataHome)
+			if tt.legacy {
+				if err := os.Mkdir(filepath.Join(home, ".datapad"), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want := tt.want
+			if rest, ok := strings.CutPrefix(want, "HOME"); ok {
+				want = home + rest
+			}
+			got, err := resolveStoragePath(tt.flag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("resolveStoragePath(%q) = %q, want %q", tt.flag, got, want)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("%w: abc", notes.ErrNoteNotFound), 3},
+		{fmt.Errorf("%w: a.png", notes.ErrImageNotFound), 4},
+		{fmt.Errorf("%w: abc", notes.ErrDuplicateID), 5},
+		{fmt.Errorf("%w: notes.json: bad", notes.ErrStorageCorrupt), 6},
+		{fmt.Errorf("%w: /store", notes.ErrReadOnly), 7},
+		{fmt.Errorf("%w: /store", notes.ErrStorageNotDir), 8},
+		{fmt.Errorf("%w: /store", notes.ErrStoragePermission), 8},
+		{fmt.Errorf("opening: %w", fmt.Errorf("%w: abc", notes.ErrNoteNotFound)), 3},
+		{errors.New("anything else"), 1},
+		{errCancelled, 1},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
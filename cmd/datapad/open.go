@@ -0,0 +1,105 @@
+package main
+
+import (
+	"datapad/internal/notes"
+	"datapad/internal/tui"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// runOpen starts the interface on a note, as desktop launchers do
+func runOpen(env *environment, args []string) error {
+	fs := env.newFlagSet("open")
+	title := fs.String("title", "", "Open the note with this title or alias rather than an ID")
+	edit := fs.Bool("edit", false, "Open the note in the editor rather than for reading")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 || (*title == "") == (fs.NArg() == 0) {
+		fs.Usage()
+		return errors.New("expected the ID of the note to open, or --title")
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+	var note *notes.Note
+	if *title != "" {
+		note, err = manager.FindByTitle(*title)
+	} else {
+		note, err = resolveNote(manager, fs.Arg(0))
+	}
+	if err != nil {
+		return fmt.Errorf("cannot open the note: %w", err)
+	}
+
+	start := tui.Start{NoteID: note.ID, Mode: tui.ModeView}
+	if *edit {
+		start.Mode = tui.ModeEdit
+	}
+	return tui.App(env.storagePath, env.workspace, env.configPath, env.config, start)
+}
+
+// runNew starts the interface on a new note, titled with the arguments
+func runNew(env *environment, args []string) error {
+	fs := env.newFlagSet("new")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	title := strings.Join(fs.Args(), " ")
+	return tui.App(env.storagePath, env.workspace, env.configPath, env.config, tui.Start{Mode: tui.ModeNew, Seed: title})
+}
+
+// runSearch starts the interface on the notes matching a search, or on the
+// search prompt without one
+func runSearch(env *environment, args []string) error {
+	fs := env.newFlagSet("search")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	query := strings.Join(fs.Args(), " ")
+	if _, err := notes.ParseQuery(query, time.Now()); err != nil {
+		return fmt.Errorf("invalid search: %w", err)
+	}
+	return tui.App(env.storagePath, env.workspace, env.configPath, env.config, tui.Start{Mode: tui.ModeSearch, Seed: query})
+}
+
+// runTag starts the interface on the notes with a tag, or on the list of
+// tags without one
+func runTag(env *environment, args []string) error {
+	fs := env.newFlagSet("tag")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errors.New("expected a single tag")
+	}
+	tag := fs.Arg(0)
+	if tag != "" {
+		manager, err := env.manager()
+		if err != nil {
+			return err
+		}
+		if len(manager.FilterByTags([]string{tag})) == 0 {
+			return fmt.Errorf("no note has the tag %q", tag)
+		}
+	}
+	return tui.App(env.storagePath, env.workspace, env.configPath, env.config, tui.Start{Mode: tui.ModeFilterByTag, Seed: tag})
+}
+
+// resolveNote returns the note with the given ID or, failing that, the one
+// with the given title or alias
+func resolveNote(manager *notes.NotesManager, ref string) (*notes.Note, error) {
+	note, err := manager.GetNoteByID(ref)
+	if err == nil {
+		return note, nil
+	}
+	if note, titleErr := manager.FindByTitle(ref); titleErr == nil {
+		return note, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"datapad/internal/notes"
+	"errors"
+	"testing"
+)
+
+func TestResolveNote(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	plan := manager.CreateNote("Trip plan")
+	plan.Aliases = []string{"itinerary"}
+	// A note titled with the ID of another one
+	impostor := manager.CreateNote(plan.ID)
+
+	tests := []struct {
+		ref  string
+		want *notes.Note
+	}{
+		{plan.ID, plan},
+		{impostor.ID, impostor},
+		{"Trip plan", plan},
+		{"  trip PLAN ", plan},
+		{"Itinerary", plan},
+	}
+	for _, tt := range tests {
+		if got, err := resolveNote(manager, tt.ref); err != nil || got != tt.want {
+			t.Errorf("resolveNote(%q) = %v, %v, want %q", tt.ref, got, err, tt.want.Title)
+		}
+	}
+
+	// The error is about the ID, as references are IDs first
+	if _, err := resolveNote(manager, "missing"); !errors.Is(err, notes.ErrNoteNotFound) {
+		t.Errorf("resolveNote(missing) = %v, want ErrNoteNotFound", err)
+	}
+}
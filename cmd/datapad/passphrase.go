@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"datapad/internal/passphrase"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// runPassphrase hashes a passphrase for the security.passphrase_hash setting
+func runPassphrase(env *environment, args []string) error {
+	fs := env.newFlagSet("passphrase")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+	first, err := readPassphrase(stdin, "Passphrase: ")
+	if err != nil {
+		return err
+	}
+	if first == "" {
+		return errors.New("the passphrase can't be empty")
+	}
+	second, err := readPassphrase(stdin, "Confirm passphrase: ")
+	if err != nil {
+		return err
+	}
+	if first != second {
+		return errors.New("the passphrases don't match")
+	}
+
+	hash, err := passphrase.Hash(first)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Add this line to the \"security\" section of the config file:")
+	fmt.Printf("  \"passphrase_hash\": %q\n", hash)
+	return nil
+}
+
+// readPassphrase reads a line from the terminal without echoing it, or from
+// standard input when it isn't a terminal
+func readPassphrase(stdin *bufio.Reader, prompt string) (string, error) {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("error reading passphrase: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	data, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("error reading passphrase: %w", err)
+	}
+	return string(data), nil
+}
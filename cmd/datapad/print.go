@@ -0,0 +1,132 @@
+package main
+
+import (
+	"datapad/internal/export"
+	"datapad/internal/notes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// pdfConverters turn the HTML of a note into a PDF that lp can print, the
+// first one installed is used
+var pdfConverters = []struct {
+	name    string
+	command func(in, out string) *exec.Cmd
+}{
+	{"wkhtmltopdf", func(in, out string) *exec.Cmd { return exec.Command("wkhtmltopdf", "--quiet", in, out) }},
+	{"pandoc", func(in, out string) *exec.Cmd { return exec.Command("pandoc", in, "-o", out) }},
+}
+
+// runPrint sends a note to the printer or writes it to a PDF file
+func runPrint(env *environment, args []string) error {
+	fs := env.newFlagSet("print")
+	pdfPath := fs.String("pdf", "", "Write the note to this PDF file instead of printing it")
+	command := fs.String("command", "", "Shell command receiving the HTML of the note, print.command by default")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected the ID of the note to print")
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+	note, err := manager.GetNoteByID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	opts := export.BookOptions{Extensions: env.config.Markdown.Extensions}
+	if *pdfPath != "" {
+		if err := export.ExportPDF(manager, []*notes.Note{note}, *pdfPath, opts); err != nil {
+			return err
+		}
+		fmt.Printf("Note written to %s\n", *pdfPath)
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", "datapad-print")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	htmlPath := filepath.Join(dir, notes.Slugify(note.Title)+".html")
+	opts.Title = note.Title
+	if err := export.ExportBook(manager, []*notes.Note{note}, htmlPath, opts); err != nil {
+		return err
+	}
+
+	if *command == "" {
+		*command = env.config.Print.Command
+	}
+	if *command != "" {
+		return pipeHTML(*command, htmlPath)
+	}
+	return printHTML(htmlPath)
+}
+
+// pipeHTML runs a shell command with the HTML file on its standard input
+func pipeHTML(command, htmlPath string) error {
+	in, err := os.Open(htmlPath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = in
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("print command failed: %w", err)
+	}
+	return nil
+}
+
+// printHTML converts the HTML file to PDF and sends it to lp. Without the
+// tools to do so, the HTML file is copied to the current directory so that
+// it can be printed from a browser.
+func printHTML(htmlPath string) error {
+	if _, err := exec.LookPath("lp"); err == nil {
+		pdfPath := strings.TrimSuffix(htmlPath, ".html") + ".pdf"
+		for _, converter := range pdfConverters {
+			if _, err := exec.LookPath(converter.name); err != nil {
+				continue
+			}
+			if err := run(converter.command(htmlPath, pdfPath)); err != nil {
+				return fmt.Errorf("%s failed: %w", converter.name, err)
+			}
+			if err := run(exec.Command("lp", pdfPath)); err != nil {
+				return fmt.Errorf("lp failed: %w", err)
+			}
+			return nil
+		}
+	}
+
+	data, err := os.ReadFile(htmlPath)
+	if err != nil {
+		return err
+	}
+	dest := filepath.Base(htmlPath)
+	if err := os.WriteFile(dest, data, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", dest, err)
+	}
+	fmt.Printf("lp with wkhtmltopdf or pandoc is needed to print, open %s in a browser to print it instead\n", dest)
+	fmt.Println("Use --pdf to write a PDF file without them")
+	return nil
+}
+
+// run runs a command showing its output
+func run(cmd *exec.Cmd) error {
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// reviewExcerptLines is the number of lines of each note printed by review
+const reviewExcerptLines = 3
+
+// runReview prints the notes that haven't been looked at for the longest time
+func runReview(env *environment, args []string) error {
+	fs := env.newFlagSet("review")
+	limit := fs.Int("limit", 5, "Number of notes to print")
+	mark := fs.Bool("mark", false, "Mark the printed notes as reviewed so that the next run shows others")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return errors.New("unexpected arguments")
+	}
+	if *limit <= 0 {
+		return errors.New("--limit must be positive")
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+
+	queue := manager.NotesForReview(*limit)
+	if len(queue) == 0 {
+		fmt.Println("No notes to review")
+		return nil
+	}
+	for i, note := range queue {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s  (%s, last seen %s)\n", note.Title, note.ID, age(note.LastSeen()))
+		lines := strings.Split(strings.TrimSpace(note.Content), "\n")
+		if len(lines) > reviewExcerptLines {
+			lines = append(lines[:reviewExcerptLines], "…")
+		}
+		for _, line := range lines {
+			if line != "" {
+				fmt.Println("    " + line)
+			}
+		}
+	}
+
+	if *mark {
+		return manager.MarkReviewed(queue...)
+	}
+	return nil
+}
+
+// age describes how long ago a time was in days
+func age(t time.Time) string {
+	days := int(time.Since(t).Hours() / 24)
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "yesterday"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
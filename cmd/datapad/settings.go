@@ -0,0 +1,98 @@
+package main
+
+import (
+	"datapad/internal/config"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// runSettings copies the settings in effect into a file, by default the
+// settings file of the storage folder, or imports a settings file into the
+// storage folder
+func runSettings(env *environment, args []string) error {
+	fs := env.newFlagSet("settings")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	storeSettings := filepath.Join(env.storagePath, config.SettingsFile)
+
+	switch {
+	case fs.NArg() >= 1 && fs.NArg() <= 2 && fs.Arg(0) == "export":
+		path := storeSettings
+		if fs.NArg() == 2 {
+			path = fs.Arg(1)
+		}
+		return exportSettings(env.config.Settings(), path, storeSettings)
+
+	case fs.NArg() == 2 && fs.Arg(0) == "import":
+		return importSettings(env, fs.Arg(1), storeSettings)
+	}
+	fs.Usage()
+	return errors.New("expected the export or import action")
+}
+
+// exportSettings writes settings to path, - standing for the standard output
+func exportSettings(settings config.Settings, path, storeSettings string) error {
+	if path == "-" {
+		data, err := settings.Marshal()
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := writeSettings(settings, path, path == storeSettings); err != nil {
+		return err
+	}
+	fmt.Printf("Settings exported to %s\n", path)
+	return nil
+}
+
+// writeSettings writes settings to a file, creating its folder first when it
+// is the storage folder
+func writeSettings(settings config.Settings, path string, inStore bool) error {
+	data, err := settings.Marshal()
+	if err != nil {
+		return err
+	}
+	if inStore {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("error creating the storage folder: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// importSettings checks the settings file at path, - standing for the
+// standard input, and writes it as the settings file of the storage folder
+func importSettings(env *environment, path, storeSettings string) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	settings, err := config.ReadSettings(data)
+	if err != nil {
+		return fmt.Errorf("invalid settings file %s: %w", path, err)
+	}
+	if err := writeSettings(settings, storeSettings, true); err != nil {
+		return err
+	}
+	fmt.Printf("Settings imported into %s\n", storeSettings)
+	if _, err := os.Stat(env.configPath); err == nil {
+		fmt.Printf("The settings of %s still win over the imported ones\n", env.configPath)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"datapad/internal/share"
+	"errors"
+	"fmt"
+)
+
+// runShare shares a note as a GitHub gist
+func runShare(env *environment, args []string) error {
+	fs := env.newFlagSet("share")
+	public := fs.Bool("public", false, "Create a public gist instead of a secret one")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected the ID of the note to share")
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+	note, err := manager.GetNoteByID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	updating := note.Gist != nil
+	ref, err := share.NewGistClient(env.config.Share.Token()).Share(note, *public)
+	if err != nil {
+		return err
+	}
+	note.Gist = ref
+	if err := manager.UpdateNote(note); err != nil {
+		return fmt.Errorf("note shared but its gist couldn't be recorded: %w", err)
+	}
+
+	if updating && *public {
+		fmt.Println("The existing gist was updated, its visibility is unchanged")
+	}
+	fmt.Println(ref.URL)
+	return nil
+}
+
+// runUnshare deletes the gist a note was shared to
+func runUnshare(env *environment, args []string) error {
+	fs := env.newFlagSet("unshare")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected the ID of the note to unshare")
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+	note, err := manager.GetNoteByID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if note.Gist == nil {
+		return errors.New("the note isn't shared")
+	}
+
+	if err := env.confirm(fmt.Sprintf("Delete the gist of %q?", note.Title)); err != nil {
+		return err
+	}
+	if env.dryRun {
+		fmt.Printf("Gist of %q would be deleted\n", note.Title)
+		return nil
+	}
+	if err := share.NewGistClient(env.config.Share.Token()).Unshare(note.Gist); err != nil {
+		return err
+	}
+	note.Gist = nil
+	if err := manager.UpdateNote(note); err != nil {
+		return err
+	}
+	fmt.Println("Gist deleted")
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+// runSnapshot takes a snapshot of the store, lists the snapshots or restores
+// a note from one of them
+func runSnapshot(env *environment, args []string) error {
+	fs := env.newFlagSet("snapshot")
+	list := fs.Bool("list", false, "List the snapshots instead of taking one")
+	restore := fs.String("restore", "", "Restore the note whose ID is given as argument from the snapshot of this day (YYYY-MM-DD)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*restore == "" && fs.NArg() != 0) || (*restore != "" && fs.NArg() != 1) {
+		fs.Usage()
+		return errors.New("expected the ID of the note to restore with --restore only")
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *restore != "":
+		question := fmt.Sprintf("Replace note %s with its version from the snapshot of %s?", fs.Arg(0), *restore)
+		if err := env.confirm(question); err != nil {
+			return err
+		}
+		note, err := manager.RestoreNote(*restore, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Note %q restored from the snapshot of %s\n", note.Title, *restore)
+	case *list:
+		snapshots, err := manager.ListSnapshots()
+		if err != nil {
+			return err
+		}
+		if len(snapshots) == 0 {
+			fmt.Println("No snapshots")
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, snapshot := range snapshots {
+			fmt.Fprintf(w, "%s\t%s\n", snapshot.Name, snapshot.Path)
+		}
+		return w.Flush()
+	default:
+		snapshot, err := manager.Snapshot()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Snapshot written to %s\n", snapshot.Path)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// runStats prints statistics about the notes
+func runStats(env *environment, args []string) error {
+	fs := env.newFlagSet("stats")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return errors.New("unexpected arguments")
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+
+	stats := manager.Stats()
+	fmt.Printf("Notes:         %d (%d archived, %d pinned, %d empty)\n", stats.Notes, stats.Archived, stats.Pinned, stats.Empty)
+	fmt.Printf("Tags:          %d\n", stats.Tags)
+	fmt.Printf("Images:        %d\n", stats.Images)
+	fmt.Printf("Time editing:  %s\n", formatDuration(stats.TimeSpent))
+	if len(stats.MostEdited) > 0 {
+		fmt.Println("\nMost edited notes:")
+		for _, note := range stats.MostEdited {
+			fmt.Printf("  %-10s %s\n", formatDuration(note.TimeSpent), note.Title)
+		}
+	}
+	return nil
+}
+
+// formatDuration describes a duration in hours and minutes
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"datapad/internal/remote"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// runSync synchronizes the store with a WebDAV server
+func runSync(env *environment, args []string) error {
+	fs := env.newFlagSet("sync")
+	webdavURL := fs.String("webdav", env.config.Sync.WebDAVURL, "WebDAV directory to synchronize with")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *webdavURL == "" {
+		fs.Usage()
+		return errors.New("no WebDAV URL, use --webdav or set sync.webdav_url in the config")
+	}
+
+	manager, err := env.manager()
+	if err != nil {
+		return err
+	}
+
+	opts := remote.NewOptions(*webdavURL, env.config.Sync)
+	lastStep := ""
+	opts.Progress = func(p remote.Progress) {
+		if p.Step != lastStep {
+			fmt.Fprintln(os.Stderr, p.Step+"...")
+			lastStep = p.Step
+		}
+	}
+
+	result, err := remote.Sync(manager.StoragePath, manager.Notes, opts)
+	if err != nil {
+		return fmt.Errorf("sync failed: %w", err)
+	}
+	skipped, err := manager.MergeNotes(result.Notes, result.Deleted, result.Base)
+	if err != nil {
+		return err
+	}
+	if err := result.SaveState(manager.StoragePath, skipped); err != nil {
+		return err
+	}
+
+	fmt.Printf("%d note(s) uploaded, %d downloaded, %d deleted locally, %d deleted remotely, %d image(s) transferred\n",
+		result.Uploaded, result.Downloaded, result.DeletedLocal, result.DeletedRemote, result.Images)
+	if result.Conflicts > 0 {
+		fmt.Printf("%d conflict(s): the remote versions were added as notes titled \"... (conflict from <host>)\"\n", result.Conflicts)
+	}
+	return nil
+}
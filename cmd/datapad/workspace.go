@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+// runWorkspace lists the workspaces, marking the active one
+func runWorkspace(env *environment, args []string) error {
+	fs := env.newFlagSet("workspace")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || fs.Arg(0) != "list" {
+		fs.Usage()
+		return errors.New("expected the list action")
+	}
+
+	names := env.config.WorkspaceNames()
+	if len(names) == 0 {
+		fmt.Println("No workspaces, add them to the workspaces section of the config file")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		path, err := env.config.WorkspacePath(name)
+		if err != nil {
+			return err
+		}
+		active := " "
+		if name == env.workspace {
+			active = "*"
+		}
+		fmt.Fprintf(w, "%s %s\t%s\n", active, name, path)
+	}
+	return w.Flush()
+}
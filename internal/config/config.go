@@ -0,0 +1,409 @@
+package config
+
+import (
+	"datapad/internal/expand"
+	"datapad/internal/passphrase"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Config holds the user settings of the application
+type Config struct {
+	Editor        EditorConfig        `json:"editor"`
+	View          ViewConfig          `json:"view"`
+	Accessibility AccessibilityConfig `json:"accessibility"`
+	Tags          TagsConfig          `json:"tags"`
+	Archive       ArchiveConfig       `json:"archive"`
+	Share         ShareConfig         `json:"share"`
+	Sync          SyncConfig          `json:"sync"`
+	Security      SecurityConfig      `json:"security"`
+	Markdown      MarkdownConfig      `json:"markdown"`
+	Print         PrintConfig         `json:"print"`
+	Snapshots     SnapshotsConfig     `json:"snapshots"`
+	Workspaces    map[string]string   `json:"workspaces"` // Storage folders by workspace name
+}
+
+// EditorConfig holds the settings of the note editor
+type EditorConfig struct {
+	TabWidth  int    `json:"tab_width"`  // Number of columns between tab stops
+	SoftTabs  bool   `json:"soft_tabs"`  // Store indentation as spaces instead of tabs
+	Autosave  string `json:"autosave"`   // "off", "on-change", "on-blur" or a delay such as "30s"
+	TrackTime bool   `json:"track_time"` // Record the time spent editing each note
+	WarnChars int    `json:"warn_chars"` // Content length in characters above which the editor warns, 0 disables the warning
+	MaxChars  int    `json:"max_chars"`  // Content length in characters the editor doesn't go past, 0 for no limit
+	TrimSpace bool   `json:"trim_space"` // Strip trailing spaces from the lines of saved notes and end them with a single newline
+	SaveAfter string `json:"save_after"` // Delay such as "2s" grouping the saves of notes into one write of the store, empty writes each save at once
+}
+
+// Autosave modes that are not a delay
+const (
+	AutosaveOff      = "off"
+	AutosaveOnChange = "on-change"
+	AutosaveOnBlur   = "on-blur"
+)
+
+// AutosaveDelay returns the idle delay after which the editor saves the note,
+// and false when autosave is not time based
+func (e EditorConfig) AutosaveDelay() (time.Duration, bool) {
+	switch e.Autosave {
+	case "", AutosaveOff, AutosaveOnChange, AutosaveOnBlur:
+		return 0, false
+	}
+	delay, err := time.ParseDuration(e.Autosave)
+	if err != nil || delay <= 0 {
+		return 0, false
+	}
+	return delay, true
+}
+
+// AutosaveOnChange reports whether the note is saved as soon as typing in the
+// editor pauses
+func (e EditorConfig) AutosaveOnChange() bool {
+	return e.Autosave == AutosaveOnChange
+}
+
+// AutosaveOnBlur reports whether the note is saved when the editor loses focus
+func (e EditorConfig) AutosaveOnBlur() bool {
+	return e.Autosave == AutosaveOnBlur
+}
+
+// SaveDelay returns the delay during which the saves of notes are grouped
+// before the store is written, and false when each save is written at once
+func (e EditorConfig) SaveDelay() (time.Duration, bool) {
+	if e.SaveAfter == "" {
+		return 0, false
+	}
+	delay, err := time.ParseDuration(e.SaveAfter)
+	if err != nil || delay <= 0 {
+		return 0, false
+	}
+	return delay, true
+}
+
+// ViewConfig holds the settings of the note view
+type ViewConfig struct {
+	WrapNavigation   bool   `json:"wrap_navigation"`   // Wrap around at the ends when jumping between notes
+	MaxWidth         int    `json:"max_width"`         // Width of the wide note column, 0 uses the whole terminal
+	Sort             string `json:"sort"`              // "updated" or "manual"
+	RenderANSI       bool   `json:"render_ansi"`       // Display the colors of pasted terminal output instead of removing them
+	DescriptionLines int    `json:"description_lines"` // Lines of text under each note of the list, 1 or 2
+	ImageCount       bool   `json:"image_count"`       // Show how many images each note of the list holds
+	AgeColors        bool   `json:"age_colors"`        // Tint the titles of the list by how recently the notes were updated
+}
+
+// Note orderings
+const (
+	NoteSortUpdated = "updated"
+	NoteSortManual  = "manual"
+)
+
+// AccessibilityConfig holds settings helping to produce accessible notes
+type AccessibilityConfig struct {
+	RequireAltText bool `json:"require_alt_text"` // Refuse to add images without alt text
+}
+
+// TagsConfig holds the settings of tag lists
+type TagsConfig struct {
+	Sort     string   `json:"sort"`     // "alpha" or "frequency"
+	Defaults []string `json:"defaults"` // Tags given to every new note
+}
+
+// Tag orderings
+const (
+	TagSortAlpha     = "alpha"
+	TagSortFrequency = "frequency"
+)
+
+// ArchiveConfig holds the settings of automatic archiving
+type ArchiveConfig struct {
+	AfterDays int `json:"after_days"` // Archive notes untouched for this many days at startup, 0 disables it
+}
+
+// ShareConfig holds the settings of note sharing
+type ShareConfig struct {
+	GitHubToken string `json:"github_token"` // Token with the gist scope, GITHUB_TOKEN is used when empty
+}
+
+// Token returns the GitHub token from the config or the environment
+func (s ShareConfig) Token() string {
+	if s.GitHubToken != "" {
+		return s.GitHubToken
+	}
+	return os.Getenv("GITHUB_TOKEN")
+}
+
+// SyncConfig holds the settings of the WebDAV synchronization
+type SyncConfig struct {
+	WebDAVURL string `json:"webdav_url"` // Directory holding the synchronized store, such as a Nextcloud folder
+	Username  string `json:"username"`   // DATAPAD_WEBDAV_USER is used when empty
+	Password  string `json:"password"`   // DATAPAD_WEBDAV_PASSWORD is used when empty
+}
+
+// Credentials returns the WebDAV user and password from the config or the environment
+func (s SyncConfig) Credentials() (string, string) {
+	username, password := s.Username, s.Password
+	if username == "" {
+		username = os.Getenv("DATAPAD_WEBDAV_USER")
+	}
+	if password == "" {
+		password = os.Getenv("DATAPAD_WEBDAV_PASSWORD")
+	}
+	return username, password
+}
+
+// SecurityConfig holds the settings of the screen lock
+type SecurityConfig struct {
+	LockAfter      string `json:"lock_after"`      // Idle delay such as "5m" after which the screen locks, empty disables it
+	PassphraseHash string `json:"passphrase_hash"` // Hash printed by "datapad passphrase", required to unlock when set
+}
+
+// LockDelay returns the idle delay after which the screen locks, and false
+// when locking is disabled
+func (s SecurityConfig) LockDelay() (time.Duration, bool) {
+	if s.LockAfter == "" {
+		return 0, false
+	}
+	delay, err := time.ParseDuration(s.LockAfter)
+	if err != nil || delay <= 0 {
+		return 0, false
+	}
+	return delay, true
+}
+
+// MarkdownConfig holds the settings of Markdown rendering
+type MarkdownConfig struct {
+	Extensions   []string `json:"extensions"`    // Goldmark extensions to enable, see MarkdownExtensions
+	PreviewLimit int      `json:"preview_limit"` // Size in bytes above which the preview isn't rendered, 0 disables the limit
+	TOCDepth     int      `json:"toc_depth"`     // Deepest heading level listed in tables of contents, from 1 to 6
+}
+
+// Markdown extensions that can be enabled
+const (
+	ExtensionGFM            = "gfm" // Tables, strikethrough, task lists and autolinks
+	ExtensionTable          = "table"
+	ExtensionStrikethrough  = "strikethrough"
+	ExtensionTaskList       = "tasklist"
+	ExtensionLinkify        = "linkify"
+	ExtensionFootnote       = "footnote"
+	ExtensionDefinitionList = "definition_list"
+)
+
+// MarkdownExtensions lists the names accepted in markdown.extensions
+var MarkdownExtensions = []string{
+	ExtensionGFM,
+	ExtensionTable,
+	ExtensionStrikethrough,
+	ExtensionTaskList,
+	ExtensionLinkify,
+	ExtensionFootnote,
+	ExtensionDefinitionList,
+}
+
+// PrintConfig holds the settings of note printing
+type PrintConfig struct {
+	Command string `json:"command"` // Shell command receiving the HTML of the note on its standard input
+}
+
+// SnapshotsConfig holds how many daily snapshots of the store are kept.
+// Automatic snapshots are off until one of the counts is set.
+type SnapshotsConfig struct {
+	Daily   int `json:"daily"`   // Latest daily snapshots kept
+	Weekly  int `json:"weekly"`  // Weeks for which the last snapshot is kept
+	Monthly int `json:"monthly"` // Months for which the last snapshot is kept
+}
+
+// WorkspaceNames returns the names of the workspaces in alphabetical order
+func (c Config) WorkspaceNames() []string {
+	names := make([]string, 0, len(c.Workspaces))
+	for name := range c.Workspaces {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// WorkspacePath returns the storage folder of a workspace, a leading ~ and
+// environment variables being expanded
+func (c Config) WorkspacePath(name string) (string, error) {
+	path, ok := c.Workspaces[name]
+	if !ok {
+		if len(c.Workspaces) == 0 {
+			return "", fmt.Errorf("unknown workspace %q, none is configured", name)
+		}
+		return "", fmt.Errorf("unknown workspace %q, available workspaces are %s", name, strings.Join(c.WorkspaceNames(), ", "))
+	}
+	return expand.Path(path), nil
+}
+
+// WorkspaceFor returns the name of the workspace stored in storagePath, empty
+// when it isn't one of the workspaces
+func (c Config) WorkspaceFor(storagePath string) string {
+	target, err := filepath.Abs(storagePath)
+	if err != nil {
+		return ""
+	}
+	for _, name := range c.WorkspaceNames() {
+		path, err := c.WorkspacePath(name)
+		if err != nil {
+			continue
+		}
+		if abs, err := filepath.Abs(path); err == nil && abs == target {
+			return name
+		}
+	}
+	return ""
+}
+
+// Default returns the configuration used when no config file exists
+func Default() Config {
+	return Config{
+		Editor: EditorConfig{
+			TabWidth:  4,
+			SoftTabs:  true,
+			Autosave:  AutosaveOff,
+			TrackTime: true,
+			WarnChars: 50000,
+		},
+		View: ViewConfig{
+			WrapNavigation:   true,
+			MaxWidth:         100,
+			Sort:             NoteSortUpdated,
+			DescriptionLines: 1,
+			ImageCount:       true,
+		},
+		Tags: TagsConfig{
+			Sort: TagSortAlpha,
+		},
+		Markdown: MarkdownConfig{
+			Extensions:   []string{ExtensionGFM},
+			PreviewLimit: 100000,
+			TOCDepth:     3,
+		},
+	}
+}
+
+// DefaultPath returns the default location of the config file
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine config directory: %w", err)
+	}
+	return filepath.Join(configDir, "datapad", "config.json"), nil
+}
+
+// Load reads the config file at path on top of the default values.
+// A missing file is not an error and yields the default configuration.
+func Load(path string) (Config, error) {
+	return LoadWithSettings(path, "")
+}
+
+// LoadWithSettings reads the settings file at settingsPath, such as the one
+// of the storage folder, then the config file at path on top of it, both on
+// top of the default values. The config file wins for the settings both
+// hold. Missing files aren't errors, and an empty settingsPath skips it.
+func LoadWithSettings(path, settingsPath string) (Config, error) {
+	cfg := Default()
+	if settingsPath != "" {
+		if err := readSettingsFile(&cfg, settingsPath); err != nil {
+			return cfg, err
+		}
+		if err := cfg.validate(); err != nil {
+			return cfg, fmt.Errorf("invalid settings file %s: %w", settingsPath, err)
+		}
+		cfg.normalize()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	cfg.normalize()
+	return cfg, nil
+}
+
+// validate reports settings that can't be interpreted
+func (c Config) validate() error {
+	switch c.Editor.Autosave {
+	case "", AutosaveOff, AutosaveOnChange, AutosaveOnBlur:
+	default:
+		if _, ok := c.Editor.AutosaveDelay(); !ok {
+			return fmt.Errorf("editor.autosave must be %q, %q, %q or a duration such as \"30s\", got %q", AutosaveOff, AutosaveOnChange, AutosaveOnBlur, c.Editor.Autosave)
+		}
+	}
+	if _, ok := c.Editor.SaveDelay(); c.Editor.SaveAfter != "" && !ok {
+		return fmt.Errorf("editor.save_after must be a duration such as \"2s\", got %q", c.Editor.SaveAfter)
+	}
+	switch c.View.Sort {
+	case "", NoteSortUpdated, NoteSortManual:
+	default:
+		return fmt.Errorf("view.sort must be %q or %q, got %q", NoteSortUpdated, NoteSortManual, c.View.Sort)
+	}
+	if c.View.DescriptionLines < 0 || c.View.DescriptionLines > 2 {
+		return fmt.Errorf("view.description_lines must be 1 or 2, got %d", c.View.DescriptionLines)
+	}
+	switch c.Tags.Sort {
+	case "", TagSortAlpha, TagSortFrequency:
+	default:
+		return fmt.Errorf("tags.sort must be %q or %q, got %q", TagSortAlpha, TagSortFrequency, c.Tags.Sort)
+	}
+	if _, ok := c.Security.LockDelay(); c.Security.LockAfter != "" && !ok {
+		return fmt.Errorf("security.lock_after must be a duration such as \"5m\", got %q", c.Security.LockAfter)
+	}
+	for _, name := range c.Markdown.Extensions {
+		if !slices.Contains(MarkdownExtensions, name) {
+			return fmt.Errorf("unknown markdown extension %q, available extensions are %s", name, strings.Join(MarkdownExtensions, ", "))
+		}
+	}
+	if c.Markdown.TOCDepth < 0 || c.Markdown.TOCDepth > 6 {
+		return fmt.Errorf("markdown.toc_depth must be between 1 and 6, got %d", c.Markdown.TOCDepth)
+	}
+	if c.Editor.WarnChars < 0 || c.Editor.MaxChars < 0 {
+		return errors.New("editor.warn_chars and editor.max_chars can't be negative")
+	}
+	if c.Snapshots.Daily < 0 || c.Snapshots.Weekly < 0 || c.Snapshots.Monthly < 0 {
+		return errors.New("snapshots counts can't be negative")
+	}
+	if c.Security.PassphraseHash != "" && !passphrase.Valid(c.Security.PassphraseHash) {
+		return errors.New("security.passphrase_hash must be generated by \"datapad passphrase\"")
+	}
+	for name, path := range c.Workspaces {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("workspaces can't have an empty name")
+		}
+		if strings.TrimSpace(path) == "" {
+			return fmt.Errorf("workspace %q has no storage folder", name)
+		}
+	}
+	return nil
+}
+
+// normalize replaces invalid values with their defaults
+func (c *Config) normalize() {
+	if c.Editor.TabWidth <= 0 {
+		c.Editor.TabWidth = Default().Editor.TabWidth
+	}
+	if c.View.DescriptionLines == 0 {
+		c.View.DescriptionLines = Default().View.DescriptionLines
+	}
+	if c.Markdown.TOCDepth == 0 {
+		c.Markdown.TOCDepth = Default().Markdown.TOCDepth
+	}
+}
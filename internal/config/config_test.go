@@ -0,0 +1,9 @@
+package config
+
+import "testing"
+
+func TestDefaultSnapshotsOff(t *testing.T) {
+	if snapshots := Default().Snapshots; snapshots != (SnapshotsConfig{}) {
+		t.Errorf("default snapshots %+v, want them off", snapshots)
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// SettingsFile is the name of the settings file kept in the storage folder,
+// so that the settings travel with the notes
+const SettingsFile = "settings.json"
+
+// SettingsVersion is the version of the settings file written by this
+// version of datapad. Files of a later version are refused rather than
+// partly understood.
+const SettingsVersion = 1
+
+// Settings are the preferences of the config that don't depend on the
+// machine: credentials, the passphrase and the workspace folders stay in the
+// config file
+type Settings struct {
+	Version       int                 `json:"version"`
+	Editor        EditorConfig        `json:"editor"`
+	View          ViewConfig          `json:"view"`
+	Accessibility AccessibilityConfig `json:"accessibility"`
+	Tags          TagsConfig          `json:"tags"`
+	Archive       ArchiveConfig       `json:"archive"`
+	Markdown      MarkdownConfig      `json:"markdown"`
+	Print         PrintConfig         `json:"print"`
+	Snapshots     SnapshotsConfig     `json:"snapshots"`
+}
+
+// Settings returns the portable preferences of the config
+func (c Config) Settings() Settings {
+	return Settings{
+		Version:       SettingsVersion,
+		Editor:        c.Editor,
+		View:          c.View,
+		Accessibility: c.Accessibility,
+		Tags:          c.Tags,
+		Archive:       c.Archive,
+		Markdown:      c.Markdown,
+		Print:         c.Print,
+		Snapshots:     c.Snapshots,
+	}
+}
+
+// applySettings replaces the portable preferences of the config
+func (c *Config) applySettings(s Settings) {
+	c.Editor = s.Editor
+	c.View = s.View
+	c.Accessibility = s.Accessibility
+	c.Tags = s.Tags
+	c.Archive = s.Archive
+	c.Markdown = s.Markdown
+	c.Print = s.Print
+	c.Snapshots = s.Snapshots
+}
+
+// decodeSettings reads settings on top of the preferences of cfg, the
+// settings missing from data keeping their value
+func decodeSettings(cfg *Config, data []byte) error {
+	settings := cfg.Settings()
+	settings.Version = 0
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return err
+	}
+	if settings.Version > SettingsVersion {
+		return fmt.Errorf("version %d was written by a later version of datapad, this one reads up to version %d", settings.Version, SettingsVersion)
+	}
+	cfg.applySettings(settings)
+	return nil
+}
+
+// ReadSettings reads a settings file on top of the default values and checks
+// it as Load checks the config file
+func ReadSettings(data []byte) (Settings, error) {
+	cfg := Default()
+	if err := decodeSettings(&cfg, data); err != nil {
+		return Settings{}, err
+	}
+	if err := cfg.validate(); err != nil {
+		return Settings{}, err
+	}
+	cfg.normalize()
+	return cfg.Settings(), nil
+}
+
+// Marshal returns the settings as indented JSON
+func (s Settings) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding settings: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// readSettingsFile reads the settings file at path on top of the preferences
+// of cfg. A missing file is not an error and changes nothing, nor is a
+// storage folder that is a file: the commands using the store report it.
+func readSettingsFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+			return nil
+		}
+		return fmt.Errorf("error reading settings file: %w", err)
+	}
+	if err := decodeSettings(cfg, data); err != nil {
+		return fmt.Errorf("error parsing settings file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadWithSettings(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	settingsPath := filepath.Join(dir, SettingsFile)
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(settingsPath, `{"version": 1, "editor": {"tab_width": 2, "warn_chars": 10}}`)
+	write(configPath, `{"editor": {"tab_width": 8}}`)
+
+	cfg, err := LoadWithSettings(configPath, settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Editor.TabWidth != 8 {
+		t.Errorf("tab width %d, the config file must win over the settings", cfg.Editor.TabWidth)
+	}
+	if cfg.Editor.WarnChars != 10 {
+		t.Errorf("warn chars %d, the settings must apply beneath the config file", cfg.Editor.WarnChars)
+	}
+}
+
+func TestLoadWithSettingsWithoutFile(t *testing.T) {
+	dir := t.TempDir()
+	storeFile := filepath.Join(dir, "store")
+	if err := os.WriteFile(storeFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, settingsPath := range map[string]string{
+		"missing file":          filepath.Join(dir, SettingsFile),
+		"missing folder":        filepath.Join(dir, "missing", SettingsFile),
+		"storage folder a file": filepath.Join(storeFile, SettingsFile),
+	} {
+		cfg, err := LoadWithSettings(filepath.Join(dir, "config.json"), settingsPath)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if cfg.Editor.TabWidth != Default().Editor.TabWidth {
+			t.Errorf("%s: defaults not kept", name)
+		}
+	}
+}
+
+func TestReadSettingsLaterVersion(t *testing.T) {
+	if _, err := ReadSettings([]byte(`{"version": 99}`)); err == nil {
+		t.Error("settings of a later version were accepted")
+	}
+}
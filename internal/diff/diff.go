@@ -0,0 +1,152 @@
+// Package diff computes the differences between two texts line by line
+package diff
+
+import "strings"
+
+// Op is the kind of an edit
+type Op int
+
+// Kinds of edits turning the old text into the new one
+const (
+	Equal  Op = iota // Line of both texts
+	Delete           // Line of the old text only
+	Insert           // Line of the new text only
+)
+
+// Edit is a line of the old text, of the new text or of both
+type Edit struct {
+	Op   Op
+	Line string
+}
+
+// Lines returns a shortest sequence of edits turning a into b, computed with
+// the Myers algorithm
+func Lines(a, b []string) []Edit {
+	// The common beginning and end are kept out of the search, which is
+	// quadratic in the number of differences
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]Edit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, Edit{Equal, line})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, Edit{Equal, line})
+	}
+	return edits
+}
+
+// Texts returns the edits turning the lines of a into those of b
+func Texts(a, b string) []Edit {
+	return Lines(strings.Split(a, "\n"), strings.Split(b, "\n"))
+}
+
+// myers finds the furthest reaching path of each number of differences d
+// until one reaches the end of both texts, then walks it back
+func myers(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	limit := n + m
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	// trace[d] holds the diagonals d-1 differences reach, around 0
+	trace := [][]int{}
+
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // Down: an insertion
+			} else {
+				x = v[offset+k-1] + 1 // Right: a deletion
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack rebuilds the edits from the end of both texts
+func backtrack(a, b []string, trace [][]int) []Edit {
+	edits := []Edit{}
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, Edit{Equal, a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, Edit{Insert, b[y-1]})
+			} else {
+				edits = append(edits, Edit{Delete, a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// Merge returns the lines common to both texts as they are and each block of
+// differences between conflict markers, the lines of the old text labeled
+// oldLabel first
+func Merge(edits []Edit, oldLabel, newLabel string) []string {
+	merged := []string{}
+	var deleted, inserted []string
+	flush := func() {
+		if len(deleted) == 0 && len(inserted) == 0 {
+			return
+		}
+		merged = append(merged, "<<<<<<< "+oldLabel)
+		merged = append(merged, deleted...)
+		merged = append(merged, "=======")
+		merged = append(merged, inserted...)
+		merged = append(merged, ">>>>>>> "+newLabel)
+		deleted, inserted = nil, nil
+	}
+	for _, edit := range edits {
+		switch edit.Op {
+		case Delete:
+			deleted = append(deleted, edit.Line)
+		case Insert:
+			inserted = append(inserted, edit.Line)
+		default:
+			flush()
+			merged = append(merged, edit.Line)
+		}
+	}
+	flush()
+	return merged
+}
@@ -0,0 +1,109 @@
+package diff
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// format writes edits as lines prefixed by " ", "-" or "+"
+func format(edits []Edit) []string {
+	lines := make([]string, len(edits))
+	for i, edit := range edits {
+		lines[i] = [...]string{" ", "-", "+"}[edit.Op] + edit.Line
+	}
+	return lines
+}
+
+// sides returns the old and new texts the edits turn into each other
+func sides(edits []Edit) (old, new []string) {
+	old, new = []string{}, []string{}
+	for _, edit := range edits {
+		if edit.Op != Insert {
+			old = append(old, edit.Line)
+		}
+		if edit.Op != Delete {
+			new = append(new, edit.Line)
+		}
+	}
+	return old, new
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string // Lines separated by spaces
+		want []string
+	}{
+		{"both empty", "", "", []string{}},
+		{"old empty", "", "a b", []string{"+a", "+b"}},
+		{"new empty", "a b", "", []string{"-a", "-b"}},
+		{"equal", "a b c", "a b c", []string{" a", " b", " c"}},
+		{"insert at the start", "b c", "a b c", []string{"+a", " b", " c"}},
+		{"insert in the middle", "a c", "a b c", []string{" a", "+b", " c"}},
+		{"insert at the end", "a b", "a b c", []string{" a", " b", "+c"}},
+		{"delete at the start", "a b c", "b c", []string{"-a", " b", " c"}},
+		{"delete in the middle", "a b c", "a c", []string{" a", "-b", " c"}},
+		{"delete at the end", "a b c", "a b", []string{" a", " b", "-c"}},
+		{"replace", "a b c", "a x c", []string{" a", "-b", "+x", " c"}},
+		{"replace everything", "a b", "x y", []string{"-a", "-b", "+x", "+y"}},
+		{"several hunks", "a b c d e", "a x c e f", []string{" a", "-b", "+x", " c", "-d", " e", "+f"}},
+		{"repeated lines", "a a a", "a a", []string{" a", " a", "-a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := strings.Fields(tt.a), strings.Fields(tt.b)
+			edits := Lines(a, b)
+			if got := format(edits); !slices.Equal(got, tt.want) {
+				t.Errorf("Lines(%q, %q) = %q, want %q", a, b, got, tt.want)
+			}
+			if old, new := sides(edits); !slices.Equal(old, a) || !slices.Equal(new, b) {
+				t.Errorf("edits turn %q into %q, want %q into %q", old, new, a, b)
+			}
+		})
+	}
+}
+
+func TestLinesShortest(t *testing.T) {
+	// The example of the Myers paper: 4 lines in common, so 5 changes
+	a := strings.Fields("a b c a b b a")
+	b := strings.Fields("c b a b a c")
+	edits := Lines(a, b)
+	changes := 0
+	for _, edit := range edits {
+		if edit.Op != Equal {
+			changes++
+		}
+	}
+	if changes != 5 {
+		t.Errorf("Lines made %d change(s), want 5: %q", changes, format(edits))
+	}
+	if old, new := sides(edits); !slices.Equal(old, a) || !slices.Equal(new, b) {
+		t.Errorf("edits turn %q into %q, want %q into %q", old, new, a, b)
+	}
+}
+
+func TestTexts(t *testing.T) {
+	if got, want := format(Texts("", "")), []string{" "}; !slices.Equal(got, want) {
+		t.Errorf("Texts of empty texts = %q, want %q", got, want)
+	}
+	if got, want := format(Texts("a\nb\n", "a\nc\n")), []string{" a", "-b", "+c", " "}; !slices.Equal(got, want) {
+		t.Errorf("Texts = %q, want %q", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	edits := Lines(strings.Fields("a b c d"), strings.Fields("a x c d e"))
+	want := []string{
+		"a",
+		"<<<<<<< mine", "b", "=======", "x", ">>>>>>> theirs",
+		"c", "d",
+		"<<<<<<< mine", "=======", "e", ">>>>>>> theirs",
+	}
+	if got := Merge(edits, "mine", "theirs"); !slices.Equal(got, want) {
+		t.Errorf("Merge = %q, want %q", got, want)
+	}
+	if got := Merge(Lines(nil, nil), "mine", "theirs"); len(got) != 0 {
+		t.Errorf("Merge of no edits = %q, want nothing", got)
+	}
+}
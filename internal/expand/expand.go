@@ -0,0 +1,42 @@
+// Package expand expands the home directory and environment variables in
+// paths typed by the user, as a shell would
+package expand
+
+import (
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+)
+
+// variable matches $VAR and ${VAR}
+var variable = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Path expands a leading ~ or ~user into a home directory and $VAR or ${VAR}
+// into the value of the environment variable. A home directory that can't be
+// found and an unset variable are left as typed, so that the path still
+// tells what was meant when it doesn't exist.
+func Path(path string) string {
+	path = variable.ReplaceAllStringFunc(path, func(match string) string {
+		name := strings.Trim(match, "${}")
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		return match
+	})
+	if !strings.HasPrefix(path, "~") {
+		return path
+	}
+
+	name, _, _ := strings.Cut(path[1:], "/")
+	home := ""
+	if name == "" {
+		home, _ = os.UserHomeDir()
+	} else if u, err := user.Lookup(name); err == nil {
+		home = u.HomeDir
+	}
+	if home == "" {
+		return path
+	}
+	return home + path[1+len(name):]
+}
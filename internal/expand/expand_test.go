@@ -0,0 +1,54 @@
+package expand
+
+import (
+	"os/user"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	t.Setenv("HOME", "/home/ann")
+	t.Setenv("NOTES", "/data/notes")
+	t.Setenv("EMPTY", "")
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~", "/home/ann"},
+		{"~/", "/home/ann/"},
+		{"~/x", "/home/ann/x"},
+		{"~/a/b.png", "/home/ann/a/b.png"},
+		{"a/~/b", "a/~/b"},
+		{"$NOTES", "/data/notes"},
+		{"$NOTES/img.png", "/data/notes/img.png"},
+		{"${NOTES}", "/data/notes"},
+		{"${NOTES}2024/a.png", "/data/notes2024/a.png"},
+		{"$NOTES2024/a.png", "$NOTES2024/a.png"},
+		{"/srv/$NOTES", "/srv//data/notes"},
+		{"$EMPTY/a", "/a"},
+		{"$UNSET_VARIABLE/a", "$UNSET_VARIABLE/a"},
+		{"${UNSET_VARIABLE}/a", "${UNSET_VARIABLE}/a"},
+		{"$HOME/x", "/home/ann/x"},
+		{"~/$NOTES", "/home/ann//data/notes"},
+		{"price $5", "price $5"},
+		{"/plain/path", "/plain/path"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Path(tt.path); got != tt.want {
+			t.Errorf("Path(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPathOtherUser(t *testing.T) {
+	current, err := user.Current()
+	if err != nil {
+		t.Skip("current user unknown:", err)
+	}
+	if got, want := Path("~"+current.Username+"/x"), current.HomeDir+"/x"; got != want {
+		t.Errorf("Path(~%s/x) = %q, want %q", current.Username, got, want)
+	}
+	if got := Path("~no-such-user-here/x"); got != "~no-such-user-here/x" {
+		t.Errorf("unknown user expanded to %q", got)
+	}
+}
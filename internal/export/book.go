@@ -0,0 +1,179 @@
+package export
+
+import (
+	"bytes"
+	"datapad/internal/markdown"
+	"datapad/internal/notes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Orders of the notes of a book
+const (
+	BookOrderSelection = "selection" // Order in which the notes were given
+	BookOrderTitle     = "title"
+	BookOrderCreated   = "created"
+	BookOrderUpdated   = "updated"
+)
+
+// ErrUnsupportedFormat is returned for book files whose extension isn't handled
+var ErrUnsupportedFormat = errors.New("unsupported book format, use a .html or .pdf file")
+
+// BookOptions configures the export of several notes as a single document
+type BookOptions struct {
+	Title     string // Title of the document, "Notes" when empty
+	Order     string // One of the BookOrder constants, selection order when empty
+	Manifest  string // Optional .json or .md file listing the images of the book
+	SkipEmpty bool   // Leave out the notes with neither text nor images instead of marking them empty
+
+	// Markdown extensions enabled, among config.MarkdownExtensions
+	Extensions []string
+}
+
+// bookChapter is a note of the book with the anchor the contents link to
+type bookChapter struct {
+	Anchor string
+	Page   sitePage
+}
+
+// ExportBook writes the given notes to a single self-contained document with a
+// table of contents linking to each note. Wikilinks between notes of the book
+// become links within the document and images are embedded. The format
+// follows the extension of path, .html or .pdf. The images are also listed in
+// opts.Manifest when it is set.
+func ExportBook(manager *notes.NotesManager, selection []*notes.Note, path string, opts BookOptions) error {
+	if opts.SkipEmpty {
+		selection = NonEmpty(selection)
+	}
+	if len(selection) == 0 {
+		return errors.New("no notes to export")
+	}
+	if opts.Manifest != "" && !manifestFormat(opts.Manifest) {
+		return ErrUnsupportedManifestFormat
+	}
+	ordered, err := orderBook(selection, opts.Order)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html":
+		err = exportHTMLBook(manager, ordered, path, opts)
+	case ".pdf":
+		err = ExportPDF(manager, ordered, path, opts)
+	default:
+		return ErrUnsupportedFormat
+	}
+	if err != nil || opts.Manifest == "" {
+		return err
+	}
+	return WriteManifest(Manifest(manager, ordered, func(img notes.Image) string {
+		return img.Path
+	}, func(*notes.Note) string {
+		return ""
+	}), opts.Manifest)
+}
+
+// exportHTMLBook writes the ordered notes to a self-contained HTML document
+func exportHTMLBook(manager *notes.NotesManager, ordered []*notes.Note, path string, opts BookOptions) error {
+	anchors := bookAnchors(ordered)
+
+	md := markdown.New(opts.Extensions)
+	chapters := make([]bookChapter, 0, len(ordered))
+	for _, note := range ordered {
+		page, err := buildPage(md, manager, note, func(target *notes.Note) string {
+			if anchor, ok := anchors[target.ID]; ok {
+				return "#" + anchor
+			}
+			return ""
+		}, func(img notes.Image) (string, error) {
+			return dataURI(manager.GetImageFullPath(img.Path))
+		})
+		if err != nil {
+			return err
+		}
+		chapters = append(chapters, bookChapter{Anchor: anchors[note.ID], Page: page})
+	}
+
+	data, err := fs.ReadFile(embeddedTemplates, "templates/book.html")
+	if err != nil {
+		return fmt.Errorf("error reading template book.html: %w", err)
+	}
+	tmpl, err := template.New("book.html").Parse(string(data))
+	if err != nil {
+		return fmt.Errorf("error parsing template book.html: %w", err)
+	}
+	style, err := fs.ReadFile(embeddedTemplates, "templates/style.css")
+	if err != nil {
+		return fmt.Errorf("error reading template style.css: %w", err)
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = "Notes"
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, struct {
+		Title    string
+		Style    template.CSS
+		Chapters []bookChapter
+	}{title, template.CSS(style), chapters})
+	if err != nil {
+		return fmt.Errorf("error rendering book: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error writing book: %w", err)
+	}
+	return nil
+}
+
+// orderBook returns the notes of a book in the requested order
+func orderBook(selection []*notes.Note, order string) ([]*notes.Note, error) {
+	ordered := append([]*notes.Note(nil), selection...)
+	var less func(a, b *notes.Note) bool
+	switch order {
+	case "", BookOrderSelection:
+		return ordered, nil
+	case BookOrderTitle:
+		less = func(a, b *notes.Note) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) }
+	case BookOrderCreated:
+		less = func(a, b *notes.Note) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case BookOrderUpdated:
+		less = func(a, b *notes.Note) bool { return a.UpdatedAt.After(b.UpdatedAt) }
+	default:
+		return nil, fmt.Errorf("unknown order %q, use %s, %s, %s or %s", order, BookOrderSelection, BookOrderTitle, BookOrderCreated, BookOrderUpdated)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return less(ordered[i], ordered[j]) })
+	return ordered, nil
+}
+
+// bookAnchors assigns a unique anchor to every note of the book
+func bookAnchors(ns []*notes.Note) map[string]string {
+	anchors := pageNames(ns)
+	for id, name := range anchors {
+		anchors[id] = strings.TrimSuffix(name, ".html")
+	}
+	return anchors
+}
+
+// dataURI encodes an image so that the document doesn't depend on the store
+func dataURI(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading image %s: %w", filepath.Base(path), err)
+	}
+	mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(path)))
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
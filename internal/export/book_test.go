@@ -0,0 +1,173 @@
+package export
+
+import (
+	"datapad/internal/config"
+	"datapad/internal/notes"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	tocEntry  = regexp.MustCompile(`<li><a href="#([^"]*)">([^<]*)</a></li>`)
+	chapterID = regexp.MustCompile(`<article class="chapter" id="([^"]*)">\n<h1>([^<]*)</h1>`)
+)
+
+// readBook exports the notes to an HTML book and returns it
+func readBook(t *testing.T, manager *notes.NotesManager, ns []*notes.Note, opts BookOptions) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "book.html")
+	if err := ExportBook(manager, ns, path, opts); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// extendedMarkdown uses the syntax of the optional Markdown extensions
+const extendedMarkdown = "| a | b |\n|---|---|\n| 1 | 2 |\n\n- [x] done\n\n~~old~~ and a note[^1]\n\n[^1]: Footnote text\n"
+
+func TestExportBookExtensions(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions []string
+		want       []string
+		unwanted   []string
+	}{
+		{"none", nil, []string{"| a | b |", "[x] done", "~~old~~"}, []string{"<table>", "checkbox", "<del>"}},
+		{"gfm", []string{config.ExtensionGFM}, []string{"<table>", `type="checkbox"`, "<del>old</del>"}, []string{"|---|"}},
+		{"footnote", []string{config.ExtensionFootnote}, []string{`class="footnotes"`, "Footnote text"}, []string{"[^1]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := notes.OpenNotesManager(t.TempDir())
+			note := manager.CreateNote("Extended")
+			note.Content = extendedMarkdown
+			path := filepath.Join(t.TempDir(), "book.html")
+			if err := ExportBook(manager, []*notes.Note{note}, path, BookOptions{Extensions: tt.extensions}); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("%q missing from the book", want)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(string(data), unwanted) {
+					t.Errorf("%q found in the book", unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestExportBookContents(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	ns := []*notes.Note{
+		siteNote(manager, "Plan", "See [[Trip]] and [[Elsewhere]]", 0),
+		siteNote(manager, "Trip", "Back to [[Plan]]", 1),
+		siteNote(manager, "Plan", "Another plan", 2),
+	}
+	book := readBook(t, manager, ns, BookOptions{Title: "Holidays"})
+
+	// The contents list every chapter in order, each linking to its anchor
+	toc := tocEntry.FindAllStringSubmatch(book, -1)
+	chapters := chapterID.FindAllStringSubmatch(book, -1)
+	want := [][]string{{"plan", "Plan"}, {"trip", "Trip"}, {"plan-2", "Plan"}}
+	if len(toc) != len(want) || len(chapters) != len(want) {
+		t.Fatalf("%d contents entries and %d chapter(s), want %d", len(toc), len(chapters), len(want))
+	}
+	for i, w := range want {
+		if !slices.Equal(toc[i][1:], w) || !slices.Equal(chapters[i][1:], w) {
+			t.Errorf("entry %d links %q to %q, chapter %q is %q, want %q", i, toc[i][2], toc[i][1], chapters[i][1], chapters[i][2], w)
+		}
+	}
+	if !strings.Contains(book, "<title>Holidays</title>") || !strings.Contains(book, "<h1>Holidays</h1>") {
+		t.Error("book title missing")
+	}
+
+	// Wikilinks point at the chapters, links to notes left out aren't links
+	for _, want := range []string{`href="#trip"`, `href="#plan"`} {
+		if !strings.Contains(book, want) {
+			t.Errorf("%s missing from the book", want)
+		}
+	}
+	if strings.Contains(book, "Elsewhere</a>") {
+		t.Error("link to a note missing from the book")
+	}
+}
+
+func TestExportBookSingleNote(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	book := readBook(t, manager, []*notes.Note{siteNote(manager, "Plan", "Text", 0)}, BookOptions{})
+	if strings.Contains(book, `class="toc"`) {
+		t.Error("contents written for a single note")
+	}
+	if !strings.Contains(book, "<title>Notes</title>") {
+		t.Error("default title missing")
+	}
+}
+
+func TestExportBookOrder(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	// Titles, creation and update dates each give a different order
+	ns := []*notes.Note{
+		siteNote(manager, "beta", "b", 2),
+		siteNote(manager, "Alpha", "a", 1),
+		siteNote(manager, "gamma", "c", 0),
+	}
+	for i, minutes := range []int{5, 3, 9} {
+		ns[i].UpdatedAt = time.Date(2024, 2, 1, 0, minutes, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{"", []string{"beta", "Alpha", "gamma"}},
+		{BookOrderSelection, []string{"beta", "Alpha", "gamma"}},
+		{BookOrderTitle, []string{"Alpha", "beta", "gamma"}},
+		{BookOrderCreated, []string{"gamma", "Alpha", "beta"}},
+		{BookOrderUpdated, []string{"gamma", "beta", "Alpha"}},
+	}
+	for _, tt := range tests {
+		book := readBook(t, manager, ns, BookOptions{Order: tt.order})
+		titles := []string{}
+		for _, chapter := range chapterID.FindAllStringSubmatch(book, -1) {
+			titles = append(titles, chapter[2])
+		}
+		if !slices.Equal(titles, tt.want) {
+			t.Errorf("order %q gives %q, want %q", tt.order, titles, tt.want)
+		}
+	}
+	if names := []string{ns[0].Title, ns[1].Title, ns[2].Title}; !slices.Equal(names, []string{"beta", "Alpha", "gamma"}) {
+		t.Errorf("selection reordered to %q", names)
+	}
+}
+
+func TestExportBookErrors(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	note := siteNote(manager, "Plan", "Text", 0)
+	dir := t.TempDir()
+	if err := ExportBook(manager, []*notes.Note{note}, filepath.Join(dir, "book.html"), BookOptions{Order: "size"}); err == nil || !strings.Contains(err.Error(), `unknown order "size"`) {
+		t.Errorf("unknown order gives %v", err)
+	}
+	if err := ExportBook(manager, []*notes.Note{note}, filepath.Join(dir, "book.txt"), BookOptions{}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("book.txt gives %v, want ErrUnsupportedFormat", err)
+	}
+	if err := ExportBook(manager, nil, filepath.Join(dir, "book.html"), BookOptions{}); err == nil {
+		t.Error("book without notes exported")
+	}
+}
@@ -0,0 +1,59 @@
+package export
+
+import (
+	"datapad/internal/notes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImagesReport summarizes an export of the images of a note
+type ImagesReport struct {
+	Written []string // Files written, in the order of the images
+	Missing []string // Images of the note missing from the store, skipped
+}
+
+// ExportImages copies the images of a note into dir, named after their caption
+// or after their stored file when they have none. Names already taken, by
+// another image or by a file of dir, get a numeric suffix so that nothing is
+// overwritten. Images missing from the store are reported rather than failing
+// the export.
+func ExportImages(manager *notes.NotesManager, note *notes.Note, dir string) (ImagesReport, error) {
+	var report ImagesReport
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return report, fmt.Errorf("unable to create export directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return report, fmt.Errorf("error reading export directory: %w", err)
+	}
+	used := map[string]bool{}
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+		used[strings.TrimSuffix(name, filepath.Ext(name))] = true
+	}
+
+	for _, img := range note.Images {
+		if !manager.ImageExists(img.Path) {
+			report.Missing = append(report.Missing, img.Path)
+			continue
+		}
+		ext := filepath.Ext(img.Path)
+		name := strings.TrimSpace(img.Caption)
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(img.Path), ext)
+		}
+		dest := filepath.Join(dir, notes.UniqueSlug(name, used)+strings.ToLower(ext))
+
+		data, err := os.ReadFile(manager.GetImageFullPath(img.Path))
+		if err != nil {
+			return report, fmt.Errorf("error reading image %s: %w", img.Path, err)
+		}
+		if err := os.WriteFile(dest, data, 0644); err != nil {
+			return report, fmt.Errorf("error copying image %s: %w", img.Path, err)
+		}
+		report.Written = append(report.Written, dest)
+	}
+	return report, nil
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"datapad/internal/notes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Launcher formats, chosen by the extension of the launcher file
+const (
+	LauncherDesktop = ".desktop" // Desktop entry of Linux desktops
+	LauncherCommand = ".command" // Shell script opened by the macOS Finder
+)
+
+// Launcher is a file opening datapad on a note when clicked on the desktop
+type Launcher struct {
+	Executable  string // Absolute path of the datapad program
+	StoragePath string // Storage folder holding the note
+	Note        *notes.Note
+}
+
+// args returns the command line opening the note
+func (l Launcher) args() []string {
+	return []string{l.Executable, "-storage", l.StoragePath, "open", l.Note.ID}
+}
+
+// Desktop returns the launcher as a desktop entry, run in a terminal
+func (l Launcher) Desktop() []byte {
+	args := make([]string, len(l.args()))
+	for i, arg := range l.args() {
+		args[i] = desktopQuote(arg)
+	}
+	title := desktopValue(l.Note.Title)
+	if title == "" {
+		title = "Untitled"
+	}
+	var b strings.Builder
+	b.WriteString("[Desktop Entry]\n")
+	b.WriteString("Type=Application\n")
+	fmt.Fprintf(&b, "Name=%s\n", title)
+	fmt.Fprintf(&b, "Comment=Open %s in Datapad\n", title)
+	fmt.Fprintf(&b, "Exec=%s\n", strings.Join(args, " "))
+	b.WriteString("Terminal=true\n")
+	b.WriteString("Icon=accessories-text-editor\n")
+	b.WriteString("Categories=Office;\n")
+	return []byte(b.String())
+}
+
+// Command returns the launcher as a shell script, which the macOS Finder runs
+// in a terminal
+func (l Launcher) Command() []byte {
+	args := make([]string, len(l.args()))
+	for i, arg := range l.args() {
+		args[i] = shellQuote(arg)
+	}
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# Opens %s in Datapad\n", strings.Join(strings.Fields(l.Note.Title), " "))
+	fmt.Fprintf(&b, "exec %s\n", strings.Join(args, " "))
+	return []byte(b.String())
+}
+
+// WriteLauncher writes the launcher to path in the format of its extension,
+// executable so that the desktop runs it
+func WriteLauncher(l Launcher, path string) error {
+	var data []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case LauncherDesktop:
+		data = l.Desktop()
+	case LauncherCommand:
+		data = l.Command()
+	default:
+		return fmt.Errorf("unknown launcher format %q, name the file *%s or *%s", filepath.Ext(path), LauncherDesktop, LauncherCommand)
+	}
+	if err := os.WriteFile(path, data, 0755); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0755); err != nil {
+		return fmt.Errorf("error making %s executable: %w", path, err)
+	}
+	return nil
+}
+
+// desktopValue returns text as the value of a desktop entry key, on a single
+// line with its backslashes escaped
+func desktopValue(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, `\`, `\\`)
+}
+
+// desktopQuote quotes an argument of the Exec key of a desktop entry. The
+// specification asks for double quotes around arguments holding reserved
+// characters, with ", `, $ and \ escaped within them, then for the
+// backslashes to be escaped again as in any value. A literal % is doubled.
+func desktopQuote(arg string) string {
+	arg = strings.ReplaceAll(arg, "%", "%%")
+	if !strings.ContainsAny(arg, " \t\n\"'\\><~|&;$*?#()`") {
+		return arg
+	}
+	quoted := strings.NewReplacer(`"`, `\"`, "`", "\\`", `$`, `\$`, `\`, `\\`).Replace(arg)
+	return strings.ReplaceAll(`"`+quoted+`"`, `\`, `\\`)
+}
+
+// shellQuote quotes an argument for a POSIX shell
+func shellQuote(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
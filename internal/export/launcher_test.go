@@ -0,0 +1,90 @@
+package export
+
+import (
+	"datapad/internal/notes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// launcher returns a launcher of a note with the given title
+func launcher(title string) Launcher {
+	note := notes.NewNote(title)
+	note.ID = "20240101000000abcdef"
+	return Launcher{Executable: "/usr/bin/datapad", StoragePath: "/home/me/notes", Note: note}
+}
+
+func TestLauncherDesktop(t *testing.T) {
+	want := "[Desktop Entry]\n" +
+		"Type=Application\n" +
+		"Name=Trip plan\n" +
+		"Comment=Open Trip plan in Datapad\n" +
+		"Exec=/usr/bin/datapad -storage /home/me/notes open 20240101000000abcdef\n" +
+		"Terminal=true\n" +
+		"Icon=accessories-text-editor\n" +
+		"Categories=Office;\n"
+	if got := string(launcher("Trip plan").Desktop()); got != want {
+		t.Errorf("desktop entry =\n%s\nwant\n%s", got, want)
+	}
+
+	// Titles are kept on one line and paths quoted as the specification asks
+	l := launcher("Two\nlines \\ here")
+	l.StoragePath = `/home/me/My "notes" $HOME 100%`
+	entry := string(l.Desktop())
+	for _, want := range []string{
+		"Name=Two lines \\\\ here\n",
+		`Exec=/usr/bin/datapad -storage "/home/me/My \\"notes\\" \\$HOME 100%%" open`,
+	} {
+		if !strings.Contains(entry, want) {
+			t.Errorf("%q missing from\n%s", want, entry)
+		}
+	}
+	if !strings.Contains(string(launcher(" ").Desktop()), "Name=Untitled\n") {
+		t.Error("launcher of an untitled note has no name")
+	}
+}
+
+func TestLauncherCommand(t *testing.T) {
+	l := launcher("It's\tmine")
+	l.StoragePath = "/home/me/it's here"
+	want := "#!/bin/sh\n" +
+		"# Opens It's mine in Datapad\n" +
+		`exec '/usr/bin/datapad' '-storage' '/home/me/it'\''s here' 'open' '20240101000000abcdef'` + "\n"
+	if got := string(l.Command()); got != want {
+		t.Errorf("command =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteLauncher(t *testing.T) {
+	dir := t.TempDir()
+	l := launcher("Plan")
+	for name, want := range map[string][]byte{"plan.desktop": l.Desktop(), "plan.COMMAND": l.Command()} {
+		path := filepath.Join(dir, name)
+		// An existing file is overwritten and made executable
+		if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteLauncher(l, path); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != string(want) {
+			t.Errorf("%s holds\n%s\nwant\n%s", name, data, want)
+		}
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0755 {
+			t.Errorf("%s not executable: %v", name, info.Mode())
+		}
+	}
+
+	path := filepath.Join(dir, "plan.lnk")
+	if err := WriteLauncher(l, path); err == nil || !strings.Contains(err.Error(), `unknown launcher format ".lnk"`) {
+		t.Errorf("WriteLauncher to .lnk = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("launcher of an unknown format written")
+	}
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"datapad/internal/notes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsupportedManifestFormat is returned for manifest files whose extension
+// isn't handled
+var ErrUnsupportedManifestFormat = errors.New("unsupported manifest format, use a .json or .md file")
+
+// ManifestEntry describes an image exported with a note
+type ManifestEntry struct {
+	Note    string `json:"note"`           // Title of the note
+	NoteID  string `json:"note_id"`        // ID of the note
+	Page    string `json:"page,omitempty"` // Page of the note in a site
+	File    string `json:"file"`           // Exported image file
+	Caption string `json:"caption"`
+	AltText string `json:"alt_text"`
+}
+
+// Manifest lists the images of the given notes that exist on disk, in the
+// order of the notes. file gives the name of each image in the export and
+// page the page of its note, if any.
+func Manifest(manager *notes.NotesManager, ns []*notes.Note, file func(img notes.Image) string, page func(note *notes.Note) string) []ManifestEntry {
+	entries := []ManifestEntry{}
+	for _, note := range ns {
+		for _, img := range note.Images {
+			if !manager.ImageExists(img.Path) {
+				continue
+			}
+			entries = append(entries, ManifestEntry{
+				Note:    note.Title,
+				NoteID:  note.ID,
+				Page:    page(note),
+				File:    file(img),
+				Caption: img.Caption,
+				AltText: img.AltText,
+			})
+		}
+	}
+	return entries
+}
+
+// manifestFormat reports whether the extension of path is a manifest format
+func manifestFormat(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".json" || ext == ".md"
+}
+
+// WriteManifest writes the entries to path as JSON or as a Markdown index
+// grouped by note, following its extension
+func WriteManifest(entries []ManifestEntry, path string) error {
+	var data []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var err error
+		data, err = json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error serializing manifest: %w", err)
+		}
+	case ".md":
+		data = []byte(manifestMarkdown(entries))
+	default:
+		return ErrUnsupportedManifestFormat
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+	return nil
+}
+
+// manifestMarkdown renders the entries as a Markdown list with a section per note
+func manifestMarkdown(entries []ManifestEntry) string {
+	var b strings.Builder
+	b.WriteString("# Attachments\n")
+	if len(entries) == 0 {
+		b.WriteString("\nNo images were exported.\n")
+	}
+
+	noteID := ""
+	for _, entry := range entries {
+		if entry.NoteID != noteID {
+			noteID = entry.NoteID
+			heading := entry.Note
+			if entry.Page != "" {
+				heading = fmt.Sprintf("[%s](%s)", entry.Note, entry.Page)
+			}
+			fmt.Fprintf(&b, "\n## %s\n\n", heading)
+		}
+		fmt.Fprintf(&b, "- `%s`\n", entry.File)
+		fmt.Fprintf(&b, "  - Caption: %s\n", orNone(entry.Caption))
+		fmt.Fprintf(&b, "  - Alt text: %s\n", orNone(entry.AltText))
+	}
+	return b.String()
+}
+
+// orNone returns s, or "(none)" when it is empty
+func orNone(s string) string {
+	if s = strings.TrimSpace(s); s == "" {
+		return "(none)"
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
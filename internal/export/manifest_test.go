@@ -0,0 +1,144 @@
+package export
+
+import (
+	"datapad/internal/notes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// addImage stores a file holding content and attaches it to the note
+func addImage(t *testing.T, manager *notes.NotesManager, note *notes.Note, content, caption, alt string) string {
+	t.Helper()
+	source := filepath.Join(t.TempDir(), content+".png")
+	if err := os.WriteFile(source, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := manager.ImportImage(note.ID, source, caption, alt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stored
+}
+
+// manifestNotes returns a manager whose notes Trip and Garden hold several
+// images, one of them missing from the store
+func manifestNotes(t *testing.T) (*notes.NotesManager, []*notes.Note) {
+	t.Helper()
+	manager := notes.OpenNotesManager(t.TempDir())
+	trip := siteNote(manager, "Trip", "Photos", 0)
+	garden := siteNote(manager, "Garden", "Plants", 1)
+	addImage(t, manager, trip, "beach", "The beach", "Sand and waves")
+	addImage(t, manager, trip, "hotel", "Hotel", "")
+	addImage(t, manager, garden, "roses", "", "Red roses")
+	garden.AddImage("missing.png", "Lost", "Gone")
+	return manager, []*notes.Note{trip, garden}
+}
+
+// readManifest decodes a JSON manifest
+func readManifest(t *testing.T, path string) []ManifestEntry {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []ManifestEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestManifest(t *testing.T) {
+	manager, ns := manifestNotes(t)
+	entries := Manifest(manager, ns, func(img notes.Image) string {
+		return "files/" + img.Path
+	}, func(note *notes.Note) string {
+		return note.Title + ".html"
+	})
+
+	trip, garden := ns[0], ns[1]
+	want := []ManifestEntry{
+		{Note: "Trip", NoteID: trip.ID, Page: "Trip.html", File: "files/" + trip.Images[0].Path, Caption: "The beach", AltText: "Sand and waves"},
+		{Note: "Trip", NoteID: trip.ID, Page: "Trip.html", File: "files/" + trip.Images[1].Path, Caption: "Hotel"},
+		{Note: "Garden", NoteID: garden.ID, Page: "Garden.html", File: "files/" + garden.Images[0].Path, AltText: "Red roses"},
+	}
+	if !slices.Equal(entries, want) {
+		t.Errorf("Manifest =\n%+v\nwant\n%+v", entries, want)
+	}
+}
+
+func TestSiteManifest(t *testing.T) {
+	manager, ns := manifestNotes(t)
+	dir := t.TempDir()
+	exportSite(t, manager, dir)
+
+	entries := readManifest(t, filepath.Join(dir, "attachments.json"))
+	if len(entries) != 3 {
+		t.Fatalf("site manifest lists %d image(s), want 3", len(entries))
+	}
+	for i, entry := range entries {
+		note := ns[min(i/2, 1)]
+		if entry.NoteID != note.ID || entry.Page != "notes/"+notes.Slugify(note.Title)+".html" {
+			t.Errorf("entry %d belongs to %q on %q, want %q", i, entry.NoteID, entry.Page, note.Title)
+		}
+		if _, err := os.Stat(filepath.Join(dir, entry.File)); err != nil {
+			t.Errorf("entry %d names a file missing from the site: %v", i, err)
+		}
+	}
+	if entries[0].Caption != "The beach" || entries[0].AltText != "Sand and waves" || entries[2].AltText != "Red roses" {
+		t.Errorf("captions and alt texts lost: %+v", entries)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "attachments.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := string(data)
+	for _, want := range []string{
+		"## [Trip](notes/trip.html)\n\n- `assets/" + ns[0].Images[0].Path + "`\n  - Caption: The beach\n  - Alt text: Sand and waves\n",
+		"  - Caption: Hotel\n  - Alt text: (none)\n",
+		"## [Garden](notes/garden.html)\n\n- `assets/" + ns[1].Images[0].Path + "`\n  - Caption: (none)\n  - Alt text: Red roses\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("%q missing from attachments.md:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "missing.png") {
+		t.Error("image missing from the store listed")
+	}
+}
+
+func TestBookManifest(t *testing.T) {
+	manager, ns := manifestNotes(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "garden.json")
+	opts := BookOptions{Manifest: path}
+	if err := ExportBook(manager, ns[1:], filepath.Join(dir, "garden.html"), opts); err != nil {
+		t.Fatal(err)
+	}
+	entries := readManifest(t, path)
+	want := []ManifestEntry{
+		{Note: "Garden", NoteID: ns[1].ID, File: ns[1].Images[0].Path, AltText: "Red roses"},
+	}
+	if !slices.Equal(entries, want) {
+		t.Errorf("note manifest =\n%+v\nwant\n%+v", entries, want)
+	}
+}
+
+func TestWriteManifestFormat(t *testing.T) {
+	if err := WriteManifest(nil, filepath.Join(t.TempDir(), "list.txt")); !errors.Is(err, ErrUnsupportedManifestFormat) {
+		t.Errorf("WriteManifest to .txt = %v, want ErrUnsupportedManifestFormat", err)
+	}
+	path := filepath.Join(t.TempDir(), "empty.md")
+	if err := WriteManifest(nil, path); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "No images were exported.") {
+		t.Errorf("empty manifest says %q", data)
+	}
+}
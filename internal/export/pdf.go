@@ -0,0 +1,523 @@
+package export
+
+import (
+	"bytes"
+	"datapad/internal/markdown"
+	"datapad/internal/notes"
+	"datapad/internal/pdf"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Page layout of the PDF documents, in points
+const (
+	pdfMargin     = 56
+	pdfBodySize   = 11
+	pdfCodeSize   = 9.5
+	pdfSmallSize  = 9
+	pdfLineHeight = 1.4 // Line spacing relative to the font size
+	pdfIndent     = 18  // Indentation of list items and quotes
+)
+
+// ErrUnsupportedCharacters is returned when the notes hold characters the
+// fonts of the PDF documents lack, such as emoji or CJK
+var ErrUnsupportedCharacters = errors.New("characters missing from the PDF fonts")
+
+// pdfHeadingSizes gives the font size of each heading level
+var pdfHeadingSizes = [...]float64{0, 20, 16, 13.5, 12, 11, 11}
+
+// pdfRun is a piece of text drawn with a single font
+type pdfRun struct {
+	Text string
+	Font pdf.Font
+}
+
+// pdfLayout places the notes on the pages of a document. Positions are
+// measured from the top of the page and converted when drawing.
+type pdfLayout struct {
+	doc    *pdf.Document
+	page   *pdf.Page
+	y      float64 // Top of the next line
+	source []byte  // Markdown of the note being laid out
+	marker string  // List marker drawn before the next line
+	gray   float64 // Color of the text, kept across pages
+}
+
+// ExportPDF writes the given notes to a PDF document, each note starting on a
+// new page. Several notes are preceded by a table of contents. Rather than
+// dropping the characters the fonts lack, it fails without writing anything.
+func ExportPDF(manager *notes.NotesManager, selection []*notes.Note, path string, opts BookOptions) error {
+	if opts.SkipEmpty {
+		selection = NonEmpty(selection)
+	}
+	if len(selection) == 0 {
+		return errors.New("no notes to export")
+	}
+	ordered, err := orderBook(selection, opts.Order)
+	if err != nil {
+		return err
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = "Notes"
+	}
+	if len(ordered) == 1 && opts.Title == "" {
+		title = ordered[0].Title
+	}
+
+	l := &pdfLayout{doc: pdf.New(pdf.A4Width, pdf.A4Height)}
+	l.doc.Title = title
+	md := markdown.New(opts.Extensions)
+
+	starts := make([]int, len(ordered))
+	for i, note := range ordered {
+		l.newPage()
+		starts[i] = l.doc.PageCount()
+		if err := l.note(md, manager, note); err != nil {
+			return err
+		}
+	}
+	if len(ordered) > 1 {
+		l.contents(title, ordered, starts)
+	}
+	if chars := l.doc.Unsupported(); len(chars) > 0 {
+		return fmt.Errorf("%w: %s, export to HTML instead", ErrUnsupportedCharacters, quoteChars(chars))
+	}
+
+	var buf bytes.Buffer
+	if _, err := l.doc.WriteTo(&buf); err != nil {
+		return fmt.Errorf("error rendering PDF: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error writing PDF: %w", err)
+	}
+	return nil
+}
+
+// quoteChars lists characters for an error message, the first few of them
+// when there are many
+func quoteChars(chars []rune) string {
+	const shown = 10
+	quoted := []string{}
+	for _, r := range chars[:min(len(chars), shown)] {
+		quoted = append(quoted, strconv.QuoteRune(r))
+	}
+	if len(chars) > shown {
+		quoted = append(quoted, fmt.Sprintf("and %d more", len(chars)-shown))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// contents inserts the table of contents before the notes, starts holding
+// the page each note begins on
+func (l *pdfLayout) contents(title string, ordered []*notes.Note, starts []int) {
+	lineHeight := pdfBodySize * pdfLineHeight
+	perPage := int((pdf.A4Height - 2*pdfMargin - 80) / lineHeight)
+	pages := (len(ordered) + perPage - 1) / perPage
+
+	for p := 0; p < pages; p++ {
+		l.page = l.doc.InsertPage(p)
+		l.y = pdfMargin
+		if p == 0 {
+			l.text([]pdfRun{{title, pdf.HelveticaBold}}, 0, pdfHeadingSizes[1])
+			l.y += pdfBodySize
+		}
+		end := min((p+1)*perPage, len(ordered))
+		for i := p * perPage; i < end; i++ {
+			number := fmt.Sprint(starts[i] + pages)
+			width := pdf.Helvetica.Width(number, pdfBodySize)
+			l.text([]pdfRun{{ordered[i].Title, pdf.Helvetica}}, 0, pdfBodySize)
+			l.page.Text(pdf.A4Width-pdfMargin-width, l.baseline(l.y-lineHeight, pdfBodySize), pdf.Helvetica, pdfBodySize, number)
+		}
+	}
+}
+
+// note lays out the title, content and images of a note
+func (l *pdfLayout) note(md goldmark.Markdown, manager *notes.NotesManager, note *notes.Note) error {
+	l.text([]pdfRun{{note.Title, pdf.HelveticaBold}}, 0, 22)
+
+	meta := "Updated " + note.UpdatedAt.Format("02/01/2006 15:04")
+	if len(note.Tags) > 0 {
+		meta += "   " + strings.Join(note.Tags, ", ")
+	}
+	l.setGray(0.4)
+	l.text([]pdfRun{{meta, pdf.Helvetica}}, 0, pdfSmallSize)
+	l.setGray(0)
+	l.y += pdfBodySize
+
+	if note.IsEmpty() {
+		l.setGray(0.5)
+		l.text([]pdfRun{{"This note is empty.", pdf.HelveticaOblique}}, 0, pdfBodySize)
+		l.setGray(0)
+		return nil
+	}
+
+	// Wikilinks keep their label, there is nothing to link to
+	l.source = []byte(notes.ReplaceWikiLinks(note.Content, func(link notes.WikiLink) string {
+		return link.Label
+	}))
+	doc := md.Parser().Parse(text.NewReader(l.source))
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		l.block(child, 0)
+	}
+
+	for _, img := range note.Images {
+		if !manager.ImageExists(img.Path) {
+			continue
+		}
+		if err := l.image(manager.GetImageFullPath(img.Path), img.Caption); err != nil {
+			return fmt.Errorf("error adding image to %q: %w", note.Title, err)
+		}
+	}
+	return nil
+}
+
+// newPage starts a new page
+func (l *pdfLayout) newPage() {
+	l.page = l.doc.AddPage()
+	l.page.SetFillGray(l.gray)
+	l.y = pdfMargin
+}
+
+// setGray changes the color of the text that follows
+func (l *pdfLayout) setGray(gray float64) {
+	l.gray = gray
+	l.page.SetFillGray(gray)
+}
+
+// ensure starts a new page unless height points fit on the current one
+func (l *pdfLayout) ensure(height float64) {
+	if l.y+height > pdf.A4Height-pdfMargin && l.y > pdfMargin {
+		l.newPage()
+	}
+}
+
+// baseline converts the bottom of a line to the PDF baseline of its text
+func (l *pdfLayout) baseline(bottom, size float64) float64 {
+	return pdf.A4Height - bottom + size*(pdfLineHeight-1)/2 + size*0.2
+}
+
+// contentWidth returns the width available at the given indentation
+func contentWidth(indent float64) float64 {
+	return pdf.A4Width - 2*pdfMargin - indent
+}
+
+// block lays out a block element of the Markdown tree
+func (l *pdfLayout) block(n ast.Node, indent float64) {
+	switch n := n.(type) {
+	case *ast.Heading:
+		level := min(n.Level, len(pdfHeadingSizes)-1)
+		l.y += pdfHeadingSizes[level] * 0.6
+		l.ensure(pdfHeadingSizes[level] * 3)
+		l.text(l.inline(n, pdf.HelveticaBold), indent, pdfHeadingSizes[level])
+		l.y += pdfBodySize * 0.4
+
+	case *ast.Paragraph:
+		l.text(l.inline(n, pdf.Helvetica), indent, pdfBodySize)
+		l.y += pdfBodySize * 0.6
+
+	case *ast.TextBlock:
+		l.text(l.inline(n, pdf.Helvetica), indent, pdfBodySize)
+
+	case *ast.List:
+		number := n.Start
+		for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+			l.marker = "•"
+			if n.IsOrdered() {
+				l.marker = fmt.Sprintf("%d.", number)
+				number++
+			}
+			for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+				l.block(child, indent+pdfIndent)
+			}
+			l.marker = ""
+		}
+		l.y += pdfBodySize * 0.4
+
+	case *ast.Blockquote:
+		gray := l.gray
+		l.setGray(0.35)
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			l.block(child, indent+pdfIndent)
+		}
+		l.setGray(gray)
+
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		l.code(n, indent)
+
+	case *ast.ThematicBreak:
+		l.ensure(pdfBodySize)
+		l.y += pdfBodySize / 2
+		l.page.SetStrokeGray(0.6)
+		l.page.Line(pdfMargin+indent, pdf.A4Height-l.y, pdf.A4Width-pdfMargin, pdf.A4Height-l.y, 0.5)
+		l.page.SetStrokeGray(0)
+		l.y += pdfBodySize
+
+	case *ast.HTMLBlock:
+		// Raw HTML has no equivalent
+
+	case *extast.Table:
+		// Cells are separated rather than aligned in columns
+		for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+			font := pdf.Helvetica
+			if _, ok := row.(*extast.TableHeader); ok {
+				font = pdf.HelveticaBold
+			}
+			runs := []pdfRun{}
+			for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+				if cell != row.FirstChild() {
+					runs = append(runs, pdfRun{" | ", font})
+				}
+				runs = append(runs, l.inline(cell, font)...)
+			}
+			l.text(runs, indent, pdfBodySize)
+		}
+		l.y += pdfBodySize * 0.6
+
+	case *extast.DefinitionTerm:
+		l.text(l.inline(n, pdf.HelveticaBold), indent, pdfBodySize)
+
+	case *extast.DefinitionDescription:
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			l.block(child, indent+pdfIndent)
+		}
+
+	case *extast.Footnote:
+		l.marker = fmt.Sprintf("%d.", n.Index)
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			l.block(child, indent+pdfIndent)
+		}
+		l.marker = ""
+
+	default:
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			l.block(child, indent)
+		}
+	}
+}
+
+// inline collects the text of the inline children of n, font giving the
+// style of plain text
+func (l *pdfLayout) inline(n ast.Node, font pdf.Font) []pdfRun {
+	runs := []pdfRun{}
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		switch child := child.(type) {
+		case *ast.Text:
+			runs = append(runs, pdfRun{string(child.Segment.Value(l.source)), font})
+			if child.HardLineBreak() {
+				runs = append(runs, pdfRun{"\n", font})
+			} else if child.SoftLineBreak() {
+				runs = append(runs, pdfRun{" ", font})
+			}
+		case *ast.String:
+			runs = append(runs, pdfRun{string(child.Value), font})
+		case *ast.CodeSpan:
+			runs = append(runs, l.inline(child, pdf.Courier)...)
+		case *ast.Emphasis:
+			runs = append(runs, l.inline(child, emphasize(font, child.Level))...)
+		case *ast.AutoLink:
+			runs = append(runs, pdfRun{string(child.URL(l.source)), font})
+		case *ast.Image:
+			runs = append(runs, pdfRun{"[" + string(child.Text(l.source)) + "]", font})
+		case *ast.RawHTML:
+			// Inline HTML tags are dropped, the text around them is kept
+		case *extast.TaskCheckBox:
+			box := "[ ] "
+			if child.IsChecked {
+				box = "[x] "
+			}
+			runs = append(runs, pdfRun{box, font})
+		case *extast.FootnoteLink:
+			runs = append(runs, pdfRun{fmt.Sprintf("[%d]", child.Index), font})
+		default:
+			runs = append(runs, l.inline(child, font)...)
+		}
+	}
+	return runs
+}
+
+// emphasize returns the italic (level 1) or bold (level 2) variant of font
+func emphasize(font pdf.Font, level int) pdf.Font {
+	bold := font == pdf.HelveticaBold || font == pdf.HelveticaBoldOblique
+	italic := font == pdf.HelveticaOblique || font == pdf.HelveticaBoldOblique
+	switch {
+	case font == pdf.Courier:
+		return font
+	case level >= 2:
+		bold = true
+	default:
+		italic = true
+	}
+	switch {
+	case bold && italic:
+		return pdf.HelveticaBoldOblique
+	case bold:
+		return pdf.HelveticaBold
+	case italic:
+		return pdf.HelveticaOblique
+	}
+	return pdf.Helvetica
+}
+
+// pdfWord is a word with the space preceding it
+type pdfWord struct {
+	pdfRun
+	space bool
+}
+
+// text wraps runs to the available width and draws them line by line
+func (l *pdfLayout) text(runs []pdfRun, indent, size float64) {
+	width := contentWidth(indent)
+	lineHeight := size * pdfLineHeight
+
+	var line []pdfWord
+	flush := func() {
+		l.ensure(lineHeight)
+		l.y += lineHeight
+		baseline := l.baseline(l.y, size)
+		if l.marker != "" {
+			l.page.Text(pdfMargin+indent-pdfIndent, baseline, pdf.Helvetica, size, l.marker)
+			l.marker = ""
+		}
+		// Words are drawn together until the font changes, keeping the
+		// spaces for readers that copy the text
+		x := pdfMargin + indent
+		var segment strings.Builder
+		for i, word := range line {
+			if word.space && i > 0 {
+				segment.WriteString(" ")
+			}
+			segment.WriteString(word.Text)
+			if i == len(line)-1 || line[i+1].Font != word.Font {
+				l.page.Text(x, baseline, word.Font, size, segment.String())
+				x += word.Font.Width(segment.String(), size)
+				segment.Reset()
+			}
+		}
+		line = nil
+	}
+
+	used := 0.0
+	space := false
+	for _, run := range runs {
+		if run.Text == "\n" {
+			flush()
+			used, space = 0, false
+			continue
+		}
+		words := strings.Split(run.Text, " ")
+		for i, text := range words {
+			if i > 0 {
+				space = true
+			}
+			if text == "" {
+				continue
+			}
+			wordWidth := run.Font.Width(text, size)
+			spaceWidth := 0.0
+			if space && len(line) > 0 {
+				spaceWidth = run.Font.Width(" ", size)
+			}
+			if len(line) > 0 && used+spaceWidth+wordWidth > width {
+				flush()
+				used, spaceWidth = 0, 0
+			}
+			// Words longer than a line are cut
+			for wordWidth > width && len(line) == 0 {
+				cut := fitting(text, run.Font, size, width)
+				line = append(line, pdfWord{pdfRun{text[:cut], run.Font}, false})
+				flush()
+				text = text[cut:]
+				wordWidth = run.Font.Width(text, size)
+			}
+			line = append(line, pdfWord{pdfRun{text, run.Font}, space})
+			used += spaceWidth + wordWidth
+			space = false
+		}
+	}
+	if len(line) > 0 || l.marker != "" {
+		flush()
+	}
+}
+
+// fitting returns the length in bytes of the longest prefix of s narrower
+// than width, at least one character
+func fitting(s string, font pdf.Font, size, width float64) int {
+	end := 0
+	for i, r := range s {
+		next := i + len(string(r))
+		if font.Width(s[:next], size) > width && end > 0 {
+			break
+		}
+		end = next
+	}
+	return end
+}
+
+// code draws a code block on a gray background, long lines being wrapped
+func (l *pdfLayout) code(n ast.Node, indent float64) {
+	lineHeight := pdfCodeSize * pdfLineHeight
+	width := contentWidth(indent)
+	columns := max(int((width-8)/pdf.Courier.Width(" ", pdfCodeSize)), 1)
+
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		line := strings.TrimRight(string(segment.Value(l.source)), "\n")
+		line = strings.ReplaceAll(line, "\t", "    ")
+		chars := []rune(line)
+		for {
+			part := chars[:min(columns, len(chars))]
+			l.ensure(lineHeight)
+			l.page.SetFillGray(0.93)
+			l.page.Rect(pdfMargin+indent, pdf.A4Height-l.y-lineHeight, width, lineHeight)
+			l.page.SetFillGray(l.gray)
+			l.y += lineHeight
+			l.page.Text(pdfMargin+indent+4, l.baseline(l.y, pdfCodeSize), pdf.Courier, pdfCodeSize, string(part))
+			chars = chars[len(part):]
+			if len(chars) == 0 {
+				break
+			}
+		}
+	}
+	l.y += pdfBodySize * 0.6
+}
+
+// image draws an image file scaled to fit the page, with its caption below
+func (l *pdfLayout) image(path, caption string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	img, err := l.doc.AddImage(data)
+	if err != nil {
+		return err
+	}
+
+	// Pixels are drawn at 96 dpi unless the image doesn't fit
+	maxWidth := contentWidth(0)
+	maxHeight := pdf.A4Height - 2*pdfMargin - 3*pdfSmallSize
+	scale := min(0.75, maxWidth/float64(img.Width), maxHeight/float64(img.Height))
+	width, height := float64(img.Width)*scale, float64(img.Height)*scale
+
+	l.ensure(height + 2*pdfSmallSize)
+	l.y += height
+	l.page.Image(img, pdfMargin, pdf.A4Height-l.y, width, height)
+	l.y += pdfSmallSize / 2
+	if caption != "" {
+		l.setGray(0.4)
+		l.text([]pdfRun{{caption, pdf.HelveticaOblique}}, 0, pdfSmallSize)
+		l.setGray(0)
+	}
+	l.y += pdfBodySize
+	return nil
+}
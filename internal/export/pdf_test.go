@@ -0,0 +1,107 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"datapad/internal/config"
+	"datapad/internal/notes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportPDF(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	note := manager.CreateNote("Café")
+	note.Content = "# Menu\n\n- “Crème brûlée” – 5 €\n"
+	path := filepath.Join(t.TempDir(), "menu.pdf")
+	if err := ExportPDF(manager, []*notes.Note{note}, path, BookOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "%PDF-") {
+		t.Errorf("%s isn't a PDF file", path)
+	}
+}
+
+func TestExportPDFUnsupportedCharacters(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	note := manager.CreateNote("Trip")
+	note.Content = "Flight to 東京 ✈"
+	path := filepath.Join(t.TempDir(), "trip.pdf")
+	err := ExportPDF(manager, []*notes.Note{note}, path, BookOptions{})
+	if !errors.Is(err, ErrUnsupportedCharacters) {
+		t.Fatalf("ExportPDF = %v, want ErrUnsupportedCharacters", err)
+	}
+	for _, char := range []string{"'東'", "'京'", "'✈'"} {
+		if !strings.Contains(err.Error(), char) {
+			t.Errorf("error %q doesn't name %s", err, char)
+		}
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("%s written despite the error", path)
+	}
+}
+
+func TestQuoteChars(t *testing.T) {
+	if got, want := quoteChars([]rune("ab")), "'a' 'b'"; got != want {
+		t.Errorf("quoteChars = %q, want %q", got, want)
+	}
+	if got, want := quoteChars([]rune("abcdefghijkl")), "'a' 'b' 'c' 'd' 'e' 'f' 'g' 'h' 'i' 'j' and 2 more"; got != want {
+		t.Errorf("quoteChars = %q, want %q", got, want)
+	}
+}
+
+// pdfText returns the content streams of a PDF file, decompressed
+func pdfText(t *testing.T, data []byte) string {
+	t.Helper()
+	var text strings.Builder
+	for {
+		start := bytes.Index(data, []byte("stream\n"))
+		if start < 0 {
+			return text.String()
+		}
+		data = data[start+len("stream\n"):]
+		end := bytes.Index(data, []byte("\nendstream"))
+		if end < 0 {
+			t.Fatal("unterminated stream")
+		}
+		if r, err := zlib.NewReader(bytes.NewReader(data[:end])); err == nil {
+			content, _ := io.ReadAll(r)
+			text.Write(content)
+		} else {
+			text.Write(data[:end])
+		}
+		data = data[end+len("\nendstream"):]
+	}
+}
+
+func TestExportPDFExtensions(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	note := manager.CreateNote("Extended")
+	note.Content = extendedMarkdown
+	path := filepath.Join(t.TempDir(), "extended.pdf")
+	opts := BookOptions{Extensions: []string{config.ExtensionGFM, config.ExtensionFootnote}}
+	if err := ExportPDF(manager, []*notes.Note{note}, path, opts); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := pdfText(t, data)
+	for _, want := range []string{"(a | b)", "(1 | 2)", "([x] done)", "(old and a note[1])", "(1.)", "(Footnote text)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("%s missing from the PDF:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "|---|") {
+		t.Error("table drawn as text")
+	}
+}
@@ -0,0 +1,517 @@
+package export
+
+import (
+	"bytes"
+	"datapad/internal/markdown"
+	"datapad/internal/notes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+)
+
+//go:embed templates/*
+var embeddedTemplates embed.FS
+
+// SiteOptions configures the static site export
+type SiteOptions struct {
+	OutputDir   string // Directory receiving the generated site
+	TemplateDir string // Optional directory whose files override the embedded templates
+	SkipEmpty   bool   // Leave out the notes with neither text nor images instead of marking them empty
+
+	// Markdown extensions enabled, among config.MarkdownExtensions
+	Extensions []string
+}
+
+// SiteReport summarizes a static site export
+type SiteReport struct {
+	Written int // Note pages generated
+	Skipped int // Note pages already up to date
+	Removed int // Pages of notes no longer exported, deleted
+	Images  int // Images copied into the assets folder
+	Empty   int // Empty notes left out with SkipEmpty
+}
+
+// sitePage holds the data given to the note template
+type sitePage struct {
+	Title   string
+	Content template.HTML
+	Tags    []string
+	Images  []siteImage
+	Created time.Time
+	Updated time.Time
+}
+
+// siteImage is an image attached to a note page
+type siteImage struct {
+	Src     template.URL // Built by the exporter, may be a data URI
+	Alt     string
+	Caption string
+	Width   int // In pixels, zero when unknown
+	Height  int
+}
+
+// siteLink is a link to a note page from the index
+type siteLink struct {
+	Title string
+	URL   string
+}
+
+// siteGroup lists the notes sharing a tag on the index page
+type siteGroup struct {
+	Tag   string
+	Notes []siteLink
+}
+
+// searchEntry is an entry of the client-side search index
+type searchEntry struct {
+	Title string   `json:"title"`
+	URL   string   `json:"url"`
+	Tags  []string `json:"tags"`
+	Text  string   `json:"text"`
+}
+
+// ExportSite generates a browsable static site from the notes: an index
+// grouped by tag, one page per note, a JSON search index and a manifest of
+// the images in JSON and Markdown. Note pages generated from the same
+// version of their note and of the pages it links to are left untouched, and
+// the pages of notes no longer exported are removed.
+func ExportSite(manager *notes.NotesManager, opts SiteOptions) (SiteReport, error) {
+	var report SiteReport
+
+	templates, err := loadTemplates(opts.TemplateDir)
+	if err != nil {
+		return report, err
+	}
+
+	notesDir := filepath.Join(opts.OutputDir, "notes")
+	assetsDir := filepath.Join(opts.OutputDir, "assets")
+	for _, dir := range []string{notesDir, assetsDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return report, fmt.Errorf("unable to create export directory: %w", err)
+		}
+	}
+
+	published := publishedNotes(manager)
+	if opts.SkipEmpty {
+		kept := NonEmpty(published)
+		report.Empty = len(published) - len(kept)
+		published = kept
+	}
+	pages := pageNames(published)
+	md := markdown.New(opts.Extensions)
+
+	previous := readPageStates(opts.OutputDir)
+	states := make(map[string]pageState, len(published))
+	for _, note := range published {
+		pagePath := filepath.Join(notesDir, pages[note.ID])
+		state := currentPageState(manager, note, pages)
+		states[pages[note.ID]] = state
+
+		copied, err := copyImages(manager, note, assetsDir)
+		if err != nil {
+			return report, err
+		}
+		report.Images += copied
+
+		if old, ok := previous[pages[note.ID]]; ok && old.equal(state) && upToDate(pagePath, templates.modTime) {
+			report.Skipped++
+			continue
+		}
+
+		page, err := buildPage(md, manager, note, func(target *notes.Note) string {
+			return pages[target.ID]
+		}, func(img notes.Image) (string, error) {
+			return "../assets/" + img.Path, nil
+		})
+		if err != nil {
+			return report, err
+		}
+
+		var buf bytes.Buffer
+		if err := templates.note.Execute(&buf, page); err != nil {
+			return report, fmt.Errorf("error rendering page for %q: %w", note.Title, err)
+		}
+		if err := os.WriteFile(pagePath, buf.Bytes(), 0644); err != nil {
+			return report, fmt.Errorf("error writing page for %q: %w", note.Title, err)
+		}
+		report.Written++
+	}
+
+	removed, err := removeStalePages(notesDir, previous, states)
+	report.Removed = removed
+	if err != nil {
+		return report, err
+	}
+	if err := writePageStates(opts.OutputDir, states); err != nil {
+		return report, err
+	}
+	if err := writeIndex(opts.OutputDir, templates, published, pages); err != nil {
+		return report, err
+	}
+	if err := writeSearchIndex(opts.OutputDir, published, pages); err != nil {
+		return report, err
+	}
+	if err := writeSiteManifest(manager, opts.OutputDir, published, pages); err != nil {
+		return report, err
+	}
+	return report, writeStaticFiles(opts.OutputDir, templates)
+}
+
+// siteTemplates holds the parsed templates and static files of the site
+type siteTemplates struct {
+	note    *template.Template
+	index   *template.Template
+	static  map[string][]byte // Files copied as is (stylesheet, script)
+	modTime time.Time         // Most recent modification of an override, zero if none
+}
+
+// loadTemplates parses the embedded templates, replacing each one found in
+// overrideDir by the user's version
+func loadTemplates(overrideDir string) (siteTemplates, error) {
+	t := siteTemplates{static: map[string][]byte{}}
+
+	read := func(name string) ([]byte, error) {
+		if overrideDir != "" {
+			path := filepath.Join(overrideDir, name)
+			if info, err := os.Stat(path); err == nil {
+				if info.ModTime().After(t.modTime) {
+					t.modTime = info.ModTime()
+				}
+				return os.ReadFile(path)
+			}
+		}
+		return fs.ReadFile(embeddedTemplates, "templates/"+name)
+	}
+
+	for _, name := range []string{"note.html", "index.html"} {
+		data, err := read(name)
+		if err != nil {
+			return t, fmt.Errorf("error reading template %s: %w", name, err)
+		}
+		tmpl, err := template.New(name).Parse(string(data))
+		if err != nil {
+			return t, fmt.Errorf("error parsing template %s: %w", name, err)
+		}
+		if name == "note.html" {
+			t.note = tmpl
+		} else {
+			t.index = tmpl
+		}
+	}
+
+	for _, name := range []string{"style.css", "search.js"} {
+		data, err := read(name)
+		if err != nil {
+			return t, fmt.Errorf("error reading template %s: %w", name, err)
+		}
+		t.static[name] = data
+	}
+	return t, nil
+}
+
+// publishedNotes returns the notes included in exports, archived notes
+// excepted, in a stable order
+func publishedNotes(manager *notes.NotesManager) []*notes.Note {
+	published := []*notes.Note{}
+	for _, note := range manager.Notes {
+		if !note.Archived {
+			published = append(published, note)
+		}
+	}
+	sort.SliceStable(published, func(i, j int) bool {
+		if !published[i].CreatedAt.Equal(published[j].CreatedAt) {
+			return published[i].CreatedAt.Before(published[j].CreatedAt)
+		}
+		return published[i].ID < published[j].ID
+	})
+	return published
+}
+
+// NonEmpty returns the notes that have text or images, in the same order
+func NonEmpty(ns []*notes.Note) []*notes.Note {
+	kept := []*notes.Note{}
+	for _, note := range ns {
+		if !note.IsEmpty() {
+			kept = append(kept, note)
+		}
+	}
+	return kept
+}
+
+// pageNames assigns a unique file name to every note, derived from its title
+func pageNames(ns []*notes.Note) map[string]string {
+	pages := make(map[string]string, len(ns))
+	// The index page is written next to the notes
+	used := map[string]bool{"index": true}
+	for _, note := range ns {
+		pages[note.ID] = notes.UniqueSlug(note.Title, used) + ".html"
+	}
+	return pages
+}
+
+// pageStatesFile records the note each page was generated from, read back by
+// the next export to find the pages to rewrite and the ones to remove
+const pageStatesFile = ".pages.json"
+
+// pageState is what a note page was generated from
+type pageState struct {
+	ID      string            `json:"id"`
+	Updated time.Time         `json:"updated"`
+	Links   map[string]string `json:"links,omitempty"` // Page of each wikilink target, "" when it has none
+}
+
+// equal reports whether two pages were generated from the same note version
+// with the same links
+func (s pageState) equal(other pageState) bool {
+	return s.ID == other.ID && s.Updated.Equal(other.Updated) && maps.Equal(s.Links, other.Links)
+}
+
+// currentPageState returns what the page of a note is generated from. The
+// page names of the link targets are recorded since they change when a note
+// is renamed, deleted or created, without the linking note being updated.
+func currentPageState(manager *notes.NotesManager, note *notes.Note, pages map[string]string) pageState {
+	state := pageState{ID: note.ID, Updated: note.UpdatedAt}
+	for _, link := range notes.WikiLinks(note.Content) {
+		if state.Links == nil {
+			state.Links = map[string]string{}
+		}
+		state.Links[link.Target] = ""
+		if target, err := manager.FindByTitle(link.Target); err == nil {
+			state.Links[link.Target] = pages[target.ID]
+		}
+	}
+	return state
+}
+
+// readPageStates returns the pages of the previous export. A missing or
+// unreadable record only makes every page be rewritten.
+func readPageStates(outputDir string) map[string]pageState {
+	states := map[string]pageState{}
+	data, err := os.ReadFile(filepath.Join(outputDir, pageStatesFile))
+	if err != nil {
+		return states
+	}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return map[string]pageState{}
+	}
+	return states
+}
+
+// writePageStates records the pages of this export
+func writePageStates(outputDir string, states map[string]pageState) error {
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing page list: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, pageStatesFile), data, 0644); err != nil {
+		return fmt.Errorf("error writing page list: %w", err)
+	}
+	return nil
+}
+
+// removeStalePages deletes the pages the previous export generated that no
+// note maps to anymore. Other files of the folder are left alone.
+func removeStalePages(notesDir string, previous, current map[string]pageState) (int, error) {
+	removed := 0
+	for name := range previous {
+		if _, ok := current[name]; ok || name != filepath.Base(name) || !strings.HasSuffix(name, ".html") {
+			continue
+		}
+		err := os.Remove(filepath.Join(notesDir, name))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, fmt.Errorf("error removing page %s: %w", name, err)
+		}
+		if err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// upToDate reports whether a generated file exists and is newer than the
+// templates used to render it
+func upToDate(path string, templatesModTime time.Time) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.ModTime().After(templatesModTime)
+}
+
+// emptyContent replaces the content of notes with neither text nor images
+const emptyContent = `<p class="empty"><em>This note is empty.</em></p>`
+
+// buildPage renders the Markdown of a note. Wikilinks become links to the
+// URL href returns for their target, or plain text when it returns "", and
+// src gives the URL of each image. Empty notes say so.
+func buildPage(md goldmark.Markdown, manager *notes.NotesManager, note *notes.Note, href func(target *notes.Note) string, src func(img notes.Image) (string, error)) (sitePage, error) {
+	content := notes.ReplaceWikiLinks(note.Content, func(link notes.WikiLink) string {
+		target, err := manager.FindByTitle(link.Target)
+		if err != nil {
+			return link.Label
+		}
+		url := href(target)
+		if url == "" {
+			return link.Label
+		}
+		return fmt.Sprintf("[%s](%s)", link.Label, url)
+	})
+
+	var html bytes.Buffer
+	if err := md.Convert([]byte(content), &html); err != nil {
+		return sitePage{}, fmt.Errorf("error rendering %q: %w", note.Title, err)
+	}
+
+	page := sitePage{
+		Title:   note.Title,
+		Content: template.HTML(html.String()),
+		Tags:    note.Tags,
+		Created: note.CreatedAt,
+		Updated: note.UpdatedAt,
+	}
+	if note.IsEmpty() {
+		page.Content = emptyContent
+	}
+	for _, img := range note.Images {
+		if !manager.ImageExists(img.Path) {
+			continue
+		}
+		url, err := src(img)
+		if err != nil {
+			return page, err
+		}
+		page.Images = append(page.Images, siteImage{
+			Src:     template.URL(url),
+			Alt:     img.AltText,
+			Caption: img.Caption,
+			Width:   img.Width,
+			Height:  img.Height,
+		})
+	}
+	return page, nil
+}
+
+// copyImages copies the images of a note that are missing from the assets folder
+func copyImages(manager *notes.NotesManager, note *notes.Note, assetsDir string) (int, error) {
+	copied := 0
+	for _, img := range note.Images {
+		if !manager.ImageExists(img.Path) {
+			continue
+		}
+		dest := filepath.Join(assetsDir, img.Path)
+		// Images are content-addressed, an existing file is always identical
+		if _, err := os.Stat(dest); err == nil {
+			continue
+		}
+		data, err := os.ReadFile(manager.GetImageFullPath(img.Path))
+		if err != nil {
+			return copied, fmt.Errorf("error reading image %s: %w", img.Path, err)
+		}
+		if err := os.WriteFile(dest, data, 0644); err != nil {
+			return copied, fmt.Errorf("error copying image %s: %w", img.Path, err)
+		}
+		copied++
+	}
+	return copied, nil
+}
+
+// writeIndex generates the index page listing the notes grouped by tag
+func writeIndex(outputDir string, templates siteTemplates, published []*notes.Note, pages map[string]string) error {
+	byTag := map[string][]siteLink{}
+	untagged := []siteLink{}
+	for _, note := range published {
+		link := siteLink{Title: note.Title, URL: "notes/" + pages[note.ID]}
+		if len(note.Tags) == 0 {
+			untagged = append(untagged, link)
+		}
+		for _, tag := range note.Tags {
+			byTag[tag] = append(byTag[tag], link)
+		}
+	}
+
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	groups := []siteGroup{}
+	for _, tag := range tags {
+		groups = append(groups, siteGroup{Tag: tag, Notes: byTag[tag]})
+	}
+	if len(untagged) > 0 {
+		groups = append(groups, siteGroup{Tag: "Untagged", Notes: untagged})
+	}
+
+	var buf bytes.Buffer
+	if err := templates.index.Execute(&buf, struct{ Groups []siteGroup }{groups}); err != nil {
+		return fmt.Errorf("error rendering index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "index.html"), buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error writing index: %w", err)
+	}
+	return nil
+}
+
+// writeSearchIndex generates the JSON index filtered by the search script
+func writeSearchIndex(outputDir string, published []*notes.Note, pages map[string]string) error {
+	entries := make([]searchEntry, 0, len(published))
+	for _, note := range published {
+		tags := note.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		entries = append(entries, searchEntry{
+			Title: note.Title,
+			URL:   "notes/" + pages[note.ID],
+			Tags:  tags,
+			Text:  strings.Join(strings.Fields(note.Content), " "),
+		})
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("error serializing search index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "search.json"), data, 0644); err != nil {
+		return fmt.Errorf("error writing search index: %w", err)
+	}
+	return nil
+}
+
+// writeSiteManifest writes the manifest of the copied images as
+// attachments.json and attachments.md
+func writeSiteManifest(manager *notes.NotesManager, outputDir string, published []*notes.Note, pages map[string]string) error {
+	entries := Manifest(manager, published, func(img notes.Image) string {
+		return "assets/" + img.Path
+	}, func(note *notes.Note) string {
+		return "notes/" + pages[note.ID]
+	})
+	for _, name := range []string{"attachments.json", "attachments.md"} {
+		if err := WriteManifest(entries, filepath.Join(outputDir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeStaticFiles writes the stylesheet and the search script
+func writeStaticFiles(outputDir string, templates siteTemplates) error {
+	for name, data := range templates.static {
+		if err := os.WriteFile(filepath.Join(outputDir, name), data, 0644); err != nil {
+			return fmt.Errorf("error writing %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package export
+
+import (
+	"datapad/internal/notes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// siteNote creates a note, created minutes after the start of the day so
+// that the export gives the pages of notes with the same title in this order
+func siteNote(manager *notes.NotesManager, title, content string, minutes int) *notes.Note {
+	note := manager.CreateNote(title)
+	note.Content = content
+	note.CreatedAt = time.Date(2024, 1, 1, 0, minutes, 0, 0, time.UTC)
+	return note
+}
+
+// exportSite exports the site of manager to dir and fails the test on error
+func exportSite(t *testing.T, manager *notes.NotesManager, dir string) SiteReport {
+	t.Helper()
+	report, err := ExportSite(manager, SiteOptions{OutputDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+// readPage returns the page generated for a note, "" when there is none
+func readPage(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "notes", name))
+	if errors.Is(err, os.ErrNotExist) {
+		return ""
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestExportSiteSkipsUnchangedPages(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	siteNote(manager, "Home", "See [[Plan]]", 0)
+	siteNote(manager, "Plan", "Steps", 1)
+	dir := t.TempDir()
+
+	if report := exportSite(t, manager, dir); report.Written != 2 || report.Skipped != 0 {
+		t.Errorf("first export wrote %d and skipped %d page(s), want 2 and 0", report.Written, report.Skipped)
+	}
+	if report := exportSite(t, manager, dir); report.Written != 0 || report.Skipped != 2 || report.Removed != 0 {
+		t.Errorf("second export wrote %d, skipped %d and removed %d page(s), want 0, 2 and 0", report.Written, report.Skipped, report.Removed)
+	}
+}
+
+func TestExportSiteRenamedPage(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	first := siteNote(manager, "Foo", "First foo", 0)
+	siteNote(manager, "Foo", "Second foo", 1)
+	dir := t.TempDir()
+	exportSite(t, manager, dir)
+	if page := readPage(t, dir, "foo-2.html"); !strings.Contains(page, "Second foo") {
+		t.Fatalf("foo-2.html doesn't hold the second note:\n%s", page)
+	}
+
+	// The second note takes foo.html, whose file is newer than the note
+	if err := manager.DeleteNote(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	report := exportSite(t, manager, dir)
+	if page := readPage(t, dir, "foo.html"); !strings.Contains(page, "Second foo") || strings.Contains(page, "First foo") {
+		t.Errorf("foo.html still holds the deleted note:\n%s", page)
+	}
+	if readPage(t, dir, "foo-2.html") != "" {
+		t.Error("page of no note left in the site")
+	}
+	if report.Written != 1 || report.Removed != 1 {
+		t.Errorf("export wrote %d and removed %d page(s), want 1 and 1", report.Written, report.Removed)
+	}
+}
+
+func TestExportSiteLinkTargets(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	siteNote(manager, "Home", "See [[Plan]]", 0)
+	dir := t.TempDir()
+	exportSite(t, manager, dir)
+	if page := readPage(t, dir, "home.html"); strings.Contains(page, "plan.html") {
+		t.Fatalf("link to a missing note:\n%s", page)
+	}
+
+	// Creating the target of a dangling link gives the link its page
+	plan := siteNote(manager, "Plan", "Steps", 1)
+	exportSite(t, manager, dir)
+	if page := readPage(t, dir, "home.html"); !strings.Contains(page, `href="plan.html"`) {
+		t.Errorf("link to the created note missing:\n%s", page)
+	}
+
+	// Renaming the target leaves the link without a page again
+	plan.Title = "Roadmap"
+	if err := manager.UpdateNote(plan); err != nil {
+		t.Fatal(err)
+	}
+	exportSite(t, manager, dir)
+	if page := readPage(t, dir, "home.html"); strings.Contains(page, "plan.html") {
+		t.Errorf("link to the renamed note left:\n%s", page)
+	}
+	if readPage(t, dir, "plan.html") != "" || readPage(t, dir, "roadmap.html") == "" {
+		t.Error("page of the renamed note not moved")
+	}
+}
+
+func TestExportSiteKeepsOtherFiles(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	note := siteNote(manager, "Foo", "Text", 0)
+	dir := t.TempDir()
+	exportSite(t, manager, dir)
+	other := filepath.Join(dir, "notes", "about.html")
+	if err := os.WriteFile(other, []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.DeleteNote(note.ID); err != nil {
+		t.Fatal(err)
+	}
+	exportSite(t, manager, dir)
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("file not generated by the export removed: %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+// Filters the notes listed in search.json as the user types
+(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("results");
+  var groups = document.getElementById("groups");
+  var index = [];
+
+  fetch("search.json")
+    .then(function (response) { return response.json(); })
+    .then(function (data) { index = data; });
+
+  input.addEventListener("input", function () {
+    var query = input.value.trim().toLowerCase();
+    results.innerHTML = "";
+    if (query === "") {
+      results.hidden = true;
+      groups.hidden = false;
+      return;
+    }
+
+    index.forEach(function (entry) {
+      var haystack = (entry.title + " " + entry.tags.join(" ") + " " + entry.text).toLowerCase();
+      if (haystack.indexOf(query) === -1) {
+        return;
+      }
+      var item = document.createElement("li");
+      var link = document.createElement("a");
+      link.href = entry.url;
+      link.textContent = entry.title;
+      item.appendChild(link);
+      results.appendChild(item);
+    });
+    results.hidden = false;
+    groups.hidden = true;
+  });
+})();
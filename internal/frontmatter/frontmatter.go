@@ -0,0 +1,206 @@
+// Package frontmatter reads and writes the YAML frontmatter of Markdown
+// files, the block between --- lines at their top. Only the flat mappings
+// of notes are interpreted, whose values are scalars or lists of scalars.
+// Other values, comments and the order of the keys are kept as written, so
+// that the metadata of other tools survives a datapad edit.
+package frontmatter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// bom is the byte order mark some editors write at the start of UTF-8 files
+const bom = "\ufeff"
+
+// ErrUnterminated is returned when the frontmatter isn't closed by a ---
+// line
+var ErrUnterminated = errors.New("frontmatter isn't closed by a --- line")
+
+// ErrSyntax is returned for a frontmatter line that isn't a key, a value or
+// a comment
+var ErrSyntax = errors.New("invalid frontmatter line")
+
+// Frontmatter holds the keys of a frontmatter in their order. The zero value
+// is an empty frontmatter, which isn't written by Serialize until a key is
+// set.
+type Frontmatter struct {
+	fields  []field
+	trail   []string // Comments and blank lines before the closing line
+	present bool     // The text had a frontmatter, even an empty one
+}
+
+// field is a key of the frontmatter with its value as written
+type field struct {
+	lead  []string // Comments and blank lines above the key
+	key   string
+	lines []string // Line of the key followed by the lines of its value
+}
+
+// Parse splits a text into its frontmatter and its body. A text without
+// frontmatter is all body. Windows line endings and a byte order mark are
+// accepted, and the body is returned as it is written.
+//
+// When the frontmatter can't be read, the error is returned with the whole
+// text as body so that nothing is lost.
+func Parse(data []byte) (Frontmatter, string, error) {
+	text := strings.TrimPrefix(string(data), bom)
+	first, rest, _ := strings.Cut(text, "\n")
+	if !delimiter(first, "---") {
+		return Frontmatter{}, text, nil
+	}
+
+	fm := Frontmatter{present: true}
+	pending := []string{}
+	for number := 2; rest != ""; number++ {
+		line, next, _ := strings.Cut(rest, "\n")
+		rest = next
+		line = strings.TrimSuffix(line, "\r")
+
+		switch {
+		case delimiter(line, "---") || delimiter(line, "..."):
+			fm.trail = pending
+			return fm, rest, nil
+
+		case strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#"):
+			pending = append(pending, line)
+
+		case line[0] == ' ' || line[0] == '\t' || line == "-" || strings.HasPrefix(line, "- "):
+			// The value of the previous key goes on, blank lines and
+			// comments within it included
+			if len(fm.fields) == 0 {
+				return Frontmatter{}, text, fmt.Errorf("%w: line %d: %q", ErrSyntax, number, line)
+			}
+			last := &fm.fields[len(fm.fields)-1]
+			last.lines = append(append(last.lines, pending...), line)
+			pending = []string{}
+
+		default:
+			key, _, ok := splitKey(line)
+			if !ok {
+				return Frontmatter{}, text, fmt.Errorf("%w: line %d: %q", ErrSyntax, number, line)
+			}
+			fm.fields = append(fm.fields, field{lead: pending, key: key, lines: []string{line}})
+			pending = []string{}
+		}
+	}
+	return Frontmatter{}, text, ErrUnterminated
+}
+
+// Serialize writes a frontmatter followed by a body. Keys that weren't set
+// since Parse are written as they were read, with Unix line endings.
+func Serialize(fm Frontmatter, body string) []byte {
+	if !fm.present && len(fm.fields) == 0 {
+		return []byte(body)
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	for _, f := range fm.fields {
+		writeLines(&b, f.lead)
+		writeLines(&b, f.lines)
+	}
+	writeLines(&b, fm.trail)
+	b.WriteString("---\n")
+	b.WriteString(body)
+	return []byte(b.String())
+}
+
+// writeLines writes lines each followed by a newline
+func writeLines(b *strings.Builder, lines []string) {
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+}
+
+// delimiter reports whether a line is the given delimiter, trailing spaces
+// and a carriage return aside
+func delimiter(line, mark string) bool {
+	return strings.TrimRight(line, " \t\r") == mark
+}
+
+// splitKey splits the line of a key into the key and the rest of the line,
+// after the colon
+func splitKey(line string) (key, rest string, ok bool) {
+	if line[0] == '"' || line[0] == '\'' {
+		end := strings.IndexByte(line[1:], line[0])
+		if end < 0 {
+			return "", "", false
+		}
+		key, rest = scalar(line[:end+2]), line[end+2:]
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		rest = rest[1:]
+	} else {
+		if line[0] == '[' || line[0] == '{' {
+			return "", "", false
+		}
+		i := strings.Index(line, ": ")
+		if j := strings.Index(line, ":\t"); j >= 0 && (i < 0 || j < i) {
+			i = j
+		}
+		switch {
+		case i >= 0:
+			key, rest = line[:i], line[i+1:]
+		case strings.HasSuffix(line, ":"):
+			key = line[:len(line)-1]
+		default:
+			return "", "", false
+		}
+		key = strings.TrimRight(key, " \t")
+	}
+	if key == "" || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return "", "", false
+	}
+	return key, rest, true
+}
+
+// Keys returns the keys of the frontmatter in their order
+func (fm Frontmatter) Keys() []string {
+	keys := make([]string, len(fm.fields))
+	for i, f := range fm.fields {
+		keys[i] = f.key
+	}
+	return keys
+}
+
+// Has reports whether the frontmatter has a key
+func (fm Frontmatter) Has(key string) bool {
+	return fm.lookup(key) != nil
+}
+
+// Delete removes a key and its value, the comments above it included
+func (fm *Frontmatter) Delete(key string) {
+	for i, f := range fm.fields {
+		if f.key == key {
+			fm.fields = append(fm.fields[:i], fm.fields[i+1:]...)
+			return
+		}
+	}
+}
+
+// lookup returns the field of a key, nil when there is none. The first one
+// counts when a key is repeated.
+func (fm Frontmatter) lookup(key string) *field {
+	for i := range fm.fields {
+		if fm.fields[i].key == key {
+			return &fm.fields[i]
+		}
+	}
+	return nil
+}
+
+// setRaw sets the value of a key as written in YAML, in place when the key
+// exists, where the key is kept as written, and at the end otherwise
+func (fm *Frontmatter) setRaw(key, raw string) {
+	fm.present = true
+	if f := fm.lookup(key); f != nil {
+		_, rest, _ := splitKey(f.lines[0])
+		f.lines = []string{f.lines[0][:len(f.lines[0])-len(rest)] + " " + raw}
+		return
+	}
+	fm.fields = append(fm.fields, field{key: key, lines: []string{quote(key, false) + ": " + raw}})
+}
@@ -0,0 +1,127 @@
+package frontmatter
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// frontmatterSeeds are texts the fuzz targets start from
+var frontmatterSeeds = []string{
+	"",
+	"just a body\n",
+	"---\ntitle: Plan\ntags: [a, b]\npinned: true\n---\nBody\n",
+	"\ufeff---\r\ntitle: Windows\r\n---\r\nBody\r\n",
+	"---\n# comment\ntitle: \"quoted \\\"value\\\"\"\nlist:\n  - one\n  - two\n---\n",
+	"---\ntext: |\n  block\n  scalar\n...\nrest",
+	"---\ntitle: unterminated\n",
+	"---\n- not a key\n---\n",
+	"---\n'single': x\n\"double\": y\n[flow]: z\n---\n",
+	"---\ncreated: 2024-05-01T10:00:00Z\nodd: {a: 1}\n---\n",
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range frontmatterSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fm, body, err := Parse(data)
+		text := strings.TrimPrefix(string(data), bom)
+		if err != nil {
+			if !errors.Is(err, ErrUnterminated) && !errors.Is(err, ErrSyntax) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if body != text {
+				t.Fatalf("body %q after an error, want the whole text", body)
+			}
+			return
+		}
+		if !strings.HasSuffix(text, body) {
+			t.Fatalf("body %q isn't the end of the text", body)
+		}
+
+		// Reading the values of any key never panics
+		for _, key := range fm.Keys() {
+			fm.String(key)
+			fm.Strings(key)
+			fm.Bool(key)
+			fm.Time(key)
+		}
+
+		// Written back, the frontmatter reads the same
+		again, againBody, err := Parse(Serialize(fm, body))
+		if err != nil {
+			t.Fatalf("serialized frontmatter can't be read: %v", err)
+		}
+		if againBody != body || !slices.Equal(again.Keys(), fm.Keys()) {
+			t.Fatalf("read back keys %q and body %q, want %q and %q", again.Keys(), againBody, fm.Keys(), body)
+		}
+		for _, key := range fm.Keys() {
+			value, ok := fm.String(key)
+			againValue, againOk := again.String(key)
+			if value != againValue || ok != againOk {
+				t.Fatalf("value of %q read back as %q, want %q", key, againValue, value)
+			}
+		}
+	})
+}
+
+func FuzzSet(f *testing.F) {
+	for _, seed := range []string{"Plan", "", " padded ", "a: b", "#tag", "true", "12", "line\nbreak", "\"quoted\"", "tab\there", "[list]", "\ufeffbom"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		if !utf8.ValidString(value) {
+			return // Frontmatters are UTF-8 text
+		}
+		fm, body, err := Parse([]byte(frontmatterSeeds[2]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fm.Set("title", value)
+		fm.SetStrings("tags", []string{value, "other"})
+
+		again, againBody, err := Parse(Serialize(fm, body))
+		if err != nil {
+			t.Fatalf("frontmatter with %q can't be read: %v", value, err)
+		}
+		if againBody != body {
+			t.Fatalf("body read back as %q, want %q", againBody, body)
+		}
+		if got, _ := again.String("title"); got != value {
+			t.Fatalf("title read back as %q, want %q", got, value)
+		}
+		if got, _ := again.Strings("tags"); !slices.Equal(got, []string{value, "other"}) {
+			t.Fatalf("tags read back as %q, want %q", got, []string{value, "other"})
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		title string
+		body  string
+		err   error
+	}{
+		{"no frontmatter", "# Title\n", "", "# Title\n", nil},
+		{"frontmatter", "---\ntitle: Plan\n---\nBody\n", "Plan", "Body\n", nil},
+		{"windows line endings and BOM", "\ufeff---\r\ntitle: Plan\r\n---\r\nBody\r\n", "Plan", "Body\r\n", nil},
+		{"unterminated", "---\ntitle: Plan\n", "", "---\ntitle: Plan\n", ErrUnterminated},
+		{"syntax error", "---\nnot a key\n---\n", "", "---\nnot a key\n---\n", ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body, err := Parse([]byte(tt.text))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error %v, want %v", err, tt.err)
+			}
+			if title, _ := fm.String("title"); title != tt.title || body != tt.body {
+				t.Errorf("title %q and body %q, want %q and %q", title, body, tt.title, tt.body)
+			}
+		})
+	}
+}
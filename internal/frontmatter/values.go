@@ -0,0 +1,447 @@
+package frontmatter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// timeLayouts are the dates Time reads, the first one being written by
+// SetTime
+var timeLayouts = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// String returns the value of a key as a string. It reports false when the
+// key is missing or when its value isn't a scalar, such as a list.
+func (fm Frontmatter) String(key string) (string, bool) {
+	f := fm.lookup(key)
+	if f == nil {
+		return "", false
+	}
+	return f.scalar()
+}
+
+// Strings returns the value of a key as a list, written either as [a, b] or
+// as lines starting with a dash. A scalar is a list of one item, an empty
+// value an empty list. It reports false when the key is missing or when its
+// value is a mapping.
+func (fm Frontmatter) Strings(key string) ([]string, bool) {
+	f := fm.lookup(key)
+	if f == nil {
+		return nil, false
+	}
+	inline, block := f.value()
+	switch {
+	case strings.HasPrefix(inline, "["):
+		return flowList(strings.Join(append([]string{inline}, trimLines(block)...), " "))
+
+	case inline == "" && sequence(block):
+		items := []string{}
+		for _, line := range trimLines(block) {
+			if line == "-" || strings.HasPrefix(line, "- ") {
+				items = append(items, scalar(line[1:]))
+			} else if len(items) > 0 {
+				// An item going on over several lines
+				items[len(items)-1] += " " + scalar(line)
+			}
+		}
+		return items, true
+	}
+
+	value, ok := f.scalar()
+	if !ok {
+		return nil, false
+	}
+	if value == "" {
+		return []string{}, true
+	}
+	return []string{value}, true
+}
+
+// Bool returns the value of a key as a boolean, reporting false when the key
+// is missing or isn't true, false, yes or no
+func (fm Frontmatter) Bool(key string) (bool, bool) {
+	value, _ := fm.String(key)
+	switch strings.ToLower(value) {
+	case "true", "yes", "on":
+		return true, true
+	case "false", "no", "off":
+		return false, true
+	}
+	return false, false
+}
+
+// Time returns the value of a key as a date, written as RFC 3339 or as a
+// date with an optional time in the local time zone. It reports false when
+// the key is missing or isn't a date.
+func (fm Frontmatter) Time(key string) (time.Time, bool) {
+	value, ok := fm.String(key)
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Set sets the value of a key to a string, quoted when needed. The key
+// keeps its place when it exists and goes at the end otherwise. A key
+// already holding the value is left as written.
+func (fm *Frontmatter) Set(key, value string) {
+	if current, ok := fm.String(key); ok && current == value {
+		return
+	}
+	fm.setRaw(key, quote(value, false))
+}
+
+// SetStrings sets the value of a key to a list, written as [a, b]
+func (fm *Frontmatter) SetStrings(key string, values []string) {
+	if current, ok := fm.Strings(key); ok && slices.Equal(current, values) {
+		return
+	}
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quote(value, true)
+	}
+	fm.setRaw(key, "["+strings.Join(quoted, ", ")+"]")
+}
+
+// SetBool sets the value of a key to true or false
+func (fm *Frontmatter) SetBool(key string, value bool) {
+	if current, ok := fm.Bool(key); ok && current == value {
+		return
+	}
+	fm.setRaw(key, strconv.FormatBool(value))
+}
+
+// SetTime sets the value of a key to a date written as RFC 3339, to the
+// second
+func (fm *Frontmatter) SetTime(key string, value time.Time) {
+	value = value.Truncate(time.Second)
+	if current, ok := fm.Time(key); ok && current.Equal(value) {
+		return
+	}
+	fm.setRaw(key, value.Format(timeLayouts[0]))
+}
+
+// value returns the value of a field: the rest of the line of its key and
+// the lines below it
+func (f field) value() (string, []string) {
+	_, rest, _ := splitKey(f.lines[0])
+	return strings.TrimSpace(rest), f.lines[1:]
+}
+
+// scalar returns the value of a field as a string, reporting false when it
+// is a list or a mapping
+func (f field) scalar() (string, bool) {
+	inline, block := f.value()
+	lines := trimLines(block)
+	switch {
+	case strings.HasPrefix(inline, "|") || strings.HasPrefix(inline, ">"):
+		return blockScalar(inline, block), true
+	case strings.HasPrefix(inline, "[") || strings.HasPrefix(inline, "{"):
+		return "", false
+	case inline == "" && len(lines) > 0:
+		if sequence(block) {
+			return "", false
+		}
+		if _, _, ok := splitKey(lines[0]); ok {
+			return "", false
+		}
+	}
+
+	// A scalar going on over several lines is folded into one
+	if strings.HasPrefix(inline, "\"") || strings.HasPrefix(inline, "'") {
+		return scalar(strings.Join(append([]string{inline}, lines...), " ")), true
+	}
+	parts := []string{}
+	for _, line := range append([]string{inline}, lines...) {
+		if line = stripComment(line); line != "" {
+			parts = append(parts, line)
+		}
+	}
+	return scalar(strings.Join(parts, " ")), true
+}
+
+// trimLines returns the lines of a value without their indentation, leaving
+// out blank lines and comments
+func trimLines(lines []string) []string {
+	trimmed := []string{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			trimmed = append(trimmed, line)
+		}
+	}
+	return trimmed
+}
+
+// sequence reports whether the lines of a value are the items of a list
+func sequence(lines []string) bool {
+	trimmed := trimLines(lines)
+	return len(trimmed) > 0 && (trimmed[0] == "-" || strings.HasPrefix(trimmed[0], "- "))
+}
+
+// scalar returns the string a YAML scalar stands for, quoted or not
+func scalar(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+
+	switch text[0] {
+	case '"':
+		var b strings.Builder
+		for i := 1; i < len(text); i++ {
+			switch text[i] {
+			case '"':
+				return unescape(b.String())
+			case '\\':
+				if i+1 < len(text) {
+					b.WriteByte(text[i])
+					i++
+				}
+			}
+			b.WriteByte(text[i])
+		}
+		return unescape(b.String())
+
+	case '\'':
+		var b strings.Builder
+		for i := 1; i < len(text); i++ {
+			if text[i] == '\'' {
+				if i+1 < len(text) && text[i+1] == '\'' {
+					i++
+				} else {
+					break
+				}
+			}
+			b.WriteByte(text[i])
+		}
+		return b.String()
+	}
+
+	plain := stripComment(text)
+	switch plain {
+	case "~", "null", "Null", "NULL":
+		return ""
+	}
+	return plain
+}
+
+// stripComment removes the comment ending a plain scalar
+func stripComment(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "#") {
+		return ""
+	}
+	for i := 1; i < len(text); i++ {
+		if text[i] == '#' && (text[i-1] == ' ' || text[i-1] == '\t') {
+			return strings.TrimSpace(text[:i])
+		}
+	}
+	return text
+}
+
+// escapes are the characters of the one letter escapes of double-quoted
+// scalars
+var escapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n",
+	'v': "\v", 'f': "\f", 'r': "\r", 'e': "\x1b", ' ': " ", '"': "\"",
+	'/': "/", '\\': "\\", 'N': "\u0085", '_': "\u00a0", 'L': "\u2028",
+	'P': "\u2029",
+}
+
+// unescape replaces the escapes of a double-quoted scalar. An invalid escape
+// is kept as written.
+func unescape(text string) string {
+	if !strings.Contains(text, "\\") {
+		return text
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' || i+1 == len(text) {
+			b.WriteByte(text[i])
+			continue
+		}
+		if s, ok := escapes[text[i+1]]; ok {
+			b.WriteString(s)
+			i++
+			continue
+		}
+		size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[text[i+1]]
+		if size > 0 && i+2+size <= len(text) {
+			if code, err := strconv.ParseUint(text[i+2:i+2+size], 16, 32); err == nil && utf8.ValidRune(rune(code)) {
+				b.WriteRune(rune(code))
+				i += 1 + size
+				continue
+			}
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+// blockScalar returns the string of a literal (|) or folded (>) block
+// scalar, header being its indicator line
+func blockScalar(header string, lines []string) string {
+	// Lines are taken out of the indentation of the first one
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			indent = len(line) - len(strings.TrimLeft(line, " \t"))
+			break
+		}
+	}
+	if indent < 0 {
+		return ""
+	}
+	text := make([]string, len(lines))
+	for i, line := range lines {
+		text[i] = strings.TrimLeft(line[:min(indent, len(line))], " \t") + line[min(indent, len(line)):]
+		if strings.TrimSpace(line) == "" {
+			text[i] = ""
+		}
+	}
+	trailing := 0
+	for len(text) > 0 && text[len(text)-1] == "" {
+		text = text[:len(text)-1]
+		trailing++
+	}
+
+	var value string
+	if header[0] == '|' {
+		value = strings.Join(text, "\n")
+	} else {
+		// Folded lines are joined with spaces, blank lines being line breaks
+		var b strings.Builder
+		for i, line := range text {
+			switch {
+			case line == "":
+				b.WriteByte('\n')
+			case i > 0 && text[i-1] != "":
+				b.WriteString(" " + line)
+			default:
+				b.WriteString(line)
+			}
+		}
+		value = b.String()
+	}
+
+	switch {
+	case strings.Contains(header, "-"):
+		return value
+	case strings.Contains(header, "+"):
+		return value + strings.Repeat("\n", trailing+1)
+	}
+	return value + "\n"
+}
+
+// flowList returns the items of a list written as [a, b], reporting false
+// when it isn't closed
+func flowList(text string) ([]string, bool) {
+	items := []string{}
+	start, depth := 1, 0
+	var quote byte
+	for i := 1; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && strings.TrimSpace(text[start:i]) == "":
+			// Only an item starting with a quote is quoted
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case (c == ']' || c == '}') && depth > 0:
+			depth--
+		case c == ',' || c == ']':
+			if item := scalar(text[start:i]); item != "" || c == ',' {
+				items = append(items, item)
+			}
+			if c == ']' {
+				return items, true
+			}
+			start = i + 1
+		}
+	}
+	return nil, false
+}
+
+// quote writes a string as a YAML scalar: as it is when it reads back the
+// same, double-quoted otherwise. inList tells that it's an item of a [a, b]
+// list, where commas and brackets must be quoted.
+func quote(s string, inList bool) string {
+	if plain(s, inList) {
+		return s
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f || r == '\ufeff':
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// plain reports whether a string can be written as a plain scalar, which
+// other YAML readers wouldn't take for a number, a boolean or a date either
+func plain(s string, inList bool) bool {
+	if s == "" || s != strings.TrimSpace(s) || !utf8.ValidString(s) {
+		return false
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	if inList && strings.ContainsAny(s, ",[]{}") {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f || r == '\ufeff' {
+			return false
+		}
+	}
+
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return false
+	}
+	if _, err := strconv.ParseFloat(strings.ReplaceAll(s, "_", ""), 64); err == nil {
+		return false
+	}
+	for _, layout := range timeLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return false
+		}
+	}
+	return true
+}
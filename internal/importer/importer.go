@@ -0,0 +1,22 @@
+package importer
+
+import (
+	"datapad/internal/notes"
+	"fmt"
+)
+
+// Report summarizes an import
+type Report struct {
+	Imported int           // Notes added to the store
+	Archived int           // Imported notes that were archived in the source
+	Trashed  int           // Imported notes that were in the trash of the source
+	Skipped  int           // Notes left out on purpose
+	Images   int           // Images copied into the store
+	Warnings []string      // Content that couldn't be converted or files that couldn't be read
+	Remapped notes.IDRemap // New IDs of the imported notes whose ID was taken
+}
+
+// warn records a problem that didn't stop the import
+func (r *Report) warn(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,194 @@
+package importer
+
+import (
+	"datapad/internal/notes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrashedTag is given to notes that were in the trash of the source
+const TrashedTag = "trashed"
+
+// KeepOptions configures the import of a Google Keep Takeout export
+type KeepOptions struct {
+	SkipTrashed bool // Leave out the notes in the Keep trash
+}
+
+// keepNote is the JSON file Takeout writes for every Keep note
+type keepNote struct {
+	Title                   string           `json:"title"`
+	TextContent             string           `json:"textContent"`
+	ListContent             []keepListItem   `json:"listContent"`
+	Labels                  []keepLabel      `json:"labels"`
+	Attachments             []keepAttachment `json:"attachments"`
+	IsArchived              bool             `json:"isArchived"`
+	IsTrashed               bool             `json:"isTrashed"`
+	IsPinned                bool             `json:"isPinned"`
+	UserEditedTimestampUsec int64            `json:"userEditedTimestampUsec"`
+	CreatedTimestampUsec    int64            `json:"createdTimestampUsec"`
+}
+
+type keepListItem struct {
+	Text      string `json:"text"`
+	IsChecked bool   `json:"isChecked"`
+}
+
+type keepLabel struct {
+	Name string `json:"name"`
+}
+
+type keepAttachment struct {
+	FilePath string `json:"filePath"`
+	Mimetype string `json:"mimetype"`
+}
+
+// ImportKeep imports the notes of a Google Keep Takeout export. dir is either
+// the Keep folder itself or the Takeout folder containing it.
+func ImportKeep(manager *notes.NotesManager, dir string, opts KeepOptions) (Report, error) {
+	var report Report
+
+	if info, err := os.Stat(filepath.Join(dir, "Keep")); err == nil && info.IsDir() {
+		dir = filepath.Join(dir, "Keep")
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return report, fmt.Errorf("error listing Keep export: %w", err)
+	}
+	if len(files) == 0 {
+		return report, fmt.Errorf("no Keep notes found in %s", dir)
+	}
+	sort.Strings(files)
+
+	imported := []*notes.Note{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			report.warn("%s: %s", filepath.Base(file), err)
+			continue
+		}
+		var kn keepNote
+		if err := json.Unmarshal(data, &kn); err != nil {
+			report.warn("%s: not a Keep note: %s", filepath.Base(file), err)
+			continue
+		}
+
+		if kn.IsTrashed && opts.SkipTrashed {
+			report.Skipped++
+			continue
+		}
+
+		imported = append(imported, convertKeepNote(manager, dir, file, kn, &report))
+	}
+
+	remap, err := manager.AddNotes(imported)
+	if err != nil {
+		return report, err
+	}
+	report.Remapped = remap
+	report.Imported = len(imported)
+	return report, nil
+}
+
+// convertKeepNote builds a note from a Keep note, copying its images
+func convertKeepNote(manager *notes.NotesManager, dir, file string, kn keepNote, report *Report) *notes.Note {
+	title := strings.TrimSpace(kn.Title)
+	if title == "" {
+		// Takeout names the files of untitled notes after their date
+		title = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+
+	note := notes.NewNote(title)
+	note.Content = keepContent(kn)
+	for _, label := range kn.Labels {
+		if name := strings.TrimSpace(label.Name); name != "" {
+			note.AddTag(name)
+		}
+	}
+
+	for _, attachment := range kn.Attachments {
+		if !strings.HasPrefix(attachment.Mimetype, "image/") {
+			report.warn("%s: attachment %s (%s) is not an image and was not imported", title, attachment.FilePath, attachment.Mimetype)
+			continue
+		}
+		path, ok := findAttachment(dir, attachment.FilePath)
+		if !ok {
+			report.warn("%s: attachment %s not found", title, attachment.FilePath)
+			continue
+		}
+		name, err := manager.StoreImage(path)
+		if err != nil {
+			report.warn("%s: %s", title, err)
+			continue
+		}
+		note.AddImage(name, "", "")
+		report.Images++
+	}
+
+	if kn.IsArchived {
+		note.SetArchived(true)
+		report.Archived++
+	}
+	// Datapad has no trash, trashed notes are kept out of the way in the archive
+	if kn.IsTrashed {
+		note.AddTag(TrashedTag)
+		note.SetArchived(true)
+		report.Trashed++
+	}
+	note.SetPinned(kn.IsPinned && !note.Archived)
+
+	// Dates are set last since adding tags and images touches them
+	if kn.UserEditedTimestampUsec > 0 {
+		note.UpdatedAt = time.UnixMicro(kn.UserEditedTimestampUsec)
+	}
+	note.CreatedAt = note.UpdatedAt
+	if kn.CreatedTimestampUsec > 0 {
+		note.CreatedAt = time.UnixMicro(kn.CreatedTimestampUsec)
+	}
+	return note
+}
+
+// keepContent converts the text and checklist of a Keep note to Markdown
+func keepContent(kn keepNote) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(kn.TextContent, "\n"))
+
+	if len(kn.ListContent) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		for i, item := range kn.ListContent {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			box := "[ ]"
+			if item.IsChecked {
+				box = "[x]"
+			}
+			b.WriteString("- " + box + " " + strings.ReplaceAll(item.Text, "\n", " "))
+		}
+	}
+	return b.String()
+}
+
+// findAttachment locates an attachment of the export. Takeout sometimes
+// writes images with a different extension than the one in the note.
+func findAttachment(dir, name string) (string, bool) {
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return path, true
+	}
+
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, ext := range []string{".jpg", ".jpeg", ".png", ".gif", ".webp"} {
+		if _, err := os.Stat(base + ext); err == nil {
+			return base + ext, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,455 @@
+package importer
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"datapad/internal/notes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// notionHash matches the identifier Notion appends to exported file names
+var notionHash = regexp.MustCompile(`\s+[0-9a-f]{32}$`)
+
+// markdownLink matches Markdown links and images with their label and target
+var markdownLink = regexp.MustCompile(`(!?)\[([^\]]*)\]\(([^)]+)\)`)
+
+// blankLines matches runs of empty lines
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// notionPage is an exported page waiting to be converted
+type notionPage struct {
+	path  string   // Path of the Markdown file, relative to the export root
+	title string   // Unique title of the resulting note
+	tags  []string // Hierarchical tag of the folder holding the page
+	meta  []string // Fields of the database row the page comes from
+	body  string
+	note  *notes.Note
+}
+
+// ImportNotion imports a Notion "Markdown & CSV" export, given either as the
+// zip file downloaded from Notion or as the folder it was extracted to.
+// Nested pages are tagged with the path of their parents, such as
+// "Projects/Website", since datapad has no notebooks.
+func ImportNotion(manager *notes.NotesManager, path string) (Report, error) {
+	var report Report
+
+	root := path
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		dir, err := os.MkdirTemp("", "datapad-notion-")
+		if err != nil {
+			return report, fmt.Errorf("unable to create temporary directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+		if err := extractZip(path, dir); err != nil {
+			return report, err
+		}
+		root = dir
+	}
+
+	pages, err := collectNotionPages(root, &report)
+	if err != nil {
+		return report, err
+	}
+	if len(pages) == 0 {
+		return report, fmt.Errorf("no Notion pages found in %s", path)
+	}
+
+	// Titles are made unique before converting links so that links point
+	// to the title the target note ends up with
+	used := map[string]bool{}
+	for _, note := range manager.Notes {
+		used[strings.ToLower(strings.TrimSpace(note.Title))] = true
+	}
+	byPath := map[string]*notionPage{}
+	for _, page := range pages {
+		title := page.title
+		for i := 2; used[strings.ToLower(title)]; i++ {
+			title = fmt.Sprintf("%s (%d)", page.title, i)
+		}
+		if title != page.title {
+			report.warn("%s: title already used, imported as %q", page.path, title)
+		}
+		used[strings.ToLower(title)] = true
+		page.title = title
+		byPath[page.path] = page
+	}
+
+	imported := []*notes.Note{}
+	for _, page := range pages {
+		note := notes.NewNote(page.title)
+		for _, tag := range page.tags {
+			note.AddTag(tag)
+		}
+		page.note = note
+		note.Content = convertNotionBody(manager, root, page, byPath, &report)
+		imported = append(imported, note)
+	}
+
+	remap, err := manager.AddNotes(imported)
+	if err != nil {
+		return report, err
+	}
+	report.Remapped = remap
+	report.Imported = len(imported)
+	return report, nil
+}
+
+// extractZip extracts an archive into dir, refusing entries escaping it
+func extractZip(path, dir string) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", path, err)
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		dest := filepath.Join(dir, file.Name)
+		if !strings.HasPrefix(dest, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path in archive: %s", file.Name)
+		}
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(dest, 0755); err != nil {
+				return fmt.Errorf("error extracting %s: %w", file.Name, err)
+			}
+			continue
+		}
+		if err := extractFile(file, dest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractFile writes a single archive entry to dest
+func extractFile(file *zip.File, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("error extracting %s: %w", file.Name, err)
+	}
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("error extracting %s: %w", file.Name, err)
+	}
+	defer src.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("error extracting %s: %w", file.Name, err)
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return fmt.Errorf("error extracting %s: %w", file.Name, err)
+	}
+	return out.Close()
+}
+
+// collectNotionPages reads every page and database row of the export
+func collectNotionPages(root string, report *Report) ([]*notionPage, error) {
+	var markdown, databases []string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".md":
+			markdown = append(markdown, filepath.ToSlash(rel))
+		case ".csv":
+			databases = append(databases, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading Notion export: %w", err)
+	}
+	sort.Strings(markdown)
+
+	// Database rows are exported both as CSV lines and as pages in the
+	// folder of the database, the CSV only adds their properties
+	rows := map[string][]string{}
+	headers := map[string][]string{}
+	for _, path := range preferredDatabases(databases) {
+		folder := strings.TrimSuffix(strings.TrimSuffix(path, filepath.Ext(path)), "_all")
+		header, records, err := readDatabase(filepath.Join(root, path))
+		if err != nil {
+			report.warn("%s: %s", path, err)
+			continue
+		}
+		headers[folder] = header
+		for _, record := range records {
+			rows[folder+"/"+strings.TrimSpace(record[0])] = databaseFields(header, record)
+		}
+	}
+
+	pages := []*notionPage{}
+	for _, path := range markdown {
+		data, err := os.ReadFile(filepath.Join(root, path))
+		if err != nil {
+			report.warn("%s: %s", path, err)
+			continue
+		}
+
+		page := &notionPage{path: path, title: cleanNotionName(filepath.Base(path))}
+		body := string(bytes.TrimPrefix(data, []byte("\ufeff")))
+		if heading, rest, ok := strings.Cut(body, "\n"); ok && strings.HasPrefix(heading, "# ") {
+			page.title = strings.TrimSpace(strings.TrimPrefix(heading, "# "))
+			body = rest
+		} else if strings.HasPrefix(body, "# ") {
+			page.title = strings.TrimSpace(strings.TrimPrefix(body, "# "))
+			body = ""
+		}
+
+		folder := filepath.ToSlash(filepath.Dir(path))
+		if fields, ok := rows[folder+"/"+page.title]; ok {
+			page.meta = fields
+			body = stripProperties(body, headers[folder])
+		}
+		page.body = strings.Trim(body, "\n")
+
+		if folder != "." {
+			page.tags = []string{notionTag(folder)}
+		}
+		pages = append(pages, page)
+	}
+
+	// Rows without a page of their own still become notes
+	seen := map[string]bool{}
+	for _, page := range pages {
+		seen[filepath.ToSlash(filepath.Dir(page.path))+"/"+page.title] = true
+	}
+	keys := make([]string, 0, len(rows))
+	for key := range rows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		i := strings.LastIndex(key, "/")
+		folder, title := key[:i], key[i+1:]
+		pages = append(pages, &notionPage{
+			path:  key + ".csv",
+			title: title,
+			tags:  []string{notionTag(folder)},
+			meta:  rows[key],
+		})
+	}
+	return pages, nil
+}
+
+// preferredDatabases keeps, for each database exported twice, the "_all"
+// CSV that holds every row rather than only those of the saved view
+func preferredDatabases(paths []string) []string {
+	all := map[string]bool{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_all.csv") {
+			all[strings.TrimSuffix(path, "_all.csv")] = true
+		}
+	}
+	kept := []string{}
+	for _, path := range paths {
+		if !strings.HasSuffix(path, "_all.csv") && all[strings.TrimSuffix(path, ".csv")] {
+			continue
+		}
+		kept = append(kept, path)
+	}
+	sort.Strings(kept)
+	return kept
+}
+
+// readDatabase reads the header and the rows of a database CSV
+func readDatabase(path string) ([]string, [][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, nil
+	}
+	rows := [][]string{}
+	for _, record := range records[1:] {
+		if len(record) > 0 && strings.TrimSpace(record[0]) != "" {
+			rows = append(rows, record)
+		}
+	}
+	return records[0], rows, nil
+}
+
+// databaseFields formats the properties of a database row, the first column
+// being the title of the row
+func databaseFields(header, record []string) []string {
+	fields := []string{}
+	for i := 1; i < len(header) && i < len(record); i++ {
+		if value := strings.TrimSpace(record[i]); value != "" {
+			fields = append(fields, header[i]+": "+value)
+		}
+	}
+	return fields
+}
+
+// stripProperties removes the property lines Notion writes below the title
+// of a database row page, since they come from the CSV already
+func stripProperties(body string, header []string) string {
+	lines := strings.Split(strings.TrimLeft(body, "\n"), "\n")
+	i := 0
+	for ; i < len(lines); i++ {
+		key, _, ok := strings.Cut(lines[i], ":")
+		if !ok || !containsString(header, key) {
+			break
+		}
+	}
+	return strings.Join(lines[i:], "\n")
+}
+
+// cleanNotionName removes the extension and the identifier of an exported name
+func cleanNotionName(name string) string {
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.TrimSpace(notionHash.ReplaceAllString(name, ""))
+}
+
+// notionTag turns the folder of a page into a hierarchical tag
+func notionTag(folder string) string {
+	parts := strings.Split(folder, "/")
+	for i, part := range parts {
+		parts[i] = cleanNotionName(part + ".x")
+	}
+	return strings.Join(parts, "/")
+}
+
+// convertNotionBody converts the Markdown of a page: links to other pages
+// become wikilinks, relative images are imported and Notion specific HTML is
+// replaced by its closest Markdown equivalent
+func convertNotionBody(manager *notes.NotesManager, root string, page *notionPage, byPath map[string]*notionPage, report *Report) string {
+	parts := []string{}
+	if len(page.meta) > 0 {
+		parts = append(parts, "- "+strings.Join(page.meta, "\n- "))
+	}
+	if page.body != "" {
+		parts = append(parts, convertNotionBlocks(page, report))
+	}
+	body := strings.Join(parts, "\n\n")
+
+	dir := filepath.ToSlash(filepath.Dir(page.path))
+	body = markdownLink.ReplaceAllStringFunc(body, func(match string) string {
+		parts := markdownLink.FindStringSubmatch(match)
+		image, label, target := parts[1] == "!", parts[2], parts[3]
+		if strings.Contains(target, "://") || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "mailto:") {
+			return match
+		}
+
+		unescaped, err := url.PathUnescape(target)
+		if err != nil {
+			unescaped = target
+		}
+		rel := filepath.ToSlash(filepath.Clean(filepath.Join(dir, unescaped)))
+
+		switch {
+		case image:
+			name, err := manager.StoreImage(filepath.Join(root, filepath.FromSlash(rel)))
+			if err != nil {
+				report.warn("%s: image %s not imported: %s", page.title, unescaped, err)
+				return match
+			}
+			page.note.AddImage(name, "", label)
+			report.Images++
+			// The image is attached to the note, the inline reference is dropped
+			return ""
+		case strings.EqualFold(filepath.Ext(rel), ".md"):
+			if linked, ok := byPath[rel]; ok {
+				if label == "" || label == linked.title {
+					return "[[" + linked.title + "]]"
+				}
+				return "[[" + linked.title + "|" + label + "]]"
+			}
+			report.warn("%s: link to missing page %s kept as text", page.title, unescaped)
+			return label
+		case strings.EqualFold(filepath.Ext(rel), ".csv"):
+			report.warn("%s: link to database %s kept as text, its rows are tagged %q", page.title, label, notionTag(strings.TrimSuffix(rel, ".csv")))
+			return label
+		default:
+			report.warn("%s: attachment %s not imported", page.title, unescaped)
+			return match
+		}
+	})
+	// Dropped images leave empty paragraphs behind
+	return strings.Trim(blankLines.ReplaceAllString(body, "\n\n"), "\n")
+}
+
+// convertNotionBlocks replaces the HTML Notion uses for callouts and toggles.
+// Both lose their styling, which is reported.
+func convertNotionBlocks(page *notionPage, report *Report) string {
+	lines := []string{}
+	inCallout := false
+	callouts, toggles := 0, 0
+
+	scanner := bufio.NewScanner(strings.NewReader(page.body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "<aside>":
+			inCallout = true
+			callouts++
+			continue
+		case trimmed == "</aside>":
+			inCallout = false
+			for len(lines) > 0 && lines[len(lines)-1] == ">" {
+				lines = lines[:len(lines)-1]
+			}
+			continue
+		case strings.Contains(line, "<details>") || strings.Contains(line, "</details>") || strings.Contains(line, "<summary>"):
+			if strings.Contains(line, "<summary>") {
+				toggles++
+			}
+			line = strings.NewReplacer("<details>", "", "</details>", "", "<summary>", "**", "</summary>", "**").Replace(line)
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+		}
+		if inCallout {
+			if trimmed == "" {
+				line = ">"
+			} else {
+				line = "> " + line
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	if callouts > 0 {
+		report.warn("%s: %d callout(s) rendered as blockquotes", page.title, callouts)
+	}
+	if toggles > 0 {
+		report.warn("%s: %d toggle(s) flattened, their content is always visible", page.title, toggles)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package instance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+// dialTimeout is how long connecting to the socket of the interface may
+// take. Nobody listening on it fails at once.
+const dialTimeout = time.Second
+
+// dial connects to the socket of an instance file
+func dial(rec *record) (net.Conn, error) {
+	return net.DialTimeout("unix", rec.Socket, dialTimeout)
+}
+
+// Submit sends a request to the interface that has the store open. It
+// reports false, without an error, when none has, and the caller then
+// writes the store itself. The instance file and socket left behind by an
+// interface that crashed are removed on the way.
+//
+// Once the request is sent, a failure is returned rather than reported as no
+// interface: the interface may still make the change.
+func Submit(storagePath string, req Request) (Response, bool, error) {
+	// Without a folder for the instance files no interface could serve
+	path, err := recordPath(storagePath)
+	if err != nil {
+		return Response{}, false, nil
+	}
+	rec, err := readRecord(path)
+	if err != nil || rec == nil || !rec.local() {
+		return Response{}, false, err
+	}
+	conn, err := dial(rec)
+	if err != nil {
+		removeStale(path, rec)
+		return Response{}, false, nil
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(requestTimeout))
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, false, fmt.Errorf("error sending the request to the running interface: %w", err)
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return Response{}, false, fmt.Errorf("no answer from the running interface, check it before trying again: %w", err)
+	}
+	if resp.Elsewhere {
+		return Response{}, false, nil
+	}
+	return resp, true, nil
+}
@@ -0,0 +1,138 @@
+package instance
+
+import (
+	"crypto/sha256"
+	"datapad/internal/notes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// instancesDir is the folder, in the runtime or cache folder of the user, of
+// the files telling which socket the interface that has a store open listens
+// on. They are kept out of the store, which is only created on the first save.
+const instancesDir = "datapad"
+
+// OpAdd creates a note
+const OpAdd = "add"
+
+// ErrRunning is returned by Listen when another interface already serves the
+// store
+var ErrRunning = errors.New("another datapad interface has the store open")
+
+// Request is an operation submitted to the running interface, one JSON
+// object per connection
+type Request struct {
+	Op      string   `json:"op"`
+	Title   string   `json:"title"`
+	Content string   `json:"content,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// Response is the answer of the running interface to a request
+type Response struct {
+	ID        string `json:"id,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Duplicate bool   `json:"duplicate,omitempty"` // Another note already has the title
+	Error     string `json:"error,omitempty"`
+
+	// The interface switched to another workspace and no longer has the
+	// store open, the command can write it itself
+	Elsewhere bool `json:"elsewhere,omitempty"`
+}
+
+// record is the content of the instance file
+type record struct {
+	PID    int    `json:"pid"`
+	Host   string `json:"host"`
+	Socket string `json:"socket"`
+}
+
+// Apply carries out a request on a store, whether the running interface or
+// a command holds it. The errors of the store are returned as they are for
+// the caller to report, those of the request in the response.
+func Apply(manager *notes.NotesManager, req Request) (Response, error) {
+	switch req.Op {
+	case OpAdd:
+		title := strings.TrimSpace(req.Title)
+		if title == "" {
+			return Response{Error: "the note has no title"}, nil
+		}
+		duplicate := manager.TitleExists(title)
+		note := manager.CreateNote(title)
+		note.Content = req.Content
+		for _, tag := range req.Tags {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				note.AddTag(tag)
+			}
+		}
+		if err := manager.UpdateNote(note); err != nil {
+			return Response{}, fmt.Errorf("error saving note: %w", err)
+		}
+		return Response{ID: note.ID, Title: note.Title, Duplicate: duplicate}, nil
+	}
+	return Response{Error: fmt.Sprintf("unknown operation %q", req.Op)}, nil
+}
+
+// recordPath returns the path of the instance file of a store, named after a
+// hash of its absolute path. The files live in $XDG_RUNTIME_DIR when it is
+// set, otherwise in the cache folder of the user, both private to the user.
+func recordPath(storagePath string) (string, error) {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("no folder for the instance files: %w", err)
+		}
+		dir = cache
+	}
+
+	path, err := filepath.Abs(storagePath)
+	if err != nil {
+		return "", fmt.Errorf("error resolving the storage path: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(dir, instancesDir, "instance-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// readRecord reads the instance file of a store, nil when there is none
+func readRecord(path string) (*record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		// A file cut short by a crash is as good as none
+		return &record{}, nil
+	}
+	return &rec, nil
+}
+
+// local reports whether the record was written on this machine, the storage
+// folder may be shared with others
+func (rec *record) local() bool {
+	host, err := os.Hostname()
+	return err == nil && rec.Host == host
+}
+
+// removeStale removes the instance file at path and the socket of an
+// interface that stopped without cleaning up
+func removeStale(path string, rec *record) {
+	os.Remove(path)
+	// Only a socket is removed, whatever the file names
+	if info, err := os.Lstat(rec.Socket); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(rec.Socket)
+		os.Remove(filepath.Dir(rec.Socket))
+	}
+}
@@ -0,0 +1,132 @@
+package instance
+
+import (
+	"datapad/internal/notes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// isolate keeps the instance files of a test in a folder of its own
+func isolate(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+}
+
+func TestListenKeepsStoreUntouched(t *testing.T) {
+	isolate(t)
+	store := filepath.Join(t.TempDir(), "store")
+	server, err := Listen(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	if _, err := os.Stat(store); !os.IsNotExist(err) {
+		t.Error("serving the store created its folder")
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	isolate(t)
+	store := t.TempDir()
+	if _, served, err := Submit(store, Request{Op: OpAdd, Title: "Alone"}); served || err != nil {
+		t.Fatalf("Submit without an interface: served %v, error %v", served, err)
+	}
+
+	server, err := Listen(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := notes.OpenNotesManager(store)
+	go server.Serve(func(req Request) Response {
+		resp, err := Apply(manager, req)
+		if err != nil {
+			resp.Error = err.Error()
+		}
+		return resp
+	})
+
+	resp, served, err := Submit(store, Request{Op: OpAdd, Title: "Served", Tags: []string{"cli"}})
+	if err != nil || !served {
+		t.Fatalf("Submit to the interface: served %v, error %v", served, err)
+	}
+	note, _ := manager.GetNoteByID(resp.ID)
+	if note == nil || note.Title != "Served" || !slices.Contains(note.Tags, "cli") {
+		t.Errorf("the interface added %+v", note)
+	}
+
+	if _, err := Listen(store); !errors.Is(err, ErrRunning) {
+		t.Errorf("a second interface got %v, want ErrRunning", err)
+	}
+
+	server.Close()
+	path, _ := recordPath(store)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("the instance file outlived the server")
+	}
+	if _, served, _ := Submit(store, Request{Op: OpAdd, Title: "After"}); served {
+		t.Error("a closed server was reported as serving")
+	}
+}
+
+func TestSubmitRemovesStaleRecord(t *testing.T) {
+	isolate(t)
+	store := t.TempDir()
+	path, err := recordPath(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, _ := os.Hostname()
+	data, _ := json.Marshal(record{PID: 1, Host: host, Socket: filepath.Join(t.TempDir(), "gone.sock")})
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, served, err := Submit(store, Request{Op: OpAdd, Title: "x"}); served || err != nil {
+		t.Fatalf("served %v, error %v", served, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("the record of a crashed interface was kept")
+	}
+
+	// An interface takes the store over as well
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	server, err := Listen(store)
+	if err != nil {
+		t.Fatalf("Listen over a stale record: %v", err)
+	}
+	server.Close()
+}
+
+func TestRecordPath(t *testing.T) {
+	isolate(t)
+	dir := t.TempDir()
+	a, _ := recordPath(dir)
+	b, _ := recordPath(dir + "/.")
+	c, _ := recordPath(filepath.Join(dir, "other"))
+	if a != b {
+		t.Errorf("the same store has two instance files: %s and %s", a, b)
+	}
+	if a == c {
+		t.Error("two stores share an instance file")
+	}
+}
+
+func TestApplyWithoutTitle(t *testing.T) {
+	manager := notes.OpenNotesManager(t.TempDir())
+	resp, err := Apply(manager, Request{Op: OpAdd, Title: "  "})
+	if err != nil || resp.Error == "" {
+		t.Errorf("a note without a title was accepted: %+v, %v", resp, err)
+	}
+	if resp, _ := Apply(manager, Request{Op: "remove"}); resp.Error == "" {
+		t.Error("an unknown operation was accepted")
+	}
+}
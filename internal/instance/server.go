@@ -0,0 +1,149 @@
+package instance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// requestTimeout bounds a request, from its reading to the writing of the
+// answer. The interface answers between two frames unless it is stuck.
+const requestTimeout = 10 * time.Second
+
+// Server answers the requests of the commands run while the interface has
+// the store open
+type Server struct {
+	record    string // Path of the instance file
+	socket    string
+	listener  net.Listener
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Listen opens a socket for the requests of the commands and records it in
+// the instance file of the store. The socket lives in a folder of its own
+// that only the user can enter. The instance file and socket of an interface
+// that crashed are taken over, ErrRunning is returned when another interface
+// still answers.
+func Listen(storagePath string) (*Server, error) {
+	path, err := recordPath(storagePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("error creating the folder of the instance files: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "datapad-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating the socket folder: %w", err)
+	}
+	socket := filepath.Join(dir, "instance.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		os.Remove(dir)
+		return nil, fmt.Errorf("error opening the socket: %w", err)
+	}
+
+	host, _ := os.Hostname()
+	data, err := json.Marshal(record{PID: os.Getpid(), Host: host, Socket: socket})
+	if err == nil {
+		err = claim(path, data)
+	}
+	if err != nil {
+		listener.Close()
+		os.Remove(dir)
+		return nil, err
+	}
+	return &Server{
+		record:   path,
+		socket:   socket,
+		listener: listener,
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// claim writes the instance file at path unless another interface of this
+// machine answers on the socket it names. A record of another machine
+// sharing the folder is replaced, its socket can't be reached from here.
+func claim(path string, data []byte) error {
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_, err = file.Write(data)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(path)
+				return fmt.Errorf("error writing %s: %w", path, err)
+			}
+			return nil
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("error writing %s: %w", path, err)
+		}
+
+		rec, err := readRecord(path)
+		if err != nil {
+			return err
+		}
+		if rec == nil {
+			continue
+		}
+		if rec.local() {
+			if conn, err := dial(rec); err == nil {
+				conn.Close()
+				return ErrRunning
+			}
+		}
+		removeStale(path, rec)
+	}
+	return ErrRunning
+}
+
+// Serve answers each connection with handle until the server is closed. A
+// connection carries a single request.
+func (s *Server) Serve(handle func(Request) Response) {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go answer(conn, handle)
+	}
+}
+
+// answer reads the request of a connection and writes the answer of handle
+func answer(conn net.Conn, handle func(Request) Response) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(requestTimeout))
+	var req Request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+	json.NewEncoder(conn).Encode(handle(req))
+}
+
+// Done is closed once the server is, requests waiting for the interface
+// then give up
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close stops the server and removes its socket and the instance file, unless
+// another interface took the store over in the meantime
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.listener.Close()
+		if rec, err := readRecord(s.record); err == nil && rec != nil && rec.Socket == s.socket {
+			os.Remove(s.record)
+		}
+		os.Remove(s.socket)
+		os.Remove(filepath.Dir(s.socket))
+	})
+}
@@ -0,0 +1,31 @@
+package markdown
+
+import (
+	"datapad/internal/config"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Extensions maps the names accepted in the config to goldmark extensions
+var Extensions = map[string]goldmark.Extender{
+	config.ExtensionGFM:            extension.GFM,
+	config.ExtensionTable:          extension.Table,
+	config.ExtensionStrikethrough:  extension.Strikethrough,
+	config.ExtensionTaskList:       extension.TaskList,
+	config.ExtensionLinkify:        extension.Linkify,
+	config.ExtensionFootnote:       extension.Footnote,
+	config.ExtensionDefinitionList: extension.DefinitionList,
+}
+
+// New creates the Markdown parser with the extensions of the given names, so
+// that the preview and the exports render notes alike
+func New(names []string) goldmark.Markdown {
+	extenders := []goldmark.Extender{}
+	for _, name := range names {
+		if ext, ok := Extensions[name]; ok {
+			extenders = append(extenders, ext)
+		}
+	}
+	return goldmark.New(goldmark.WithExtensions(extenders...))
+}
@@ -0,0 +1,224 @@
+package notes
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// activityFile is the log of the operations on the notes, one JSON entry per
+// line. Past activityLimit bytes it is moved to activityFile+".1", replacing
+// the previous one, so that at most twice the limit is kept.
+const (
+	activityFile  = "activity.log"
+	activityLimit = 1 << 20
+)
+
+// activityMerge is how long changes of the text of a note go unlogged after
+// an update of it is logged
+const activityMerge = 10 * time.Minute
+
+// Actions recorded in the activity log
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionDelete    = "delete"
+	ActionTag       = "tag"
+	ActionArchive   = "archive"
+	ActionUnarchive = "unarchive"
+)
+
+// Activity is an entry of the activity log
+type Activity struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+	NoteID string    `json:"note_id"`
+	Title  string    `json:"title"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// loggedNote is what the log knows of a note as last saved, to tell which
+// kind of change an update made
+type loggedNote struct {
+	text     uint64 // Hash of the title and content
+	tags     []string
+	archived bool
+	updated  time.Time // Time the last update of the text was logged
+}
+
+// newLoggedNote records the state of a note
+func newLoggedNote(note *Note) loggedNote {
+	h := fnv.New64a()
+	h.Write([]byte(note.Title))
+	h.Write([]byte{0})
+	h.Write([]byte(note.Content))
+	return loggedNote{text: h.Sum64(), tags: slices.Clone(note.Tags), archived: note.Archived}
+}
+
+// rememberNotes records the state of every note, changes are logged against it
+func (m *NotesManager) rememberNotes() {
+	m.logged = make(map[string]loggedNote, len(m.Notes))
+	for _, note := range m.Notes {
+		m.logged[note.ID] = newLoggedNote(note)
+	}
+}
+
+// logSaved logs the changes made to a note since it was last saved and
+// tells the subscribers
+func (m *NotesManager) logSaved(note *Note, detail string) {
+	if m.logged == nil {
+		m.logged = map[string]loggedNote{}
+	}
+	before, known := m.logged[note.ID]
+	after := newLoggedNote(note)
+	after.updated = before.updated
+	// A burst of edits, such as autosaves while typing, is logged once
+	textUpdated := known && before.text != after.text && time.Since(before.updated) >= activityMerge
+	if textUpdated {
+		after.updated = time.Now()
+	}
+	m.logged[note.ID] = after
+
+	if !known {
+		m.logActivity(ActionCreate, note, detail)
+		m.publish(NoteCreated, note)
+		return
+	}
+	defer m.publish(NoteUpdated, note)
+
+	changed := false
+	if added, removed := tagChanges(before.tags, after.tags); len(added)+len(removed) > 0 {
+		changes := []string{}
+		for _, tag := range added {
+			changes = append(changes, "+"+tag)
+		}
+		for _, tag := range removed {
+			changes = append(changes, "-"+tag)
+		}
+		m.logActivity(ActionTag, note, strings.Join(changes, " "))
+		changed = true
+	}
+	if before.archived != after.archived {
+		action := ActionArchive
+		if !after.archived {
+			action = ActionUnarchive
+		}
+		m.logActivity(action, note, detail)
+		changed = true
+	}
+	// Changes of other fields, such as pinning, are logged as updates
+	if textUpdated || (before.text == after.text && !changed) {
+		m.logActivity(ActionUpdate, note, detail)
+	}
+}
+
+// logDeleted logs the deletion of a note and tells the subscribers
+func (m *NotesManager) logDeleted(note *Note, detail string) {
+	delete(m.logged, note.ID)
+	m.logActivity(ActionDelete, note, detail)
+	m.publish(NoteDeleted, note)
+}
+
+// tagChanges returns the tags added and removed between two lists
+func tagChanges(before, after []string) (added, removed []string) {
+	for _, tag := range after {
+		if !slices.Contains(before, tag) {
+			added = append(added, tag)
+		}
+	}
+	for _, tag := range before {
+		if !slices.Contains(after, tag) {
+			removed = append(removed, tag)
+		}
+	}
+	return added, removed
+}
+
+// logActivity appends an entry to the activity log. The log is informative
+// only, failing to write it never prevents an operation.
+func (m *NotesManager) logActivity(action string, note *Note, detail string) {
+	entry := Activity{
+		Time:   time.Now(),
+		Action: action,
+		NoteID: note.ID,
+		Title:  note.Title,
+		Detail: detail,
+	}
+	if m.OnActivity != nil {
+		m.OnActivity(entry)
+	}
+	if m.DryRun {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := m.ensureDir(m.StoragePath); err != nil {
+		return
+	}
+
+	path := filepath.Join(m.StoragePath, activityFile)
+	if info, err := os.Stat(path); err == nil && info.Size() >= activityLimit {
+		os.Rename(path, path+".1")
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(data, '\n'))
+}
+
+// ReadActivity returns the latest entries of the activity log, the most
+// recent first, at most limit of them when limit is positive
+func (m *NotesManager) ReadActivity(limit int) ([]Activity, error) {
+	path := filepath.Join(m.StoragePath, activityFile)
+	entries := []Activity{}
+	for _, file := range []string{path + ".1", path} {
+		read, err := readActivityFile(file)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, read...)
+	}
+
+	slices.Reverse(entries)
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// readActivityFile reads the entries of a log file in order, skipping lines
+// that can't be decoded such as one cut by a crash
+func readActivityFile(path string) ([]Activity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading activity log: %w", err)
+	}
+	defer f.Close()
+
+	entries := []Activity{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Activity
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading activity log: %w", err)
+	}
+	return entries, nil
+}
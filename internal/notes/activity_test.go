@@ -0,0 +1,156 @@
+package notes
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countActions returns how many entries of the activity log of m have each
+// action
+func countActions(t *testing.T, m *NotesManager) map[string]int {
+	t.Helper()
+	entries, err := m.ReadActivity(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int{}
+	for _, entry := range entries {
+		counts[entry.Action]++
+	}
+	return counts
+}
+
+func TestActivityLogsOneUpdatePerBurst(t *testing.T) {
+	m := newTestManager(t, "A")
+	note := m.Notes[0]
+	for _, content := range []string{"a", "ab", "abc"} {
+		note.Content = content
+		if err := m.UpdateNote(note); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := countActions(t, m)[ActionUpdate]; got != 1 {
+		t.Fatalf("%d update(s) logged for a burst of edits, want 1", got)
+	}
+
+	logged := m.logged[note.ID]
+	logged.updated = logged.updated.Add(-activityMerge)
+	m.logged[note.ID] = logged
+	note.Content = "abcd"
+	if err := m.UpdateNote(note); err != nil {
+		t.Fatal(err)
+	}
+	if got := countActions(t, m)[ActionUpdate]; got != 2 {
+		t.Errorf("%d update(s) logged once the burst is over, want 2", got)
+	}
+}
+
+func TestActivityLogsOtherChanges(t *testing.T) {
+	m := newTestManager(t, "A")
+	note := m.Notes[0]
+	note.Content = "a"
+	if err := m.UpdateNote(note); err != nil {
+		t.Fatal(err)
+	}
+	note.Pinned = true
+	note.Tags = []string{"work"}
+	if err := m.UpdateNote(note); err != nil {
+		t.Fatal(err)
+	}
+	note.Pinned = false
+	if err := m.UpdateNote(note); err != nil {
+		t.Fatal(err)
+	}
+	counts := countActions(t, m)
+	if counts[ActionTag] != 1 || counts[ActionUpdate] != 2 {
+		t.Errorf("logged %v, want a tag change and 2 updates", counts)
+	}
+}
+
+func TestActivityOneEntryPerOperation(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(m *NotesManager, a, b *Note) error
+		want []Activity // Action, title and detail of the new entries, by note and then in order
+	}{
+		{"create", func(m *NotesManager, a, b *Note) error {
+			return m.UpdateNote(m.CreateNote("C"))
+		}, []Activity{{Action: ActionCreate, Title: "C"}}},
+		{"update", func(m *NotesManager, a, b *Note) error {
+			a.Content = "changed"
+			return m.UpdateNote(a)
+		}, []Activity{{Action: ActionUpdate, Title: "A"}}},
+		{"delete", func(m *NotesManager, a, b *Note) error {
+			return m.DeleteNote(b.ID)
+		}, []Activity{{Action: ActionDelete, Title: "B"}}},
+		{"tag and edit at once", func(m *NotesManager, a, b *Note) error {
+			a.Content = "changed"
+			a.Tags = []string{"work"}
+			return m.UpdateNote(a)
+		}, []Activity{{Action: ActionTag, Title: "A", Detail: "+work -home"}, {Action: ActionUpdate, Title: "A"}}},
+		{"rename a tag", func(m *NotesManager, a, b *Note) error {
+			_, err := m.RenameTag("home", "house", false)
+			return err
+		}, []Activity{
+			{Action: ActionTag, Title: "A", Detail: "+house -home"},
+			{Action: ActionTag, Title: "B", Detail: "+house -home"},
+		}},
+		{"dry run of a renaming", func(m *NotesManager, a, b *Note) error {
+			_, err := m.RenameTag("home", "house", true)
+			return err
+		}, nil},
+		{"archive", func(m *NotesManager, a, b *Note) error {
+			b.UpdatedAt = b.UpdatedAt.AddDate(-1, 0, 0)
+			_, err := m.AutoArchive(24 * time.Hour)
+			return err
+		}, []Activity{{Action: ActionArchive, Title: "B", Detail: "untouched for too long"}}},
+		{"unarchive", func(m *NotesManager, a, b *Note) error {
+			b.SetArchived(true)
+			if err := m.UpdateNote(b); err != nil {
+				return err
+			}
+			b.SetArchived(false)
+			return m.UpdateNote(b)
+		}, []Activity{{Action: ActionArchive, Title: "B"}, {Action: ActionUnarchive, Title: "B"}}},
+		{"save without changes", func(m *NotesManager, a, b *Note) error {
+			return m.SaveNotes()
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t, "A", "B")
+			a, _ := m.FindByTitle("A")
+			b, _ := m.FindByTitle("B")
+			for _, note := range []*Note{a, b} {
+				note.Tags = []string{"home"}
+				if err := m.UpdateNote(note); err != nil {
+					t.Fatal(err)
+				}
+			}
+			before, err := m.ReadActivity(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.run(m, a, b); err != nil {
+				t.Fatal(err)
+			}
+			entries, err := m.ReadActivity(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			added := entries[:len(entries)-len(before)]
+			slices.Reverse(added)
+			got := []Activity{}
+			for _, entry := range added {
+				got = append(got, Activity{Action: entry.Action, Title: entry.Title, Detail: entry.Detail})
+			}
+			slices.SortStableFunc(got, func(x, y Activity) int { return strings.Compare(x.Title, y.Title) })
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("logged %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HasAlias reports whether name is one of the aliases of the note, ignoring
+// case and whitespace differences
+func (n *Note) HasAlias(name string) bool {
+	normalized := normalizeTitle(name)
+	for _, alias := range n.Aliases {
+		if normalizeTitle(alias) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAliases replaces the aliases of a note and saves it. An alias can't be
+// the title or an alias of another note, since links to it would be ambiguous.
+func (m *NotesManager) SetAliases(note *Note, aliases []string) error {
+	cleaned := []string{}
+	seen := map[string]bool{}
+	for _, alias := range aliases {
+		alias = strings.Join(strings.Fields(alias), " ")
+		normalized := normalizeTitle(alias)
+		if alias == "" || seen[normalized] || normalized == normalizeTitle(note.Title) {
+			continue
+		}
+		seen[normalized] = true
+
+		for _, other := range m.Notes {
+			if other.ID == note.ID {
+				continue
+			}
+			if normalizeTitle(other.Title) == normalized || other.HasAlias(alias) {
+				return fmt.Errorf("%q is already used by the note %q", alias, other.Title)
+			}
+		}
+		cleaned = append(cleaned, alias)
+	}
+
+	note.Aliases = cleaned
+	return m.UpdateNote(note)
+}
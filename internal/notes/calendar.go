@@ -0,0 +1,17 @@
+package notes
+
+import "time"
+
+// NotesByDay groups the notes created during the month of the given time by
+// day of the month. Creation dates are taken in the location of month, so
+// that a note belongs to the day it was written on where it is displayed.
+func (m *NotesManager) NotesByDay(month time.Time) map[int][]*Note {
+	days := map[int][]*Note{}
+	for _, note := range m.Notes {
+		created := note.CreatedAt.In(month.Location())
+		if created.Year() == month.Year() && created.Month() == month.Month() {
+			days[created.Day()] = append(days[created.Day()], note)
+		}
+	}
+	return days
+}
@@ -0,0 +1,62 @@
+package notes
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNotesByDay(t *testing.T) {
+	paris := time.FixedZone("CET", 60*60)
+	m := OpenNotesManager(t.TempDir())
+	for _, note := range []struct {
+		title   string
+		created time.Time
+	}{
+		{"Last of January", time.Date(2024, 1, 31, 23, 30, 0, 0, time.UTC)}, // February 1st in Paris
+		{"First of February", time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC)},
+		{"Late evening", time.Date(2024, 2, 29, 23, 30, 0, 0, time.UTC)}, // March 1st in Paris
+		{"End of year", time.Date(2023, 12, 31, 12, 0, 0, 0, time.UTC)},
+		{"Next year", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+	} {
+		m.CreateNote(note.title).CreatedAt = note.created
+	}
+
+	tests := []struct {
+		name  string
+		month time.Time
+		want  map[int][]string
+	}{
+		{"UTC", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), map[int][]string{
+			1:  {"First of February"},
+			29: {"Late evening"},
+		}},
+		{"Paris", time.Date(2024, 2, 10, 0, 0, 0, 0, paris), map[int][]string{
+			1: {"Last of January", "First of February"},
+		}},
+		{"previous month", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), map[int][]string{
+			1:  {"Next year"},
+			31: {"Last of January"},
+		}},
+		{"previous year", time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), map[int][]string{
+			31: {"End of year"},
+		}},
+		{"empty month", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), map[int][]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days := m.NotesByDay(tt.month)
+			if len(days) != len(tt.want) {
+				t.Errorf("notes on %d day(s), want %d", len(days), len(tt.want))
+			}
+			for day, want := range tt.want {
+				got := titlesOf(days[day])
+				slices.Sort(got)
+				slices.Sort(want)
+				if !slices.Equal(got, want) {
+					t.Errorf("day %d holds %q, want %q", day, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package notes
+
+import (
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fileState is what a write to a file changes
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// storeState returns the state of every file of the store by path
+func storeState(t *testing.T, dir string) map[string]fileState {
+	t.Helper()
+	state := map[string]fileState{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		state[path] = fileState{info.ModTime(), info.Size()}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+// ageStore moves the modification time of every file of the store back in
+// time, so that a write shows even on file systems with coarse timestamps
+func ageStore(t *testing.T, dir string) {
+	t.Helper()
+	past := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for path := range storeState(t, dir) {
+		if err := os.Chtimes(path, past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// newDryRunManager returns a manager over a store holding two notes, an
+// image and a snapshot, aged and then opened for a dry run
+func newDryRunManager(t *testing.T) *NotesManager {
+	t.Helper()
+	m := newTestManager(t, "A", "B")
+	picture := filepath.Join(t.TempDir(), "picture.png")
+	if err := os.WriteFile(picture, []byte("picture"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.ImportImage(m.Notes[0].ID, picture, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	ageStore(t, m.StoragePath)
+
+	m.DryRun = true
+	m.Snapshots = SnapshotPolicy{Daily: 1}
+	return m
+}
+
+func TestStoreStateSeesWrites(t *testing.T) {
+	m := newTestManager(t, "A")
+	ageStore(t, m.StoragePath)
+	before := storeState(t, m.StoragePath)
+	if after := storeState(t, m.StoragePath); !maps.Equal(before, after) {
+		t.Fatal("state changed while nothing was written")
+	}
+
+	note := m.Notes[0]
+	note.Content = "changed"
+	if err := m.UpdateNote(note); err != nil {
+		t.Fatal(err)
+	}
+	notesFile := filepath.Join(m.StoragePath, "notes.json")
+	if after := storeState(t, m.StoragePath); after[notesFile] == before[notesFile] {
+		t.Error("saving a note left notes.json as it was")
+	}
+}
+
+func TestDryRunWritesNothing(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(m *NotesManager) error
+	}{
+		{"update", func(m *NotesManager) error {
+			m.Notes[0].Content = "changed"
+			return m.UpdateNote(m.Notes[0])
+		}},
+		{"create", func(m *NotesManager) error {
+			return m.UpdateNote(m.CreateNote("C"))
+		}},
+		{"delete", func(m *NotesManager) error {
+			return m.DeleteNote(m.Notes[0].ID)
+		}},
+		{"tags", func(m *NotesManager) error {
+			m.Notes[0].Tags = []string{"Work"}
+			if err := m.UpdateNote(m.Notes[0]); err != nil {
+				return err
+			}
+			_, err := m.RenameTag("Work", "job", false)
+			return err
+		}},
+		{"image", func(m *NotesManager) error {
+			picture := filepath.Join(filepath.Dir(m.StoragePath), "other.png")
+			if err := os.WriteFile(picture, []byte("other"), 0644); err != nil {
+				return err
+			}
+			_, err := m.ImportImage(m.Notes[1].ID, picture, "", "")
+			return err
+		}},
+		{"snapshot", func(m *NotesManager) error {
+			_, err := m.Snapshot()
+			return err
+		}},
+		{"restore", func(m *NotesManager) error {
+			snapshots, err := m.ListSnapshots()
+			if err != nil {
+				return err
+			}
+			_, err = m.RestoreNote(snapshots[0].Name, m.Notes[0].ID)
+			return err
+		}},
+		{"archive", func(m *NotesManager) error {
+			_, err := m.AutoArchive(time.Nanosecond)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newDryRunManager(t)
+			before := storeState(t, m.StoragePath)
+			if err := tt.run(m); err != nil {
+				t.Fatal(err)
+			}
+			after := storeState(t, m.StoragePath)
+			for path, state := range after {
+				if previous, ok := before[path]; !ok {
+					t.Errorf("%s created by a dry run", path)
+				} else if state != previous {
+					t.Errorf("%s written by a dry run", path)
+				}
+			}
+			for path := range before {
+				if _, ok := after[path]; !ok {
+					t.Errorf("%s deleted by a dry run", path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package notes
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ShortTitle returns a title on a single line fitting in width columns, its
+// end replaced by an ellipsis when it's longer. Wide characters, such as CJK
+// ones, take two columns.
+func ShortTitle(title string, width int) string {
+	return ansi.Truncate(strings.Join(strings.Fields(title), " "), max(width, 1), "…")
+}
+
+// elideMiddle shortens a name to at most size bytes by replacing its middle
+// with an ellipsis, keeping its beginning and its end, which tell names
+// apart better than their middle does
+func elideMiddle(name string, size int) string {
+	const ellipsis = "…"
+	if len(name) <= size {
+		return name
+	}
+	room := size - len(ellipsis)
+	head, tail := name[:room*2/3], name[len(name)-(room-room*2/3):]
+	// Neither part ends in the middle of a character, nor of a word when a
+	// dash between words is close enough
+	for !utf8.ValidString(head) {
+		head = head[:len(head)-1]
+	}
+	for !utf8.ValidString(tail) {
+		tail = tail[1:]
+	}
+	if i := strings.LastIndex(head, "-"); i > len(head)/2 {
+		head = head[:i]
+	}
+	if i := strings.Index(tail, "-"); i >= 0 && i < len(tail)/2 {
+		tail = tail[i+1:]
+	}
+	return strings.TrimRight(head, "-") + ellipsis + strings.TrimLeft(tail, "-")
+}
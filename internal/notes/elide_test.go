@@ -0,0 +1,61 @@
+package notes
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestShortTitle(t *testing.T) {
+	cjk := "会議メモの長い\nタイトル"
+	tests := []struct {
+		title string
+		width int
+		want  string
+	}{
+		{"Plan", 10, "Plan"},
+		{"Plan  for\tthe  trip", 10, "Plan for …"},
+		// Wide characters take two columns and are never cut in half
+		{cjk, 30, "会議メモの長い タイトル"},
+		{cjk, 23, "会議メモの長い タイトル"},
+		{cjk, 22, "会議メモの長い タイト…"},
+		{cjk, 10, "会議メモ…"},
+		{cjk, 9, "会議メモ…"},
+		{cjk, 3, "会…"},
+		{cjk, 2, "…"},
+		{cjk, 0, "…"},
+	}
+	for _, tt := range tests {
+		got := ShortTitle(tt.title, tt.width)
+		if got != tt.want {
+			t.Errorf("ShortTitle(%q, %d) = %q, want %q", tt.title, tt.width, got, tt.want)
+		}
+		if width := ansi.StringWidth(got); width > max(tt.width, 1) {
+			t.Errorf("ShortTitle(%q, %d) takes %d columns", tt.title, tt.width, width)
+		}
+	}
+}
+
+func TestElideMiddle(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		want string
+	}{
+		{"short-name", 80, "short-name"},
+		{"one-two-three-four-five-six-seven", 20, "one-two…seven"},
+		{"日本語-日本語-日本語-日本語-日本語-日本語-終わり", 30, "日本語…終わり"},
+		{strings.Repeat("語", 40), 31, strings.Repeat("語", 6) + "…" + strings.Repeat("語", 3)},
+	}
+	for _, tt := range tests {
+		got := elideMiddle(tt.name, tt.size)
+		if got != tt.want {
+			t.Errorf("elideMiddle(%q, %d) = %q, want %q", tt.name, tt.size, got, tt.want)
+		}
+		if len(got) > tt.size || !utf8.ValidString(got) {
+			t.Errorf("elideMiddle(%q, %d) = %q, too long or cut in a character", tt.name, tt.size, got)
+		}
+	}
+}
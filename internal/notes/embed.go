@@ -0,0 +1,64 @@
+package notes
+
+import (
+	"regexp"
+	"strings"
+)
+
+// embedRegex matches a ![[Title]] embed written on a line of its own
+var embedRegex = regexp.MustCompile(`^\s*!\[\[([^\[\]|]+)\]\]\s*$`)
+
+// EmbedPart is a part of a note cut around its embeds, either text or the
+// title of an embedded note
+type EmbedPart struct {
+	Text   string
+	Target string // Title of the embedded note, empty for text
+}
+
+// SplitEmbeds cuts content around its embeds, ![[Title]] lines outside code
+// blocks standing for the whole content of another note
+func SplitEmbeds(content string) []EmbedPart {
+	if !strings.Contains(content, "![[") {
+		return []EmbedPart{{Text: content}}
+	}
+
+	parts := []EmbedPart{}
+	text := []string{}
+	fence := ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		default:
+			if match := embedRegex.FindStringSubmatch(line); match != nil {
+				if len(text) > 0 {
+					parts = append(parts, EmbedPart{Text: strings.Join(text, "\n")})
+					text = []string{}
+				}
+				parts = append(parts, EmbedPart{Target: strings.TrimSpace(match[1])})
+				continue
+			}
+		}
+		text = append(text, line)
+	}
+	if len(text) > 0 {
+		parts = append(parts, EmbedPart{Text: strings.Join(text, "\n")})
+	}
+	return parts
+}
+
+// EmbedTargets returns the titles of the notes embedded in content
+func EmbedTargets(content string) []string {
+	targets := []string{}
+	for _, part := range SplitEmbeds(content) {
+		if part.Target != "" {
+			targets = append(targets, part.Target)
+		}
+	}
+	return targets
+}
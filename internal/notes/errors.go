@@ -0,0 +1,28 @@
+package notes
+
+import (
+	"errors"
+	"io/fs"
+	"syscall"
+)
+
+// ErrNoteNotFound is returned when no note has the ID or title looked for
+var ErrNoteNotFound = errors.New("note not found")
+
+// ErrDuplicateID is returned when notes given to the manager share an ID
+var ErrDuplicateID = errors.New("several notes share the same ID")
+
+// ErrReadOnly is returned when the store can be read but not written
+var ErrReadOnly = errors.New("the store is read-only")
+
+// ErrStorageCorrupt is returned when the notes file can't be decoded
+var ErrStorageCorrupt = errors.New("the notes file is corrupt")
+
+// ErrImageNotFound is returned when an image file to add doesn't exist
+var ErrImageNotFound = errors.New("image not found")
+
+// readOnly reports whether a write failed because the store can't be
+// written, as opposed to a transient failure
+func readOnly(err error) bool {
+	return errors.Is(err, syscall.EROFS) || errors.Is(err, fs.ErrPermission)
+}
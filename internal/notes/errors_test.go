@@ -0,0 +1,97 @@
+package notes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestErrorKinds(t *testing.T) {
+	m := newTestManager(t, "Note")
+	note := m.Notes[0]
+	corrupt := t.TempDir()
+	if err := os.WriteFile(filepath.Join(corrupt, "notes.json"), []byte("[{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"GetNoteByID", func() error { _, err := m.GetNoteByID("missing"); return err }(), ErrNoteNotFound},
+		{"FindByTitle", func() error { _, err := m.FindByTitle("Missing"); return err }(), ErrNoteNotFound},
+		{"DeleteNote", m.DeleteNote("missing"), ErrNoteNotFound},
+		{"ImportImage into a missing note", func() error { _, err := m.ImportImage("missing", "a.png", "", ""); return err }(), ErrNoteNotFound},
+		{"ImportImage of a missing file", func() error {
+			_, err := m.ImportImage(note.ID, filepath.Join(t.TempDir(), "a.png"), "", "")
+			return err
+		}(), ErrImageNotFound},
+		{"MergeNotes", func() error {
+			_, err := m.MergeNotes([]*Note{note, note}, nil, baseOf(m))
+			return err
+		}(), ErrDuplicateID},
+		{"NewNotesManager", func() error { _, err := NewNotesManager(corrupt); return err }(), ErrStorageCorrupt},
+	}
+	sentinels := []error{ErrNoteNotFound, ErrImageNotFound, ErrDuplicateID, ErrStorageCorrupt, ErrReadOnly, ErrStorageNotDir, ErrStoragePermission}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.err, tt.want)
+		}
+		// The kinds are told apart
+		for _, other := range sentinels {
+			if other != tt.want && errors.Is(tt.err, other) {
+				t.Errorf("%s = %v, also %v", tt.name, tt.err, other)
+			}
+		}
+	}
+}
+
+func TestErrorKindsKeepCause(t *testing.T) {
+	corrupt := t.TempDir()
+	if err := os.WriteFile(filepath.Join(corrupt, "notes.json"), []byte("[{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewNotesManager(corrupt)
+	var syntax *json.SyntaxError
+	if !errors.Is(err, ErrStorageCorrupt) || !errors.As(err, &syntax) {
+		t.Errorf("NewNotesManager = %v, want ErrStorageCorrupt wrapping the JSON error", err)
+	}
+}
+
+func TestReadOnlyStore(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permissions don't apply to root")
+	}
+	m := newTestManager(t, "Note")
+	if err := os.Chmod(m.StoragePath, 0500); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(m.StoragePath, 0755) })
+	if err := m.UpdateNote(m.Notes[0]); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("UpdateNote in a read-only store = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&fs.PathError{Op: "open", Path: "p", Err: syscall.EROFS}, true},
+		{&fs.PathError{Op: "open", Path: "p", Err: syscall.EACCES}, true},
+		{fmt.Errorf("rename: %w", syscall.EPERM), true},
+		{&fs.PathError{Op: "write", Path: "p", Err: syscall.ENOSPC}, false},
+		{errors.New("other"), false},
+	}
+	for _, tt := range tests {
+		if got := readOnly(tt.err); got != tt.want {
+			t.Errorf("readOnly(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package notes
+
+import "sync"
+
+// eventBuffer is how many events a subscriber can fall behind before the
+// next ones are dropped
+const eventBuffer = 64
+
+// EventKind is the kind of change of a note
+type EventKind int
+
+// Changes of notes sent to the subscribers
+const (
+	NoteCreated EventKind = iota
+	NoteUpdated
+	NoteDeleted
+)
+
+// Event tells that a note was saved or deleted
+type Event struct {
+	Kind   EventKind
+	NoteID string
+}
+
+// events holds the channels of the subscribers to the changes of the notes
+type events struct {
+	mu          sync.Mutex
+	subscribers []chan Event
+}
+
+// Subscribe returns a channel receiving an event for each note created,
+// updated or deleted, once the change is saved. Bookkeeping saves, of the
+// manual order, review dates and editing time, aren't sent.
+//
+// A subscriber falling too far behind misses events, so that the manager
+// never waits for it: events tell that the notes changed, the notes
+// themselves hold what changed.
+func (m *NotesManager) Subscribe() <-chan Event {
+	ch := make(chan Event, eventBuffer)
+	m.events.mu.Lock()
+	defer m.events.mu.Unlock()
+	m.events.subscribers = append(m.events.subscribers, ch)
+	return ch
+}
+
+// Unsubscribe stops the events sent to a channel returned by Subscribe and
+// closes it
+func (m *NotesManager) Unsubscribe(ch <-chan Event) {
+	m.events.mu.Lock()
+	defer m.events.mu.Unlock()
+	for i, sub := range m.events.subscribers {
+		if sub == ch {
+			m.events.subscribers = append(m.events.subscribers[:i], m.events.subscribers[i+1:]...)
+			close(sub)
+			return
+		}
+	}
+}
+
+// publish sends an event to every subscriber with room for it
+func (m *NotesManager) publish(kind EventKind, note *Note) {
+	m.events.mu.Lock()
+	defer m.events.mu.Unlock()
+	for _, ch := range m.events.subscribers {
+		select {
+		case ch <- Event{Kind: kind, NoteID: note.ID}:
+		default:
+		}
+	}
+}
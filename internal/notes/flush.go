@@ -0,0 +1,51 @@
+package notes
+
+import "time"
+
+// heldNote is a note updated while the writing of the store is held
+// back
+type heldNote struct {
+	stored time.Time // Update date of the note in the notes file
+	saved  time.Time // Update date given by the last update held back
+}
+
+// save writes the store after a note changed, or leaves it to Flush when
+// SaveDelay is set. previous is the update date the note had before the
+// change.
+func (m *NotesManager) save(note *Note, previous time.Time) error {
+	if m.SaveDelay <= 0 {
+		return m.SaveNotes()
+	}
+	m.holdBack(note, previous)
+	return nil
+}
+
+// holdBack records an update of a note left for Flush. previous is the update
+// date the note had before it: the one in the notes file for a note not held
+// back yet, or the one of a stored version the note was rebased on to resolve
+// a conflict.
+func (m *NotesManager) holdBack(note *Note, previous time.Time) {
+	if m.unwritten == nil {
+		m.unwritten = map[string]heldNote{}
+	}
+	held, ok := m.unwritten[note.ID]
+	if !ok || !previous.Equal(held.saved) {
+		held.stored = previous
+	}
+	held.saved = note.UpdatedAt
+	m.unwritten[note.ID] = held
+}
+
+// Unwritten reports whether updates are waiting for Flush to be written
+func (m *NotesManager) Unwritten() bool {
+	return len(m.unwritten) > 0
+}
+
+// Flush writes the store when updates were held back by SaveDelay, grouping
+// all of them into a single write. Any other save writes them as well.
+func (m *NotesManager) Flush() error {
+	if !m.Unwritten() {
+		return nil
+	}
+	return m.SaveNotes()
+}
@@ -0,0 +1,96 @@
+package notes
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveDelayGroupsWrites(t *testing.T) {
+	m := newTestManager(t, "A", "B")
+	m.SaveDelay = time.Second
+	ageStore(t, m.StoragePath)
+	notesFile := filepath.Join(m.StoragePath, "notes.json")
+	before := storeState(t, m.StoragePath)[notesFile]
+
+	note := m.Notes[0]
+	for i := range 50 {
+		note.Content = fmt.Sprint("edit ", i)
+		if err := m.UpdateNote(note); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if storeState(t, m.StoragePath)[notesFile] != before {
+		t.Fatal("notes.json written before Flush")
+	}
+	if !m.Unwritten() {
+		t.Fatal("no update waiting for Flush")
+	}
+
+	if err := m.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if m.Unwritten() {
+		t.Error("updates still waiting after Flush")
+	}
+	if storeState(t, m.StoragePath)[notesFile] == before {
+		t.Error("notes.json not written by Flush")
+	}
+	if stored, err := m.StoredNote(note.ID); err != nil || stored == nil || stored.Content != "edit 49" {
+		t.Errorf("notes file holds %+v, %v after Flush, want the last edit", stored, err)
+	}
+}
+
+func TestSaveWithoutDelayWritesAtOnce(t *testing.T) {
+	m := newTestManager(t, "A")
+	note := m.Notes[0]
+	note.Content = "edit"
+	if err := m.UpdateNote(note); err != nil {
+		t.Fatal(err)
+	}
+	if m.Unwritten() {
+		t.Error("update held back without a save delay")
+	}
+	if stored, err := m.StoredNote(note.ID); err != nil || stored == nil || stored.Content != "edit" {
+		t.Errorf("notes file holds %+v, %v, want the edit", stored, err)
+	}
+}
+
+// benchmarkEdits updates a note of a store of 1,000 notes as a burst of 20
+// keys would, writing the store once per burst with a save delay and on each
+// key without
+func benchmarkEdits(b *testing.B, delay time.Duration) {
+	m := OpenNotesManager(b.TempDir())
+	ns := make([]*Note, 1000)
+	for i := range ns {
+		ns[i] = NewNote(fmt.Sprint("Note ", i))
+		ns[i].Content = fmt.Sprintf("Content of note %d, long enough to look like a real one.\n", i)
+	}
+	if _, err := m.AddNotes(ns); err != nil {
+		b.Fatal(err)
+	}
+	m.SaveDelay = delay
+	note := m.Notes[0]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for key := range 20 {
+			note.Content += string(rune('a' + key))
+			if err := m.UpdateNote(note); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := m.Flush(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEditWriteEachSave(b *testing.B) {
+	benchmarkEdits(b, 0)
+}
+
+func BenchmarkEditWriteDelayed(b *testing.B) {
+	benchmarkEdits(b, time.Second)
+}
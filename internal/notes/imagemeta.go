@@ -0,0 +1,91 @@
+package notes
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+)
+
+// FillImageInfo records the dimensions and file size of the images of a note
+// that have none yet, reading the header of their stored file. Images missing
+// from the store are left as they are. It reports whether an image changed.
+func (m *NotesManager) FillImageInfo(note *Note) bool {
+	changed := false
+	for i := range note.Images {
+		if note.Images[i].Size > 0 {
+			continue
+		}
+		if m.fillImageInfo(&note.Images[i]) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// fillImageInfo records the dimensions and file size of an image from its
+// stored file, the size alone when its format can't be decoded
+func (m *NotesManager) fillImageInfo(img *Image) bool {
+	path := m.GetImageFullPath(img.Path)
+	info, err := os.Stat(path)
+	if err != nil || info.Size() == 0 {
+		return false
+	}
+	img.Size = info.Size()
+	img.Width, img.Height, _ = imageConfig(path)
+	return true
+}
+
+// imageConfig decodes the dimensions in the header of an image file. It
+// reports false when the file can't be read or isn't a GIF, JPEG or PNG
+// image.
+func imageConfig(path string) (width, height int, ok bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return 0, 0, false
+	}
+	return config.Width, config.Height, true
+}
+
+// BackfillImageInfo records the dimensions and file size of the images of a
+// note imported before they were, and saves the note when any was. The update
+// date of the note is kept, as nothing it holds was edited.
+func (m *NotesManager) BackfillImageInfo(note *Note) error {
+	if !m.FillImageInfo(note) {
+		return nil
+	}
+	return m.save(note, note.UpdatedAt)
+}
+
+// Info describes the dimensions and file size of an image, such as
+// "1920×1080, 420 KB", empty when they weren't recorded
+func (img Image) Info() string {
+	if img.Size == 0 {
+		return ""
+	}
+	if img.Width == 0 || img.Height == 0 {
+		return FormatSize(img.Size)
+	}
+	return fmt.Sprintf("%d×%d, %s", img.Width, img.Height, FormatSize(img.Size))
+}
+
+// FormatSize returns a size in bytes in the largest unit it reaches, KB and MB
+// counting 1024 of the unit below
+func FormatSize(size int64) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	case size >= 1024:
+		return fmt.Sprintf("%d KB", (size+512)/1024)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -0,0 +1,95 @@
+package notes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrInvalidNoteJSON is returned when the data given to ImportNoteJSON isn't
+// a note
+var ErrInvalidNoteJSON = errors.New("invalid note JSON")
+
+// noteJSON is a note as exported by ExportNoteJSON: the note with the full
+// path of its images, so that importing it into another store can copy them
+type noteJSON struct {
+	*Note
+	Images []imageJSON `json:"images,omitempty"`
+}
+
+// imageJSON is an image of an exported note
+type imageJSON struct {
+	Image
+	File string `json:"file,omitempty"` // Full path of the stored image, empty when it is missing
+}
+
+// ExportNoteJSON returns a note as indented JSON, its fields as they are
+// stored along with the full path of each of its images
+func (m *NotesManager) ExportNoteJSON(id string) ([]byte, error) {
+	note, err := m.GetNoteByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := noteJSON{Note: note, Images: []imageJSON{}}
+	for _, image := range note.Images {
+		file := ""
+		if m.ImageExists(image.Path) {
+			file = m.GetImageFullPath(image.Path)
+		}
+		exported.Images = append(exported.Images, imageJSON{Image: image, File: file})
+	}
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode note: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// ImportNoteJSON adds the note exported by ExportNoteJSON to the store and
+// returns it. The note gets a new ID, so that importing it twice or into its
+// own store makes a copy, and loses its gist, which stays with the original.
+// Images whose file still exists are copied into the store; the others are
+// kept as references, reported missing as they would be in the original.
+func (m *NotesManager) ImportNoteJSON(data []byte) (*Note, error) {
+	imported := noteJSON{Note: &Note{}}
+	if err := json.Unmarshal(data, &imported); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidNoteJSON, err)
+	}
+	if imported.Note.ID == "" && imported.Title == "" && imported.Content == "" {
+		return nil, fmt.Errorf("%w: no ID, title or content", ErrInvalidNoteJSON)
+	}
+
+	note := imported.Note
+	note.ID = m.uniqueID()
+	note.Gist = nil
+	if note.CreatedAt.IsZero() {
+		note.CreatedAt = time.Now()
+	}
+	if note.UpdatedAt.IsZero() {
+		note.UpdatedAt = note.CreatedAt
+	}
+	if note.Tags == nil {
+		note.Tags = []string{}
+	}
+
+	note.Images = []Image{}
+	for _, image := range imported.Images {
+		if _, err := os.Stat(image.File); image.File != "" && err == nil {
+			stored, err := m.StoreImage(image.File)
+			if err != nil {
+				return nil, err
+			}
+			image.Path = stored
+		}
+		note.Images = append(note.Images, image.Image)
+	}
+	m.FillImageInfo(note)
+
+	if _, err := m.AddNotes([]*Note{note}); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
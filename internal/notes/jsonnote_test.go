@@ -0,0 +1,112 @@
+package notes
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNoteJSONRoundTrip(t *testing.T) {
+	source := newTestManager(t)
+	picture := filepath.Join(t.TempDir(), "picture.png")
+	if err := os.WriteFile(picture, []byte("not really a PNG"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := source.StoreImage(picture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	note := NewNote("Plan")
+	note.Content = "# Plan\n\n![map](" + stored + ")\n"
+	note.Tags = []string{"work", "trip"}
+	note.Aliases = []string{"Journey"}
+	note.Pinned = true
+	note.CreatedAt = created
+	note.UpdatedAt = created.Add(time.Hour)
+	note.TimeSpent = 5 * time.Minute
+	note.Images = []Image{{ID: "img", Path: stored, Caption: "map", AltText: "the map"}}
+	note.Gist = &GistRef{ID: "g", URL: "https://gist.github.com/g", File: "plan.md"}
+	if _, err := source.AddNotes([]*Note{note}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := source.ExportNoteJSON(note.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := newTestManager(t)
+	imported, err := target.ImportNoteJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.ID == note.ID {
+		t.Error("imported note kept its ID")
+	}
+	if got, err := target.GetNoteByID(imported.ID); err != nil || got != imported {
+		t.Errorf("imported note not found by its ID: %v", err)
+	}
+	if imported.Title != note.Title || imported.Content != note.Content {
+		t.Errorf("imported %q %q, want %q %q", imported.Title, imported.Content, note.Title, note.Content)
+	}
+	if !slices.Equal(imported.Tags, note.Tags) || !slices.Equal(imported.Aliases, note.Aliases) {
+		t.Errorf("imported tags %v and aliases %v, want %v and %v", imported.Tags, imported.Aliases, note.Tags, note.Aliases)
+	}
+	if !imported.Pinned || imported.TimeSpent != note.TimeSpent {
+		t.Errorf("imported pinned %v and time spent %v", imported.Pinned, imported.TimeSpent)
+	}
+	if !imported.CreatedAt.Equal(note.CreatedAt) || !imported.UpdatedAt.Equal(note.UpdatedAt) {
+		t.Errorf("imported dates %v %v, want %v %v", imported.CreatedAt, imported.UpdatedAt, note.CreatedAt, note.UpdatedAt)
+	}
+	if imported.Gist != nil {
+		t.Error("imported note kept the gist of the original")
+	}
+	if len(imported.Images) != 1 || imported.Images[0].Path != stored || imported.Images[0].Caption != "map" {
+		t.Fatalf("imported images %+v", imported.Images)
+	}
+	if !target.ImageExists(stored) {
+		t.Error("image not copied into the store")
+	}
+}
+
+func TestImportNoteJSONTwice(t *testing.T) {
+	m := newTestManager(t, "Plan")
+	data, err := m.ExportNoteJSON(m.Notes[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := m.ImportNoteJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := m.ImportNoteJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]bool{}
+	for _, note := range m.Notes {
+		ids[note.ID] = true
+	}
+	if len(m.Notes) != 3 || len(ids) != 3 {
+		t.Errorf("%d note(s) with %d ID(s) after importing twice, want 3 and 3", len(m.Notes), len(ids))
+	}
+	if first.ID == second.ID {
+		t.Errorf("both copies have ID %s", first.ID)
+	}
+}
+
+func TestImportNoteJSONInvalid(t *testing.T) {
+	m := newTestManager(t)
+	for _, data := range []string{"", "[]", "{}", `{"title": 3}`} {
+		if _, err := m.ImportNoteJSON([]byte(data)); !errors.Is(err, ErrInvalidNoteJSON) {
+			t.Errorf("ImportNoteJSON(%q) = %v, want ErrInvalidNoteJSON", data, err)
+		}
+	}
+	if len(m.Notes) != 0 {
+		t.Errorf("%d note(s) added by invalid data", len(m.Notes))
+	}
+}
@@ -1,12 +1,17 @@
 package notes
 
 import (
+	"crypto/sha256"
+	"datapad/internal/expand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -17,24 +22,30 @@ type NotesManager struct {
 	Notes       []*Note
 	StoragePath string
 	ImageDir    string
+	DefaultTags []string       // Tags given to every new note
+	Snapshots   SnapshotPolicy // Daily snapshots of the store, off when zero
+	ManualOrder bool           // Notes are sorted by their Order rather than by update date
+	DryRun      bool           // Changes stay in memory, nothing is written to the store
+	TidyContent bool           // Saved content loses its trailing whitespace, see NormalizeWhitespace
+	OnActivity  func(Activity) // Called for each change of a note, before it is logged
+	SaveDelay   time.Duration  // UpdateNote leaves the writing of the store to Flush, 0 writes at once
+
+	titleIndex map[string][]*Note    // Notes by title slug, rebuilt on demand
+	logged     map[string]loggedNote // Notes as last saved, to log their changes
+	events     events                // Subscribers to the changes of the notes
+	unwritten  map[string]heldNote   // Notes updated since the store was last written, by ID
 }
 
 // NewNotesManager creates a new notes manager
 func NewNotesManager(storagePath string) (*NotesManager, error) {
+	manager := OpenNotesManager(storagePath)
+
 	// Create storage directory if it doesn't exist
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		return nil, fmt.Errorf("unable to create storage directory: %w", err)
+	if err := manager.ensureDir(manager.StoragePath); err != nil {
+		return nil, err
 	}
-
-	imageDir := filepath.Join(storagePath, "images")
-	if err := os.MkdirAll(imageDir, 0755); err != nil {
-		return nil, fmt.Errorf("unable to create images directory: %w", err)
-	}
-
-	manager := &NotesManager{
-		Notes:       []*Note{},
-		StoragePath: storagePath,
-		ImageDir:    imageDir,
+	if err := manager.ensureDir(manager.ImageDir); err != nil {
+		return nil, err
 	}
 
 	// Load existing notes
@@ -46,13 +57,117 @@ func NewNotesManager(storagePath string) (*NotesManager, error) {
 	return manager, nil
 }
 
+// OpenNotesManager returns a manager for the store without touching the
+// disk: LoadNotes reads the notes and the first save creates the directories
+func OpenNotesManager(storagePath string) *NotesManager {
+	return &NotesManager{
+		Notes:       []*Note{},
+		StoragePath: storagePath,
+		ImageDir:    filepath.Join(storagePath, "images"),
+	}
+}
+
 // CreateNote creates a new note and adds it to the manager
 func (m *NotesManager) CreateNote(title string) *Note {
 	note := NewNote(title)
+	note.ID = m.uniqueID()
+	for _, tag := range m.DefaultTags {
+		if tag = strings.TrimSpace(tag); tag != "" && !hasTag(note, tag) {
+			note.AddTag(tag)
+		}
+	}
 	m.Notes = append(m.Notes, note)
+	m.titleIndex = nil
 	return note
 }
 
+// AddNotes adds notes built elsewhere, such as imported ones, keeping their
+// dates, and saves the store once. Notes whose ID is taken get a new one, and
+// the returned remap tells which.
+func (m *NotesManager) AddNotes(ns []*Note) (IDRemap, error) {
+	if len(ns) == 0 {
+		return IDRemap{}, nil
+	}
+	remap := m.remapIDs(ns)
+	m.Notes = append(m.Notes, ns...)
+	m.titleIndex = nil
+	if err := m.SaveNotes(); err != nil {
+		return remap, err
+	}
+	for _, note := range ns {
+		m.logSaved(note, "imported")
+	}
+	return remap, nil
+}
+
+// MergeNotes updates the notes with the same ID as the given ones in place,
+// adds the others and removes the notes listed in deleted, keeping the dates
+// of the given notes. It is used to apply the changes of a synchronization.
+//
+// base holds the update dates of the notes the changes were computed from. A
+// note changed since, or created when it has no date in base, is left alone
+// and its ID returned in skipped, so that an edit made during a sync isn't
+// lost.
+func (m *NotesManager) MergeNotes(ns []*Note, deleted []string, base map[string]time.Time) (skipped []string, err error) {
+	// Merging the same note twice would keep one version at random
+	given := make(map[string]bool, len(ns))
+	for _, note := range ns {
+		if given[note.ID] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateID, note.ID)
+		}
+		given[note.ID] = true
+	}
+	changed := func(note *Note) bool {
+		updated, ok := base[note.ID]
+		return !ok || !note.UpdatedAt.Equal(updated)
+	}
+
+	merged := []*Note{}
+	for _, note := range ns {
+		existing, _ := m.GetNoteByID(note.ID)
+		switch {
+		case existing == nil:
+			m.Notes = append(m.Notes, note)
+		case changed(existing):
+			skipped = append(skipped, note.ID)
+			continue
+		default:
+			// Whoever holds the note, such as the editor, sees the new version
+			*existing = *note
+			note = existing
+		}
+		merged = append(merged, note)
+	}
+
+	removed := []*Note{}
+	for _, id := range deleted {
+		for i, note := range m.Notes {
+			if note.ID != id {
+				continue
+			}
+			if changed(note) {
+				skipped = append(skipped, id)
+			} else {
+				m.Notes = append(m.Notes[:i], m.Notes[i+1:]...)
+				removed = append(removed, note)
+			}
+			break
+		}
+	}
+
+	m.titleIndex = nil
+	if err := m.SaveNotes(); err != nil {
+		return skipped, err
+	}
+	for _, note := range merged {
+		m.logSaved(note, "sync")
+	}
+	for _, note := range removed {
+		m.logDeleted(note, "sync")
+	}
+	return skipped, nil
+}
+
 // GetNoteByID retrieves a note by its ID
 func (m *NotesManager) GetNoteByID(id string) (*Note, error) {
 	for _, note := range m.Notes {
@@ -60,13 +175,65 @@ func (m *NotesManager) GetNoteByID(id string) (*Note, error) {
 			return note, nil
 		}
 	}
-	return nil, errors.New("note not found")
+	return nil, fmt.Errorf("%w: %s", ErrNoteNotFound, id)
+}
+
+// FindByTitle retrieves a note by its title or one of its aliases, ignoring
+// case and whitespace differences. Titles take precedence over aliases.
+func (m *NotesManager) FindByTitle(title string) (*Note, error) {
+	normalized := normalizeTitle(title)
+	for _, note := range m.Notes {
+		if normalizeTitle(note.Title) == normalized {
+			return note, nil
+		}
+	}
+	for _, note := range m.Notes {
+		if note.HasAlias(title) {
+			return note, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrNoteNotFound, title)
+}
+
+// TitleExists reports whether a note already has the title, ignoring case
+// and whitespace differences. Aliases aren't titles and don't count.
+func (m *NotesManager) TitleExists(title string) bool {
+	normalized := normalizeTitle(title)
+	if normalized == "" {
+		return false
+	}
+	for _, note := range m.Notes {
+		if normalizeTitle(note.Title) == normalized {
+			return true
+		}
+	}
+	return false
 }
 
 // UpdateNote updates an existing note
-func (m *NotesManager) UpdateNote(note *Note) {
+func (m *NotesManager) UpdateNote(note *Note) error {
+	return m.updateNote(note, "")
+}
+
+// SavedContent returns content as UpdateNote stores it
+func (m *NotesManager) SavedContent(content string) string {
+	if m.TidyContent {
+		return NormalizeWhitespace(content)
+	}
+	return content
+}
+
+// updateNote updates a note and logs the change with an explanation
+func (m *NotesManager) updateNote(note *Note, detail string) error {
+	note.Content = m.SavedContent(note.Content)
+	previous := note.UpdatedAt
 	note.UpdatedAt = time.Now()
-	m.SaveNotes() // Automatic save after update
+	m.titleIndex = nil // The title may have changed
+	if err := m.save(note, previous); err != nil {
+		return err
+	}
+	m.logSaved(note, detail)
+	return nil
 }
 
 // DeleteNote deletes a note by its ID
@@ -75,103 +242,255 @@ func (m *NotesManager) DeleteNote(id string) error {
 		if note.ID == id {
 			// Remove note from the list
 			m.Notes = append(m.Notes[:i], m.Notes[i+1:]...)
-			return m.SaveNotes()
+			m.titleIndex = nil
+			if err := m.SaveNotes(); err != nil {
+				return err
+			}
+			m.logDeleted(note, "")
+			return nil
 		}
 	}
-	return errors.New("note not found")
+	return fmt.Errorf("%w: %s", ErrNoteNotFound, id)
 }
 
-// SearchNotes searches for notes by title or content
-func (m *NotesManager) SearchNotes(query string) []*Note {
-	if query == "" {
-		return m.Notes
-	}
-
-	query = strings.ToLower(query)
-	results := []*Note{}
+// AutoArchive archives the notes that haven't been updated for longer than
+// olderThan and returns how many were archived. Pinned notes are never archived.
+func (m *NotesManager) AutoArchive(olderThan time.Duration) (int, error) {
+	cutoff := time.Now().Add(-olderThan)
 
+	archived := []*Note{}
 	for _, note := range m.Notes {
-		if strings.Contains(strings.ToLower(note.Title), query) ||
-			strings.Contains(strings.ToLower(note.Content), query) {
-			results = append(results, note)
+		if note.Archived || note.Pinned || !note.UpdatedAt.Before(cutoff) {
+			continue
 		}
+		// The modification date is kept so that archiving doesn't make
+		// the note look recently edited
+		note.SetArchived(true)
+		archived = append(archived, note)
 	}
 
-	return results
+	if len(archived) == 0 {
+		return 0, nil
+	}
+	if err := m.SaveNotes(); err != nil {
+		return len(archived), err
+	}
+	for _, note := range archived {
+		m.logSaved(note, "untouched for too long")
+	}
+	return len(archived), nil
 }
 
-// FilterByTags filters notes by tags
+// FilterByTags returns the notes having at least one of the tags
 func (m *NotesManager) FilterByTags(tags []string) []*Note {
 	if len(tags) == 0 {
 		return m.Notes
 	}
+	return m.FilterByExpr(AnyTag(tags))
+}
 
-	results := []*Note{}
+// ImportImage imports an image into the images directory and adds it to a
+// note, returning its stored name
+func (m *NotesManager) ImportImage(noteID string, sourcePath, caption, altText string) (string, error) {
+	note, err := m.GetNoteByID(noteID)
+	if err != nil {
+		return "", err
+	}
 
-	for _, note := range m.Notes {
-		match := false
-		for _, noteTag := range note.Tags {
-			for _, filterTag := range tags {
-				if noteTag == filterTag {
-					match = true
-					break
-				}
-			}
-			if match {
-				break
-			}
-		}
-		if match {
-			results = append(results, note)
-		}
+	newFilename, err := m.StoreImage(sourcePath)
+	if err != nil {
+		return "", err
 	}
 
-	return results
+	// Add image to the note
+	note.AddImage(newFilename, caption, altText)
+	m.FillImageInfo(note)
+	return newFilename, m.UpdateNote(note)
+}
+
+// ImageImport is the outcome of importing one of the images of a batch
+type ImageImport struct {
+	Path     string // Path given for the image
+	Filename string // Stored name of the image, empty when it failed
+	Err      error
 }
 
-// ImportImage imports an image into the images directory and adds it to a note
-func (m *NotesManager) ImportImage(noteID string, sourcePath, caption, altText string) error {
+// ImportImages imports several images into a note, without caption or alt
+// text, and saves the note once. An image that can't be imported is reported
+// in its result without stopping the others. The returned error is set when
+// the note can't be found or saved, in which case no image was added.
+func (m *NotesManager) ImportImages(noteID string, paths []string) ([]ImageImport, error) {
 	note, err := m.GetNoteByID(noteID)
 	if err != nil {
-		return fmt.Errorf("note not found: %w", err)
+		return nil, err
 	}
 
+	results := make([]ImageImport, len(paths))
+	added := false
+	for i, path := range paths {
+		results[i].Path = path
+		results[i].Filename, results[i].Err = m.StoreImage(path)
+		if results[i].Err != nil {
+			continue
+		}
+		// An image the note already has keeps its caption and alt text
+		if !slices.ContainsFunc(note.Images, func(image Image) bool { return image.Path == results[i].Filename }) {
+			note.AddImage(results[i].Filename, "", "")
+			added = true
+		}
+	}
+
+	if !added {
+		return results, nil
+	}
+	m.FillImageInfo(note)
+	return results, m.UpdateNote(note)
+}
+
+// ImagePaths returns the files listed in a comma separated list of paths,
+// each of which can be a glob pattern such as ~/shots/*.png or a file
+// dropped onto the terminal. A pattern matching nothing is kept as is, so
+// that importing it reports the missing image.
+func ImagePaths(list string) []string {
+	paths := []string{}
+	for _, path := range strings.Split(list, ",") {
+		path = droppedPath(strings.TrimSpace(path))
+		if path == "" {
+			continue
+		}
+		path = expand.Path(path)
+		// A file whose name looks like a pattern is taken as it is
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+			continue
+		}
+		matches, err := filepath.Glob(path)
+		if err != nil || len(matches) == 0 {
+			paths = append(paths, path)
+			continue
+		}
+		paths = append(paths, matches...)
+	}
+	return paths
+}
+
+// imageExtensions are the extensions PastedImagePath takes for images
+// without decoding them
+var imageExtensions = []string{".png", ".jpg", ".jpeg", ".gif", ".webp", ".bmp", ".tif", ".tiff", ".heic", ".avif"}
+
+// PastedImagePath returns the image file named by a pasted text, such as the
+// path a screenshot tool copies, and false unless the text is a single line
+// naming an existing file with an image extension or decodable as an image.
+// Quotes and escaped spaces of dropped files, a file:// prefix, ~ and
+// environment variables are accepted.
+func PastedImagePath(text string) (string, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.ContainsAny(text, "\n\r") {
+		return "", false
+	}
+	path := expand.Path(strings.TrimPrefix(droppedPath(text), "file://"))
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+	if slices.Contains(imageExtensions, strings.ToLower(filepath.Ext(path))) {
+		return path, true
+	}
+	_, _, ok := imageConfig(path)
+	return path, ok
+}
+
+// droppedPath undoes the quoting terminals apply to the path of a file
+// dropped onto them: surrounding quotes or escaped spaces
+func droppedPath(path string) string {
+	if len(path) >= 2 && (path[0] == '\'' || path[0] == '"') && path[len(path)-1] == path[0] {
+		return path[1 : len(path)-1]
+	}
+	return strings.ReplaceAll(path, `\ `, " ")
+}
+
+// StoreImage copies an image into the images directory and returns its
+// stored name, without attaching it to any note. The path may start with ~
+// and hold environment variables.
+func (m *NotesManager) StoreImage(sourcePath string) (string, error) {
+	sourcePath = expand.Path(sourcePath)
+
 	// Verify the image exists
 	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-		return fmt.Errorf("image file not found at path: %s", sourcePath)
+		return "", fmt.Errorf("%w: %s", ErrImageNotFound, sourcePath)
 	}
 
-	// Create the images directory if it doesn't exist
-	if err := os.MkdirAll(m.ImageDir, 0755); err != nil {
-		return fmt.Errorf("failed to create images directory: %w", err)
+	// Name the image after its content so that re-importing the same file
+	// reuses the stored copy instead of duplicating it
+	newFilename, err := contentAddressedName(sourcePath)
+	if err != nil || m.DryRun {
+		return newFilename, err
 	}
 
-	// Generate a unique name for the image
-	ext := filepath.Ext(sourcePath)
-	newFilename := fmt.Sprintf("%s%s", generateID(), ext)
+	// Create the images directory if it doesn't exist
+	if err := m.ensureDir(m.ImageDir); err != nil {
+		return "", err
+	}
 	destPath := filepath.Join(m.ImageDir, newFilename)
 
-	// Simple file copy using io.Copy
+	if _, err := os.Stat(destPath); os.IsNotExist(err) {
+		if err := copyFile(sourcePath, destPath); err != nil {
+			if readOnly(err) {
+				return "", fmt.Errorf("%w: %s: %w", ErrReadOnly, m.ImageDir, err)
+			}
+			return "", err
+		}
+	}
+	return newFilename, nil
+}
+
+// contentAddressedName returns the stored file name of an image: the SHA-256
+// of its content followed by its lowercased extension
+func contentAddressedName(sourcePath string) (string, error) {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open source image: %w", err)
+	}
+	defer source.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, source); err != nil {
+		return "", fmt.Errorf("failed to read source image: %w", err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(sourcePath))
+	return hex.EncodeToString(hash.Sum(nil)) + ext, nil
+}
+
+// copyFile copies a file through a temporary file so that an interrupted
+// copy never leaves a truncated image behind
+func copyFile(sourcePath, destPath string) error {
 	source, err := os.Open(sourcePath)
 	if err != nil {
 		return fmt.Errorf("failed to open source image: %w", err)
 	}
 	defer source.Close()
 
-	destination, err := os.Create(destPath)
+	tmpPath := destPath + ".tmp"
+	destination, err := os.Create(tmpPath)
 	if err != nil {
 		return fmt.Errorf("failed to create destination file: %w", err)
 	}
-	defer destination.Close()
 
 	if _, err := io.Copy(destination, source); err != nil {
+		destination.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to copy image: %w", err)
+	}
+	if err := destination.Close(); err != nil {
+		os.Remove(tmpPath)
 		return fmt.Errorf("failed to copy image: %w", err)
 	}
 
-	// Add image to the note
-	note.AddImage(newFilename, caption, altText)
-	m.UpdateNote(note)
-
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to store image: %w", err)
+	}
 	return nil
 }
 
@@ -189,40 +508,153 @@ func (m *NotesManager) ImageExists(imagePath string) bool {
 
 // SaveNotes saves all notes to a JSON file
 func (m *NotesManager) SaveNotes() error {
-	// Sort notes by update date (most recent first)
-	sort.Slice(m.Notes, func(i, j int) bool {
-		return m.Notes[i].UpdatedAt.After(m.Notes[j].UpdatedAt)
-	})
+	m.SortNotes()
+	if m.DryRun {
+		return nil
+	}
 
 	data, err := json.MarshalIndent(m.Notes, "", "  ")
 	if err != nil {
 		return fmt.Errorf("error serializing notes: %w", err)
 	}
 
+	// A failed snapshot must not prevent saving the notes
+	m.snapshotIfDue()
+
+	if err := m.ensureDir(m.StoragePath); err != nil {
+		return err
+	}
 	notesFile := filepath.Join(m.StoragePath, "notes.json")
-	if err := os.WriteFile(notesFile, data, 0644); err != nil {
+	if err := writeFileAtomic(notesFile, data); err != nil {
+		if readOnly(err) {
+			return fmt.Errorf("%w: %s: %w", ErrReadOnly, m.StoragePath, err)
+		}
 		return fmt.Errorf("error writing notes file: %w", err)
 	}
 
+	m.unwritten = nil
 	return nil
 }
 
+// writeFileAtomic writes data next to path then renames it over path, so
+// that an interrupted write never leaves a truncated file behind
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 // LoadNotes loads all notes from a JSON file
 func (m *NotesManager) LoadNotes() error {
+	notes, err := m.readNotes()
+	if err != nil {
+		return err
+	}
+
+	m.Notes = notes
+	m.titleIndex = nil
+	m.unwritten = nil
+
+	// IDs used to be generated from the clock alone and could collide, in
+	// which case lookups by ID would act on the wrong note
+	fixed := m.fixDuplicateIDs()
+	m.rememberNotes()
+	m.SortNotes()
+	if fixed > 0 {
+		return m.SaveNotes()
+	}
+	return nil
+}
+
+// readNotes reads the notes file as it is on disk
+func (m *NotesManager) readNotes() ([]*Note, error) {
 	notesFile := filepath.Join(m.StoragePath, "notes.json")
 
 	data, err := os.ReadFile(notesFile)
 	if err != nil {
-		return err
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, storageError(notesFile, err)
+		}
+		return nil, err
 	}
 
 	var notes []*Note
 	if err := json.Unmarshal(data, &notes); err != nil {
-		return fmt.Errorf("error deserializing notes: %w", err)
+		return nil, fmt.Errorf("%w: %s: %w", ErrStorageCorrupt, notesFile, err)
 	}
+	return notes, nil
+}
 
-	m.Notes = notes
-	return nil
+// StoredNote returns the note with the given ID as it is in the notes file,
+// which differs from the loaded note when another program changed it. A note
+// whose update is held back for Flush counts as written as long as the file
+// still holds the version it replaces. It returns nil when the file doesn't
+// hold the note.
+func (m *NotesManager) StoredNote(id string) (*Note, error) {
+	notes, err := m.readNotes()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		if note.ID != id {
+			continue
+		}
+		if held, ok := m.unwritten[id]; ok && note.UpdatedAt.Equal(held.stored) {
+			if loaded, err := m.GetNoteByID(id); err == nil {
+				return loaded.Clone(), nil
+			}
+		}
+		return note, nil
+	}
+	return nil, nil
+}
+
+// RevertNote replaces a loaded note with its stored version, as returned by
+// StoredNote, without saving anything
+func (m *NotesManager) RevertNote(note, stored *Note) {
+	*note = *stored
+	delete(m.unwritten, note.ID)
+	m.titleIndex = nil
+}
+
+// fixDuplicateIDs gives a new ID to the notes whose ID is already used by an
+// earlier note and returns how many were changed
+func (m *NotesManager) fixDuplicateIDs() int {
+	seen := make(map[string]bool, len(m.Notes))
+	duplicates := []string{}
+	for _, note := range m.Notes {
+		if seen[note.ID] {
+			duplicates = append(duplicates, note.ID)
+		}
+		seen[note.ID] = true
+	}
+
+	changed := 0
+	for _, id := range duplicates {
+		changed += m.renumberDuplicates(id)
+	}
+	return changed
 }
 
 // GetAllTags retrieves all unique tags used in notes
@@ -243,3 +675,68 @@ func (m *NotesManager) GetAllTags() []string {
 	sort.Strings(tags)
 	return tags
 }
+
+// TagCounts returns the number of notes using each tag
+func (m *NotesManager) TagCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, note := range m.Notes {
+		for _, tag := range note.Tags {
+			counts[tag]++
+		}
+	}
+	return counts
+}
+
+// GetAllTagsByFrequency retrieves all unique tags, most used first.
+// Tags used by the same number of notes are sorted alphabetically.
+func (m *NotesManager) GetAllTagsByFrequency() []string {
+	counts := m.TagCounts()
+
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	return tags
+}
+
+// TitleMatch describes an existing note whose title resembles another one
+type TitleMatch struct {
+	Note  *Note
+	Exact bool // Same title ignoring case and whitespace, otherwise only the slug matches
+}
+
+// SimilarTitles returns the notes, other than excludeID, whose title is the
+// same as title or would produce the same slug
+func (m *NotesManager) SimilarTitles(title, excludeID string) []TitleMatch {
+	if strings.TrimSpace(title) == "" {
+		return nil
+	}
+
+	if m.titleIndex == nil {
+		m.titleIndex = make(map[string][]*Note)
+		for _, note := range m.Notes {
+			slug := Slugify(note.Title)
+			m.titleIndex[slug] = append(m.titleIndex[slug], note)
+		}
+	}
+
+	normalized := normalizeTitle(title)
+	matches := []TitleMatch{}
+	for _, note := range m.titleIndex[Slugify(title)] {
+		if note.ID == excludeID {
+			continue
+		}
+		matches = append(matches, TitleMatch{
+			Note:  note,
+			Exact: normalizeTitle(note.Title) == normalized,
+		})
+	}
+	return matches
+}
@@ -0,0 +1,327 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newTestManager returns a manager over a temporary store holding a note
+// of each title
+func newTestManager(t *testing.T, titles ...string) *NotesManager {
+	t.Helper()
+	m := OpenNotesManager(t.TempDir())
+	for _, title := range titles {
+		if err := m.UpdateNote(m.CreateNote(title)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return m
+}
+
+// baseOf returns the update dates of the notes of m, as a sync reads them
+func baseOf(m *NotesManager) map[string]time.Time {
+	base := map[string]time.Time{}
+	for _, note := range m.Notes {
+		base[note.ID] = note.UpdatedAt
+	}
+	return base
+}
+
+func TestCreateNoteDefaultTags(t *testing.T) {
+	tests := []struct {
+		defaults []string
+		want     []string
+	}{
+		{nil, nil},
+		{[]string{"inbox"}, []string{"inbox"}},
+		{[]string{"inbox", "inbox", " inbox "}, []string{"inbox"}},
+		{[]string{"Inbox", "inbox", "INBOX"}, []string{"Inbox"}},
+		{[]string{"", "  ", "todo", "inbox"}, []string{"todo", "inbox"}},
+	}
+	for _, tt := range tests {
+		m := OpenNotesManager(t.TempDir())
+		m.DefaultTags = tt.defaults
+		if got := m.CreateNote("Note").Tags; !slices.Equal(got, tt.want) {
+			t.Errorf("defaults %q give the tags %q, want %q", tt.defaults, got, tt.want)
+		}
+		// Every note gets its own copy of the tags
+		if len(tt.want) > 0 {
+			m.Notes[0].Tags[0] = "changed"
+			if got := m.CreateNote("Other").Tags; !slices.Equal(got, tt.want) {
+				t.Errorf("second note tagged %q, want %q", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestTitleExists(t *testing.T) {
+	m := newTestManager(t, "Meeting  Notes", "Trip")
+	trip, err := m.FindByTitle("Trip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trip.Aliases = []string{"Holidays"}
+	tests := []struct {
+		title string
+		want  bool
+	}{
+		{"Meeting  Notes", true},
+		{"meeting notes", true},
+		{"  MEETING\tnotes ", true},
+		{"Meeting", false},
+		{"Meeting Notes 2", false},
+		// Aliases aren't titles
+		{"Holidays", false},
+		{"", false},
+		{"   ", false},
+	}
+	for _, tt := range tests {
+		if got := m.TitleExists(tt.title); got != tt.want {
+			t.Errorf("TitleExists(%q) = %v, want %v", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestMergeNotesInPlace(t *testing.T) {
+	m := newTestManager(t, "A", "B")
+	held, _ := m.GetNoteByID(m.Notes[0].ID)
+	base := baseOf(m)
+
+	remote := held.Clone()
+	remote.Content = "from the server"
+	remote.UpdatedAt = held.UpdatedAt.Add(time.Hour)
+	added := NewNote("New")
+	skipped, err := m.MergeNotes([]*Note{remote, added}, nil, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 0 {
+		t.Errorf("skipped %v, want none", skipped)
+	}
+	if held.Content != "from the server" {
+		t.Errorf("the note held before the merge has content %q", held.Content)
+	}
+	if note, _ := m.GetNoteByID(held.ID); note != held {
+		t.Error("the merged note was replaced rather than updated")
+	}
+	if note, _ := m.GetNoteByID(added.ID); note == nil {
+		t.Error("the new note wasn't added")
+	}
+	if len(m.Notes) != 3 {
+		t.Errorf("%d notes, want 3", len(m.Notes))
+	}
+}
+
+func TestMergeNotesSkipsChanged(t *testing.T) {
+	m := newTestManager(t, "Edited", "Deleted remotely", "Untouched")
+	base := baseOf(m)
+	edited, deleted, untouched := m.Notes[0], m.Notes[1], m.Notes[2]
+	for _, note := range []*Note{edited, deleted} {
+		// Edited during the sync
+		note.Content = "local edit"
+		note.UpdatedAt = note.UpdatedAt.Add(time.Minute)
+	}
+
+	remote := edited.Clone()
+	remote.Content = "from the server"
+	removed := untouched.ID
+	skipped, err := m.MergeNotes([]*Note{remote}, []string{deleted.ID, removed}, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 2 || skipped[0] != edited.ID || skipped[1] != deleted.ID {
+		t.Errorf("skipped %v, want [%s %s]", skipped, edited.ID, deleted.ID)
+	}
+	if edited.Content != "local edit" {
+		t.Errorf("the edit made during the sync was replaced by %q", edited.Content)
+	}
+	if note, _ := m.GetNoteByID(deleted.ID); note == nil {
+		t.Error("a note edited during the sync was deleted")
+	}
+	if note, _ := m.GetNoteByID(removed); note != nil {
+		t.Error("an unchanged note deleted remotely is still there")
+	}
+}
+
+func TestMergeNotesSkipsNotesCreatedMeanwhile(t *testing.T) {
+	m := newTestManager(t)
+	base := baseOf(m)
+	local := m.CreateNote("Created during the sync")
+	remote := local.Clone()
+	remote.Content = "other"
+	skipped, err := m.MergeNotes([]*Note{remote}, nil, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 1 || local.Content == "other" {
+		t.Errorf("skipped %v and content %q, the local note must be kept", skipped, local.Content)
+	}
+}
+
+func TestMergeNotesDuplicate(t *testing.T) {
+	m := newTestManager(t)
+	note := NewNote("Twice")
+	if _, err := m.MergeNotes([]*Note{note, note}, nil, nil); err == nil {
+		t.Error("merging a note twice succeeded")
+	}
+}
+
+// taggedNotes returns a manager holding a note for each list of tags
+func taggedNotes(t *testing.T, tags ...[]string) *NotesManager {
+	t.Helper()
+	m := OpenNotesManager(t.TempDir())
+	for i, noteTags := range tags {
+		note := NewNote(fmt.Sprint("Note ", i))
+		note.Tags = noteTags
+		m.Notes = append(m.Notes, note)
+	}
+	return m
+}
+
+func TestGetAllTagsByFrequency(t *testing.T) {
+	m := taggedNotes(t,
+		[]string{"work", "urgent"},
+		[]string{"work", "home"},
+		[]string{"work", "ideas", "home"},
+		[]string{"books", "urgent"},
+		[]string{"zen"},
+		[]string{},
+	)
+	want := []string{"work", "home", "urgent", "books", "ideas", "zen"}
+	if got := m.GetAllTagsByFrequency(); !slices.Equal(got, want) {
+		t.Errorf("GetAllTagsByFrequency() = %v, want %v", got, want)
+	}
+	if got, want := m.GetAllTags(), []string{"books", "home", "ideas", "urgent", "work", "zen"}; !slices.Equal(got, want) {
+		t.Errorf("GetAllTags() = %v, want %v", got, want)
+	}
+	if got := taggedNotes(t).GetAllTagsByFrequency(); len(got) != 0 {
+		t.Errorf("tags of an empty store %v, want none", got)
+	}
+}
+
+func TestAutoArchive(t *testing.T) {
+	const days = 30 * 24 * time.Hour
+	now := time.Now()
+	m := OpenNotesManager(t.TempDir())
+	note := func(title string, age time.Duration) *Note {
+		n := NewNote(title)
+		n.UpdatedAt = now.Add(-age)
+		m.Notes = append(m.Notes, n)
+		return n
+	}
+	old := note("old", days+time.Minute)
+	recent := note("recent", days-time.Minute)
+	pinned := note("pinned", 2*days)
+	pinned.Pinned = true
+	archived := note("archived", 2*days)
+	archived.Archived = true
+	updated := old.UpdatedAt
+
+	count, err := m.AutoArchive(days)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("%d note(s) archived, want 1", count)
+	}
+	if !old.Archived || recent.Archived || pinned.Archived {
+		t.Errorf("archived old %v, recent %v, pinned %v, want only the old note", old.Archived, recent.Archived, pinned.Archived)
+	}
+	if !old.UpdatedAt.Equal(updated) {
+		t.Errorf("archiving changed the update date to %v", old.UpdatedAt)
+	}
+
+	loaded := OpenNotesManager(m.StoragePath)
+	if err := loaded.LoadNotes(); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := loaded.GetNoteByID(old.ID); err != nil || !stored.Archived {
+		t.Errorf("archiving not saved: %v", err)
+	}
+
+	if count, err := m.AutoArchive(days); err != nil || count != 0 {
+		t.Errorf("AutoArchive again = %d, %v, want nothing left to archive", count, err)
+	}
+}
+
+func TestImportImagesMixedBatch(t *testing.T) {
+	m := newTestManager(t, "Photos")
+	note := m.Notes[0]
+	dir := t.TempDir()
+	paths := map[string]string{}
+	for _, name := range []string{"one", "two", "copy-of-two"} {
+		content := name
+		if name == "copy-of-two" {
+			content = "two"
+		}
+		paths[name] = filepath.Join(dir, name+".png")
+		if err := os.WriteFile(paths[name], []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	one, err := m.ImportImage(note.ID, paths["one"], "First", "The first one")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch := []string{paths["one"], paths["two"], filepath.Join(dir, "missing.png"), dir, paths["copy-of-two"], paths["two"]}
+	results, err := m.ImportImages(note.ID, batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(batch) {
+		t.Fatalf("%d result(s) for %d path(s)", len(results), len(batch))
+	}
+	for i, result := range results {
+		if result.Path != batch[i] {
+			t.Errorf("result %d for %s, want %s", i, result.Path, batch[i])
+		}
+		failed := i == 2 || i == 3
+		if (result.Err != nil) != failed || (result.Filename == "") != failed {
+			t.Errorf("result %d for %s: stored as %q with error %v", i, result.Path, result.Filename, result.Err)
+		}
+	}
+	if !errors.Is(results[2].Err, ErrImageNotFound) {
+		t.Errorf("missing image reported as %v, want ErrImageNotFound", results[2].Err)
+	}
+	two := results[1].Filename
+	if results[4].Filename != two || results[5].Filename != two || results[0].Filename != one {
+		t.Errorf("same content stored under different names: %+v", results)
+	}
+
+	// Each image is attached and stored once, the first keeping its caption
+	saved, err := NewNotesManager(m.StoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	images := saved.Notes[0].Images
+	if len(images) != 2 || images[0].Path != one || images[0].Caption != "First" || images[1].Path != two {
+		t.Errorf("saved images %+v, want %s with its caption then %s", images, one, two)
+	}
+	stored, err := os.ReadDir(m.ImageDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 2 {
+		t.Errorf("%d file(s) in the images directory, want 2", len(stored))
+	}
+}
+
+func TestImportImagesUnknownNote(t *testing.T) {
+	m := newTestManager(t)
+	picture := filepath.Join(t.TempDir(), "picture.png")
+	if err := os.WriteFile(picture, []byte("picture"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.ImportImages("nope", []string{picture}); !errors.Is(err, ErrNoteNotFound) {
+		t.Errorf("ImportImages into a missing note = %v, want ErrNoteNotFound", err)
+	}
+	if _, err := os.Stat(m.ImageDir); !errors.Is(err, os.ErrNotExist) {
+		t.Error("image stored for a missing note")
+	}
+}
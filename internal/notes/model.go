@@ -1,18 +1,35 @@
 package notes
 
 import (
+	"math/rand/v2"
+	"strings"
 	"time"
 )
 
 // Note represents an individual note with its content and metadata
 type Note struct {
-	ID        string    `json:"id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"` // Markdown content
-	Images    []Image   `json:"images,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Tags      []string  `json:"tags,omitempty"`
+	ID         string        `json:"id"`
+	Title      string        `json:"title"`
+	Content    string        `json:"content"` // Markdown content
+	Images     []Image       `json:"images,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	Tags       []string      `json:"tags,omitempty"`
+	Pinned     bool          `json:"pinned,omitempty"`
+	Archived   bool          `json:"archived,omitempty"`
+	Gist       *GistRef      `json:"gist,omitempty"`       // Gist the note was shared to
+	Aliases    []string      `json:"aliases,omitempty"`    // Other names the note is found by
+	ReviewedAt time.Time     `json:"reviewed_at,omitzero"` // Last time the note was kept during a review
+	TimeSpent  time.Duration `json:"time_spent,omitempty"` // Time spent in the editor, in nanoseconds
+	Order      int           `json:"order,omitempty"`      // Place in the manual order, 0 for notes never moved
+	PinOrder   int           `json:"pin_order,omitempty"`  // Place among the pinned notes, 0 for notes never moved
+}
+
+// GistRef identifies the GitHub gist a note is shared to
+type GistRef struct {
+	ID   string `json:"id"`
+	URL  string `json:"url"`
+	File string `json:"file"` // Name of the file holding the note in the gist
 }
 
 // Image represents an image embedded in a note
@@ -22,6 +39,13 @@ type Image struct {
 	Caption  string `json:"caption"`  // Optional caption
 	AltText  string `json:"alt_text"` // Alternative text for accessibility
 	Position int    `json:"position"` // Position in the note
+
+	// Recorded when the image is imported, or when its note is first viewed
+	// for images imported before. Size is zero until then, the dimensions
+	// stay zero when the format can't be decoded.
+	Width  int   `json:"width,omitempty"` // In pixels
+	Height int   `json:"height,omitempty"`
+	Size   int64 `json:"size,omitempty"` // Of the file, in bytes
 }
 
 // NewNote creates a new note with default values
@@ -37,6 +61,19 @@ func NewNote(title string) *Note {
 	}
 }
 
+// Clone returns a copy of the note that shares no slice with it
+func (n *Note) Clone() *Note {
+	c := *n
+	c.Images = append([]Image(nil), n.Images...)
+	c.Tags = append([]string(nil), n.Tags...)
+	c.Aliases = append([]string(nil), n.Aliases...)
+	if n.Gist != nil {
+		gist := *n.Gist
+		c.Gist = &gist
+	}
+	return &c
+}
+
 // AddImage adds an image to a note
 func (n *Note) AddImage(path string, caption string, altText string) {
 	// Ensure the Images slice is initialized
@@ -44,6 +81,17 @@ func (n *Note) AddImage(path string, caption string, altText string) {
 		n.Images = []Image{}
 	}
 
+	// Images are content-addressed, so importing the same file again only
+	// refreshes its caption and alt text
+	for i := range n.Images {
+		if n.Images[i].Path == path {
+			n.Images[i].Caption = caption
+			n.Images[i].AltText = altText
+			n.UpdatedAt = time.Now()
+			return
+		}
+	}
+
 	// Add the image to the note
 	n.Images = append(n.Images, Image{
 		Path:    path,
@@ -77,6 +125,50 @@ func (n *Note) RemoveTag(tag string) {
 	}
 }
 
+// SetPinned pins or unpins the note
+func (n *Note) SetPinned(pinned bool) {
+	n.Pinned = pinned
+	if !pinned {
+		n.PinOrder = 0
+	}
+}
+
+// SetArchived archives or restores the note
+func (n *Note) SetArchived(archived bool) {
+	n.Archived = archived
+}
+
+// IsBlank reports whether text holds nothing but whitespace
+func IsBlank(text string) bool {
+	return strings.TrimSpace(text) == ""
+}
+
+// IsEmpty reports whether the note has neither text nor images
+func (n *Note) IsEmpty() bool {
+	return IsBlank(n.Content) && len(n.Images) == 0
+}
+
+// NormalizeWhitespace strips the spaces and tabs ending each line of text and
+// ends it with a single newline. Blank lines within the text are kept, and
+// blank text becomes empty. A line ending with two spaces or more followed by
+// a line of text keeps two, as they make a Markdown hard line break.
+func NormalizeWhitespace(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimRight(line, " \t")
+		if trimmed != "" && strings.HasSuffix(line, "  ") && i+1 < len(lines) && !IsBlank(lines[i+1]) {
+			trimmed += "  "
+		}
+		lines[i] = trimmed
+	}
+	text = strings.TrimRight(strings.Join(lines, "\n"), "\n")
+	if text == "" {
+		return ""
+	}
+	return text + "\n"
+}
+
 // Utility function to generate a unique ID
 func generateID() string {
 	return time.Now().Format("20060102150405") + randomString(6)
@@ -87,7 +179,7 @@ func randomString(n int) string {
 	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	b := make([]byte, n)
 	for i := range b {
-		b[i] = letters[time.Now().UnixNano()%int64(len(letters))]
+		b[i] = letters[rand.IntN(len(letters))]
 	}
 	return string(b)
 }
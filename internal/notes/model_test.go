@@ -0,0 +1,60 @@
+package notes
+
+import "testing"
+
+func TestNormalizeWhitespace(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"blank", " \n\t\n\n", ""},
+		{"adds the final newline", "abc", "abc\n"},
+		{"single final newline", "abc\n\n\n", "abc\n"},
+		{"trailing spaces and tabs", "a \t\nb\t\n", "a\nb\n"},
+		{"windows line endings", "a\r\nb\r\n", "a\nb\n"},
+		{"blank lines within kept", "a\n\n\nb\n", "a\n\n\nb\n"},
+		{"whitespace lines emptied", "a\n   \nb", "a\n\nb\n"},
+		{"hard break kept", "first   \nsecond\n", "first  \nsecond\n"},
+		{"hard break before a blank line", "first  \n\nsecond\n", "first\n\nsecond\n"},
+		{"hard break on the last line", "last  \n", "last\n"},
+		{"single space isn't a break", "a \nb\n", "a\nb\n"},
+		{"tab isn't a break", "a\t\t\nb\n", "a\nb\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeWhitespace(tt.in); got != tt.want {
+				t.Errorf("NormalizeWhitespace(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeWhitespaceIdempotent(t *testing.T) {
+	for _, in := range []string{"a  \nb  \n\n c \t\n", "x\r\n  \r\ny  \r\nz"} {
+		once := NormalizeWhitespace(in)
+		if twice := NormalizeWhitespace(once); twice != once {
+			t.Errorf("NormalizeWhitespace(%q) changes again: %q then %q", in, once, twice)
+		}
+	}
+}
+
+func TestUpdateNoteTidiesContent(t *testing.T) {
+	for _, tidy := range []bool{false, true} {
+		m := OpenNotesManager(t.TempDir())
+		m.TidyContent = tidy
+		note := m.CreateNote("Note")
+		note.Content = "a  \nb \n\n"
+		if err := m.UpdateNote(note); err != nil {
+			t.Fatal(err)
+		}
+		want := "a  \nb \n\n"
+		if tidy {
+			want = "a  \nb\n"
+		}
+		if note.Content != want {
+			t.Errorf("TidyContent %v: content %q, want %q", tidy, note.Content, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package notes
+
+import (
+	"errors"
+	"sort"
+)
+
+// SortNotes orders the notes as they are stored and listed: pinned notes
+// first, those moved among the others in their pin order before those never
+// moved, then by manual order when it is enabled, then by update date, the
+// most recent first. Notes never moved come before the others in the manual
+// order. Notes updated at the
+// same time, as imported notes often are, are ordered by creation date then
+// by ID, the most recent first, so that their order doesn't depend on the
+// order they were loaded or added in. The pin order is renumbered from 1 on
+// the way, so that unpinned notes lose theirs.
+func (m *NotesManager) SortNotes() {
+	for _, note := range m.Notes {
+		if !note.Pinned {
+			note.PinOrder = 0
+		}
+	}
+	sort.SliceStable(m.Notes, func(i, j int) bool {
+		a, b := m.Notes[i], m.Notes[j]
+		if a.Pinned != b.Pinned {
+			return a.Pinned
+		}
+		if (a.PinOrder > 0) != (b.PinOrder > 0) {
+			return a.PinOrder > 0
+		}
+		if a.PinOrder != b.PinOrder {
+			return a.PinOrder < b.PinOrder
+		}
+		if m.ManualOrder && a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		if !a.UpdatedAt.Equal(b.UpdatedAt) {
+			return a.UpdatedAt.After(b.UpdatedAt)
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+	next := 1
+	for _, note := range m.Notes {
+		if note.PinOrder > 0 {
+			note.PinOrder = next
+			next++
+		}
+	}
+}
+
+// SwapNotes exchanges the places of two notes in the manual order and saves
+// the store. Every note is numbered in its current place first, so that the
+// notes never moved keep their place.
+func (m *NotesManager) SwapNotes(a, b *Note) error {
+	if !m.ManualOrder {
+		return errors.New("notes can only be moved in the manual order")
+	}
+
+	m.SortNotes()
+	for i, note := range m.Notes {
+		note.Order = i + 1
+	}
+	a.Order, b.Order = b.Order, a.Order
+	return m.SaveNotes()
+}
+
+// SwapPinned exchanges the places of two pinned notes among the pinned notes
+// and saves the store, whatever the order of the others. Every pinned note is
+// numbered in its current place first, so that the pinned notes never moved
+// keep their place.
+func (m *NotesManager) SwapPinned(a, b *Note) error {
+	if !a.Pinned || !b.Pinned {
+		return errors.New("only pinned notes can be moved among the pinned notes")
+	}
+
+	m.SortNotes()
+	pinned := 0
+	for _, note := range m.Notes {
+		if note.Pinned {
+			pinned++
+			note.PinOrder = pinned
+		}
+	}
+	a.PinOrder, b.PinOrder = b.PinOrder, a.PinOrder
+	return m.SaveNotes()
+}
@@ -0,0 +1,168 @@
+package notes
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// orderOf returns the titles of the notes of m in their order
+func orderOf(m *NotesManager) []string {
+	titles := make([]string, len(m.Notes))
+	for i, note := range m.Notes {
+		titles[i] = note.Title
+	}
+	return titles
+}
+
+// datedNote returns a note updated hours after a fixed date
+func datedNote(title string, hours int) *Note {
+	note := NewNote(title)
+	note.ID = title
+	note.CreatedAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	note.UpdatedAt = note.CreatedAt.Add(time.Duration(hours) * time.Hour)
+	return note
+}
+
+func TestSortNotesPinnedFirst(t *testing.T) {
+	m := OpenNotesManager(t.TempDir())
+	recent := datedNote("recent", 10)
+	pinned := datedNote("pinned", 1)
+	pinned.Pinned = true
+	moved := datedNote("moved", 0)
+	moved.Pinned, moved.PinOrder = true, 5
+	unpinned := datedNote("unpinned", 5)
+	unpinned.PinOrder = 2 // Left over from before it was unpinned
+	m.Notes = []*Note{recent, unpinned, pinned, moved}
+
+	m.SortNotes()
+	if got, want := orderOf(m), []string{"moved", "pinned", "recent", "unpinned"}; !slices.Equal(got, want) {
+		t.Errorf("order %v, want %v", got, want)
+	}
+	if moved.PinOrder != 1 || pinned.PinOrder != 0 || unpinned.PinOrder != 0 {
+		t.Errorf("pin orders %d %d %d, want 1 0 0", moved.PinOrder, pinned.PinOrder, unpinned.PinOrder)
+	}
+}
+
+func TestSortNotesPinnedBeforeManualOrder(t *testing.T) {
+	m := OpenNotesManager(t.TempDir())
+	m.ManualOrder = true
+	first := datedNote("first", 0)
+	first.Order = 1
+	pinned := datedNote("pinned", 0)
+	pinned.Pinned, pinned.Order = true, 9
+	m.Notes = []*Note{first, pinned}
+
+	m.SortNotes()
+	if got, want := orderOf(m), []string{"pinned", "first"}; !slices.Equal(got, want) {
+		t.Errorf("order %v, want %v", got, want)
+	}
+}
+
+func TestSwapPinned(t *testing.T) {
+	m := OpenNotesManager(t.TempDir())
+	a, b, c := datedNote("a", 3), datedNote("b", 2), datedNote("c", 1)
+	for _, note := range []*Note{a, b, c} {
+		note.Pinned = true
+	}
+	m.Notes = []*Note{a, b, c}
+	m.SortNotes()
+
+	if err := m.SwapPinned(b, c); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := orderOf(m), []string{"a", "c", "b"}; !slices.Equal(got, want) {
+		t.Errorf("order %v, want %v", got, want)
+	}
+	if a.PinOrder != 1 || c.PinOrder != 2 || b.PinOrder != 3 {
+		t.Errorf("pin orders %d %d %d, want a 1, c 2, b 3", a.PinOrder, c.PinOrder, b.PinOrder)
+	}
+	if err := m.SwapPinned(a, datedNote("loose", 0)); err == nil {
+		t.Error("an unpinned note was moved among the pinned notes")
+	}
+}
+
+func TestSortNotesTieBreak(t *testing.T) {
+	// Imported together: same update date, the creation date then the ID
+	// decide
+	older := datedNote("older", 1)
+	older.CreatedAt = older.CreatedAt.Add(-time.Hour)
+	a, b, c := datedNote("a", 1), datedNote("b", 1), datedNote("c", 1)
+	newer := datedNote("newer", 2)
+	want := []string{"newer", "c", "b", "a", "older"}
+
+	orders := [][]*Note{
+		{older, a, b, c, newer},
+		{newer, c, b, a, older},
+		{b, older, newer, c, a},
+		{a, c, older, b, newer},
+	}
+	for _, notes := range orders {
+		m := OpenNotesManager(t.TempDir())
+		m.Notes = slices.Clone(notes)
+		for range 3 {
+			m.SortNotes()
+			if got := orderOf(m); !slices.Equal(got, want) {
+				t.Fatalf("order %v, want %v", got, want)
+			}
+		}
+	}
+}
+
+func TestSwapNotes(t *testing.T) {
+	m := OpenNotesManager(t.TempDir())
+	m.ManualOrder = true
+	a, b, c := datedNote("a", 3), datedNote("b", 2), datedNote("c", 1)
+	m.Notes = []*Note{c, a, b}
+	m.SortNotes()
+
+	if err := m.SwapNotes(b, c); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := orderOf(m), []string{"a", "c", "b"}; !slices.Equal(got, want) {
+		t.Errorf("order %v, want %v", got, want)
+	}
+	if a.Order != 1 || c.Order != 2 || b.Order != 3 {
+		t.Errorf("orders %d %d %d, want a 1, c 2, b 3", a.Order, c.Order, b.Order)
+	}
+	if err := m.SwapNotes(a, c); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := orderOf(m), []string{"c", "a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("order %v after a second move, want %v", got, want)
+	}
+
+	// Editing a note doesn't move it, a new note comes first
+	b.Content = "edited"
+	if err := m.UpdateNote(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.UpdateNote(m.CreateNote("new")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := orderOf(m), []string{"new", "c", "a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("order %v after editing and adding, want %v", got, want)
+	}
+
+	// The order is saved
+	loaded := OpenNotesManager(m.StoragePath)
+	loaded.ManualOrder = true
+	if err := loaded.LoadNotes(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := orderOf(loaded), []string{"new", "c", "a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("order %v once loaded, want %v", got, want)
+	}
+}
+
+func TestSwapNotesNeedsManualOrder(t *testing.T) {
+	m := OpenNotesManager(t.TempDir())
+	a, b := datedNote("a", 2), datedNote("b", 1)
+	m.Notes = []*Note{a, b}
+	if err := m.SwapNotes(a, b); err == nil {
+		t.Error("notes moved while sorted by date")
+	}
+	if a.Order != 0 || b.Order != 0 {
+		t.Errorf("orders %d %d set while sorted by date", a.Order, b.Order)
+	}
+}
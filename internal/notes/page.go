@@ -0,0 +1,20 @@
+package notes
+
+// Page returns at most limit notes of ns starting at offset, along with the
+// number of notes in ns so that callers can tell how many pages there are. A
+// limit of zero or less keeps every note from offset, a negative offset counts
+// as zero and an offset past the end gives no note.
+func Page(ns []*Note, offset, limit int) ([]*Note, int) {
+	total := len(ns)
+	offset = min(max(offset, 0), total)
+	end := total
+	if limit > 0 {
+		end = min(offset+limit, total)
+	}
+	return ns[offset:end], total
+}
+
+// ListPaged returns a page of the notes in their order, see Page
+func (m *NotesManager) ListPaged(offset, limit int) ([]*Note, int) {
+	return Page(m.Notes, offset, limit)
+}
@@ -0,0 +1,53 @@
+package notes
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestListPaged(t *testing.T) {
+	m := OpenNotesManager(t.TempDir())
+	for i := range 5 {
+		m.Notes = append(m.Notes, datedNote(fmt.Sprint(i), -i))
+	}
+	m.SortNotes()
+
+	tests := []struct {
+		offset, limit int
+		want          []string
+	}{
+		{0, 2, []string{"0", "1"}},
+		{2, 2, []string{"2", "3"}},
+		{4, 2, []string{"4"}},
+		{5, 2, []string{}},
+		{9, 2, []string{}},
+		{-3, 2, []string{"0", "1"}},
+		{3, 0, []string{"3", "4"}},
+		{1, -1, []string{"1", "2", "3", "4"}},
+		{0, 5, []string{"0", "1", "2", "3", "4"}},
+		{0, 50, []string{"0", "1", "2", "3", "4"}},
+	}
+	for _, tt := range tests {
+		page, total := m.ListPaged(tt.offset, tt.limit)
+		if got := titlesOf(page); !slices.Equal(got, tt.want) {
+			t.Errorf("ListPaged(%d, %d) = %v, want %v", tt.offset, tt.limit, got, tt.want)
+		}
+		if total != 5 {
+			t.Errorf("ListPaged(%d, %d) total %d, want 5", tt.offset, tt.limit, total)
+		}
+	}
+
+	if page, total := OpenNotesManager(t.TempDir()).ListPaged(0, 10); len(page) != 0 || total != 0 {
+		t.Errorf("page of an empty store %v, total %d", titlesOf(page), total)
+	}
+}
+
+// titlesOf returns the titles of notes in their order
+func titlesOf(ns []*Note) []string {
+	titles := make([]string, len(ns))
+	for i, note := range ns {
+		titles[i] = note.Title
+	}
+	return titles
+}
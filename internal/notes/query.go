@@ -0,0 +1,248 @@
+package notes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query is a search combining free text with filters on the fields of notes,
+// such as `tag:work created:>2024-01-01 modified:<7d meeting`
+type Query struct {
+	Text    string // Free text, searched like Search does
+	filters []func(note *Note) bool
+}
+
+// QueryError reports where a query can't be parsed
+type QueryError struct {
+	Pos int // Byte offset of the problem in the query
+	Msg string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos+1)
+}
+
+// queryWord is a word of a query, quotes removed
+type queryWord struct {
+	text    string
+	offsets []int // Position in the query of each byte of text
+	end     int   // Position following the word
+}
+
+// pos returns the position in the query of the byte i of the word, quotes
+// being skipped
+func (w queryWord) pos(i int) int {
+	if i < len(w.offsets) {
+		return w.offsets[i]
+	}
+	return w.end
+}
+
+// ParseQuery parses a search query. Words of the form field:value filter the
+// notes, the others are searched as text:
+//
+//	tag:work               notes with the tag, "tag:my tag" when it has spaces
+//	created:>2024-01-01    created after that day, also >=, <, <= and =
+//	modified:<7d           modified less than 7 days ago, in hours (h), days
+//	                       (d), weeks (w), months (m) or years (y)
+//
+// A date without operator is that day and a duration without operator means
+// "within". Relative dates are computed from now. Unknown fields and empty
+// values are text, and a query without filters is searched as is.
+func ParseQuery(source string, now time.Time) (*Query, error) {
+	q := &Query{}
+	text := []string{}
+	for _, word := range splitQuery(source) {
+		field, value, ok := strings.Cut(word.text, ":")
+		if !ok || value == "" {
+			text = append(text, word.text)
+			continue
+		}
+
+		start := len(field) + 1
+		pos := func(i int) int { return word.pos(start + i) }
+		switch strings.ToLower(field) {
+		case "tag":
+			filter, err := tagFilter(value, pos(0))
+			if err != nil {
+				return nil, err
+			}
+			q.filters = append(q.filters, filter)
+		case "created":
+			filter, err := dateFilter(field, value, pos, now, func(note *Note) time.Time { return note.CreatedAt })
+			if err != nil {
+				return nil, err
+			}
+			q.filters = append(q.filters, filter)
+		case "modified", "updated":
+			filter, err := dateFilter(field, value, pos, now, func(note *Note) time.Time { return note.UpdatedAt })
+			if err != nil {
+				return nil, err
+			}
+			q.filters = append(q.filters, filter)
+		default:
+			text = append(text, word.text)
+		}
+	}
+
+	if len(q.filters) == 0 {
+		q.Text = source
+	} else {
+		q.Text = strings.Join(text, " ")
+	}
+	return q, nil
+}
+
+// Match reports whether a note passes every filter of the query, whatever
+// its text
+func (q *Query) Match(note *Note) bool {
+	for _, filter := range q.filters {
+		if !filter(note) {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchQuery returns the notes matching both the filters and the text of a
+// query, the text being searched like Search does
+func (m *NotesManager) SearchQuery(q *Query) []SearchResult {
+	results := []SearchResult{}
+	for _, result := range m.Search(q.Text) {
+		if q.Match(result.Note) {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// splitQuery splits a query into words separated by spaces. Double quotes
+// group spaces into a word and are removed, an unterminated one runs to the
+// end of the query.
+func splitQuery(source string) []queryWord {
+	words := []queryWord{}
+	var word strings.Builder
+	var offsets []int
+	started, quote := false, -1
+	for i := 0; i < len(source); i++ {
+		c := source[i]
+		switch {
+		case c == '"':
+			started = true
+			if quote < 0 {
+				quote = i
+			} else {
+				quote = -1
+			}
+		case quote < 0 && (c == ' ' || c == '\t' || c == '\n'):
+			if started {
+				words = append(words, queryWord{word.String(), offsets, i})
+				word.Reset()
+				offsets, started = nil, false
+			}
+		default:
+			started = true
+			word.WriteByte(c)
+			offsets = append(offsets, i)
+		}
+	}
+	if started {
+		words = append(words, queryWord{word.String(), offsets, len(source)})
+	}
+	return words
+}
+
+// tagFilter returns the filter keeping the notes with a tag, ignoring its case
+func tagFilter(tag string, pos int) (func(note *Note) bool, error) {
+	key := tagKey(tag)
+	if key == "" {
+		return nil, &QueryError{pos, "expected a tag after tag:"}
+	}
+	return func(note *Note) bool {
+		for _, t := range note.Tags {
+			if tagKey(t) == key {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// dateFilter returns the filter comparing the date date returns with value, an
+// optional operator followed by a day or a duration before now. pos gives the
+// position in the query of a byte of value.
+func dateFilter(field, value string, pos func(i int) int, now time.Time, date func(note *Note) time.Time) (func(note *Note) bool, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(value, candidate) {
+			op = candidate
+			break
+		}
+	}
+	start := len(op)
+	value = value[start:]
+
+	if day, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		next := day.AddDate(0, 0, 1)
+		return func(note *Note) bool {
+			t := date(note)
+			switch op {
+			case ">":
+				return !t.Before(next)
+			case ">=":
+				return !t.Before(day)
+			case "<":
+				return t.Before(day)
+			case "<=":
+				return t.Before(next)
+			default:
+				return !t.Before(day) && t.Before(next)
+			}
+		}, nil
+	}
+
+	cutoff, ok := durationAgo(value, now)
+	if !ok {
+		return nil, &QueryError{pos(start), fmt.Sprintf("invalid date %q for %s:, use YYYY-MM-DD or a duration such as 7d", value, field)}
+	}
+	// Operators compare ages: less than 7d ago is after the cutoff
+	return func(note *Note) bool {
+		t := date(note)
+		switch op {
+		case ">":
+			return t.Before(cutoff)
+		case ">=":
+			return !t.After(cutoff)
+		case "<":
+			return t.After(cutoff)
+		default:
+			return !t.Before(cutoff)
+		}
+	}, nil
+}
+
+// durationAgo returns the time a duration such as 7d or 2w before now
+func durationAgo(value string, now time.Time) (time.Time, bool) {
+	if len(value) < 2 {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+	switch value[len(value)-1] {
+	case 'h':
+		return now.Add(-time.Duration(n) * time.Hour), true
+	case 'd':
+		return now.AddDate(0, 0, -n), true
+	case 'w':
+		return now.AddDate(0, 0, -7*n), true
+	case 'm':
+		return now.AddDate(0, -n, 0), true
+	case 'y':
+		return now.AddDate(-n, 0, 0), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,176 @@
+package notes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// queryNow is the time relative dates of the query tests are computed from
+var queryNow = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+// at returns a time of 2024 in UTC
+func at(month time.Month, day, hour int) time.Time {
+	return time.Date(2024, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+// parseQuery parses a query from queryNow and fails the test on error
+func parseQuery(t *testing.T, source string) *Query {
+	t.Helper()
+	q, err := ParseQuery(source, queryNow)
+	if err != nil {
+		t.Fatalf("ParseQuery(%q) = %v", source, err)
+	}
+	return q
+}
+
+func TestParseQueryDates(t *testing.T) {
+	tests := []struct {
+		query string
+		date  time.Time
+		want  bool
+	}{
+		// Days, compared with the whole day
+		{"created:>2024-01-01", at(1, 2, 0), true},
+		{"created:>2024-01-01", at(1, 1, 23), false},
+		{"created:>=2024-01-01", at(1, 1, 0), true},
+		{"created:>=2024-01-01", at(1, 1, 0).Add(-time.Minute), false},
+		{"created:<2024-01-01", at(1, 1, 0).Add(-time.Minute), true},
+		{"created:<2024-01-01", at(1, 1, 0), false},
+		{"created:<=2024-01-01", at(1, 1, 23), true},
+		{"created:<=2024-01-01", at(1, 2, 0), false},
+		{"created:=2024-01-01", at(1, 1, 12), true},
+		{"created:=2024-01-01", at(1, 2, 12), false},
+		{"created:2024-01-01", at(1, 1, 0), true},
+		{"created:2024-01-01", at(1, 2, 0), false},
+
+		// Durations, compared with the age of the note
+		{"modified:<7d", at(6, 10, 0), true},
+		{"modified:<7d", at(6, 1, 0), false},
+		{"modified:7d", at(6, 10, 0), true},
+		{"modified:7d", at(6, 1, 0), false},
+		{"modified:>7d", at(6, 1, 0), true},
+		{"modified:>7d", at(6, 10, 0), false},
+		{"modified:>=7d", at(6, 8, 12), true},
+		{"modified:>=7d", at(6, 10, 0), false},
+		{"modified:<=7d", at(6, 8, 12), true},
+		{"modified:<=7d", at(6, 1, 0), false},
+		{"updated:<2h", at(6, 15, 11), true},
+		{"updated:<2h", at(6, 15, 9), false},
+		{"modified:<2w", at(6, 5, 0), true},
+		{"modified:<2w", at(5, 30, 0), false},
+		{"modified:<1m", at(5, 20, 0), true},
+		{"modified:<1m", at(5, 10, 0), false},
+		{"modified:<1y", time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), true},
+		{"modified:<1y", time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		note := NewNote("Note")
+		note.CreatedAt, note.UpdatedAt = tt.date, tt.date
+		if got := parseQuery(t, tt.query).Match(note); got != tt.want {
+			t.Errorf("%q matches a note of %s: %v, want %v", tt.query, tt.date.Format(time.DateTime), got, tt.want)
+		}
+	}
+}
+
+func TestParseQueryFieldsDiffer(t *testing.T) {
+	note := NewNote("Note")
+	note.CreatedAt = at(1, 1, 0)
+	note.UpdatedAt = at(6, 14, 0)
+	if !parseQuery(t, "created:<2024-02-01 modified:<7d").Match(note) {
+		t.Error("filters on both dates don't match")
+	}
+	if parseQuery(t, "created:<7d").Match(note) {
+		t.Error("created: compared with the update date")
+	}
+}
+
+func TestParseQueryTags(t *testing.T) {
+	note := NewNote("Note")
+	note.Tags = []string{"Work", "my tag"}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"tag:work", true},
+		{"TAG:WORK", true},
+		{"tag:home", false},
+		{`"tag:my tag"`, true},
+		{`tag:"my tag"`, true},
+		{"tag:my", false},
+		{"tag:work tag:home", false},
+	}
+	for _, tt := range tests {
+		if got := parseQuery(t, tt.query).Match(note); got != tt.want {
+			t.Errorf("%q matches: %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseQueryText(t *testing.T) {
+	tests := []struct {
+		query string
+		text  string
+	}{
+		{"meeting notes", "meeting notes"},
+		{`"exact phrase"`, `"exact phrase"`},
+		{"tag:work meeting  notes", "meeting notes"},
+		{"foo:bar baz", "foo:bar baz"},
+		{"tag:work foo:bar", "foo:bar"},
+		{"tag: todo", "tag: todo"},
+		{"https://example.com", "https://example.com"},
+	}
+	for _, tt := range tests {
+		if got := parseQuery(t, tt.query).Text; got != tt.text {
+			t.Errorf("text of %q = %q, want %q", tt.query, got, tt.text)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{"created:>yesterday", 9, `invalid date "yesterday" for created:`},
+		{"meeting modified:abc", 17, `invalid date "abc" for modified:`},
+		{"modified:<7x", 10, `invalid date "7x"`},
+		{"created:-1d", 8, `invalid date "-1d"`},
+		{`"created:soon"`, 9, `invalid date "soon"`},
+		{`created:">=soon"`, 11, `invalid date "soon"`},
+		{`"tag:my tag" created:x`, 21, `invalid date "x"`},
+		{`tag:" "`, 5, "expected a tag after tag:"},
+	}
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.query, queryNow)
+		var queryErr *QueryError
+		if !errors.As(err, &queryErr) {
+			t.Errorf("ParseQuery(%q) = %v, want a QueryError", tt.query, err)
+			continue
+		}
+		if queryErr.Pos != tt.pos || !strings.Contains(queryErr.Msg, tt.msg) {
+			t.Errorf("ParseQuery(%q) fails at %d with %q, want %d and %q", tt.query, queryErr.Pos, queryErr.Msg, tt.pos, tt.msg)
+		}
+	}
+}
+
+func TestQueryErrorMessage(t *testing.T) {
+	err := &QueryError{Pos: 9, Msg: "invalid date"}
+	if got, want := err.Error(), "invalid date at position 10"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestSearchQuery(t *testing.T) {
+	m := OpenNotesManager(t.TempDir())
+	for _, title := range []string{"Work meeting", "Home meeting", "Work plan"} {
+		note := m.CreateNote(title)
+		note.Tags = []string{strings.Fields(title)[0]}
+	}
+	results := m.SearchQuery(parseQuery(t, "tag:work meeting"))
+	if len(results) != 1 || results[0].Note.Title != "Work meeting" {
+		t.Errorf("tag:work meeting found %d note(s), want Work meeting", len(results))
+	}
+}
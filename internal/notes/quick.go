@@ -0,0 +1,25 @@
+package notes
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// quickTitleLength is the length in characters above which a quick note is
+// titled after its beginning, the whole thought going to its content
+const quickTitleLength = 60
+
+// QuickNote returns the title and content of a note captured as a single
+// thought, its whitespace collapsed. A short thought is the title alone, a
+// long one is titled after its first words.
+func QuickNote(text string) (title, content string) {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= quickTitleLength {
+		return text, ""
+	}
+	title = string([]rune(text)[:quickTitleLength])
+	if i := strings.LastIndex(title, " "); i > 0 {
+		title = title[:i]
+	}
+	return title + "…", text
+}
@@ -0,0 +1,27 @@
+package notes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuickNote(t *testing.T) {
+	long := "Call the garage about the winter tyres before the first snow comes next week"
+	tests := []struct {
+		text, title, content string
+	}{
+		{"Buy milk", "Buy milk", ""},
+		{"  Buy \t milk\n", "Buy milk", ""},
+		{"   ", "", ""},
+		{strings.Repeat("a", 60), strings.Repeat("a", 60), ""},
+		{long, "Call the garage about the winter tyres before the first…", long},
+		// Without a space the title is cut at the length
+		{strings.Repeat("é", 61), strings.Repeat("é", 60) + "…", strings.Repeat("é", 61)},
+	}
+	for _, tt := range tests {
+		title, content := QuickNote(tt.text)
+		if title != tt.title || content != tt.content {
+			t.Errorf("QuickNote(%q) = %q, %q, want %q, %q", tt.text, title, content, tt.title, tt.content)
+		}
+	}
+}
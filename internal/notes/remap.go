@@ -0,0 +1,33 @@
+package notes
+
+// IDRemap holds the new IDs given to imported notes whose ID was taken, by
+// their ID in the source
+type IDRemap map[string]string
+
+// remapIDs gives a new ID to each of the notes ns whose ID is already used,
+// by a note of the store or by an earlier note of ns, and returns the new IDs
+// by old ID. An ID repeated within ns maps to the new ID of its last copy.
+//
+// Nothing else needs rewriting: links between notes go by title or alias,
+// images by the hash of their content, and the gist a note is shared to by
+// its own ID.
+func (m *NotesManager) remapIDs(ns []*Note) IDRemap {
+	taken := make(map[string]bool, len(m.Notes)+len(ns))
+	for _, note := range m.Notes {
+		taken[note.ID] = true
+	}
+
+	remap := IDRemap{}
+	for _, note := range ns {
+		if taken[note.ID] {
+			id := generateID()
+			for taken[id] {
+				id = generateID()
+			}
+			remap[note.ID] = id
+			note.ID = id
+		}
+		taken[note.ID] = true
+	}
+	return remap
+}
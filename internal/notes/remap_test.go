@@ -0,0 +1,108 @@
+package notes
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// bundle returns the notes of an export, with the IDs they had in their store
+func bundle() []*Note {
+	ns := []*Note{}
+	for _, id := range []string{"plan", "trip"} {
+		note := NewNote("Note " + id)
+		note.ID = id
+		note.Content = "See [[Note trip]]\n\n![map](" + id + ".png)\n"
+		note.Images = []Image{{ID: "img-" + id, Path: id + ".png", Caption: "map"}}
+		ns = append(ns, note)
+	}
+	return ns
+}
+
+// checkDistinctIDs fails the test when two notes of m share an ID
+func checkDistinctIDs(t *testing.T, m *NotesManager) {
+	t.Helper()
+	seen := map[string]bool{}
+	for _, note := range m.Notes {
+		if seen[note.ID] {
+			t.Errorf("ID %q used twice", note.ID)
+		}
+		seen[note.ID] = true
+	}
+}
+
+// checkUnchanged fails the test when the notes lost the content or the
+// images of the bundle
+func checkUnchanged(t *testing.T, ns []*Note) {
+	t.Helper()
+	for i, want := range bundle() {
+		if ns[i].Content != want.Content || !slices.Equal(ns[i].Images, want.Images) {
+			t.Errorf("note %d rewritten: %q with %+v", i, ns[i].Content, ns[i].Images)
+		}
+	}
+}
+
+func TestAddNotesTwice(t *testing.T) {
+	m := newTestManager(t)
+	first := bundle()
+	remap, err := m.AddNotes(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remap) != 0 || first[0].ID != "plan" || first[1].ID != "trip" {
+		t.Errorf("free IDs remapped: %v", remap)
+	}
+
+	second := bundle()
+	remap, err = m.AddNotes(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remap) != 2 || remap["plan"] != second[0].ID || remap["trip"] != second[1].ID {
+		t.Errorf("remap %v, want plan and trip mapped to %s and %s", remap, second[0].ID, second[1].ID)
+	}
+	if len(m.Notes) != 4 {
+		t.Errorf("%d note(s) after importing twice, want 4", len(m.Notes))
+	}
+	checkDistinctIDs(t, m)
+	checkUnchanged(t, first)
+	checkUnchanged(t, second)
+}
+
+func TestAddNotesTakenIDs(t *testing.T) {
+	m := newTestManager(t, "Existing")
+	existing := m.Notes[0].ID
+	ns := bundle()
+	ns[0].ID = existing
+	remap, err := m.AddNotes(ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(remap, IDRemap{existing: ns[0].ID}) || ns[0].ID == existing {
+		t.Errorf("remap %v, want only %s remapped", remap, existing)
+	}
+	if note, err := m.GetNoteByID(existing); err != nil || note.Title != "Existing" {
+		t.Error("note of the store lost its ID")
+	}
+	checkDistinctIDs(t, m)
+	checkUnchanged(t, ns)
+}
+
+func TestAddNotesRepeatedInBatch(t *testing.T) {
+	m := newTestManager(t)
+	ns := append(bundle(), bundle()...)
+	remap, err := m.AddNotes(ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first copies keep their ID, the repeated ones map to the last copy
+	if ns[0].ID != "plan" || ns[1].ID != "trip" {
+		t.Errorf("first copies renamed to %s and %s", ns[0].ID, ns[1].ID)
+	}
+	if !maps.Equal(remap, IDRemap{"plan": ns[2].ID, "trip": ns[3].ID}) {
+		t.Errorf("remap %v, want plan and trip mapped to %s and %s", remap, ns[2].ID, ns[3].ID)
+	}
+	checkDistinctIDs(t, m)
+	checkUnchanged(t, ns[:2])
+	checkUnchanged(t, ns[2:])
+}
@@ -0,0 +1,51 @@
+package notes
+
+import (
+	"sort"
+	"time"
+)
+
+// LastSeen returns the last time the note was edited or kept in a review
+func (n *Note) LastSeen() time.Time {
+	if n.ReviewedAt.After(n.UpdatedAt) {
+		return n.ReviewedAt
+	}
+	return n.UpdatedAt
+}
+
+// NotesForReview returns the notes that haven't been edited or reviewed for
+// the longest time, at most limit of them when limit is positive. Archived
+// notes are left out, they have been dealt with already.
+func (m *NotesManager) NotesForReview(limit int) []*Note {
+	queue := []*Note{}
+	for _, note := range m.Notes {
+		if !note.Archived {
+			queue = append(queue, note)
+		}
+	}
+
+	// The ID breaks ties so that the queue doesn't depend on the order of
+	// the store, which changes with every save
+	sort.Slice(queue, func(i, j int) bool {
+		a, b := queue[i].LastSeen(), queue[j].LastSeen()
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return queue[i].ID < queue[j].ID
+	})
+
+	if limit > 0 && len(queue) > limit {
+		queue = queue[:limit]
+	}
+	return queue
+}
+
+// MarkReviewed records that the notes were reviewed and kept. The update
+// date is left alone since the notes didn't change.
+func (m *NotesManager) MarkReviewed(ns ...*Note) error {
+	now := time.Now()
+	for _, note := range ns {
+		note.ReviewedAt = now
+	}
+	return m.SaveNotes()
+}
@@ -0,0 +1,76 @@
+package notes
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNotesForReview(t *testing.T) {
+	m := OpenNotesManager(t.TempDir())
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC) }
+	for _, note := range []struct {
+		id       string
+		updated  time.Time
+		reviewed time.Time
+		archived bool
+	}{
+		{id: "recent", updated: day(20)},
+		{id: "old", updated: day(1)},
+		{id: "kept", updated: day(2), reviewed: day(25)}, // Reviewed after the last edit
+		{id: "edited", updated: day(10), reviewed: day(5)},
+		{id: "archived", updated: day(1), archived: true},
+		{id: "tie-b", updated: day(15)},
+		{id: "tie-a", updated: day(15)},
+	} {
+		n := m.CreateNote(note.id)
+		n.ID, n.UpdatedAt, n.ReviewedAt, n.Archived = note.id, note.updated, note.reviewed, note.archived
+	}
+
+	ids := func(ns []*Note) []string {
+		out := []string{}
+		for _, note := range ns {
+			out = append(out, note.ID)
+		}
+		return out
+	}
+	want := []string{"old", "edited", "tie-a", "tie-b", "recent", "kept"}
+	if got := ids(m.NotesForReview(0)); !slices.Equal(got, want) {
+		t.Errorf("review queue %q, want %q", got, want)
+	}
+	if got := ids(m.NotesForReview(2)); !slices.Equal(got, want[:2]) {
+		t.Errorf("queue limited to 2 gives %q, want %q", got, want[:2])
+	}
+	if got := ids(m.NotesForReview(10)); !slices.Equal(got, want) {
+		t.Errorf("limit above the number of notes gives %q", got)
+	}
+
+	// The queue doesn't depend on the order of the store
+	slices.Reverse(m.Notes)
+	if got := ids(m.NotesForReview(0)); !slices.Equal(got, want) {
+		t.Errorf("review queue of the reversed store %q, want %q", got, want)
+	}
+}
+
+func TestMarkReviewed(t *testing.T) {
+	m := newTestManager(t, "Old", "Other")
+	note := m.Notes[0]
+	updated := note.UpdatedAt
+	if err := m.MarkReviewed(note); err != nil {
+		t.Fatal(err)
+	}
+	if note.ReviewedAt.IsZero() || !note.UpdatedAt.Equal(updated) {
+		t.Errorf("reviewed at %v, updated at %v, want the update date kept", note.ReviewedAt, note.UpdatedAt)
+	}
+	if queue := m.NotesForReview(0); queue[len(queue)-1] != note {
+		t.Error("reviewed note not moved to the end of the queue")
+	}
+
+	reloaded, err := NewNotesManager(m.StoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := reloaded.GetNoteByID(note.ID); err != nil || !got.ReviewedAt.Equal(note.ReviewedAt) {
+		t.Error("review date not saved")
+	}
+}
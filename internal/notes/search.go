@@ -0,0 +1,111 @@
+package notes
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Parts of a note a search can match
+const (
+	MatchTitle   = "title"
+	MatchContent = "content"
+	MatchCaption = "image caption"
+	MatchAltText = "image alt text"
+)
+
+// snippetRadius is the number of characters kept on each side of a match
+const snippetRadius = 30
+
+// SearchResult is a note matching a search and where it matched
+type SearchResult struct {
+	Note    *Note
+	Field   string // Part of the note that matched, empty when every note matches
+	Snippet string // Text around the match, empty for a title match
+}
+
+// Search returns the notes whose title, content, image captions or image alt
+// text contain query, ignoring case, in this order of precedence. A blank
+// query matches every note.
+func (m *NotesManager) Search(query string) []SearchResult {
+	results := []SearchResult{}
+	query = strings.ToLower(query)
+	for _, note := range m.Notes {
+		if IsBlank(query) {
+			results = append(results, SearchResult{Note: note})
+		} else if result, ok := matchNote(note, query); ok {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// SearchNotes searches for notes by title, content or images
+func (m *NotesManager) SearchNotes(query string) []*Note {
+	if IsBlank(query) {
+		return m.Notes
+	}
+
+	results := []*Note{}
+	for _, result := range m.Search(query) {
+		results = append(results, result.Note)
+	}
+	return results
+}
+
+// matchNote looks for a lowercase query in a note
+func matchNote(note *Note, query string) (SearchResult, bool) {
+	if strings.Contains(strings.ToLower(note.Title), query) {
+		return SearchResult{Note: note, Field: MatchTitle}, true
+	}
+	if snippet, ok := matchSnippet(note.Content, query); ok {
+		return SearchResult{Note: note, Field: MatchContent, Snippet: snippet}, true
+	}
+	for _, img := range note.Images {
+		if snippet, ok := matchSnippet(img.Caption, query); ok {
+			return SearchResult{Note: note, Field: MatchCaption, Snippet: snippet}, true
+		}
+	}
+	for _, img := range note.Images {
+		if snippet, ok := matchSnippet(img.AltText, query); ok {
+			return SearchResult{Note: note, Field: MatchAltText, Snippet: snippet}, true
+		}
+	}
+	return SearchResult{}, false
+}
+
+// matchSnippet looks for a lowercase query in text and returns the text around
+// the first match on a single line
+func matchSnippet(text, query string) (string, bool) {
+	lower := strings.ToLower(text)
+	start := strings.Index(lower, query)
+	if start < 0 {
+		return "", false
+	}
+	end := start + len(query)
+	// Lowercasing changes the length of a few letters, the offsets then
+	// don't apply to the text and the snippet starts at its beginning
+	if len(lower) != len(text) {
+		start, end = 0, 0
+	}
+
+	from := start
+	for i := 0; i < snippetRadius && from > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(text[:from])
+		from -= size
+	}
+	to := end
+	for i := 0; i < snippetRadius && to < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[to:])
+		to += size
+	}
+
+	snippet := strings.Join(strings.FieldsFunc(text[from:to], unicode.IsSpace), " ")
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(text) {
+		snippet += "…"
+	}
+	return snippet, true
+}
@@ -0,0 +1,102 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// maxSlugLength is the length in bytes above which the middle of slugs is
+// cut, leaving room for a numeric suffix and an extension within the usual
+// 255 bytes limit of file names
+const maxSlugLength = 80
+
+// transliterations spells letters with accents or ligatures in ASCII.
+// Decomposed accents, as combining marks, are dropped by Slugify instead.
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c", 'ċ': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ģ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ķ': "k",
+	'ľ': "l", 'ļ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n", 'ņ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe",
+	'ŕ': "r", 'ř': "r",
+	'ś': "s", 'š': "s", 'ş': "s", 'ș': "s", 'ß': "ss",
+	'ť': "t", 'ţ': "t", 'ț': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// reservedNames can't be used as file names on Windows, whatever the
+// extension
+var reservedNames = map[string]bool{
+	"con": true, "prn": true, "aux": true, "nul": true,
+	"com1": true, "com2": true, "com3": true, "com4": true, "com5": true,
+	"com6": true, "com7": true, "com8": true, "com9": true,
+	"lpt1": true, "lpt2": true, "lpt3": true, "lpt4": true, "lpt5": true,
+	"lpt6": true, "lpt7": true, "lpt8": true, "lpt9": true,
+}
+
+// Slugify turns a title into a lowercase string that is safe to use as a file
+// name on every system, words being separated by dashes. Accented Latin
+// letters are spelled in ASCII, other letters such as CJK ones are kept and
+// anything else, punctuation and emoji included, separates words. A long
+// title keeps its first and last words around an ellipsis. Every export
+// naming files after notes uses it.
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.Is(unicode.Mn, r) {
+			continue // Combining accent of the previous letter
+		}
+		letters, ok := transliterations[r]
+		if !ok {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				dash = true
+				continue
+			}
+			letters = string(r)
+		}
+		if dash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(letters)
+		dash = false
+	}
+
+	slug := elideMiddle(b.String(), maxSlugLength)
+	if slug == "" {
+		return "untitled"
+	}
+	if reservedNames[slug] {
+		slug += "-note"
+	}
+	return slug
+}
+
+// UniqueSlug returns the slug of a title that isn't in used, adding a numeric
+// suffix such as "-2" when needed, and adds it to used
+func UniqueSlug(title string, used map[string]bool) string {
+	slug := Slugify(title)
+	name := slug
+	for i := 2; used[name]; i++ {
+		suffix := fmt.Sprintf("-%d", i)
+		name = strings.TrimSuffix(elideMiddle(slug, maxSlugLength-len(suffix)), "-") + suffix
+	}
+	used[name] = true
+	return name
+}
+
+// normalizeTitle lowercases a title and collapses its whitespace so that
+// titles differing only by case or spacing compare equal
+func normalizeTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
@@ -0,0 +1,82 @@
+package notes
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSlugify(t *testing.T) {
+	long := strings.Repeat("word ", 40) + "end"
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Réunion d'équipe #3: Q&A", "reunion-d-equipe-3-q-a"},
+		{"Plain title", "plain-title"},
+		{"  Spaced   out  ", "spaced-out"},
+		{"Résumé", "resume"},
+		{"Re\u0301sume\u0301", "resume"}, // Decomposed accents
+		{"Straße Œuvre Æon", "strasse-oeuvre-aeon"},
+		{"Łódź, Kraków", "lodz-krakow"},
+		{"会議メモ", "会議メモ"},
+		{"東京 Trip 2024", "東京-trip-2024"},
+		{"회의록", "회의록"},
+		{"🚀 Launch 🎉", "launch"},
+		{"Ship it 👍🏽!", "ship-it"},
+		{"🎉", "untitled"},
+		{"", "untitled"},
+		{"  --  ", "untitled"},
+		{"CON", "con-note"},
+		{"nul", "nul-note"},
+		{"Aux", "aux-note"},
+		{"LPT9", "lpt9-note"},
+		{"Con men", "con-men"},
+		{"COM1.txt", "com1-txt"},
+		{"com10", "com10"},
+		{long, "word-word-word-word-word-word-word-word-word-word…word-word-word-word-end"},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.title); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestSlugifyLength(t *testing.T) {
+	for _, title := range []string{
+		strings.Repeat("a", 300),
+		strings.Repeat("日本語 ", 60),
+		strings.Repeat("é", 200),
+	} {
+		slug := Slugify(title)
+		if len(slug) > maxSlugLength {
+			t.Errorf("Slugify(%q) is %d bytes long, want at most %d", title, len(slug), maxSlugLength)
+		}
+		if !utf8.ValidString(slug) {
+			t.Errorf("Slugify(%q) = %q, cut in the middle of a character", title, slug)
+		}
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	used := map[string]bool{}
+	for _, want := range []string{"q-a", "q-a-2", "q-a-3"} {
+		if got := UniqueSlug("Q&A", used); got != want {
+			t.Errorf("UniqueSlug = %q, want %q", got, want)
+		}
+	}
+	if got := UniqueSlug("con", used); got != "con-note" {
+		t.Errorf("UniqueSlug(con) = %q, want con-note", got)
+	}
+	if got := UniqueSlug("CON", used); got != "con-note-2" {
+		t.Errorf("UniqueSlug(CON) = %q, want con-note-2", got)
+	}
+
+	long := strings.Repeat("word ", 40) + "end"
+	first := UniqueSlug(long, used)
+	second := UniqueSlug(long, used)
+	if first == second || !strings.HasSuffix(second, "-2") || len(second) > maxSlugLength {
+		t.Errorf("UniqueSlug of a long title gave %q then %q", first, second)
+	}
+}
@@ -0,0 +1,248 @@
+package notes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// snapshotDir is the folder of the store holding the snapshots, one
+// subfolder per day
+const snapshotDir = "snapshots"
+
+// snapshotLayout names the folder of each snapshot
+const snapshotLayout = "2006-01-02"
+
+// Snapshot is a copy of the store taken on a given day
+type Snapshot struct {
+	Name string // Day of the snapshot, YYYY-MM-DD
+	Path string
+	Day  time.Time
+}
+
+// SnapshotPolicy gives how many snapshots are kept: the latest daily ones,
+// then one per week and one per month. With every count at zero, snapshots
+// are only taken on demand and never pruned.
+type SnapshotPolicy struct {
+	Daily   int
+	Weekly  int
+	Monthly int
+}
+
+// enabled reports whether snapshots are taken automatically
+func (p SnapshotPolicy) enabled() bool {
+	return p.Daily > 0 || p.Weekly > 0 || p.Monthly > 0
+}
+
+// Snapshot copies the store into the snapshot of the day, replacing the one
+// already taken today, then prunes the old snapshots. Images never change
+// once stored so they are hard-linked when the file system allows it.
+func (m *NotesManager) Snapshot() (Snapshot, error) {
+	day := time.Now()
+	snapshot := Snapshot{
+		Name: day.Format(snapshotLayout),
+		Path: filepath.Join(m.StoragePath, snapshotDir, day.Format(snapshotLayout)),
+		Day:  day,
+	}
+	if m.DryRun {
+		return snapshot, nil
+	}
+
+	// The snapshot is built aside so that a failure leaves the previous one
+	tmp := snapshot.Path + ".tmp"
+	os.RemoveAll(tmp)
+	if err := os.MkdirAll(filepath.Join(tmp, "images"), 0755); err != nil {
+		return snapshot, fmt.Errorf("unable to create snapshot directory: %w", err)
+	}
+	if err := copyFile(filepath.Join(m.StoragePath, "notes.json"), filepath.Join(tmp, "notes.json")); err != nil && !errors.Is(err, os.ErrNotExist) {
+		os.RemoveAll(tmp)
+		return snapshot, fmt.Errorf("error copying notes: %w", err)
+	}
+
+	entries, err := os.ReadDir(m.ImageDir)
+	if err != nil && !os.IsNotExist(err) {
+		os.RemoveAll(tmp)
+		return snapshot, fmt.Errorf("error reading images directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		source := filepath.Join(m.ImageDir, entry.Name())
+		dest := filepath.Join(tmp, "images", entry.Name())
+		if err := os.Link(source, dest); err != nil {
+			if err := copyFile(source, dest); err != nil {
+				os.RemoveAll(tmp)
+				return snapshot, fmt.Errorf("error copying image %s: %w", entry.Name(), err)
+			}
+		}
+	}
+
+	if err := os.RemoveAll(snapshot.Path); err != nil {
+		os.RemoveAll(tmp)
+		return snapshot, fmt.Errorf("error replacing snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, snapshot.Path); err != nil {
+		return snapshot, fmt.Errorf("error saving snapshot: %w", err)
+	}
+
+	if _, err := m.PruneSnapshots(); err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+// snapshotIfDue takes the snapshot of the day before the first save of the
+// day, so that it holds the store as it was at the end of the previous one
+func (m *NotesManager) snapshotIfDue() error {
+	if !m.Snapshots.enabled() {
+		return nil
+	}
+	today := time.Now().Format(snapshotLayout)
+	if _, err := os.Stat(filepath.Join(m.StoragePath, snapshotDir, today)); err == nil {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(m.StoragePath, "notes.json")); os.IsNotExist(err) {
+		return nil // Nothing saved yet
+	}
+	_, err := m.Snapshot()
+	return err
+}
+
+// ListSnapshots returns the snapshots of the store, the most recent first
+func (m *NotesManager) ListSnapshots() ([]Snapshot, error) {
+	entries, err := os.ReadDir(filepath.Join(m.StoragePath, snapshotDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading snapshots: %w", err)
+	}
+
+	snapshots := []Snapshot{}
+	for _, entry := range entries {
+		day, err := time.ParseInLocation(snapshotLayout, entry.Name(), time.Local)
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{
+			Name: entry.Name(),
+			Path: filepath.Join(m.StoragePath, snapshotDir, entry.Name()),
+			Day:  day,
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Day.After(snapshots[j].Day) })
+	return snapshots, nil
+}
+
+// PruneSnapshots deletes the snapshots the policy doesn't keep and returns
+// how many were deleted
+func (m *NotesManager) PruneSnapshots() (int, error) {
+	if !m.Snapshots.enabled() {
+		return 0, nil
+	}
+	snapshots, err := m.ListSnapshots()
+	if err != nil {
+		return 0, err
+	}
+
+	keep := map[string]bool{}
+	weeks := map[string]bool{}
+	months := map[string]bool{}
+	for i, snapshot := range snapshots {
+		if i < m.Snapshots.Daily {
+			keep[snapshot.Name] = true
+		}
+		// The most recent snapshot of each week and month is kept
+		year, week := snapshot.Day.ISOWeek()
+		weekKey := fmt.Sprintf("%d-%d", year, week)
+		if !weeks[weekKey] && len(weeks) < m.Snapshots.Weekly {
+			weeks[weekKey] = true
+			keep[snapshot.Name] = true
+		}
+		monthKey := snapshot.Day.Format("2006-01")
+		if !months[monthKey] && len(months) < m.Snapshots.Monthly {
+			months[monthKey] = true
+			keep[snapshot.Name] = true
+		}
+	}
+
+	pruned := 0
+	for _, snapshot := range snapshots {
+		if keep[snapshot.Name] {
+			continue
+		}
+		if m.DryRun {
+			pruned++
+			continue
+		}
+		if err := os.RemoveAll(snapshot.Path); err != nil {
+			return pruned, fmt.Errorf("error deleting snapshot %s: %w", snapshot.Name, err)
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// SnapshotNote returns a note as it was in a snapshot
+func (m *NotesManager) SnapshotNote(name, id string) (*Note, error) {
+	data, err := os.ReadFile(filepath.Join(m.StoragePath, snapshotDir, name, "notes.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no snapshot %s", name)
+		}
+		return nil, fmt.Errorf("error reading snapshot %s: %w", name, err)
+	}
+	var notes []*Note
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return nil, fmt.Errorf("error deserializing snapshot %s: %w", name, err)
+	}
+	for _, note := range notes {
+		if note.ID == id {
+			return note, nil
+		}
+	}
+	return nil, fmt.Errorf("no note with ID %s in snapshot %s", id, name)
+}
+
+// RestoreNote brings back a single note as it was in a snapshot, leaving the
+// other notes alone. A note deleted since then is added back. Images missing
+// from the store are copied from the snapshot.
+func (m *NotesManager) RestoreNote(name, id string) (*Note, error) {
+	old, err := m.SnapshotNote(name, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, img := range old.Images {
+		if m.DryRun || m.ImageExists(img.Path) {
+			continue
+		}
+		source := filepath.Join(m.StoragePath, snapshotDir, name, "images", img.Path)
+		if err := copyFile(source, m.GetImageFullPath(img.Path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("error restoring image %s: %w", img.Path, err)
+		}
+	}
+
+	detail := "restored from the snapshot of " + name
+	current, err := m.GetNoteByID(id)
+	if err != nil {
+		m.Notes = append(m.Notes, old)
+		m.titleIndex = nil
+		if err := m.SaveNotes(); err != nil {
+			return nil, err
+		}
+		m.logSaved(old, detail)
+		return old, nil
+	}
+	current.Title = old.Title
+	current.Content = old.Content
+	current.Tags = old.Tags
+	current.Images = old.Images
+	current.Aliases = old.Aliases
+	return current, m.updateNote(current, detail)
+}
@@ -0,0 +1,53 @@
+package notes
+
+import "testing"
+
+func TestSnapshotsOffByDefault(t *testing.T) {
+	m := newTestManager(t, "A")
+	note := m.Notes[0]
+	note.Content = "changed"
+	if err := m.UpdateNote(note); err != nil {
+		t.Fatal(err)
+	}
+	snapshots, err := m.ListSnapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 0 {
+		t.Errorf("%d snapshot(s) taken without a policy", len(snapshots))
+	}
+
+	// Taken on demand, a snapshot isn't pruned
+	if _, err := m.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if snapshots, _ := m.ListSnapshots(); len(snapshots) != 1 {
+		t.Errorf("%d snapshot(s) after taking one, want 1", len(snapshots))
+	}
+}
+
+func TestSnapshotOnFirstSaveOfTheDay(t *testing.T) {
+	m := newTestManager(t, "A")
+	m.Snapshots = SnapshotPolicy{Daily: 7}
+	note := m.Notes[0]
+	for _, content := range []string{"first", "second"} {
+		note.Content = content
+		if err := m.UpdateNote(note); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshots, err := m.ListSnapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("%d snapshot(s) taken, want 1", len(snapshots))
+	}
+	saved, err := m.SnapshotNote(snapshots[0].Name, note.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Content != "" {
+		t.Errorf("snapshot holds %q, want the note as it was before the first save of the day", saved.Content)
+	}
+}
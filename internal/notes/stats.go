@@ -0,0 +1,57 @@
+package notes
+
+import (
+	"sort"
+	"time"
+)
+
+// Stats summarizes the store
+type Stats struct {
+	Notes      int
+	Archived   int
+	Pinned     int
+	Empty      int           // Notes with neither text nor images
+	Tags       int           // Distinct tags
+	Images     int           // Images attached to notes
+	TimeSpent  time.Duration // Time spent editing all the notes
+	MostEdited []*Note       // Notes with the most editing time, most first
+}
+
+// mostEditedCount is the number of notes listed in Stats.MostEdited
+const mostEditedCount = 5
+
+// Stats computes the statistics of the store
+func (m *NotesManager) Stats() Stats {
+	stats := Stats{Notes: len(m.Notes), Tags: len(m.GetAllTags())}
+	edited := []*Note{}
+	for _, note := range m.Notes {
+		if note.Archived {
+			stats.Archived++
+		}
+		if note.Pinned {
+			stats.Pinned++
+		}
+		if note.IsEmpty() {
+			stats.Empty++
+		}
+		stats.Images += len(note.Images)
+		stats.TimeSpent += note.TimeSpent
+		if note.TimeSpent > 0 {
+			edited = append(edited, note)
+		}
+	}
+
+	sort.SliceStable(edited, func(i, j int) bool { return edited[i].TimeSpent > edited[j].TimeSpent })
+	stats.MostEdited = edited[:min(len(edited), mostEditedCount)]
+	return stats
+}
+
+// AddTimeSpent adds editing time to a note and saves it. The update date is
+// left alone, spending time on a note doesn't mean it changed.
+func (m *NotesManager) AddTimeSpent(note *Note, spent time.Duration) error {
+	if spent <= 0 {
+		return nil
+	}
+	note.TimeSpent += spent
+	return m.save(note, note.UpdatedAt)
+}
@@ -0,0 +1,56 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// ErrStorageNotDir is returned when the storage path, or one of its
+// parents, is an existing file
+var ErrStorageNotDir = errors.New("storage path exists and is not a directory")
+
+// ErrStoragePermission is returned when the storage folder can't be read,
+// created or written
+var ErrStoragePermission = errors.New("permission denied on the storage path")
+
+// CheckStorage reports whether the storage folder can be used. A missing
+// folder is fine, it is created on the first save.
+func (m *NotesManager) CheckStorage() error {
+	info, err := os.Stat(m.StoragePath)
+	switch {
+	case err == nil && !info.IsDir():
+		return fmt.Errorf("%w: %s", ErrStorageNotDir, m.StoragePath)
+	case err == nil, errors.Is(err, fs.ErrNotExist):
+		return nil
+	default:
+		return storageError(m.StoragePath, err)
+	}
+}
+
+// ensureDir creates a directory of the store, with a clear error when the
+// path is taken by a file or not writable
+func (m *NotesManager) ensureDir(path string) error {
+	if err := m.CheckStorage(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return storageError(path, err)
+	}
+	return nil
+}
+
+// storageError turns the errors of the file system that mean the store
+// can't be used into ErrStorageNotDir and ErrStoragePermission
+func storageError(path string, err error) error {
+	switch {
+	case errors.Is(err, syscall.ENOTDIR), errors.Is(err, fs.ErrExist):
+		return fmt.Errorf("%w: %s", ErrStorageNotDir, path)
+	case errors.Is(err, fs.ErrPermission):
+		return fmt.Errorf("%w: %s", ErrStoragePermission, path)
+	default:
+		return fmt.Errorf("unable to use storage directory %s: %w", path, err)
+	}
+}
@@ -0,0 +1,89 @@
+package notes
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestCheckStorage(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(file, []byte("not a folder"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		path string
+		want error
+	}{
+		{"existing folder", dir, nil},
+		{"missing folder", filepath.Join(dir, "missing", "store"), nil},
+		{"file", file, ErrStorageNotDir},
+		{"folder inside a file", filepath.Join(file, "store"), ErrStorageNotDir},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := OpenNotesManager(tt.path)
+			if err := m.CheckStorage(); !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("CheckStorage() = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil {
+				return
+			}
+			m.CreateNote("Note")
+			if err := m.SaveNotes(); !errors.Is(err, tt.want) {
+				t.Errorf("SaveNotes() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckStoragePermission(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permissions don't apply to root")
+	}
+	locked := filepath.Join(t.TempDir(), "locked")
+	if err := os.Mkdir(locked, 0500); err != nil {
+		t.Fatal(err)
+	}
+	m := OpenNotesManager(filepath.Join(locked, "store"))
+	m.CreateNote("Note")
+	if err := m.SaveNotes(); !errors.Is(err, ErrStoragePermission) {
+		t.Errorf("SaveNotes() in an unwritable folder = %v, want ErrStoragePermission", err)
+	}
+
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(locked, 0755) })
+	if err := m.CheckStorage(); !errors.Is(err, ErrStoragePermission) {
+		t.Errorf("CheckStorage() in an unreadable folder = %v, want ErrStoragePermission", err)
+	}
+}
+
+func TestStorageError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{&fs.PathError{Op: "mkdir", Path: "p", Err: syscall.ENOTDIR}, ErrStorageNotDir},
+		{&fs.PathError{Op: "mkdir", Path: "p", Err: syscall.EEXIST}, ErrStorageNotDir},
+		{&fs.PathError{Op: "mkdir", Path: "p", Err: syscall.EACCES}, ErrStoragePermission},
+		{&fs.PathError{Op: "mkdir", Path: "p", Err: syscall.EPERM}, ErrStoragePermission},
+	}
+	for _, tt := range tests {
+		if err := storageError("p", tt.err); !errors.Is(err, tt.want) {
+			t.Errorf("storageError(%v) = %v, want %v", tt.err, err, tt.want)
+		}
+	}
+
+	other := &fs.PathError{Op: "mkdir", Path: "p", Err: syscall.ENOSPC}
+	err := storageError("p", other)
+	if errors.Is(err, ErrStorageNotDir) || errors.Is(err, ErrStoragePermission) || !errors.Is(err, syscall.ENOSPC) {
+		t.Errorf("storageError(%v) = %v, want the error wrapped as is", other, err)
+	}
+}
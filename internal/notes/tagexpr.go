@@ -0,0 +1,280 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TagExpr is a boolean expression on the tags of a note, such as
+// `work AND (urgent OR review) AND NOT done`. The operators are uppercase,
+// tags containing spaces, parentheses or an operator name are quoted.
+type TagExpr struct {
+	root   tagNode
+	source string
+}
+
+// TagExprError reports where an expression can't be parsed
+type TagExprError struct {
+	Pos int // Byte offset of the problem in the expression
+	Msg string
+}
+
+func (e *TagExprError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos+1)
+}
+
+// tagNode is a node of a parsed expression, evaluated on a set of tags
+type tagNode interface {
+	eval(tags map[string]bool) bool
+}
+
+type (
+	tagTerm string
+	tagNot  struct{ operand tagNode }
+	tagAnd  struct{ left, right tagNode }
+	tagOr   struct{ left, right tagNode }
+)
+
+func (t tagTerm) eval(tags map[string]bool) bool { return tags[string(t)] }
+func (n tagNot) eval(tags map[string]bool) bool  { return !n.operand.eval(tags) }
+func (a tagAnd) eval(tags map[string]bool) bool  { return a.left.eval(tags) && a.right.eval(tags) }
+func (o tagOr) eval(tags map[string]bool) bool   { return o.left.eval(tags) || o.right.eval(tags) }
+
+// AnyTag returns the expression matching notes with at least one of the tags
+func AnyTag(tags []string) *TagExpr {
+	quoted := make([]string, len(tags))
+	var root tagNode
+	for i, tag := range tags {
+		quoted[i] = quoteTag(tag)
+		if root == nil {
+			root = tagTerm(tag)
+		} else {
+			root = tagOr{root, tagTerm(tag)}
+		}
+	}
+	return &TagExpr{root: root, source: strings.Join(quoted, " OR ")}
+}
+
+// quoteTag quotes a tag for an expression, escaping the quotes and
+// backslashes it holds as the tokenizer reads them
+func quoteTag(tag string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(tag) + `"`
+}
+
+// Match reports whether a note with the given tags satisfies the expression.
+// An empty expression matches every note.
+func (e *TagExpr) Match(tags []string) bool {
+	if e.root == nil {
+		return true
+	}
+	set := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		set[tag] = true
+	}
+	return e.root.eval(set)
+}
+
+// String returns the expression as it was written
+func (e *TagExpr) String() string {
+	return e.source
+}
+
+// FilterByExpr returns the notes whose tags satisfy the expression
+func (m *NotesManager) FilterByExpr(expr *TagExpr) []*Note {
+	results := []*Note{}
+	for _, note := range m.Notes {
+		if expr.Match(note.Tags) {
+			results = append(results, note)
+		}
+	}
+	return results
+}
+
+// Tokens of the expressions
+type tagTokenKind int
+
+const (
+	tokenEnd tagTokenKind = iota
+	tokenTag
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+type tagToken struct {
+	kind tagTokenKind
+	text string
+	pos  int
+}
+
+// tagParser is a recursive descent parser of tag expressions:
+//
+//	or      = and { "OR" and }
+//	and     = not { "AND" not }
+//	not     = "NOT" not | primary
+//	primary = "(" or ")" | tag
+type tagParser struct {
+	tokens []tagToken
+	next   int
+}
+
+// ParseTagExpr parses a tag expression, a blank one matching every note
+func ParseTagExpr(source string) (*TagExpr, error) {
+	tokens, err := tokenizeTagExpr(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return &TagExpr{source: source}, nil
+	}
+
+	p := &tagParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEnd {
+		return nil, &TagExprError{tok.pos, fmt.Sprintf("unexpected %s", describeToken(tok))}
+	}
+	return &TagExpr{root: root, source: source}, nil
+}
+
+func (p *tagParser) peek() tagToken {
+	return p.tokens[p.next]
+}
+
+func (p *tagParser) advance() tagToken {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEnd {
+		p.next++
+	}
+	return tok
+}
+
+func (p *tagParser) parseOr() (tagNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.advance()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = tagOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *tagParser) parseAnd() (tagNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.advance()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = tagAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *tagParser) parseNot() (tagNode, error) {
+	if p.peek().kind == tokenNot {
+		p.advance()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return tagNot{operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *tagParser) parsePrimary() (tagNode, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokenTag:
+		return tagTerm(tok.text), nil
+	case tokenOpen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokenClose {
+			return nil, &TagExprError{closing.pos, fmt.Sprintf("expected ) to close the ( at position %d, found %s", tok.pos+1, describeToken(closing))}
+		}
+		return inner, nil
+	default:
+		return nil, &TagExprError{tok.pos, fmt.Sprintf("expected a tag, found %s", describeToken(tok))}
+	}
+}
+
+// describeToken names a token in error messages
+func describeToken(tok tagToken) string {
+	switch tok.kind {
+	case tokenEnd:
+		return "the end of the expression"
+	case tokenTag:
+		return fmt.Sprintf("tag %q", tok.text)
+	default:
+		return tok.text
+	}
+}
+
+// tokenizeTagExpr splits an expression into tokens, ending with tokenEnd
+func tokenizeTagExpr(source string) ([]tagToken, error) {
+	tokens := []tagToken{}
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, tagToken{tokenOpen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, tagToken{tokenClose, ")", i})
+			i++
+		case c == '"':
+			start := i
+			var tag strings.Builder
+			i++
+			for i < len(source) && source[i] != '"' {
+				if source[i] == '\\' && i+1 < len(source) {
+					i++
+				}
+				tag.WriteByte(source[i])
+				i++
+			}
+			if i >= len(source) {
+				return nil, &TagExprError{start, "unterminated quoted tag"}
+			}
+			i++
+			tokens = append(tokens, tagToken{tokenTag, tag.String(), start})
+		default:
+			start := i
+			for i < len(source) && !strings.ContainsRune(" \t\n()\"", rune(source[i])) {
+				i++
+			}
+			word := source[start:i]
+			kind := tokenTag
+			switch word {
+			case "AND":
+				kind = tokenAnd
+			case "OR":
+				kind = tokenOr
+			case "NOT":
+				kind = tokenNot
+			}
+			tokens = append(tokens, tagToken{kind, word, start})
+		}
+	}
+	return append(tokens, tagToken{tokenEnd, "", len(source)}), nil
+}
@@ -0,0 +1,133 @@
+package notes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// parseTagExpr parses an expression and fails the test on error
+func parseTagExpr(t *testing.T, source string) *TagExpr {
+	t.Helper()
+	expr, err := ParseTagExpr(source)
+	if err != nil {
+		t.Fatalf("ParseTagExpr(%q) = %v", source, err)
+	}
+	return expr
+}
+
+func TestTagExprMatch(t *testing.T) {
+	tests := []struct {
+		expr string
+		tags []string
+		want bool
+	}{
+		// NOT binds tighter than AND, which binds tighter than OR
+		{"a OR b AND NOT c", []string{"a", "c"}, true},
+		{"a OR b AND NOT c", []string{"b"}, true},
+		{"a OR b AND NOT c", []string{"b", "c"}, false},
+		{"NOT a AND b", []string{"b"}, true},
+		{"NOT a AND b", []string{"a", "b"}, false},
+		{"NOT a OR b", []string{"a", "b"}, true},
+		{"NOT NOT a", []string{"a"}, true},
+		{"a AND b OR c", []string{"c"}, true},
+
+		// Parentheses override the precedence
+		{"(a OR b) AND NOT c", []string{"a", "c"}, false},
+		{"NOT (a AND b)", []string{"a"}, true},
+		{"NOT (a AND b)", []string{"a", "b"}, false},
+		{"((a))", []string{"a"}, true},
+		{"work AND (urgent OR review) AND NOT done", []string{"work", "review"}, true},
+		{"work AND (urgent OR review) AND NOT done", []string{"work", "review", "done"}, false},
+
+		// Quoted tags hold spaces, operators and escaped characters
+		{`"my tag"`, []string{"my tag"}, true},
+		{`"my tag"`, []string{"my", "tag"}, false},
+		{`"AND" OR "NOT"`, []string{"AND"}, true},
+		{`"say \"hi\"" AND "back\\slash"`, []string{`say "hi"`, `back\slash`}, true},
+		{`"(x)"`, []string{"(x)"}, true},
+
+		// Tags are compared as written and operators are uppercase
+		{"Work", []string{"work"}, false},
+		{"and OR not", []string{"not"}, true},
+		{"", nil, true},
+		{"  ", []string{"a"}, true},
+	}
+	for _, tt := range tests {
+		if got := parseTagExpr(t, tt.expr).Match(tt.tags); got != tt.want {
+			t.Errorf("%q matches %q: %v, want %v", tt.expr, tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestTagExprString(t *testing.T) {
+	source := "work AND  NOT done"
+	if got := parseTagExpr(t, source).String(); got != source {
+		t.Errorf("String() = %q, want %q", got, source)
+	}
+}
+
+func TestAnyTagRoundTrip(t *testing.T) {
+	tags := []string{"work", "my tag", `say "hi"`, `back\slash`, "AND", "(x)", "tab\there", "café"}
+	expr := AnyTag(tags)
+	parsed := parseTagExpr(t, expr.String())
+	for _, tag := range tags {
+		if !expr.Match([]string{tag}) || !parsed.Match([]string{tag}) {
+			t.Errorf("%q not matched by %s", tag, expr)
+		}
+	}
+	for _, other := range []string{"home", "my", `say "hi`, "tabthere"} {
+		if expr.Match([]string{other}) || parsed.Match([]string{other}) {
+			t.Errorf("%q matched by %s", other, expr)
+		}
+	}
+	if !AnyTag(nil).Match(nil) {
+		t.Error("AnyTag without tags doesn't match every note")
+	}
+}
+
+func TestTagExprErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{`work AND "my tag`, 9, "unterminated quoted tag"},
+		{`"a\"`, 0, "unterminated quoted tag"},
+		{"(a OR b", 7, "expected ) to close the ( at position 1, found the end of the expression"},
+		{"a AND ((b OR c)", 15, "expected ) to close the ( at position 7"},
+		{"a AND", 5, "expected a tag, found the end of the expression"},
+		{"a OR NOT", 8, "expected a tag, found the end of the expression"},
+		{"AND a", 0, "expected a tag, found AND"},
+		{"a OR OR b", 5, "expected a tag, found OR"},
+		{"()", 1, "expected a tag, found )"},
+		{"a b", 2, `unexpected tag "b"`},
+		{"a)", 1, "unexpected )"},
+	}
+	for _, tt := range tests {
+		_, err := ParseTagExpr(tt.expr)
+		var exprErr *TagExprError
+		if !errors.As(err, &exprErr) {
+			t.Errorf("ParseTagExpr(%q) = %v, want a TagExprError", tt.expr, err)
+			continue
+		}
+		if exprErr.Pos != tt.pos || !strings.Contains(exprErr.Msg, tt.msg) {
+			t.Errorf("ParseTagExpr(%q) fails at %d with %q, want %d and %q", tt.expr, exprErr.Pos, exprErr.Msg, tt.pos, tt.msg)
+		}
+	}
+	err := &TagExprError{Pos: 5, Msg: "expected a tag"}
+	if got, want := err.Error(), "expected a tag at position 6"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestFilterByExpr(t *testing.T) {
+	m := OpenNotesManager(t.TempDir())
+	for _, tags := range [][]string{{"work"}, {"work", "done"}, {"home"}} {
+		m.CreateNote(strings.Join(tags, " ")).Tags = tags
+	}
+	results := m.FilterByExpr(parseTagExpr(t, "work AND NOT done"))
+	if len(results) != 1 || results[0].Title != "work" {
+		t.Errorf("work AND NOT done found %d note(s), want the one tagged work", len(results))
+	}
+}
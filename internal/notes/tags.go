@@ -0,0 +1,142 @@
+package notes
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// tagKey identifies a tag whatever its case and surrounding whitespace
+func tagKey(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// canonicalTags returns the spelling every tag should have, by tag key: its
+// lowercase form when lowercase is set, otherwise the spelling most notes use,
+// the first in alphabetical order on a tie
+func (m *NotesManager) canonicalTags(lowercase bool) map[string]string {
+	counts := map[string]int{}
+	for _, note := range m.Notes {
+		for _, tag := range note.Tags {
+			counts[strings.TrimSpace(tag)]++
+		}
+	}
+
+	canonical := map[string]string{}
+	for tag, count := range counts {
+		key := tagKey(tag)
+		if lowercase {
+			canonical[key] = key
+			continue
+		}
+		best, ok := canonical[key]
+		if !ok || count > counts[best] || (count == counts[best] && tag < best) {
+			canonical[key] = tag
+		}
+	}
+	return canonical
+}
+
+// normalizeTags returns tags in their canonical spelling, without duplicates
+// or empty tags
+func normalizeTags(tags []string, canonical map[string]string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = canonical[tagKey(tag)]
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// NormalizeAllTags gives every tag the same spelling in all notes, trimmed and
+// in lowercase when asked or else as most notes write it, merging the tags of
+// a note that become the same. It saves the store and returns the number of
+// notes changed.
+func (m *NotesManager) NormalizeAllTags(lowercase bool) (int, error) {
+	canonical := m.canonicalTags(lowercase)
+	changed := []*Note{}
+	for _, note := range m.Notes {
+		tags := normalizeTags(note.Tags, canonical)
+		if slices.Equal(tags, note.Tags) {
+			continue
+		}
+		note.Tags = tags
+		changed = append(changed, note)
+	}
+
+	if len(changed) == 0 {
+		return 0, nil
+	}
+	if err := m.SaveNotes(); err != nil {
+		return 0, err
+	}
+	for _, note := range changed {
+		m.logSaved(note, "tags normalized")
+	}
+	return len(changed), nil
+}
+
+// RenameTag gives the notes tagged old, whatever its case, the tag new
+// instead. On a note that already has new, the two tags merge into new. It
+// returns the notes that change; with dryRun they are only returned, and
+// nothing is changed or saved.
+func (m *NotesManager) RenameTag(old, new string, dryRun bool) ([]*Note, error) {
+	new = strings.TrimSpace(new)
+	if new == "" {
+		return nil, errors.New("the new tag is empty")
+	}
+
+	changed := []*Note{}
+	renamed := map[*Note][]string{}
+	for _, note := range m.Notes {
+		if !hasTag(note, old) {
+			continue
+		}
+		tags := []string{}
+		for _, tag := range note.Tags {
+			if tagKey(tag) == tagKey(old) || tagKey(tag) == tagKey(new) {
+				tag = new
+			}
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		if !slices.Equal(tags, note.Tags) {
+			changed = append(changed, note)
+			renamed[note] = tags
+		}
+	}
+	if dryRun || len(changed) == 0 {
+		return changed, nil
+	}
+
+	for _, note := range changed {
+		note.Tags = renamed[note]
+	}
+	if err := m.SaveNotes(); err != nil {
+		return nil, err
+	}
+	for _, note := range changed {
+		m.logSaved(note, "tag renamed")
+	}
+	return changed, nil
+}
+
+// CountTagged returns the number of notes having at least one of the tags,
+// whatever their case
+func (m *NotesManager) CountTagged(tags ...string) int {
+	count := 0
+	for _, note := range m.Notes {
+		if slices.ContainsFunc(tags, func(tag string) bool { return hasTag(note, tag) }) {
+			count++
+		}
+	}
+	return count
+}
+
+// hasTag reports whether a note has a tag, whatever its case
+func hasTag(note *Note, tag string) bool {
+	return slices.ContainsFunc(note.Tags, func(t string) bool { return tagKey(t) == tagKey(tag) })
+}
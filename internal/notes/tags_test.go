@@ -0,0 +1,188 @@
+package notes
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// taggedManager returns a saved store whose notes have the given tags, and
+// the notes in the same order
+func taggedManager(t *testing.T, tags ...[]string) (*NotesManager, []*Note) {
+	t.Helper()
+	m := newTestManager(t)
+	ns := []*Note{}
+	for _, noteTags := range tags {
+		note := m.CreateNote("Note")
+		note.Tags = noteTags
+		ns = append(ns, note)
+	}
+	if err := m.SaveNotes(); err != nil {
+		t.Fatal(err)
+	}
+	return m, ns
+}
+
+// tagsOf returns the tags of the notes, in order
+func tagsOf(ns []*Note) [][]string {
+	tags := [][]string{}
+	for _, note := range ns {
+		tags = append(tags, note.Tags)
+	}
+	return tags
+}
+
+func TestRenameTag(t *testing.T) {
+	m, ns := taggedManager(t,
+		[]string{"Work", "urgent"},
+		[]string{" work "},
+		[]string{"job", "WORK"}, // Already has the new tag
+		[]string{"home"},
+	)
+	changed, err := m.RenameTag("work", "job", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 3 || slices.Contains(changed, ns[3]) {
+		t.Errorf("%d note(s) changed, want the ones tagged work", len(changed))
+	}
+	want := [][]string{{"job", "urgent"}, {"job"}, {"job"}, {"home"}}
+	if got := tagsOf(ns); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("tags after renaming %q, want %q", got, want)
+	}
+
+	reloaded, err := NewNotesManager(m.StoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, note := range ns {
+		if saved, err := reloaded.GetNoteByID(note.ID); err != nil || !slices.Equal(saved.Tags, want[i]) {
+			t.Errorf("tags of note %d not saved", i)
+		}
+	}
+}
+
+func TestRenameTagDryRun(t *testing.T) {
+	m, ns := taggedManager(t, []string{"Work"}, []string{"home"}, []string{"work", "Job"})
+	before := tagsOf(ns)
+	ageStore(t, m.StoragePath)
+	files := storeState(t, m.StoragePath)
+
+	changed, err := m.RenameTag("WORK", "job", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 2 || slices.Contains(changed, ns[1]) {
+		t.Errorf("dry run returns %d note(s), want the ones tagged work", len(changed))
+	}
+	if got := tagsOf(ns); !slices.EqualFunc(got, before, slices.Equal) {
+		t.Errorf("dry run changed the tags to %q", got)
+	}
+	if !maps.Equal(storeState(t, m.StoragePath), files) {
+		t.Error("dry run wrote to the store")
+	}
+}
+
+func TestRenameTagNothingToDo(t *testing.T) {
+	m, ns := taggedManager(t, []string{"job"}, []string{"home"})
+	if changed, err := m.RenameTag("work", "job", false); err != nil || len(changed) != 0 {
+		t.Errorf("renaming a missing tag changed %d note(s) with %v", len(changed), err)
+	}
+	// Renaming to the same tag changes only other spellings
+	if changed, err := m.RenameTag("job", "job", false); err != nil || len(changed) != 0 {
+		t.Errorf("renaming a tag to itself changed %d note(s) with %v", len(changed), err)
+	}
+	if _, err := m.RenameTag("home", "  ", false); err == nil {
+		t.Error("tag renamed to an empty tag")
+	}
+	if got := tagsOf(ns); !slices.EqualFunc(got, [][]string{{"job"}, {"home"}}, slices.Equal) {
+		t.Errorf("tags changed to %q", got)
+	}
+}
+
+func TestNormalizeAllTags(t *testing.T) {
+	tests := []struct {
+		name      string
+		tags      [][]string
+		lowercase bool
+		want      [][]string
+	}{
+		{
+			name: "spelling of most notes",
+			tags: [][]string{{"Work"}, {"work"}, {"Work", "home"}},
+			want: [][]string{{"Work"}, {"Work"}, {"Work", "home"}},
+		},
+		{
+			name: "first spelling alphabetically on a tie",
+			tags: [][]string{{"work"}, {"Work"}},
+			want: [][]string{{"Work"}, {"Work"}},
+		},
+		{
+			name: "whitespace trimmed",
+			tags: [][]string{{" work "}, {"work\t"}, {"home"}},
+			want: [][]string{{"work"}, {"work"}, {"home"}},
+		},
+		{
+			name: "variants of a note merged",
+			tags: [][]string{{"Work", "home", " WORK", "work"}, {"work"}},
+			want: [][]string{{"work", "home"}, {"work"}},
+		},
+		{
+			name: "empty tags dropped",
+			tags: [][]string{{"work", "  ", ""}},
+			want: [][]string{{"work"}},
+		},
+		{
+			name:      "lowercase",
+			tags:      [][]string{{"Work", "HOME"}, {"Work"}, {"work", " Work "}},
+			lowercase: true,
+			want:      [][]string{{"work", "home"}, {"work"}, {"work"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, ns := taggedManager(t, tt.tags...)
+			changed, err := m.NormalizeAllTags(tt.lowercase)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantChanged := 0
+			for i := range tt.tags {
+				if !slices.Equal(tt.tags[i], tt.want[i]) {
+					wantChanged++
+				}
+			}
+			if changed != wantChanged {
+				t.Errorf("%d note(s) changed, want %d", changed, wantChanged)
+			}
+			if got := tagsOf(ns); !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("tags %q, want %q", got, tt.want)
+			}
+
+			reloaded, err := NewNotesManager(m.StoragePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, note := range ns {
+				if saved, err := reloaded.GetNoteByID(note.ID); err != nil || !slices.Equal(saved.Tags, tt.want[i]) {
+					t.Errorf("tags of note %d not saved", i)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeAllTagsNothingToDo(t *testing.T) {
+	m, ns := taggedManager(t, []string{"Work", "home"}, []string{"Work"})
+	ageStore(t, m.StoragePath)
+	files := storeState(t, m.StoragePath)
+	if changed, err := m.NormalizeAllTags(false); err != nil || changed != 0 {
+		t.Errorf("normalizing tags already alike changed %d note(s) with %v", changed, err)
+	}
+	if !maps.Equal(storeState(t, m.StoragePath), files) {
+		t.Error("store written without any change")
+	}
+	if got := tagsOf(ns); !slices.EqualFunc(got, [][]string{{"Work", "home"}, {"Work"}}, slices.Equal) {
+		t.Errorf("tags changed to %q", got)
+	}
+}
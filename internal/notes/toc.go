@@ -0,0 +1,172 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Markers delimiting the table of contents of a note, which is refreshed
+// between them rather than inserted again
+const (
+	TOCStart = "<!-- toc -->"
+	TOCEnd   = "<!-- /toc -->"
+)
+
+// headingLinkRegex matches the inline links and images of a heading, of
+// which only the text is shown
+var headingLinkRegex = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+
+// Heading is an ATX heading of a note
+type Heading struct {
+	Level  int
+	Text   string
+	Anchor string // Anchor GitHub gives the heading, unique within the note
+}
+
+// Headings returns the ATX headings of content outside code blocks, their
+// anchors numbered as GitHub does when several headings share a text
+func Headings(content string) []Heading {
+	headings := []Heading{}
+	seen := map[string]int{}
+	fence := ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		default:
+			if level, text, ok := parseHeading(line); ok {
+				headings = append(headings, Heading{Level: level, Text: text, Anchor: uniqueAnchor(text, seen)})
+			}
+		}
+	}
+	return headings
+}
+
+// parseHeading returns the level and text of an ATX heading, without its
+// closing #s. Indented code and #tags aren't headings.
+func parseHeading(line string) (level int, text string, ok bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return 0, "", false
+	}
+	level = len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+	if level < 1 || level > 6 || (level < len(trimmed) && trimmed[level] != ' ' && trimmed[level] != '\t') {
+		return 0, "", false
+	}
+	text = strings.TrimSpace(trimmed[level:])
+	if closed := strings.TrimRight(text, "#"); closed == "" || strings.HasSuffix(closed, " ") || strings.HasSuffix(closed, "\t") {
+		text = strings.TrimSpace(closed)
+	}
+	return level, text, text != ""
+}
+
+// Anchor returns the anchor GitHub gives a heading: its text in lowercase,
+// without punctuation and with dashes for spaces
+func Anchor(text string) string {
+	text = headingLinkRegex.ReplaceAllString(text, "$1")
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsNumber(r), unicode.IsMark(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// uniqueAnchor returns the anchor of a heading, suffixed with -1, -2… when
+// an earlier heading took it, as GitHub does
+func uniqueAnchor(text string, seen map[string]int) string {
+	base := Anchor(text)
+	anchor := base
+	for {
+		if _, taken := seen[anchor]; !taken {
+			break
+		}
+		seen[base]++
+		anchor = fmt.Sprintf("%s-%d", base, seen[base])
+	}
+	seen[anchor] = 0
+	return anchor
+}
+
+// TableOfContents returns the lines of the table of contents of content
+// between its markers: a list linking to the headings down to maxDepth,
+// nested by level. It returns nil when there is no such heading.
+func TableOfContents(content string, maxDepth int) []string {
+	headings := []Heading{}
+	top := maxDepth
+	for _, heading := range Headings(content) {
+		if heading.Level <= maxDepth {
+			headings = append(headings, heading)
+			top = min(top, heading.Level)
+		}
+	}
+	if len(headings) == 0 {
+		return nil
+	}
+
+	lines := []string{TOCStart}
+	for _, heading := range headings {
+		text := headingLinkRegex.ReplaceAllString(heading.Text, "$1")
+		text = strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text)
+		lines = append(lines, fmt.Sprintf("%s- [%s](#%s)", strings.Repeat("  ", heading.Level-top), text, heading.Anchor))
+	}
+	return append(lines, TOCEnd)
+}
+
+// SetTableOfContents replaces the table of contents between the markers of
+// content with toc or, when there are none, inserts it before line row
+// surrounded by blank lines. It returns the new content, the line following
+// the table and whether an existing table was refreshed. Refreshing an up to
+// date table leaves content as it was.
+func SetTableOfContents(content string, toc []string, row int) (updated string, end int, refreshed bool) {
+	lines := strings.Split(content, "\n")
+	if start, stop, ok := tocMarkers(lines); ok {
+		lines = append(lines[:start], append(toc, lines[stop+1:]...)...)
+		return strings.Join(lines, "\n"), start + len(toc), true
+	}
+
+	row = max(0, min(row, len(lines)))
+	block := toc
+	if row > 0 && strings.TrimSpace(lines[row-1]) != "" {
+		block = append([]string{""}, block...)
+	}
+	if row < len(lines) && strings.TrimSpace(lines[row]) != "" {
+		block = append(block, "")
+	}
+	lines = append(lines[:row], append(block, lines[row:]...)...)
+	return strings.Join(lines, "\n"), row + len(block), false
+}
+
+// tocMarkers returns the lines of the first pair of table of contents
+// markers outside code blocks
+func tocMarkers(lines []string) (start, stop int, ok bool) {
+	start = -1
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case start < 0 && trimmed == TOCStart:
+			start = i
+		case start >= 0 && trimmed == TOCEnd:
+			return start, i, true
+		}
+	}
+	return 0, 0, false
+}
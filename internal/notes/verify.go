@@ -0,0 +1,215 @@
+package notes
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// IssueKind identifies a category of problem found in the store
+type IssueKind string
+
+// Problems reported by Verify
+const (
+	IssueDuplicateID  IssueKind = "duplicate-id"
+	IssueMissingImage IssueKind = "missing-image"
+	IssueOrphanImage  IssueKind = "orphan-image"
+	IssueEmptyTitle   IssueKind = "empty-title"
+	IssueDanglingLink IssueKind = "dangling-link"
+	IssueTagVariant   IssueKind = "tag-variant"
+)
+
+// Issue is a problem found in the store with a suggested fix
+type Issue struct {
+	Kind    IssueKind
+	NoteID  string // Note concerned, empty for store-wide issues
+	Note    *Note  // Note concerned, nil for store-wide issues
+	Subject string // Image path or link target the issue is about
+	Message string
+	Fix     string // Suggested fix
+	Fixable bool   // Whether Repair can apply the fix safely
+}
+
+// Verify checks the store for inconsistencies: duplicate IDs, images missing
+// from disk, image files no note uses, untitled notes, wikilinks to notes
+// that don't exist and tags written differently from one note to another
+func (m *NotesManager) Verify() []Issue {
+	issues := []Issue{}
+	canonical := m.canonicalTags(false)
+
+	seen := map[string]int{}
+	for _, note := range m.Notes {
+		seen[note.ID]++
+		if seen[note.ID] == 2 {
+			issues = append(issues, Issue{
+				Kind:    IssueDuplicateID,
+				NoteID:  note.ID,
+				Note:    note,
+				Message: fmt.Sprintf("ID %s is shared by several notes", note.ID),
+				Fix:     "give the duplicates a new ID",
+				Fixable: true,
+			})
+		}
+	}
+
+	used := map[string]bool{}
+	for _, note := range m.Notes {
+		if strings.TrimSpace(note.Title) == "" {
+			issues = append(issues, Issue{
+				Kind:    IssueEmptyTitle,
+				NoteID:  note.ID,
+				Note:    note,
+				Message: fmt.Sprintf("note %s has no title", note.ID),
+				Fix:     fmt.Sprintf("title it %q", untitledTitle),
+				Fixable: true,
+			})
+		}
+
+		for _, img := range note.Images {
+			used[img.Path] = true
+			if !m.ImageExists(img.Path) {
+				issues = append(issues, Issue{
+					Kind:    IssueMissingImage,
+					NoteID:  note.ID,
+					Note:    note,
+					Subject: img.Path,
+					Message: fmt.Sprintf("%q references the missing image %s", note.Title, img.Path),
+					Fix:     "remove the image from the note",
+					Fixable: true,
+				})
+			}
+		}
+
+		if tags := normalizeTags(note.Tags, canonical); !slices.Equal(tags, note.Tags) {
+			issues = append(issues, Issue{
+				Kind:    IssueTagVariant,
+				NoteID:  note.ID,
+				Note:    note,
+				Message: fmt.Sprintf("%q is tagged %s instead of %s", note.Title, quoteAll(note.Tags), quoteAll(tags)),
+				Fix:     "use the spelling of the other notes and merge the duplicates",
+				Fixable: true,
+			})
+		}
+
+		for _, link := range WikiLinks(note.Content) {
+			if _, err := m.FindByTitle(link.Target); err != nil {
+				issues = append(issues, Issue{
+					Kind:    IssueDanglingLink,
+					NoteID:  note.ID,
+					Note:    note,
+					Subject: link.Target,
+					Message: fmt.Sprintf("%q links to the missing note %q", note.Title, link.Target),
+					Fix:     "create the note or correct the link",
+				})
+			}
+		}
+	}
+
+	if entries, err := os.ReadDir(m.ImageDir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() || used[entry.Name()] {
+				continue
+			}
+			issues = append(issues, Issue{
+				Kind:    IssueOrphanImage,
+				Subject: entry.Name(),
+				Message: fmt.Sprintf("image %s isn't used by any note", entry.Name()),
+				Fix:     "delete the file if it is no longer needed",
+			})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Kind < issues[j].Kind
+	})
+	return issues
+}
+
+// quoteAll quotes each of a list of tags, so that whitespace shows
+func quoteAll(tags []string) string {
+	quoted := make([]string, len(tags))
+	for i, tag := range tags {
+		quoted[i] = fmt.Sprintf("%q", tag)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// untitledTitle is given to notes repaired for having no title
+const untitledTitle = "Untitled"
+
+// Repair applies the safe fixes of the given issues and saves the store.
+// It returns the number of issues fixed.
+func (m *NotesManager) Repair(issues []Issue) (int, error) {
+	fixed := 0
+	repaired := []*Note{}
+	canonical := m.canonicalTags(false)
+	for _, issue := range issues {
+		if !issue.Fixable || issue.Note == nil {
+			continue
+		}
+		if !slices.Contains(repaired, issue.Note) {
+			repaired = append(repaired, issue.Note)
+		}
+		switch issue.Kind {
+		case IssueDuplicateID:
+			fixed += m.renumberDuplicates(issue.NoteID)
+		case IssueEmptyTitle:
+			issue.Note.Title = untitledTitle
+			fixed++
+		case IssueTagVariant:
+			issue.Note.Tags = normalizeTags(issue.Note.Tags, canonical)
+			fixed++
+		case IssueMissingImage:
+			for i, img := range issue.Note.Images {
+				if img.Path == issue.Subject {
+					issue.Note.Images = append(issue.Note.Images[:i], issue.Note.Images[i+1:]...)
+					fixed++
+					break
+				}
+			}
+		}
+	}
+
+	if fixed == 0 {
+		return 0, nil
+	}
+	m.titleIndex = nil
+	if err := m.SaveNotes(); err != nil {
+		return fixed, err
+	}
+	for _, note := range repaired {
+		m.logSaved(note, "repaired")
+	}
+	return fixed, nil
+}
+
+// renumberDuplicates gives a new ID to every note sharing id except the first
+// and returns how many were changed
+func (m *NotesManager) renumberDuplicates(id string) int {
+	changed := 0
+	first := true
+	for _, note := range m.Notes {
+		if note.ID != id {
+			continue
+		}
+		if first {
+			first = false
+			continue
+		}
+		note.ID = m.uniqueID()
+		changed++
+	}
+	return changed
+}
+
+// uniqueID generates an ID no note of the store uses
+func (m *NotesManager) uniqueID() string {
+	for {
+		id := generateID()
+		if _, err := m.GetNoteByID(id); err != nil {
+			return id
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeNotesFile writes a notes.json with the given content into dir
+func writeNotesFile(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "notes.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadNotesFixesDuplicateIDs(t *testing.T) {
+	dir := t.TempDir()
+	writeNotesFile(t, dir, `[
+		{"id": "dup", "title": "First", "content": "one"},
+		{"id": "other", "title": "Other", "content": "two"},
+		{"id": "dup", "title": "Second", "content": "three"},
+		{"id": "dup", "title": "Third", "content": "four"}
+	]`)
+
+	m := OpenNotesManager(dir)
+	if err := m.LoadNotes(); err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]string{}
+	for _, note := range m.Notes {
+		if title, taken := ids[note.ID]; taken {
+			t.Errorf("%q and %q share ID %s", title, note.Title, note.ID)
+		}
+		ids[note.ID] = note.Title
+	}
+	if ids["dup"] != "First" || ids["other"] != "Other" {
+		t.Errorf("notes by ID %v, the first of the duplicates must keep its ID", ids)
+	}
+	for id, title := range ids {
+		note, err := m.GetNoteByID(id)
+		if err != nil || note.Title != title {
+			t.Errorf("GetNoteByID(%s) = %v, %v, want %q", id, note, err, title)
+		}
+	}
+
+	// The fix is saved, loading again gives the same IDs
+	again := OpenNotesManager(dir)
+	if err := again.LoadNotes(); err != nil {
+		t.Fatal(err)
+	}
+	for _, note := range again.Notes {
+		if ids[note.ID] != note.Title {
+			t.Errorf("note %q has ID %s after loading again", note.Title, note.ID)
+		}
+	}
+}
+
+func TestLoadNotesWithoutDuplicatesUntouched(t *testing.T) {
+	dir := t.TempDir()
+	content := `[{"id": "a", "title": "A"}, {"id": "b", "title": "B"}]`
+	writeNotesFile(t, dir, content)
+
+	m := OpenNotesManager(dir)
+	if err := m.LoadNotes(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "notes.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != content {
+		t.Errorf("notes.json rewritten without duplicate IDs:\n%s", data)
+	}
+}
+
+// fixtureStore returns the manager of a store holding the notes of
+// notesJSON and image files of the given names
+func fixtureStore(t *testing.T, notesJSON string, images ...string) *NotesManager {
+	t.Helper()
+	dir := t.TempDir()
+	writeNotesFile(t, dir, notesJSON)
+	if err := os.MkdirAll(filepath.Join(dir, "images"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range images {
+		if err := os.WriteFile(filepath.Join(dir, "images", name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, err := NewNotesManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		notes   string
+		images  []string
+		kind    IssueKind
+		noteID  string
+		subject string
+		fixable bool
+	}{
+		{
+			name:    "missing image",
+			notes:   `[{"id": "a", "title": "A", "images": [{"path": "kept.png"}, {"path": "gone.png"}]}]`,
+			images:  []string{"kept.png"},
+			kind:    IssueMissingImage,
+			noteID:  "a",
+			subject: "gone.png",
+			fixable: true,
+		},
+		{
+			name:    "orphan image",
+			notes:   `[{"id": "a", "title": "A", "images": [{"path": "kept.png"}]}]`,
+			images:  []string{"kept.png", "stray.png"},
+			kind:    IssueOrphanImage,
+			subject: "stray.png",
+		},
+		{
+			name:    "empty title",
+			notes:   `[{"id": "a", "title": "  "}]`,
+			kind:    IssueEmptyTitle,
+			noteID:  "a",
+			fixable: true,
+		},
+		{
+			name:    "dangling link",
+			notes:   `[{"id": "a", "title": "A", "content": "See [[A]] and [[Nowhere|there]]"}]`,
+			kind:    IssueDanglingLink,
+			noteID:  "a",
+			subject: "Nowhere",
+		},
+		{
+			name:    "tag variant",
+			notes:   `[{"id": "a", "title": "A", "tags": ["Work"]}, {"id": "b", "title": "B", "tags": ["Work"]}, {"id": "c", "title": "C", "tags": ["work "]}]`,
+			kind:    IssueTagVariant,
+			noteID:  "c",
+			fixable: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := fixtureStore(t, tt.notes, tt.images...)
+			issues := m.Verify()
+			if len(issues) != 1 {
+				t.Fatalf("%d issue(s) found, want 1: %+v", len(issues), issues)
+			}
+			issue := issues[0]
+			if issue.Kind != tt.kind || issue.NoteID != tt.noteID || issue.Subject != tt.subject || issue.Fixable != tt.fixable {
+				t.Errorf("issue %+v, want %s on note %q about %q, fixable %v", issue, tt.kind, tt.noteID, tt.subject, tt.fixable)
+			}
+			if (issue.Note == nil) != (tt.noteID == "") || issue.Message == "" || issue.Fix == "" {
+				t.Errorf("issue %+v incomplete", issue)
+			}
+
+			// Repairing fixes the safe issues only
+			wantFixed := 0
+			if tt.fixable {
+				wantFixed = 1
+			}
+			fixed, err := m.Repair(issues)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fixed != wantFixed {
+				t.Errorf("Repair fixed %d issue(s), want %d", fixed, wantFixed)
+			}
+			if left := len(m.Verify()); left != 1-wantFixed {
+				t.Errorf("%d issue(s) left after repairing, want %d", left, 1-wantFixed)
+			}
+		})
+	}
+}
+
+func TestVerifyDuplicateID(t *testing.T) {
+	m := fixtureStore(t, `[{"id": "a", "title": "A"}, {"id": "b", "title": "B"}]`)
+	// Loading renumbers duplicates, they can only come from notes added since
+	b, err := m.GetNoteByID("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.ID = "a"
+	m.Notes = append(m.Notes, &Note{ID: "a", Title: "C"})
+	issues := m.Verify()
+	if len(issues) != 1 || issues[0].Kind != IssueDuplicateID || issues[0].NoteID != "a" || !issues[0].Fixable {
+		t.Fatalf("issues %+v, want one duplicate ID a", issues)
+	}
+	if fixed, err := m.Repair(issues); err != nil || fixed != 2 {
+		t.Errorf("Repair fixed %d issue(s) with %v, want 2", fixed, err)
+	}
+	ids := []string{}
+	for _, note := range m.Notes {
+		ids = append(ids, note.ID)
+	}
+	slices.Sort(ids)
+	if len(slices.Compact(ids)) != 3 {
+		t.Errorf("IDs after repairing: %v", ids)
+	}
+}
+
+func TestVerifyCleanStore(t *testing.T) {
+	m := fixtureStore(t, `[{"id": "a", "title": "A", "content": "[[B]]", "tags": ["work"], "images": [{"path": "x.png"}]}, {"id": "b", "title": "B", "tags": ["work"]}]`, "x.png")
+	if issues := m.Verify(); len(issues) != 0 {
+		t.Errorf("issues in a clean store: %+v", issues)
+	}
+}
+
+func TestVerifyOrder(t *testing.T) {
+	m := fixtureStore(t, `[{"id": "a", "title": "", "content": "[[Missing]]", "images": [{"path": "gone.png"}]}]`, "stray.png")
+	kinds := []IssueKind{}
+	for _, issue := range m.Verify() {
+		kinds = append(kinds, issue.Kind)
+	}
+	want := []IssueKind{IssueDanglingLink, IssueEmptyTitle, IssueMissingImage, IssueOrphanImage}
+	if !slices.Equal(kinds, want) {
+		t.Errorf("issues %v, want %v", kinds, want)
+	}
+}
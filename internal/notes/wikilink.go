@@ -0,0 +1,41 @@
+package notes
+
+import (
+	"regexp"
+	"strings"
+)
+
+// wikilinkRegex matches [[Title]] and [[Title|label]] links between notes
+var wikilinkRegex = regexp.MustCompile(`\[\[([^\[\]|]+)(?:\|([^\[\]]+))?\]\]`)
+
+// WikiLink is a link to another note written as [[Title]] or [[Title|label]]
+type WikiLink struct {
+	Target string // Title of the linked note
+	Label  string // Text displayed for the link
+}
+
+// ReplaceWikiLinks calls replace for every wikilink of content and substitutes
+// the link with the returned text
+func ReplaceWikiLinks(content string, replace func(link WikiLink) string) string {
+	return wikilinkRegex.ReplaceAllStringFunc(content, func(match string) string {
+		parts := wikilinkRegex.FindStringSubmatch(match)
+		link := WikiLink{
+			Target: strings.TrimSpace(parts[1]),
+			Label:  strings.TrimSpace(parts[2]),
+		}
+		if link.Label == "" {
+			link.Label = link.Target
+		}
+		return replace(link)
+	})
+}
+
+// WikiLinks returns the wikilinks found in content
+func WikiLinks(content string) []WikiLink {
+	links := []WikiLink{}
+	ReplaceWikiLinks(content, func(link WikiLink) string {
+		links = append(links, link)
+		return ""
+	})
+	return links
+}
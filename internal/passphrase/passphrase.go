@@ -0,0 +1,77 @@
+package passphrase
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parameters of the key derivation used for new hashes
+const (
+	scheme     = "pbkdf2-sha256"
+	iterations = 600000
+	saltSize   = 16
+	keySize    = 32
+)
+
+// ErrInvalidHash is returned for hashes not produced by Hash
+var ErrInvalidHash = errors.New("invalid passphrase hash")
+
+// Hash derives a storable hash of a passphrase, formatted as
+// "pbkdf2-sha256$<iterations>$<salt>$<key>"
+func Hash(passphrase string) (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("error generating salt: %w", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, keySize)
+	if err != nil {
+		return "", fmt.Errorf("error hashing passphrase: %w", err)
+	}
+	return strings.Join([]string{
+		scheme,
+		strconv.Itoa(iterations),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	}, "$"), nil
+}
+
+// Verify reports whether passphrase matches a hash produced by Hash
+func Verify(passphrase, hash string) (bool, error) {
+	if !Valid(hash) {
+		return false, ErrInvalidHash
+	}
+	parts := strings.Split(hash, "$")
+	iter, _ := strconv.Atoi(parts[1])
+	salt, _ := base64.RawStdEncoding.DecodeString(parts[2])
+	want, _ := base64.RawStdEncoding.DecodeString(parts[3])
+
+	got, err := pbkdf2.Key(sha256.New, passphrase, salt, iter, len(want))
+	if err != nil {
+		return false, fmt.Errorf("error hashing passphrase: %w", err)
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+// Valid reports whether hash has the format produced by Hash
+func Valid(hash string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != scheme {
+		return false
+	}
+	if iter, err := strconv.Atoi(parts[1]); err != nil || iter <= 0 {
+		return false
+	}
+	for _, part := range parts[2:] {
+		if data, err := base64.RawStdEncoding.DecodeString(part); err != nil || len(data) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+// Package pathcomplete completes partially typed file paths, as a shell does
+package pathcomplete
+
+import (
+	"datapad/internal/expand"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Completion is what a partially typed path can continue into
+type Completion struct {
+	Value      string   // Path completed as far as every candidate agrees
+	Candidates []string // Names of the matching entries, directories ending with a slash
+}
+
+// Complete completes the last element of a path with the entries of its
+// directory. A leading ~ and environment variables are expanded to read the
+// directory and kept as typed in the completed path. Hidden entries are only
+// offered once a dot is typed.
+func Complete(path string) Completion {
+	if path == "~" {
+		return Completion{Value: "~/"}
+	}
+
+	dir, base := "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		dir, base = path[:i+1], path[i+1:]
+	}
+	entries, err := os.ReadDir(directory(dir))
+	if err != nil {
+		return Completion{Value: path}
+	}
+
+	candidates := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".")) {
+			continue
+		}
+		// Links to directories are completed as directories
+		if info, err := os.Stat(filepath.Join(directory(dir), name)); err == nil && info.IsDir() {
+			name += "/"
+		}
+		candidates = append(candidates, name)
+	}
+	if len(candidates) == 0 {
+		return Completion{Value: path}
+	}
+	return Completion{Value: dir + commonPrefix(candidates), Candidates: candidates}
+}
+
+// directory returns the directory to read for the directory part of a path
+func directory(dir string) string {
+	if dir == "" {
+		return "."
+	}
+	return expand.Path(dir)
+}
+
+// commonPrefix returns the longest beginning shared by the names, never
+// cutting a character in two
+func commonPrefix(names []string) string {
+	prefix := names[0]
+	for _, name := range names[1:] {
+		i := 0
+		for i < len(prefix) && i < len(name) && prefix[i] == name[i] {
+			i++
+		}
+		prefix = prefix[:i]
+	}
+	for !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+	return prefix
+}
@@ -0,0 +1,101 @@
+package pathcomplete
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// tree creates files and directories, those ending with a slash, in a
+// temporary directory and returns it
+func tree(t *testing.T, paths ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, path := range paths {
+		full := filepath.Join(root, path)
+		if path[len(path)-1] == '/' {
+			if err := os.MkdirAll(full, 0755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestComplete(t *testing.T) {
+	root := tree(t, "notes.md", "notebook/", "shot-1.png", "shot-2.png", ".hidden", ".config/",
+		"accents/élan.png", "accents/èbe.png", "accents/étoile.png")
+	if err := os.Symlink(filepath.Join(root, "notebook"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		value      string
+		candidates []string
+	}{
+		{"common prefix", "note", "note", []string{"notebook/", "notes.md"}},
+		{"single file", "notes", "notes.md", []string{"notes.md"}},
+		{"directory", "noteb", "notebook/", []string{"notebook/"}},
+		{"link to a directory", "li", "link/", []string{"link/"}},
+		{"prefix longer than typed", "sh", "shot-", []string{"shot-1.png", "shot-2.png"}},
+		{"hidden entries left out", "", "", []string{"accents/", "link/", "notebook/", "notes.md", "shot-1.png", "shot-2.png"}},
+		{"hidden entries once a dot is typed", ".", ".", []string{".config/", ".hidden"}},
+		{"no match", "zzz", "zzz", nil},
+		{"missing directory", "missing/x", "missing/x", nil},
+		{"characters never cut", "accents/", "accents/", []string{"èbe.png", "élan.png", "étoile.png"}},
+		{"multi-byte prefix", "accents/é", "accents/é", []string{"élan.png", "étoile.png"}},
+		{"multi-byte prefix completed", "accents/ét", "accents/étoile.png", []string{"étoile.png"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Complete(root + "/" + tt.path)
+			if got.Value != root+"/"+tt.value {
+				t.Errorf("value %q, want %q", got.Value, root+"/"+tt.value)
+			}
+			if !slices.Equal(got.Candidates, tt.candidates) {
+				t.Errorf("candidates %q, want %q", got.Candidates, tt.candidates)
+			}
+		})
+	}
+}
+
+func TestCompleteRelative(t *testing.T) {
+	t.Chdir(tree(t, "notes.md"))
+	if got := Complete("no"); got.Value != "notes.md" {
+		t.Errorf("value %q, want notes.md", got.Value)
+	}
+}
+
+func TestCompleteHome(t *testing.T) {
+	home := tree(t, "Pictures/", "Public/")
+	t.Setenv("HOME", home)
+	t.Setenv("PICS", filepath.Join(home, "Pictures"))
+
+	if got := Complete("~"); got.Value != "~/" || got.Candidates != nil {
+		t.Errorf("~ completed as %+v, want ~/", got)
+	}
+	// The typed ~ and variables are kept
+	if got := Complete("~/P"); got.Value != "~/P" || !slices.Equal(got.Candidates, []string{"Pictures/", "Public/"}) {
+		t.Errorf("~/P completed as %+v", got)
+	}
+	if got := Complete("~/Pi"); got.Value != "~/Pictures/" {
+		t.Errorf("~/Pi completed as %q, want ~/Pictures/", got.Value)
+	}
+	shot := filepath.Join(home, "Pictures", "shot.png")
+	if err := os.WriteFile(shot, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Complete("$PICS/s"); got.Value != "$PICS/shot.png" {
+		t.Errorf("$PICS/s completed as %q, want $PICS/shot.png", got.Value)
+	}
+}
@@ -0,0 +1,107 @@
+package pdf
+
+import "fmt"
+
+// Font is one of the standard PDF fonts, available in every reader
+type Font int
+
+// Standard fonts used by the documents
+const (
+	Helvetica Font = iota
+	HelveticaBold
+	HelveticaOblique
+	HelveticaBoldOblique
+	Courier
+)
+
+// fonts lists every font, they are declared in each document
+var fonts = []Font{Helvetica, HelveticaBold, HelveticaOblique, HelveticaBoldOblique, Courier}
+
+// name returns the PostScript name of the font
+func (f Font) name() string {
+	switch f {
+	case HelveticaBold:
+		return "Helvetica-Bold"
+	case HelveticaOblique:
+		return "Helvetica-Oblique"
+	case HelveticaBoldOblique:
+		return "Helvetica-BoldOblique"
+	case Courier:
+		return "Courier"
+	default:
+		return "Helvetica"
+	}
+}
+
+// resource returns the name of the font in the page resources
+func (f Font) resource() string {
+	return fmt.Sprintf("F%d", int(f)+1)
+}
+
+// Widths of the printable ASCII characters, from space to tilde, in
+// thousandths of the font size
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// Width returns the width of s in points when drawn at the given size.
+// Characters outside ASCII are given the width of a digit.
+func (f Font) Width(s string, size float64) float64 {
+	total := 0
+	for _, r := range s {
+		switch {
+		case f == Courier:
+			total += 600
+		case r < ' ' || r > '~':
+			total += 556
+		case f == HelveticaBold || f == HelveticaBoldOblique:
+			total += helveticaBoldWidths[r-' ']
+		default:
+			total += helveticaWidths[r-' ']
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// winAnsi maps the characters of Windows-1252 that differ from Latin-1
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// encode converts s to the encoding of the fonts, characters it lacks
+// become question marks and are added to unsupported when it isn't nil
+func encode(s string, unsupported map[rune]bool) string {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			out = append(out, byte(r))
+		case winAnsi[r] != 0:
+			out = append(out, winAnsi[r])
+		default:
+			out = append(out, '?')
+			if unsupported != nil {
+				unsupported[r] = true
+			}
+		}
+	}
+	return string(out)
+}
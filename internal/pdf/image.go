@@ -0,0 +1,69 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// Image is a picture added to a document, it can be drawn on several pages
+type Image struct {
+	Width  int // Size in pixels
+	Height int
+
+	index      int
+	data       []byte
+	jpeg       bool
+	colorSpace string
+}
+
+// resource returns the name of the image in the page resources
+func (img *Image) resource() string {
+	return fmt.Sprintf("Im%d", img.index+1)
+}
+
+// AddImage adds a JPEG, PNG or GIF image to the document. JPEG files are
+// embedded as is, other formats are stored as RGB pixels on a white background.
+func (d *Document) AddImage(data []byte) (*Image, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image: %w", err)
+	}
+
+	img := &Image{Width: cfg.Width, Height: cfg.Height, index: len(d.images)}
+	switch {
+	case format == "jpeg" && cfg.ColorModel == color.GrayModel:
+		img.data, img.jpeg, img.colorSpace = data, true, "DeviceGray"
+	case format == "jpeg" && cfg.ColorModel == color.YCbCrModel:
+		img.data, img.jpeg, img.colorSpace = data, true, "DeviceRGB"
+	default:
+		decoded, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode image: %w", err)
+		}
+		img.data, img.colorSpace = rgbPixels(decoded), "DeviceRGB"
+	}
+
+	d.images = append(d.images, img)
+	return img, nil
+}
+
+// rgbPixels returns the pixels of img row by row, blending transparent
+// pixels with white
+func rgbPixels(img image.Image) []byte {
+	bounds := img.Bounds()
+	pixels := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// Colors are premultiplied by alpha, add the white showing through
+			r, g, b, a := img.At(x, y).RGBA()
+			white := 0xffff - a
+			pixels = append(pixels, byte((r+white)>>8), byte((g+white)>>8), byte((b+white)>>8))
+		}
+	}
+	return pixels
+}
@@ -0,0 +1,232 @@
+// Package pdf writes simple PDF documents: text in the standard fonts, filled
+// rectangles, lines and images. It only covers what the exports need and
+// doesn't embed fonts, so text is limited to the Windows-1252 character set.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Size of an A4 page in points
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Document is a PDF being built page by page
+type Document struct {
+	Width  float64
+	Height float64
+	Title  string
+
+	pages  []*Page
+	images []*Image
+}
+
+// New creates an empty document whose pages have the given size in points
+func New(width, height float64) *Document {
+	return &Document{Width: width, Height: height}
+}
+
+// Page is a page of a document. Coordinates are in points from the bottom
+// left corner of the page.
+type Page struct {
+	content     bytes.Buffer
+	images      map[*Image]bool
+	unsupported map[rune]bool // Characters of the text the fonts lack
+}
+
+// AddPage appends a blank page to the document
+func (d *Document) AddPage() *Page {
+	return d.InsertPage(len(d.pages))
+}
+
+// InsertPage inserts a blank page so that it becomes the page at index i
+func (d *Document) InsertPage(i int) *Page {
+	page := &Page{images: map[*Image]bool{}, unsupported: map[rune]bool{}}
+	d.pages = append(d.pages, nil)
+	copy(d.pages[i+1:], d.pages[i:])
+	d.pages[i] = page
+	return page
+}
+
+// PageCount returns the number of pages of the document
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Unsupported returns the characters of the title and text of the document
+// that the fonts lack, in order. They are drawn as question marks.
+func (d *Document) Unsupported() []rune {
+	unsupported := map[rune]bool{}
+	encode(d.Title, unsupported)
+	for _, page := range d.pages {
+		maps.Copy(unsupported, page.unsupported)
+	}
+	return slices.Sorted(maps.Keys(unsupported))
+}
+
+// SetFillGray sets the color of the next text and rectangles, from 0 (black)
+// to 1 (white)
+func (p *Page) SetFillGray(gray float64) {
+	fmt.Fprintf(&p.content, "%.3f g\n", gray)
+}
+
+// SetStrokeGray sets the color of the next lines
+func (p *Page) SetStrokeGray(gray float64) {
+	fmt.Fprintf(&p.content, "%.3f G\n", gray)
+}
+
+// Text draws s with its baseline starting at x, y
+func (p *Page) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font.resource(), size, x, y, escape(encode(s, p.unsupported)))
+}
+
+// Rect fills a rectangle whose bottom left corner is at x, y
+func (p *Page) Rect(x, y, width, height float64) {
+	fmt.Fprintf(&p.content, "%.2f %.2f %.2f %.2f re f\n", x, y, width, height)
+}
+
+// Line draws a line between two points
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// Image draws an image of the document scaled to the given size, with its
+// bottom left corner at x, y
+func (p *Page) Image(img *Image, x, y, width, height float64) {
+	p.images[img] = true
+	fmt.Fprintf(&p.content, "q %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q\n", width, height, x, y, img.resource())
+}
+
+// escape protects the delimiters of a PDF string
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", `\r`).Replace(s)
+}
+
+// writer numbers the objects of the file and records their offsets
+type writer struct {
+	w       io.Writer
+	written int64
+	offsets []int64
+	err     error
+}
+
+func (w *writer) printf(format string, args ...any) {
+	if w.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(w.w, format, args...)
+	w.written += int64(n)
+	w.err = err
+}
+
+func (w *writer) write(data []byte) {
+	if w.err != nil {
+		return
+	}
+	n, err := w.w.Write(data)
+	w.written += int64(n)
+	w.err = err
+}
+
+// object starts object number id, objects must be written in order
+func (w *writer) object(id int) {
+	for len(w.offsets) < id {
+		w.offsets = append(w.offsets, 0)
+	}
+	w.offsets[id-1] = w.written
+	w.printf("%d 0 obj\n", id)
+}
+
+// stream writes a compressed stream object with extra dictionary entries
+func (w *writer) stream(id int, dict string, data []byte, compress bool) {
+	if compress {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		zw.Write(data)
+		zw.Close()
+		data = buf.Bytes()
+		dict += " /Filter /FlateDecode"
+	}
+	w.object(id)
+	w.printf("<< %s /Length %d >>\nstream\n", strings.TrimSpace(dict), len(data))
+	w.write(data)
+	w.printf("\nendstream\nendobj\n")
+}
+
+// WriteTo writes the document as a PDF file
+func (d *Document) WriteTo(out io.Writer) (int64, error) {
+	w := &writer{w: out}
+
+	// Object numbers: catalog, page tree, info, fonts, images, then a page
+	// and its content for each page
+	const catalogID, pagesID, infoID = 1, 2, 3
+	fontID := func(f Font) int { return 4 + int(f) }
+	imageID := func(i int) int { return 4 + len(fonts) + i }
+	pageID := func(i int) int { return 4 + len(fonts) + len(d.images) + 2*i }
+
+	w.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	w.object(catalogID)
+	w.printf("<< /Type /Catalog /Pages %d 0 R >>\nendobj\n", pagesID)
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageID(i))
+	}
+	w.object(pagesID)
+	w.printf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %.2f %.2f] >>\nendobj\n",
+		strings.Join(kids, " "), len(d.pages), d.Width, d.Height)
+
+	w.object(infoID)
+	w.printf("<< /Title (%s) /Producer (Datapad) >>\nendobj\n", escape(encode(d.Title, nil)))
+
+	for _, f := range fonts {
+		w.object(fontID(f))
+		w.printf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\nendobj\n", f.name())
+	}
+
+	for i, img := range d.images {
+		dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8",
+			img.Width, img.Height, img.colorSpace)
+		if img.jpeg {
+			w.stream(imageID(i), dict+" /Filter /DCTDecode", img.data, false)
+		} else {
+			w.stream(imageID(i), dict, img.data, true)
+		}
+	}
+
+	var fontRefs, imageRefs strings.Builder
+	for _, f := range fonts {
+		fmt.Fprintf(&fontRefs, " /%s %d 0 R", f.resource(), fontID(f))
+	}
+	for i, page := range d.pages {
+		imageRefs.Reset()
+		for j, img := range d.images {
+			if page.images[img] {
+				fmt.Fprintf(&imageRefs, " /%s %d 0 R", img.resource(), imageID(j))
+			}
+		}
+		w.object(pageID(i))
+		w.printf("<< /Type /Page /Parent %d 0 R /Contents %d 0 R /Resources << /Font <<%s >> /XObject <<%s >> >> >>\nendobj\n",
+			pagesID, pageID(i)+1, fontRefs.String(), imageRefs.String())
+		w.stream(pageID(i)+1, "", page.content.Bytes(), true)
+	}
+
+	xref := w.written
+	w.printf("xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		w.printf("%010d 00000 n \n", offset)
+	}
+	w.printf("trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.offsets)+1, catalogID, infoID, xref)
+
+	return w.written, w.err
+}
@@ -0,0 +1,53 @@
+package pdf
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		in          string
+		want        string
+		unsupported []rune
+	}{
+		{"plain", "plain", nil},
+		{"café", "caf\xe9", nil},
+		{"“quoted” – €5…", "\x93quoted\x94 \x96 \x805\x85", nil},
+		{"日本 ok", "?? ok", []rune{'日', '本'}},
+		{"done ✅", "done ?", []rune{'✅'}},
+	}
+	for _, tt := range tests {
+		unsupported := map[rune]bool{}
+		if got := encode(tt.in, unsupported); got != tt.want {
+			t.Errorf("encode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		for _, r := range tt.unsupported {
+			if !unsupported[r] {
+				t.Errorf("encode(%q) didn't report %q", tt.in, r)
+			}
+		}
+		if len(unsupported) != len(tt.unsupported) {
+			t.Errorf("encode(%q) reported %d character(s), want %d", tt.in, len(unsupported), len(tt.unsupported))
+		}
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	d := New(A4Width, A4Height)
+	d.Title = "Trip ✈"
+	d.AddPage().Text(0, 0, Helvetica, 10, "Café ☕")
+	d.AddPage().Text(0, 0, Courier, 10, "日本 ☕")
+	if got, want := d.Unsupported(), []rune{'☕', '✈', '日', '本'}; !slices.Equal(got, want) {
+		t.Errorf("Unsupported() = %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("%PDF-1.4")) {
+		t.Error("no PDF header written")
+	}
+}
@@ -0,0 +1,432 @@
+package remote
+
+import (
+	"datapad/internal/config"
+	"datapad/internal/notes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// stateFile records, in the storage directory, what the last sync saw
+const stateFile = "sync-state.json"
+
+// Remote layout: one JSON file per note and the images as stored locally
+const (
+	notesDir  = "notes"
+	imagesDir = "images"
+)
+
+// Options configures a synchronization
+type Options struct {
+	URL      string // WebDAV directory holding the synchronized store
+	Username string
+	Password string
+	Host     string         // Name of this machine, recorded with uploaded notes
+	Progress func(Progress) // Optional, called as the sync advances
+}
+
+// NewOptions builds the options of a sync with url from the settings and
+// the environment
+func NewOptions(url string, cfg config.SyncConfig) Options {
+	username, password := cfg.Credentials()
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown host"
+	}
+	return Options{
+		URL:      url,
+		Username: username,
+		Password: password,
+		Host:     host,
+	}
+}
+
+// Progress describes the current step of a synchronization
+type Progress struct {
+	Step  string
+	Done  int
+	Total int
+}
+
+// Result lists what a synchronization changed. Notes and Deleted must be
+// applied to the local store, see NotesManager.MergeNotes, then the state
+// saved with SaveState.
+type Result struct {
+	Uploaded      int
+	Downloaded    int
+	DeletedLocal  int
+	DeletedRemote int
+	Conflicts     int
+	Images        int
+
+	Notes   []*notes.Note        // Notes to add or replace locally
+	Deleted []string             // IDs of the notes to remove locally
+	Base    map[string]time.Time // Update dates of the local notes the sync read
+
+	state    syncState // State once the result is applied
+	previous syncState // State of the last sync
+}
+
+// syncState is the content of the state file
+type syncState struct {
+	URL   string               `json:"url"`
+	Notes map[string]noteState `json:"notes"`
+}
+
+// noteState is the version of a note both sides had after the last sync
+type noteState struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	ETag      string    `json:"etag"`
+}
+
+// remoteNote is the file stored on the server for each note
+type remoteNote struct {
+	Host string      `json:"host"` // Machine that uploaded this version
+	Note *notes.Note `json:"note"`
+}
+
+// Sync synchronizes the notes of storagePath with a WebDAV directory. Notes
+// are compared with the state of the last sync: a note changed on one side
+// only is copied to the other, and a note changed on both sides is kept
+// locally while the remote version is added as a copy titled
+// "<title> (conflict from <host>)". local is only read, the changes to apply
+// locally are returned in the result, and the state of the sync is only
+// recorded by Result.SaveState once they are.
+func Sync(storagePath string, local []*notes.Note, opts Options) (Result, error) {
+	result := Result{Base: map[string]time.Time{}}
+	progress := func(step string, done, total int) {
+		if opts.Progress != nil {
+			opts.Progress(Progress{Step: step, Done: done, Total: total})
+		}
+	}
+
+	client, err := newDavClient(opts.URL, opts.Username, opts.Password)
+	if err != nil {
+		return result, err
+	}
+
+	state, err := loadState(storagePath, opts.URL)
+	if err != nil {
+		return result, err
+	}
+
+	progress("Listing remote notes", 0, 0)
+	entries, err := client.list(notesDir)
+	if err != nil {
+		return result, err
+	}
+	remoteETags := map[string]string{}
+	for _, entry := range entries {
+		if id, ok := strings.CutSuffix(entry.Name, ".json"); ok {
+			remoteETags[id] = entry.ETag
+		}
+	}
+
+	localByID := map[string]*notes.Note{}
+	for _, note := range local {
+		localByID[note.ID] = note
+		result.Base[note.ID] = note.UpdatedAt
+	}
+
+	ids := map[string]bool{}
+	for id := range localByID {
+		ids[id] = true
+	}
+	for id := range remoteETags {
+		ids[id] = true
+	}
+	for id := range state.Notes {
+		ids[id] = true
+	}
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	upload := []*notes.Note{}
+	removeRemote := []string{}
+	for i, id := range sorted {
+		progress("Comparing notes", i+1, len(sorted))
+
+		localNote := localByID[id]
+		etag, onRemote := remoteETags[id]
+		base, synced := state.Notes[id]
+
+		localChanged := localNote != nil && (!synced || !localNote.UpdatedAt.Equal(base.UpdatedAt))
+		remoteChanged := onRemote && (!synced || etag != base.ETag)
+
+		switch {
+		case localNote == nil && !onRemote:
+			// Deleted on both sides
+
+		case localNote == nil:
+			if remoteChanged {
+				// Changed remotely after a local deletion, the change wins
+				if err := download(client, id, &result); err != nil {
+					return result, err
+				}
+			} else {
+				removeRemote = append(removeRemote, id)
+			}
+
+		case !onRemote:
+			if synced && !localChanged {
+				result.Deleted = append(result.Deleted, id)
+				result.DeletedLocal++
+			} else {
+				upload = append(upload, localNote)
+			}
+
+		case localChanged && remoteChanged:
+			remote, err := fetch(client, id)
+			if err != nil {
+				return result, err
+			}
+			if sameNote(localNote, remote.Note) {
+				continue
+			}
+			// Both versions are kept: the local one overwrites the remote
+			// file and the remote one becomes a new note on both sides
+			conflict := *remote.Note
+			conflict.ID = notes.NewNote("").ID
+			conflict.Title = fmt.Sprintf("%s (conflict from %s)", remote.Note.Title, remote.Host)
+			result.Notes = append(result.Notes, &conflict)
+			upload = append(upload, localNote, &conflict)
+			result.Conflicts++
+
+		case localChanged:
+			upload = append(upload, localNote)
+
+		case remoteChanged:
+			if err := download(client, id, &result); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	for i, note := range upload {
+		progress("Uploading notes", i+1, len(upload))
+		data, err := json.MarshalIndent(remoteNote{Host: opts.Host, Note: note}, "", "  ")
+		if err != nil {
+			return result, fmt.Errorf("error serializing note: %w", err)
+		}
+		if err := client.put(notesDir+"/"+note.ID+".json", data); err != nil {
+			return result, err
+		}
+		result.Uploaded++
+	}
+
+	for i, id := range removeRemote {
+		progress("Deleting remote notes", i+1, len(removeRemote))
+		if err := client.remove(notesDir + "/" + id + ".json"); err != nil {
+			return result, err
+		}
+		result.DeletedRemote++
+	}
+
+	if err := syncImages(client, storagePath, local, result.Notes, progress, &result); err != nil {
+		return result, err
+	}
+
+	// The new ETags are read back so that the next sync recognizes the
+	// files written by this one
+	progress("Recording sync state", 0, 0)
+	entries, err = client.list(notesDir)
+	if err != nil {
+		return result, err
+	}
+	result.state = newState(opts.URL, finalNotes(local, result), entries)
+	result.previous = state
+	return result, nil
+}
+
+// download fetches a remote note into the result
+func download(client *davClient, id string, result *Result) error {
+	remote, err := fetch(client, id)
+	if err != nil {
+		return err
+	}
+	result.Notes = append(result.Notes, remote.Note)
+	result.Downloaded++
+	return nil
+}
+
+// fetch reads the remote file of a note
+func fetch(client *davClient, id string) (remoteNote, error) {
+	var remote remoteNote
+	data, err := client.get(notesDir + "/" + id + ".json")
+	if err != nil {
+		return remote, err
+	}
+	if err := json.Unmarshal(data, &remote); err != nil || remote.Note == nil {
+		return remote, fmt.Errorf("invalid remote note %s", id)
+	}
+	if remote.Host == "" {
+		remote.Host = "unknown host"
+	}
+	return remote, nil
+}
+
+// sameNote reports whether two versions of a note have the same content
+func sameNote(a, b *notes.Note) bool {
+	return a.Title == b.Title && a.Content == b.Content &&
+		strings.Join(a.Tags, "\x00") == strings.Join(b.Tags, "\x00")
+}
+
+// syncImages uploads the images of local notes missing from the server and
+// downloads those of downloaded notes missing locally. Images are
+// content-addressed, so a file with the same name is always identical.
+func syncImages(client *davClient, storagePath string, local, downloaded []*notes.Note, progress func(string, int, int), result *Result) error {
+	entries, err := client.list(imagesDir)
+	if err != nil {
+		return err
+	}
+	remote := map[string]bool{}
+	for _, entry := range entries {
+		remote[entry.Name] = true
+	}
+	localDir := filepath.Join(storagePath, "images")
+
+	toUpload := []string{}
+	for _, note := range local {
+		for _, img := range note.Images {
+			if remote[img.Path] {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(localDir, img.Path)); err == nil {
+				toUpload = append(toUpload, img.Path)
+				remote[img.Path] = true
+			}
+		}
+	}
+	for i, name := range toUpload {
+		progress("Uploading images", i+1, len(toUpload))
+		data, err := os.ReadFile(filepath.Join(localDir, name))
+		if err != nil {
+			return fmt.Errorf("error reading image %s: %w", name, err)
+		}
+		if err := client.put(imagesDir+"/"+name, data); err != nil {
+			return err
+		}
+		result.Images++
+	}
+
+	toDownload := []string{}
+	for _, note := range downloaded {
+		for _, img := range note.Images {
+			if _, err := os.Stat(filepath.Join(localDir, img.Path)); err != nil && remote[img.Path] {
+				toDownload = append(toDownload, img.Path)
+			}
+		}
+	}
+	if len(toDownload) > 0 {
+		if err := os.MkdirAll(localDir, 0755); err != nil {
+			return fmt.Errorf("unable to create images directory: %w", err)
+		}
+	}
+	for i, name := range toDownload {
+		progress("Downloading images", i+1, len(toDownload))
+		data, err := client.get(imagesDir + "/" + name)
+		if err != nil {
+			return err
+		}
+		tmp := filepath.Join(localDir, name+".tmp")
+		if err := os.WriteFile(tmp, data, 0644); err != nil {
+			return fmt.Errorf("error writing image %s: %w", name, err)
+		}
+		if err := os.Rename(tmp, filepath.Join(localDir, name)); err != nil {
+			return fmt.Errorf("error writing image %s: %w", name, err)
+		}
+		result.Images++
+	}
+	return nil
+}
+
+// finalNotes returns the notes as they are once the result is applied
+func finalNotes(local []*notes.Note, result Result) map[string]*notes.Note {
+	final := map[string]*notes.Note{}
+	for _, note := range local {
+		final[note.ID] = note
+	}
+	for _, note := range result.Notes {
+		final[note.ID] = note
+	}
+	for _, id := range result.Deleted {
+		delete(final, id)
+	}
+	return final
+}
+
+// loadState reads the state of the last sync with url. A state recorded for
+// another server is ignored.
+func loadState(storagePath, url string) (syncState, error) {
+	state := syncState{URL: url, Notes: map[string]noteState{}}
+	data, err := os.ReadFile(filepath.Join(storagePath, stateFile))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("error reading sync state: %w", err)
+	}
+
+	var saved syncState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return state, fmt.Errorf("error reading sync state: %w", err)
+	}
+	if saved.URL != url || saved.Notes == nil {
+		return state, nil
+	}
+	return saved, nil
+}
+
+// newState returns the version of every note present on both sides
+func newState(url string, final map[string]*notes.Note, entries []davEntry) syncState {
+	state := syncState{URL: url, Notes: map[string]noteState{}}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name, ".json")
+		if !ok {
+			continue
+		}
+		if note, ok := final[id]; ok {
+			state.Notes[id] = noteState{UpdatedAt: note.UpdatedAt, ETag: entry.ETag}
+		}
+	}
+	return state
+}
+
+// SaveState records the state of the sync once its result was applied to
+// the store. The notes skipped by the merge keep the state of the previous
+// sync, so that the next one sees them as changed locally and compares them
+// with the remote version again.
+func (r Result) SaveState(storagePath string, skipped []string) error {
+	if r.state.Notes == nil {
+		return nil
+	}
+	state := syncState{URL: r.state.URL, Notes: maps.Clone(r.state.Notes)}
+	for _, id := range skipped {
+		if previous, ok := r.previous.Notes[id]; ok {
+			state.Notes[id] = previous
+		} else {
+			delete(state.Notes, id)
+		}
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing sync state: %w", err)
+	}
+	if err := os.MkdirAll(storagePath, 0755); err != nil {
+		return fmt.Errorf("unable to create storage directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(storagePath, stateFile), data, 0644); err != nil {
+		return fmt.Errorf("error writing sync state: %w", err)
+	}
+	return nil
+}
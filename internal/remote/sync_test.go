@@ -0,0 +1,53 @@
+package remote
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveStateKeepsSkippedNotes(t *testing.T) {
+	dir := t.TempDir()
+	const url = "https://dav.example/notes"
+	then := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	now := then.Add(time.Hour)
+	result := Result{
+		previous: syncState{URL: url, Notes: map[string]noteState{
+			"kept":    {UpdatedAt: then, ETag: "old"},
+			"skipped": {UpdatedAt: then, ETag: "old"},
+		}},
+		state: syncState{URL: url, Notes: map[string]noteState{
+			"kept":    {UpdatedAt: now, ETag: "new"},
+			"skipped": {UpdatedAt: now, ETag: "new"},
+			"new":     {UpdatedAt: now, ETag: "new"},
+		}},
+	}
+	if err := result.SaveState(dir, []string{"skipped", "new"}); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := loadState(dir, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Notes["kept"]; got.ETag != "new" || !got.UpdatedAt.Equal(now) {
+		t.Errorf("merged note recorded as %+v", got)
+	}
+	if got := state.Notes["skipped"]; got.ETag != "old" || !got.UpdatedAt.Equal(then) {
+		t.Errorf("skipped note recorded as %+v, want the previous state", got)
+	}
+	if _, ok := state.Notes["new"]; ok {
+		t.Error("a skipped note unknown to the previous sync is recorded")
+	}
+}
+
+func TestSaveStateWithoutSync(t *testing.T) {
+	dir := t.TempDir()
+	if err := (Result{}).SaveState(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, stateFile)); !os.IsNotExist(err) {
+		t.Error("the result of a failed sync wrote a state")
+	}
+}
@@ -0,0 +1,184 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// errNotFound is returned when a remote file doesn't exist
+var errNotFound = errors.New("not found on the server")
+
+// davClient is a minimal WebDAV client covering what sync needs
+type davClient struct {
+	base     *url.URL
+	username string
+	password string
+	http     *http.Client
+}
+
+// davEntry is a file listed in a remote directory
+type davEntry struct {
+	Name string
+	ETag string
+}
+
+// multistatus is the body of a PROPFIND response
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Prop struct {
+				ETag         string `xml:"getetag"`
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// newDavClient creates a client for the WebDAV directory at rawURL
+func newDavClient(rawURL, username, password string) (*davClient, error) {
+	base, err := url.Parse(rawURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") {
+		return nil, fmt.Errorf("invalid WebDAV URL %q", rawURL)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	return &davClient{
+		base:     base,
+		username: username,
+		password: password,
+		http:     &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// request sends a request for a path relative to the base URL
+func (c *davClient) request(method, name string, body []byte, headers map[string]string) (*http.Response, error) {
+	target := *c.base
+	target.Path = path.Join(c.base.Path, name)
+	if strings.HasSuffix(name, "/") {
+		target.Path += "/"
+	}
+
+	req, err := http.NewRequest(method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to reach the WebDAV server: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errNotFound
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		resp.Body.Close()
+		return nil, errors.New("the WebDAV server rejected the credentials")
+	case resp.StatusCode >= 300:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s failed: %s", method, name, resp.Status)
+	}
+	return resp, nil
+}
+
+// list returns the files of a remote directory, which is created if missing
+func (c *davClient) list(dir string) ([]davEntry, error) {
+	const body = `<?xml version="1.0"?><d:propfind xmlns:d="DAV:"><d:prop><d:getetag/><d:resourcetype/></d:prop></d:propfind>`
+	resp, err := c.request("PROPFIND", dir+"/", []byte(body), map[string]string{
+		"Depth":        "1",
+		"Content-Type": "application/xml",
+	})
+	if errors.Is(err, errNotFound) {
+		return nil, c.mkcol(dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var ms multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("invalid listing from the WebDAV server: %w", err)
+	}
+
+	entries := []davEntry{}
+	for _, r := range ms.Responses {
+		if len(r.Propstat) == 0 || r.Propstat[0].Prop.ResourceType.Collection != nil {
+			continue
+		}
+		href, err := url.PathUnescape(r.Href)
+		if err != nil {
+			href = r.Href
+		}
+		entries = append(entries, davEntry{
+			Name: path.Base(href),
+			ETag: strings.Trim(r.Propstat[0].Prop.ETag, `"`),
+		})
+	}
+	return entries, nil
+}
+
+// mkcol creates a remote directory
+func (c *davClient) mkcol(dir string) error {
+	resp, err := c.request("MKCOL", dir+"/", nil, nil)
+	if err != nil {
+		return fmt.Errorf("unable to create remote directory %s: %w", dir, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// get downloads a remote file
+func (c *davClient) get(name string) ([]byte, error) {
+	resp, err := c.request(http.MethodGet, name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// put uploads a file
+func (c *davClient) put(name string, data []byte) error {
+	resp, err := c.request(http.MethodPut, name, data, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// remove deletes a remote file, a missing file isn't an error
+func (c *davClient) remove(name string) error {
+	resp, err := c.request(http.MethodDelete, name, nil, nil)
+	if errors.Is(err, errNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,168 @@
+package share
+
+import (
+	"bytes"
+	"datapad/internal/notes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultAPIURL is the GitHub API used to create gists
+const DefaultAPIURL = "https://api.github.com"
+
+// ErrNoToken is returned when sharing without a GitHub token
+var ErrNoToken = errors.New("no GitHub token, set share.github_token in the config or the GITHUB_TOKEN environment variable")
+
+// errGistNotFound is returned when the gist of a note no longer exists
+var errGistNotFound = errors.New("gist not found")
+
+// GistClient shares notes as GitHub gists
+type GistClient struct {
+	Token  string
+	APIURL string
+	HTTP   *http.Client
+}
+
+// NewGistClient creates a client for the public GitHub API
+func NewGistClient(token string) *GistClient {
+	return &GistClient{
+		Token:  token,
+		APIURL: DefaultAPIURL,
+		HTTP:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// gistFile is a file of a gist in API requests and responses
+type gistFile struct {
+	Filename string `json:"filename,omitempty"`
+	Content  string `json:"content"`
+}
+
+// gistRequest is the body sent to create or update a gist
+type gistRequest struct {
+	Description string              `json:"description"`
+	Public      *bool               `json:"public,omitempty"`
+	Files       map[string]gistFile `json:"files"`
+}
+
+// gistResponse holds the fields of a gist used by datapad
+type gistResponse struct {
+	ID      string `json:"id"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Share publishes the note as a gist, secret unless public is set. A note
+// shared before updates its gist instead of creating a new one; visibility
+// can only be chosen when the gist is created. The returned reference is
+// meant to be stored in the note.
+func (c *GistClient) Share(note *notes.Note, public bool) (*notes.GistRef, error) {
+	if c.Token == "" {
+		return nil, ErrNoToken
+	}
+
+	file := notes.Slugify(note.Title) + ".md"
+	content := "# " + note.Title + "\n\n" + note.Content
+
+	if note.Gist != nil {
+		ref, err := c.update(note.Gist, note.Title, file, content)
+		if !errors.Is(err, errGistNotFound) {
+			return ref, err
+		}
+		// The gist was deleted on GitHub, share the note again
+	}
+
+	body := gistRequest{
+		Description: note.Title,
+		Public:      &public,
+		Files:       map[string]gistFile{file: {Content: content}},
+	}
+	var created gistResponse
+	if err := c.do(http.MethodPost, "/gists", body, &created); err != nil {
+		return nil, err
+	}
+	return &notes.GistRef{ID: created.ID, URL: created.HTMLURL, File: file}, nil
+}
+
+// update replaces the content of an existing gist, renaming its file if the
+// title changed
+func (c *GistClient) update(ref *notes.GistRef, title, file, content string) (*notes.GistRef, error) {
+	body := gistRequest{
+		Description: title,
+		Files:       map[string]gistFile{ref.File: {Filename: file, Content: content}},
+	}
+	var updated gistResponse
+	if err := c.do(http.MethodPatch, "/gists/"+ref.ID, body, &updated); err != nil {
+		return nil, err
+	}
+	return &notes.GistRef{ID: updated.ID, URL: updated.HTMLURL, File: file}, nil
+}
+
+// Unshare deletes the gist of a note. A gist already deleted on GitHub is not
+// an error.
+func (c *GistClient) Unshare(ref *notes.GistRef) error {
+	if c.Token == "" {
+		return ErrNoToken
+	}
+	err := c.do(http.MethodDelete, "/gists/"+ref.ID, nil, nil)
+	if errors.Is(err, errGistNotFound) {
+		return nil
+	}
+	return err
+}
+
+// do sends a request to the GitHub API and decodes the response into out
+func (c *GistClient) do(method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error serializing gist: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.APIURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach GitHub: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errGistNotFound
+	case resp.StatusCode == http.StatusUnauthorized:
+		return errors.New("GitHub rejected the token, check that it is valid and has the gist scope")
+	case resp.StatusCode >= 300:
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = resp.Status
+		}
+		return fmt.Errorf("GitHub returned an error: %s", apiErr.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid response from GitHub: %w", err)
+	}
+	return nil
+}
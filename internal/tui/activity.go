@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"crypto/sha256"
+	"datapad/internal/notes"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// activityLimit is the number of log entries shown by the activity view
+const activityLimit = 500
+
+// The date and action of an activity line take activityPrefixWidth columns
+// before the title, which keeps activityTitleWidth columns at least
+const (
+	activityPrefixWidth = 28
+	activityTitleWidth  = 20
+)
+
+// Colors of the actions in the activity view
+var activityColors = map[string]lipgloss.Color{
+	notes.ActionCreate:    lipgloss.Color("#5f5"),
+	notes.ActionUpdate:    lipgloss.Color("#3498db"),
+	notes.ActionDelete:    lipgloss.Color("#ff5555"),
+	notes.ActionTag:       lipgloss.Color("#FFA500"),
+	notes.ActionArchive:   lipgloss.Color("#888888"),
+	notes.ActionUnarchive: lipgloss.Color("#888888"),
+}
+
+// openActivity displays the latest operations on the notes
+func (m *Model) openActivity() {
+	entries, err := m.notesManager.ReadActivity(activityLimit)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Error: %s", err)
+		return
+	}
+	m.activity = entries
+	m.mode = ModeActivity
+	m.statusMsg = fmt.Sprintf("%d recent operation(s)", len(entries))
+	m.viewport.SetContent(m.activityBody())
+	m.viewKey = [sha256.Size]byte{}
+	m.viewport.GotoTop()
+}
+
+// updateActivityMode handles the activity view, which only scrolls
+func (m Model) updateActivityMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.Back) {
+		m.activity = nil
+		m.mode = ModeList
+		m.statusMsg = ""
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// activityBody renders the entries of the log, one per line
+func (m Model) activityBody() string {
+	if len(m.activity) == 0 {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("No activity recorded yet")
+	}
+
+	dateStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	detailStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	lines := make([]string, len(m.activity))
+	for i, entry := range m.activity {
+		action := lipgloss.NewStyle().
+			Foreground(activityColors[entry.Action]).
+			Width(10).
+			Render(entry.Action)
+		// The title makes room for the detail on the width of the screen
+		detail := ""
+		if entry.Detail != "" {
+			detail = " " + detailStyle.Render("("+entry.Detail+")")
+		}
+		title := sanitizeTerminal(entry.Title, false)
+		if m.width > 0 {
+			title = notes.ShortTitle(title, max(m.width-activityPrefixWidth-lipgloss.Width(detail), activityTitleWidth))
+		}
+		line := dateStyle.Render(entry.Time.Format("02/01/2006 15:04")) + "  " + action + title + detail
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// viewActivity displays the activity log
+func (m Model) viewActivity() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFA500"))
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render("Activity"),
+		m.viewport.View(),
+		m.statusBar(),
+		m.helpView(),
+	)
+}
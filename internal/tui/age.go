@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ageBucket tells how recently a note was updated
+type ageBucket int
+
+// Ages of the notes, from the freshest
+const (
+	ageToday    ageBucket = iota // Updated today
+	ageThisWeek                  // Updated within the last seven days
+	ageOlder                     // Left alone for longer
+)
+
+// noteAge returns the bucket of a note updated at updated, today being the
+// day of now
+func noteAge(updated, now time.Time) ageBucket {
+	updated = updated.In(now.Location())
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	switch {
+	case !updated.Before(today):
+		return ageToday
+	case !updated.Before(today.AddDate(0, 0, -6)):
+		return ageThisWeek
+	default:
+		return ageOlder
+	}
+}
+
+// ageColor returns the color of the titles of notes of an age, bright for
+// fresh notes and dim for old ones
+func ageColor(age ageBucket) lipgloss.TerminalColor {
+	switch age {
+	case ageToday:
+		return lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"}
+	case ageThisWeek:
+		return lipgloss.AdaptiveColor{Light: "#444444", Dark: "#BBBBBB"}
+	default:
+		return lipgloss.AdaptiveColor{Light: "#999999", Dark: "#666666"}
+	}
+}
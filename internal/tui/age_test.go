@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestNoteAge(t *testing.T) {
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, zone)
+	tests := []struct {
+		updated time.Time
+		want    ageBucket
+	}{
+		{now, ageToday},
+		{time.Date(2024, 3, 10, 0, 0, 0, 0, zone), ageToday},
+		{time.Date(2024, 3, 9, 22, 30, 0, 0, time.UTC), ageToday}, // March 10th where now is
+		{time.Date(2024, 3, 9, 23, 59, 0, 0, zone), ageThisWeek},
+		{time.Date(2024, 3, 4, 0, 0, 0, 0, zone), ageThisWeek},
+		{time.Date(2024, 3, 3, 23, 59, 0, 0, zone), ageOlder},
+		{time.Date(2023, 3, 10, 15, 0, 0, 0, zone), ageOlder},
+		{now.Add(time.Hour), ageToday}, // Clock of another machine ahead
+	}
+	for _, tt := range tests {
+		if got := noteAge(tt.updated, now); got != tt.want {
+			t.Errorf("noteAge(%v) = %d, want %d", tt.updated, got, tt.want)
+		}
+	}
+}
+
+func TestAgeColor(t *testing.T) {
+	// Older notes fade into the background, on dark and light terminals
+	ages := []ageBucket{ageToday, ageThisWeek, ageOlder}
+	want := []lipgloss.AdaptiveColor{
+		{Light: "#000000", Dark: "#FFFFFF"},
+		{Light: "#444444", Dark: "#BBBBBB"},
+		{Light: "#999999", Dark: "#666666"},
+	}
+	for i, age := range ages {
+		if got := ageColor(age); got != want[i] {
+			t.Errorf("ageColor(%d) = %v, want %v", age, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateEditAliasesMode handles the prompt for the aliases of the open note.
+// A conflicting alias keeps the prompt open so that it can be corrected.
+func (m Model) updateEditAliasesMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeView
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		aliases := strings.Split(m.aliasInput.Value(), ",")
+		if err := m.notesManager.SetAliases(m.selectedNote, aliases); err != nil {
+			m.statusMsg = fmt.Sprintf("Error: %s", storeError(err))
+			return m, nil
+		}
+		m.statusMsg = "Aliases saved"
+		m.mode = ModeView
+		return m, nil
+	}
+
+	m.aliasInput, cmd = m.aliasInput.Update(msg)
+	return m, cmd
+}
@@ -1,20 +1,27 @@
 package tui
 
 import (
+	"crypto/sha256"
+	"datapad/internal/config"
+	"datapad/internal/markdown"
 	"datapad/internal/notes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
-	"regexp"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/yuin/goldmark"
 )
 
@@ -32,6 +39,17 @@ const (
 	ModeAddTag
 	ModeFilterByTag
 	ModeViewImage // New mode for viewing images
+	ModeExportBook
+	ModeEditAliases
+	ModeNoteSearch
+	ModeActivity
+	ModeReview
+	ModeCalendar
+	ModeWorkspaces
+	ModeConflict
+	ModeExportImages
+	ModeQuickNote
+	ModeRenameTag
 )
 
 // KeyMap defines the shortcut keys for the application
@@ -50,11 +68,54 @@ type KeyMap struct {
 	Help          key.Binding
 	AddTag        key.Binding
 	FilterByTag   key.Binding
+	RenameTag     key.Binding
 	TogglePreview key.Binding
+	ForcePreview  key.Binding
 	ViewImage     key.Binding
 	NextImage     key.Binding
 	PrevImage     key.Binding
 	OpenImage     key.Binding // Nouveau raccourci pour ouvrir directement l'image
+	SwitchFocus   key.Binding
+	Indent        key.Binding
+	NextNote      key.Binding
+	PrevNote      key.Binding
+	CopyID        key.Binding
+	Pin           key.Binding
+	Archive       key.Binding
+	ShowArchived  key.Binding
+	Share         key.Binding
+	Unshare       key.Binding
+	Mark          key.Binding
+	ExportBook    key.Binding
+	Export        key.Binding
+	ExportImages  key.Binding
+	Aliases       key.Binding
+	ReadingWidth  key.Binding
+	Sync          key.Binding
+	Activity      key.Binding
+	Review        key.Binding
+	Keep          key.Binding
+	Calendar      key.Binding
+	PrevDay       key.Binding
+	NextDay       key.Binding
+	PrevMonth     key.Binding
+	NextMonth     key.Binding
+	DetailPane    key.Binding
+	Workspaces    key.Binding
+	MoveUp        key.Binding
+	MoveDown      key.Binding
+	MoveLineUp    key.Binding
+	MoveLineDown  key.Binding
+	DuplicateLine key.Binding
+	JoinLines     key.Binding
+	SmartPaste    key.Binding
+	UndoPaste     key.Binding
+	TOC           key.Binding
+	Suspend       key.Binding
+	QuickNote     key.Binding
+	KeepMine      key.Binding
+	TakeTheirs    key.Binding
+	MergeManually key.Binding
 }
 
 // DefaultKeyMap returns the default key mapping
@@ -105,8 +166,8 @@ func DefaultKeyMap() KeyMap {
 			key.WithHelp("ctrl+f", "search"),
 		),
 		Help: key.NewBinding(
-			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
+			key.WithKeys("?", "f1"),
+			key.WithHelp("?/f1", "help"),
 		),
 		AddTag: key.NewBinding(
 			key.WithKeys("t"),
@@ -116,10 +177,18 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("f"),
 			key.WithHelp("f", "filter by tag"),
 		),
+		RenameTag: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "rename tag"),
+		),
 		TogglePreview: key.NewBinding(
 			key.WithKeys("ctrl+p"),
 			key.WithHelp("ctrl+p", "toggle preview"),
 		),
+		ForcePreview: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "render large note"),
+		),
 		ViewImage: key.NewBinding(
 			key.WithKeys("v"),
 			key.WithHelp("v", "voir l'image"),
@@ -136,6 +205,170 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("o"),
 			key.WithHelp("o", "ouvrir l'image"),
 		),
+		SwitchFocus: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "switch title/content"),
+		),
+		Indent: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "indent"),
+		),
+		NextNote: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next note"),
+		),
+		PrevNote: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "previous note"),
+		),
+		CopyID: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy id"),
+		),
+		Pin: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pin"),
+		),
+		Archive: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "archive"),
+		),
+		ShowArchived: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "show archived"),
+		),
+		Share: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "share as gist"),
+		),
+		Unshare: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "unshare"),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "mark"),
+		),
+		ExportBook: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "export book"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "export"),
+		),
+		ExportImages: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "export images"),
+		),
+		Aliases: key.NewBinding(
+			key.WithKeys("@"),
+			key.WithHelp("@", "aliases"),
+		),
+		ReadingWidth: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "reading width"),
+		),
+		Sync: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "sync"),
+		),
+		Activity: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "activity"),
+		),
+		Review: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "review"),
+		),
+		Keep: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "keep"),
+		),
+		Calendar: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "calendar"),
+		),
+		PrevDay: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "previous day"),
+		),
+		NextDay: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "next day"),
+		),
+		PrevMonth: key.NewBinding(
+			key.WithKeys("[", "pgup"),
+			key.WithHelp("[", "previous month"),
+		),
+		NextMonth: key.NewBinding(
+			key.WithKeys("]", "pgdown"),
+			key.WithHelp("]", "next month"),
+		),
+		Workspaces: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "workspaces"),
+		),
+		DetailPane: key.NewBinding(
+			key.WithKeys("|"),
+			key.WithHelp("|", "detail pane"),
+		),
+		MoveUp: key.NewBinding(
+			key.WithKeys("K", "shift+up"),
+			key.WithHelp("K", "move up"),
+		),
+		MoveDown: key.NewBinding(
+			key.WithKeys("J", "shift+down"),
+			key.WithHelp("J", "move down"),
+		),
+		MoveLineUp: key.NewBinding(
+			key.WithKeys("alt+up"),
+			key.WithHelp("alt+↑", "move line up"),
+		),
+		MoveLineDown: key.NewBinding(
+			key.WithKeys("alt+down"),
+			key.WithHelp("alt+↓", "move line down"),
+		),
+		DuplicateLine: key.NewBinding(
+			key.WithKeys("ctrl+d"),
+			key.WithHelp("ctrl+d", "duplicate line"),
+		),
+		JoinLines: key.NewBinding(
+			key.WithKeys("ctrl+j"),
+			key.WithHelp("ctrl+j", "join lines"),
+		),
+		SmartPaste: key.NewBinding(
+			key.WithKeys("alt+v"),
+			key.WithHelp("alt+v", "smart paste"),
+		),
+		UndoPaste: key.NewBinding(
+			key.WithKeys("alt+z"),
+			key.WithHelp("alt+z", "undo paste"),
+		),
+		TOC: key.NewBinding(
+			key.WithKeys("alt+t"),
+			key.WithHelp("alt+t", "table of contents"),
+		),
+		Suspend: key.NewBinding(
+			key.WithKeys("ctrl+z"),
+			key.WithHelp("ctrl+z", "suspend"),
+		),
+		QuickNote: key.NewBinding(
+			key.WithKeys("ctrl+g"),
+			key.WithHelp("ctrl+g", "quick note"),
+		),
+		KeepMine: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "keep mine"),
+		),
+		TakeTheirs: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "take theirs"),
+		),
+		MergeManually: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "merge manually"),
+		),
 	}
 }
 
@@ -148,8 +381,16 @@ type Model struct {
 	titleInput    textinput.Model
 	imagePath     textinput.Model
 	imageCaption  textinput.Model
+	imageAlt      textinput.Model
+	pathMatches   []string // Completions of the image path listed under it
 	searchInput   textinput.Model
 	tagInput      textinput.Model
+	aliasInput    textinput.Model
+	noteSearch    textinput.Model
+	bookPath      textinput.Model
+	imagesDir     textinput.Model // Folder receiving the images of the open note
+	quickInput    textinput.Model // Thought captured as a quick note
+	quickFrom     Mode            // Mode the quick note prompt returns to
 	selectedNote  *notes.Note
 	selectedImage int // Index of the currently selected image
 	keys          KeyMap
@@ -158,27 +399,91 @@ type Model struct {
 	width, height int
 	statusMsg     string
 	markdown      goldmark.Markdown
+	hyperlinks    bool // The terminal supports OSC 8 hyperlinks
+	config        config.Config
+	noteEvents    <-chan notes.Event // Changes of the notes, handled by notesChanged
+	clipboard     Clipboard
+	autosaveSeq   int       // Identifies the latest scheduled autosave
+	lastAutosave  time.Time // Time of the last successful autosave
+	saveErr       error     // Outstanding save error, pauses autosave
+	flushPending  bool      // A write of the saves held back by the manager is scheduled
+	showArchived  bool
+	titleCheckSeq int    // Identifies the latest scheduled duplicate title check
+	titleWarning  string // Result of the last duplicate title check
+	viewport      viewport.Model
+	detail        viewport.Model // Note under the cursor, next to the list
+	detailID      string         // Note displayed in the detail pane
+	readingPos    map[string]int // Scroll offset of the notes already read, by note ID
+	state         uiState        // Settings kept between sessions
+	marked        []string       // IDs of the notes selected for a book, in selection order
+	exporting     []*notes.Note  // Notes written by the export prompt
+	exportFrom    Mode           // Mode the export prompt returns to
+	syncEvents    chan tea.Msg   // Messages of the running sync, nil when idle
+	locked        bool           // Interface hidden after being idle
+	lockSeq       int            // Identifies the latest scheduled lock
+	unlockInput   textinput.Model
+	unlockErr     string
+	unlocking     bool              // The passphrase entered is being checked
+	previewKey    [sha256.Size]byte // Hash of the width and content of the rendered preview
+	previewOut    string            // Latest rendered preview
+	previewBusy   bool              // A preview is being rendered
+	previewSkip   bool              // The content is over the preview limit
+	previewForce  bool              // Render the preview whatever the size of the content
+	previewWant   [sha256.Size]byte // Hash of the content the preview renders once typing pauses
+	previewSeq    int               // Identifies the latest scheduled preview
+	previewIdle   bool              // Typing paused since the content last changed
+	previewCache  *blockCache       // Rendered blocks of the preview
+	viewKey       [sha256.Size]byte // Hash of what the note view was rendered from, see bodyKey
+	detailKey     [sha256.Size]byte // Hash of what the detail pane was rendered from
+	storeVersion  int               // Counts the changes of the notes, which embeds depend on
+	noteMatches   []textMatch       // Matches of the search within the open note
+	currentMatch  int               // Index of the match the note is scrolled to
+	loading       bool              // The notes are being read in the background
+	loadErr       error             // Failure to read the notes, returned by App
+	activity      []notes.Activity  // Entries of the activity view
+	review        []*notes.Note     // Notes of the review session
+	reviewIndex   int               // Note being reviewed
+	calendarDay   time.Time         // Day selected in the calendar
+	editSpent     time.Duration     // Time counted in the current editor session
+	editLast      time.Time         // Last key press counted in the editor
+	workspace     string            // Name of the open workspace, empty outside workspaces
+	configPath    string            // Config file, read again over the settings of each workspace
+	helpScreen    viewport.Model    // Bindings of every mode
+	helpFrom      Mode              // Mode the help screen returns to
+	conflict      *notes.Note       // Version on disk of the note being edited, changed outside datapad
+	conflictView  viewport.Model    // Differences between the editor and the conflicting version
+	quitPending   bool              // Quitting waits for a confirmation, the editor holding unsaved changes
+	pasteUndo     *pasteUndo        // Editor before the last smart paste, nil when there is nothing to undo
+	pastedImage   *pastedImage      // Pasted path to an image, waiting for the choice to import it
+	editorStored  editedLines       // Lines of the note the editor shows without their tabs
+	listedNote    string            // Note highlighted before the list showed tags or workspaces
+	renamedTag    string            // Tag being renamed
+	renameNotes   []*notes.Note     // Notes a tag rename changes, nil until it is previewed
+	filter        listFilter        // What the note list is narrowed to
+	start         Start             // Note opened once the notes are loaded
+
+	// Requests of commands received while the notes were loading, answered
+	// once they are
+	instanceQueue []instanceRequestMsg
+
+	// Socket the commands submit their changes to, nil outside App
+	instance *instanceServer
 }
 
 // NewModel creates a new application model
-func NewModel(notesManager *notes.NotesManager) Model {
+func NewModel(notesManager *notes.NotesManager, cfg config.Config) Model {
 	keys := DefaultKeyMap()
 	helpModel := help.New()
 
-	// Configure the notes list
-	noteItems := []list.Item{}
-	for _, note := range notesManager.Notes {
-		noteItems = append(noteItems, NoteItem{note})
-	}
-
-	noteList := list.New(noteItems, list.NewDefaultDelegate(), 0, 0)
+	// Configure the notes list, filled once the model exists
+	noteList := list.New([]list.Item{}, newNoteDelegate(cfg.View), 0, 0)
 	noteList.Title = "Notes"
 	noteList.SetShowHelp(false)
 
 	// Configure the text editor
 	ta := textarea.New()
 	ta.Placeholder = "Write your note here..."
-	ta.CharLimit = 0
+	ta.CharLimit = cfg.Editor.MaxChars
 	ta.SetWidth(80)
 	ta.SetHeight(20)
 	ta.ShowLineNumbers = true
@@ -192,7 +497,7 @@ func NewModel(notesManager *notes.NotesManager) Model {
 	// Configure image fields
 	imagePath := textinput.New()
 	imagePath.Placeholder = "Path to image"
-	imagePath.CharLimit = 500
+	imagePath.CharLimit = 2000
 	imagePath.Width = 40
 
 	imageCaption := textinput.New()
@@ -200,6 +505,11 @@ func NewModel(notesManager *notes.NotesManager) Model {
 	imageCaption.CharLimit = 100
 	imageCaption.Width = 40
 
+	imageAlt := textinput.New()
+	imageAlt.Placeholder = "Alternative text"
+	imageAlt.CharLimit = 250
+	imageAlt.Width = 40
+
 	// Configure search field
 	searchInput := textinput.New()
 	searchInput.Placeholder = "Search..."
@@ -212,7 +522,47 @@ func NewModel(notesManager *notes.NotesManager) Model {
 	tagInput.CharLimit = 50
 	tagInput.Width = 30
 
-	return Model{
+	aliasInput := textinput.New()
+	aliasInput.Placeholder = "Comma-separated aliases"
+	aliasInput.CharLimit = 500
+	aliasInput.Width = 40
+
+	// Configure the search field of the note view
+	noteSearch := textinput.New()
+	noteSearch.Prompt = "/ "
+	noteSearch.Placeholder = "Find in note"
+	noteSearch.CharLimit = 100
+
+	// Configure the book file field
+	bookPath := textinput.New()
+	bookPath.Placeholder = "Book file"
+	bookPath.CharLimit = 500
+	bookPath.Width = 40
+
+	imagesDir := textinput.New()
+	imagesDir.Placeholder = "Folder"
+	imagesDir.CharLimit = 500
+	imagesDir.Width = 40
+
+	// Configure the quick note field, shown over any mode
+	quickInput := textinput.New()
+	quickInput.Placeholder = "A thought to keep, Enter saves it as a note"
+	quickInput.CharLimit = 1000
+
+	// Configure the passphrase field of the lock screen
+	unlockInput := textinput.New()
+	unlockInput.EchoMode = textinput.EchoPassword
+	unlockInput.EchoCharacter = '•'
+	unlockInput.CharLimit = 200
+	unlockInput.Width = 30
+
+	// Configure the scrollable note view. Half-page scrolling only uses the
+	// ctrl keys since d is already bound to delete.
+	vp := viewport.New(0, 0)
+	vp.KeyMap.HalfPageDown = key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "½ page down"))
+	vp.KeyMap.HalfPageUp = key.NewBinding(key.WithKeys("ctrl+u"), key.WithHelp("ctrl+u", "½ page up"))
+
+	model := Model{
 		notesManager: notesManager,
 		mode:         ModeList,
 		noteList:     noteList,
@@ -220,41 +570,85 @@ func NewModel(notesManager *notes.NotesManager) Model {
 		titleInput:   ti,
 		imagePath:    imagePath,
 		imageCaption: imageCaption,
+		imageAlt:     imageAlt,
 		searchInput:  searchInput,
 		tagInput:     tagInput,
+		aliasInput:   aliasInput,
+		noteSearch:   noteSearch,
+		bookPath:     bookPath,
+		imagesDir:    imagesDir,
+		quickInput:   quickInput,
+		unlockInput:  unlockInput,
 		keys:         keys,
 		help:         helpModel,
 		showPreview:  false,
-		markdown:     goldmark.New(),
+		markdown:     markdown.New(cfg.Markdown.Extensions),
+		previewCache: newBlockCache(),
+		hyperlinks:   supportsHyperlinks(),
+		config:       cfg,
+		clipboard:    systemClipboard{},
+		viewport:     vp,
+		detail:       vp,
+		helpScreen:   viewport.New(0, 0),
+		conflictView: viewport.New(0, 0),
+		readingPos:   map[string]int{},
+		state:        loadState(notesManager.StoragePath),
+		noteEvents:   notesManager.Subscribe(),
 	}
+	model.refreshNoteList()
+	return model
 }
 
 // NoteItem is a wrapper to adapt Note to the list.Item interface
 type NoteItem struct {
 	*notes.Note
+	Marked  bool   // Selected for a book export
+	Snippet string // Where a search matched, shown instead of the content
 }
 
 // Title returns the title of a note for display in the list
 func (n NoteItem) Title() string {
-	return n.Note.Title
+	title := sanitizeTerminal(n.Note.Title, false)
+	if n.Note.Pinned {
+		title = "📌 " + title
+	}
+	if n.Marked {
+		title = "✓ " + title
+	}
+	if n.Note.Archived {
+		title += " (archived)"
+	}
+	return title
 }
 
-// Description returns a description of the note for display in the list
+// Description returns a description of the note for display in the list, the
+// list itself fitting it to its width
 func (n NoteItem) Description() string {
-	content := n.Note.Content
-	if len(content) > 50 {
-		content = content[:50] + "..."
-	}
-	tags := strings.Join(n.Note.Tags, ", ")
-	if tags != "" {
-		tags = "[" + tags + "]"
+	return n.describe(fallbackDescriptionWidth, 1, true)
+}
+
+// emptyNoteStyle dims the placeholders of notes without text
+var emptyNoteStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Italic(true)
+
+// emptyNoteLabel describes a note without text in the list
+func emptyNoteLabel(note *notes.Note) string {
+	switch len(note.Images) {
+	case 0:
+		return "(empty note)"
+	case 1:
+		return "(1 image)"
+	default:
+		return fmt.Sprintf("(%d images)", len(note.Images))
 	}
-	return fmt.Sprintf("%s %s", content, lipgloss.NewStyle().Foreground(lipgloss.Color("#5f5")).Render(tags))
 }
 
 // FilterValue returns the value to use for filtering notes
 func (n NoteItem) FilterValue() string {
-	return n.Note.Title + " " + n.Note.Content + " " + strings.Join(n.Note.Tags, " ")
+	images := []string{}
+	for _, img := range n.Note.Images {
+		images = append(images, img.Caption, img.AltText)
+	}
+	return n.Note.Title + " " + strings.Join(n.Note.Aliases, " ") + " " + n.Note.Content + " " + strings.Join(n.Note.Tags, " ") + " " + strings.Join(images, " ")
 }
 
 // TagItem represents a tag in the tag list
@@ -279,11 +673,54 @@ func (t TagItem) FilterValue() string {
 
 // Init initializes the application model
 func (m Model) Init() tea.Cmd {
-	return nil
+	cmds := []tea.Cmd{waitForNoteEvent(m.noteEvents)}
+	if m.loading {
+		cmds = append(cmds, m.loadNotes())
+	}
+
+	// Start the idle delay, the first key press replaces this tick
+	if delay, ok := m.config.Security.LockDelay(); ok {
+		seq := m.lockSeq
+		cmds = append(cmds, tea.Tick(delay, func(time.Time) tea.Msg {
+			return lockMsg{seq: seq}
+		}))
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update updates the application model based on received messages
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	model := updated.(Model)
+
+	// Changes of the notes made by this update show right away
+	model.notesChanged(model.pendingNoteEvents())
+
+	// Any key press restarts the idle delay of the screen lock
+	_, keyPress := msg.(tea.KeyMsg)
+	if keyPress {
+		cmd = tea.Batch(cmd, model.scheduleLock())
+	}
+
+	// Count the time spent in the editor
+	model.trackEditTime(m.mode, keyPress && !m.locked)
+
+	// Render the preview of the edited content once it changes
+	if model.mode != ModeEdit && model.mode != ModeNew {
+		model.previewForce = false
+	}
+	cmd = tea.Batch(cmd, model.schedulePreview())
+
+	// Write the saves held back by the manager once their delay is over
+	cmd = tea.Batch(cmd, model.scheduleFlush())
+
+	// Recompute the layout so that every mode fits the terminal
+	model.layout()
+	return model, cmd
+}
+
+// update dispatches a message to the handler of the current mode
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
@@ -291,17 +728,114 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.noteList.SetWidth(msg.Width)
-		m.noteList.SetHeight(msg.Height - 4) // Reserve space for status
-		m.textArea.SetWidth(msg.Width)
-		m.textArea.SetHeight(msg.Height - 6)
 		return m, nil
 
+	case titleCheckMsg:
+		if msg.seq == m.titleCheckSeq {
+			m.checkTitle()
+		}
+		return m, nil
+
+	case autosaveMsg:
+		if msg.seq == m.autosaveSeq {
+			m.autosave()
+		}
+		return m, nil
+
+	case flushMsg:
+		m.flush()
+		return m, nil
+
+	case gistMsg:
+		m.handleGist(msg)
+		return m, nil
+
+	case noteEventMsg:
+		m.notesChanged(append([]notes.Event{msg.event}, m.pendingNoteEvents()...))
+		return m, waitForNoteEvent(m.noteEvents)
+
+	case syncProgressMsg:
+		return m, m.syncProgress(msg.progress)
+
+	case syncDoneMsg:
+		m.finishSync(msg)
+		return m, nil
+
+	case notesLoadedMsg:
+		return m, m.finishLoading(msg)
+
+	case instanceRequestMsg:
+		m.answerInstance(msg)
+		return m, nil
+
+	case previewTickMsg:
+		if msg.seq == m.previewSeq {
+			m.previewIdle = true
+		}
+		return m, nil
+
+	case previewMsg:
+		m.previewBusy = false
+		m.previewKey = msg.key
+		m.previewOut = msg.rendered
+		return m, nil
+
+	case lockMsg:
+		if msg.seq == m.lockSeq && m.lockEnabled() {
+			m.lock()
+		}
+		return m, nil
+
+	case unlockMsg:
+		return m, m.finishUnlock(msg)
+
+	case tea.KeyMsg:
+		if m.locked {
+			return m.updateLocked(msg)
+		}
+		// Nothing can be done on the notes before they are loaded
+		if m.loading {
+			if key.Matches(msg, m.keys.Quit) {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		// Lock before suspending so that the notes aren't displayed again
+		// when the program is resumed, possibly by someone else
+		if key.Matches(msg, m.keys.Suspend) {
+			if m.lockEnabled() {
+				m.lock()
+			}
+			return m, tea.Suspend
+		}
+	}
+
+	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		// Handle global keys
+		if m.quitPending {
+			return m.confirmQuit(msg)
+		}
+
+		// Handle global keys, q and ? being text in the input fields where
+		// only ctrl+c quits
 		switch {
-		case key.Matches(msg, m.keys.Quit):
+		case key.Matches(msg, m.keys.Quit) && !m.typing(msg):
+			// The mode behind the quick note decides what quitting loses
+			if m.mode == ModeQuickNote {
+				m.closeQuickNote()
+			}
+			if m.unsavedEdits() {
+				m.quitPending = true
+				m.statusMsg = "Unsaved changes — quit anyway? y/n"
+				return m, nil
+			}
 			return m, tea.Quit
+		case key.Matches(msg, m.keys.Help) && m.mode != ModeHelp && !m.typing(msg):
+			m.openHelp()
+			return m, nil
+		case key.Matches(msg, m.keys.QuickNote) && m.mode != ModeQuickNote:
+			m.openQuickNote()
+			return m, nil
 		}
 
 		// Handle keys based on mode
@@ -407,8 +941,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Add tag to the note
 				if m.tagInput.Value() != "" {
 					m.selectedNote.AddTag(m.tagInput.Value())
-					m.notesManager.UpdateNote(m.selectedNote)
-					m.statusMsg = "Tag added successfully"
+					if err := m.notesManager.UpdateNote(m.selectedNote); err != nil {
+						m.statusMsg = fmt.Sprintf("Error saving note: %s", storeError(err))
+					} else {
+						m.statusMsg = "Tag added successfully"
+					}
 					m.mode = ModeView
 				}
 				return m, nil
@@ -416,38 +953,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.tagInput, cmd = m.tagInput.Update(msg)
 			cmds = append(cmds, cmd)
 
+		case ModeEditAliases:
+			return m.updateEditAliasesMode(msg)
+
 		case ModeFilterByTag:
 			if key.Matches(msg, m.keys.Back) {
 				m.mode = ModeList
+				m.refreshNoteList()
 				return m, nil
 			} else if key.Matches(msg, m.keys.Enter) {
-				// Get all tags
-				tags := m.notesManager.GetAllTags()
-
 				// If no tags exist, return to the list
-				if len(tags) == 0 {
+				if len(m.noteList.Items()) == 0 {
 					m.statusMsg = "No tags available"
 					m.mode = ModeList
 					return m, nil
 				}
 
-				// Select the tag at the current index
-				index := m.noteList.Index()
-				if index >= 0 && index < len(tags) {
-					selectedTag := tags[index]
-
-					// Filter notes by this tag
-					filteredNotes := m.notesManager.FilterByTags([]string{selectedTag})
-
-					// Update the list of notes
-					items := []list.Item{}
-					for _, n := range filteredNotes {
-						items = append(items, NoteItem{n})
-					}
-					m.noteList.SetItems(items)
-
-					m.statusMsg = fmt.Sprintf("Notes filtered by tag: %s", selectedTag)
-					m.mode = ModeList
+				// Select the highlighted tag
+				if item, ok := m.noteList.SelectedItem().(TagItem); ok {
+					m.filterByTag(item.Tag)
+				}
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.RenameTag) && !m.noteList.SettingFilter() {
+				if item, ok := m.noteList.SelectedItem().(TagItem); ok {
+					m.openRenameTag(item.Tag)
 				}
 				return m, nil
 			}
@@ -456,54 +986,39 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		case ModeList:
 			return m.updateListMode(msg)
+		case ModeWorkspaces:
+			return m.updateWorkspacesMode(msg)
+		case ModeHelp:
+			return m.updateHelpMode(msg)
+		case ModeConflict:
+			return m.updateConflictMode(msg)
+		case ModeExportBook:
+			return m.updateExportBookMode(msg)
+		case ModeExportImages:
+			return m.updateExportImagesMode(msg)
+		case ModeQuickNote:
+			return m.updateQuickNoteMode(msg)
+		case ModeRenameTag:
+			return m.updateRenameTagMode(msg)
 		case ModeView:
 			return m.updateViewMode(msg)
+		case ModeNoteSearch:
+			return m.updateNoteSearchMode(msg)
+		case ModeActivity:
+			return m.updateActivityMode(msg)
+		case ModeReview:
+			return m.updateReviewMode(msg)
+		case ModeCalendar:
+			return m.updateCalendarMode(msg)
 		case ModeEdit, ModeNew:
-			if key.Matches(msg, m.keys.Save) {
-				return m.saveNote()
-			} else if key.Matches(msg, m.keys.Back) {
-				if m.mode == ModeNew {
-					m.mode = ModeList
-				} else {
-					m.mode = ModeView
-				}
-				return m, nil
-			} else if key.Matches(msg, m.keys.TogglePreview) {
-				m.showPreview = !m.showPreview
-				return m, nil
-			}
-
-			if m.titleInput.Focused() {
-				m.titleInput, cmd = m.titleInput.Update(msg)
-				cmds = append(cmds, cmd)
-			} else {
-				m.textArea, cmd = m.textArea.Update(msg)
-				cmds = append(cmds, cmd)
-			}
-
-			// Switch focus between title and content with tab
-			if msg.String() == "tab" {
-				if m.titleInput.Focused() {
-					m.titleInput.Blur()
-					m.textArea.Focus()
-				} else {
-					m.textArea.Blur()
-					m.titleInput.Focus()
-				}
-			}
+			return m.updateEditMode(msg)
 
 		case ModeSearch:
 			if key.Matches(msg, m.keys.Back) {
 				m.mode = ModeList
 				return m, nil
 			} else if key.Matches(msg, m.keys.Enter) {
-				items := []list.Item{}
-				notes := m.notesManager.SearchNotes(m.searchInput.Value())
-				for _, note := range notes {
-					items = append(items, NoteItem{note})
-				}
-				m.noteList.SetItems(items)
-				m.mode = ModeList
+				m.applySearch(m.searchInput.Value())
 				return m, nil
 			}
 
@@ -515,43 +1030,76 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.mode = ModeView
 				return m, nil
 			} else if key.Matches(msg, m.keys.Enter) {
+				// Several paths or a pattern add every image they list
+				paths := notes.ImagePaths(m.imagePath.Value())
+				if len(paths) > 1 {
+					return m.addImages(paths)
+				}
+				path := m.imagePath.Value()
+				if len(paths) == 1 {
+					path = paths[0]
+				}
+
+				altText := strings.TrimSpace(m.imageAlt.Value())
+				if altText == "" && m.config.Accessibility.RequireAltText {
+					m.statusMsg = "Alt text is required to add an image"
+					return m, nil
+				}
+
 				// Add the image to the note
-				err := m.notesManager.ImportImage(
+				_, err := m.notesManager.ImportImage(
 					m.selectedNote.ID,
-					m.imagePath.Value(),
+					path,
 					m.imageCaption.Value(),
-					"", // No alt text for now
+					altText,
 				)
 
 				if err != nil {
-					m.statusMsg = fmt.Sprintf("Error: %s", err)
+					m.statusMsg = fmt.Sprintf("Error: %s", storeError(err))
 				} else {
 					m.statusMsg = "Image added successfully"
+					if altText == "" {
+						m.statusMsg = "Image added without alt text, screen readers won't be able to describe it"
+					}
 					m.imagePath.Reset()
 					m.imageCaption.Reset()
+					m.imageAlt.Reset()
 					m.mode = ModeView
 				}
 				return m, nil
 			}
 
-			if m.imagePath.Focused() {
-				m.imagePath, cmd = m.imagePath.Update(msg)
-				cmds = append(cmds, cmd)
-			} else {
-				m.imageCaption, cmd = m.imageCaption.Update(msg)
-				cmds = append(cmds, cmd)
-			}
-
-			// Switch focus
+			// Tab completes the image path, then cycles focus between path,
+			// caption and alt text
 			if msg.String() == "tab" {
-				if m.imagePath.Focused() {
+				if m.imagePath.Focused() && m.completeImagePath() {
+					return m, nil
+				}
+				m.pathMatches = nil
+				switch {
+				case m.imagePath.Focused():
 					m.imagePath.Blur()
 					m.imageCaption.Focus()
-				} else {
+				case m.imageCaption.Focused():
 					m.imageCaption.Blur()
+					m.imageAlt.Focus()
+				default:
+					m.imageAlt.Blur()
 					m.imagePath.Focus()
 				}
+				return m, nil
+			}
+
+			switch {
+			case m.imagePath.Focused():
+				m.imagePath, cmd = m.imagePath.Update(msg)
+				m.pathMatches = nil
+			case m.imageCaption.Focused():
+				m.imageCaption, cmd = m.imageCaption.Update(msg)
+			default:
+				m.imageAlt, cmd = m.imageAlt.Update(msg)
 			}
+			cmds = append(cmds, cmd)
 		}
 
 	}
@@ -565,10 +1113,7 @@ func (m Model) updateListMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	switch {
 	case key.Matches(msg, m.keys.New):
-		m.mode = ModeNew
-		m.titleInput.Reset()
-		m.textArea.Reset()
-		m.titleInput.Focus()
+		m.startNew("")
 		return m, nil
 
 	case key.Matches(msg, m.keys.Enter):
@@ -577,37 +1122,74 @@ func (m Model) updateListMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		item, ok := m.noteList.SelectedItem().(NoteItem)
 		if ok {
-			m.selectedNote = item.Note
-			m.mode = ModeView
+			m.openNote(item.Note)
 			return m, nil
 		}
 
-	case key.Matches(msg, m.keys.Search):
-		m.mode = ModeSearch
-		m.searchInput.Reset()
-		m.searchInput.Focus()
+	case key.Matches(msg, m.keys.Mark):
+		m.toggleMark()
 		return m, nil
 
-	case key.Matches(msg, m.keys.FilterByTag):
-		// Get all tags
-		tags := m.notesManager.GetAllTags()
+	case key.Matches(msg, m.keys.ExportBook):
+		m.startBookExport()
+		return m, nil
 
-		// If no tags exist, display a message
-		if len(tags) == 0 {
-			m.statusMsg = "No tags available"
-			return m, nil
-		}
+	case key.Matches(msg, m.keys.Sync):
+		return m, m.startSync()
 
-		// Create TagItem elements for the list
-		items := []list.Item{}
-		for _, tag := range tags {
-			items = append(items, TagItem{Tag: tag})
+	case key.Matches(msg, m.keys.Activity):
+		m.openActivity()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Review):
+		m.startReview()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Calendar):
+		m.openCalendar()
+		return m, nil
+
+	case key.Matches(msg, m.keys.MoveUp):
+		m.moveSelectedNote(-1)
+		return m, nil
+
+	case key.Matches(msg, m.keys.MoveDown):
+		m.moveSelectedNote(1)
+		return m, nil
+
+	case key.Matches(msg, m.keys.Workspaces):
+		m.openWorkspaces()
+		return m, nil
+
+	case key.Matches(msg, m.keys.DetailPane):
+		m.toggleDetailPane()
+		return m, nil
+
+	case m.detailPane() && key.Matches(msg, m.detail.KeyMap.HalfPageDown, m.detail.KeyMap.HalfPageUp):
+		m.detail, cmd = m.detail.Update(msg)
+		return m, cmd
+
+	case key.Matches(msg, m.keys.ShowArchived):
+		m.showArchived = !m.showArchived
+		m.refreshNoteList()
+		if m.showArchived {
+			m.statusMsg = "Showing archived notes"
+		} else {
+			m.statusMsg = "Archived notes hidden"
 		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Search):
+		m.openSearch(m.filter.search)
+		return m, nil
 
-		// Configure the list to display tags
-		m.noteList.SetItems(items)
-		m.mode = ModeFilterByTag
-		m.statusMsg = "Select a tag"
+	case key.Matches(msg, m.keys.Back) && m.filter.active() && m.noteList.FilterState() == list.Unfiltered:
+		m.clearFilter()
+		m.statusMsg = "Filters cleared"
+		return m, nil
+
+	case key.Matches(msg, m.keys.FilterByTag):
+		m.openTagFilter()
 		return m, nil
 	}
 
@@ -616,38 +1198,166 @@ func (m Model) updateListMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateEditMode handles updates in edit and new note modes
+func (m Model) updateEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if m.pastedImage != nil && m.answerImageImport(msg) {
+		return m, nil
+	}
+
+	if key.Matches(msg, m.keys.Save) {
+		return m.saveNote()
+	} else if key.Matches(msg, m.keys.Back) {
+		if m.mode == ModeNew {
+			m.mode = ModeList
+			return m, nil
+		}
+		// Leaving the editor is a blur, and with autosave enabled pending
+		// changes are kept rather than discarded
+		if m.autosaveEnabled() && m.editorDirty() && !m.persistEdit() {
+			return m, nil
+		}
+		m.mode = ModeView
+		return m, nil
+	} else if key.Matches(msg, m.keys.TogglePreview) {
+		m.showPreview = !m.showPreview
+		return m, nil
+	} else if key.Matches(msg, m.keys.ForcePreview) {
+		m.previewForce = true
+		return m, nil
+	}
+
+	// Switch focus between title and content. Indent moves from the
+	// title to the content since titles can't be indented.
+	if key.Matches(msg, m.keys.SwitchFocus) || (key.Matches(msg, m.keys.Indent) && m.titleInput.Focused()) {
+		m.switchEditorFocus()
+		if m.config.Editor.AutosaveOnBlur() {
+			m.autosave()
+		}
+		return m, nil
+	}
+
+	if m.titleInput.Focused() {
+		m.titleInput, cmd = m.titleInput.Update(msg)
+		cmd = tea.Batch(cmd, m.scheduleTitleCheck())
+	} else if key.Matches(msg, m.keys.Indent) {
+		m.insertIndent()
+	} else if key.Matches(msg, m.keys.MoveLineUp) {
+		m.moveLine(-1)
+	} else if key.Matches(msg, m.keys.MoveLineDown) {
+		m.moveLine(1)
+	} else if key.Matches(msg, m.keys.DuplicateLine) {
+		m.duplicateLine()
+	} else if key.Matches(msg, m.keys.JoinLines) {
+		m.joinLines()
+	} else if key.Matches(msg, m.keys.SmartPaste) {
+		m.smartPaste()
+	} else if key.Matches(msg, m.keys.UndoPaste) {
+		m.undoPaste()
+	} else if key.Matches(msg, m.keys.TOC) {
+		m.editorTableOfContents()
+	} else {
+		_, row, col := m.editorLines()
+		m.textArea, cmd = m.textArea.Update(msg)
+		if _, endRow, endCol := m.editorLines(); msg.Paste && endRow == row {
+			m.offerImageImport(row, col, endCol)
+		}
+	}
+
+	return m, tea.Batch(cmd, m.scheduleAutosave())
+}
+
 // updateViewMode handles updates in view mode
 func (m Model) updateViewMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
 	switch {
 	case key.Matches(msg, m.keys.Back):
+		m.rememberPosition()
 		m.mode = ModeList
 		return m, nil
 
-	case key.Matches(msg, m.keys.Edit):
-		m.mode = ModeEdit
-		m.titleInput.SetValue(m.selectedNote.Title)
-		m.textArea.SetValue(m.selectedNote.Content)
-		m.titleInput.Focus()
+	case key.Matches(msg, m.keys.NextNote):
+		m.stepNote(1)
 		return m, nil
 
-	case key.Matches(msg, m.keys.Delete):
-		m.notesManager.DeleteNote(m.selectedNote.ID)
+	case key.Matches(msg, m.keys.PrevNote):
+		m.stepNote(-1)
+		return m, nil
+
+	case key.Matches(msg, m.keys.Pin):
+		m.selectedNote.SetPinned(!m.selectedNote.Pinned)
+		if err := m.notesManager.UpdateNote(m.selectedNote); err != nil {
+			m.statusMsg = fmt.Sprintf("Error saving note: %s", storeError(err))
+			return m, nil
+		}
+		if m.selectedNote.Pinned {
+			m.statusMsg = "Note pinned"
+		} else {
+			m.statusMsg = "Note unpinned"
+		}
+		return m, nil
 
-		// Update the list
-		items := []list.Item{}
-		for _, note := range m.notesManager.Notes {
-			items = append(items, NoteItem{note})
+	case key.Matches(msg, m.keys.Archive):
+		m.selectedNote.SetArchived(!m.selectedNote.Archived)
+		if err := m.notesManager.UpdateNote(m.selectedNote); err != nil {
+			m.statusMsg = fmt.Sprintf("Error saving note: %s", storeError(err))
+			return m, nil
 		}
-		m.noteList.SetItems(items)
+		if m.selectedNote.Archived {
+			m.statusMsg = "Note archived"
+		} else {
+			m.statusMsg = "Note restored from the archive"
+		}
+		return m, nil
 
+	case key.Matches(msg, m.keys.ExportImages):
+		m.startImagesExport()
+		return m, nil
+
+	case key.Matches(msg, m.keys.CopyID):
+		if err := m.clipboard.WriteAll(m.selectedNote.ID); err != nil {
+			m.statusMsg = fmt.Sprintf("Unable to copy note ID: %s", err)
+		} else {
+			m.statusMsg = "Note ID copied to clipboard"
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Share):
+		return m, m.shareNote()
+
+	case key.Matches(msg, m.keys.Unshare):
+		return m, m.unshareNote()
+
+	case key.Matches(msg, m.keys.Export):
+		m.startNoteExport()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Edit):
+		m.startEdit()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Delete):
+		err := m.notesManager.DeleteNote(m.selectedNote.ID)
+		delete(m.readingPos, m.selectedNote.ID)
 		m.mode = ModeList
 		m.statusMsg = "Note deleted"
+		// A note already gone is as good as deleted, a failed save leaves
+		// it in the file until the next one
+		if err != nil && !errors.Is(err, notes.ErrNoteNotFound) {
+			m.statusMsg = fmt.Sprintf("Error deleting note: %s", storeError(err))
+		}
 		return m, nil
 
 	case key.Matches(msg, m.keys.AddImage):
 		m.mode = ModeAddImage
 		m.imagePath.Reset()
+		m.pathMatches = nil
 		m.imageCaption.Reset()
+		m.imageAlt.Reset()
+		m.imageCaption.Blur()
+		m.imageAlt.Blur()
 		m.imagePath.Focus()
 		return m, nil
 
@@ -657,6 +1367,25 @@ func (m Model) updateViewMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.tagInput.Focus()
 		return m, nil
 
+	case key.Matches(msg, m.keys.ReadingWidth):
+		m.cycleReadingWidth()
+		return m, nil
+
+	case key.Matches(msg, m.keys.TOC):
+		m.noteTableOfContents()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Search):
+		m.startNoteSearch()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Aliases):
+		m.mode = ModeEditAliases
+		m.aliasInput.SetValue(strings.Join(m.selectedNote.Aliases, ", "))
+		m.aliasInput.CursorEnd()
+		m.aliasInput.Focus()
+		return m, nil
+
 	case key.Matches(msg, m.keys.ViewImage):
 		// Check if the note has any images
 		if len(m.selectedNote.Images) > 0 {
@@ -683,38 +1412,256 @@ func (m Model) updateViewMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	return m, nil
+	// Other keys scroll the note
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// startNew opens the editor on a new note with the given title
+func (m *Model) startNew(title string) {
+	m.mode = ModeNew
+	m.titleWarning = ""
+	m.titleInput.Reset()
+	m.titleInput.SetValue(title)
+	m.textArea.Reset()
+	m.textArea.CharLimit = m.config.Editor.MaxChars
+	m.titleInput.Focus()
+}
+
+// openSearch opens the search prompt of the list, filled with query
+func (m *Model) openSearch(query string) {
+	m.mode = ModeSearch
+	m.searchInput.SetValue(query)
+	m.searchInput.CursorEnd()
+	m.searchInput.Focus()
+}
+
+// applySearch narrows the list to the notes matching query and goes back to
+// the list. An invalid query is reported and the prompt stays open.
+func (m *Model) applySearch(query string) {
+	query = strings.TrimSpace(query)
+	if _, err := notes.ParseQuery(query, time.Now()); err != nil {
+		m.statusMsg = fmt.Sprintf("Invalid search: %v", err)
+		return
+	}
+	m.filter.search = query
+	m.refreshNoteList()
+	m.mode = ModeList
+}
+
+// openTagFilter lists the tags to pick the one narrowing the list
+func (m *Model) openTagFilter() {
+	// The highlighted note is selected again once the tags are gone
+	note := m.highlightedNote()
+	if !m.listTags() {
+		m.statusMsg = "No tags available"
+		return
+	}
+	if note != nil {
+		m.listedNote = note.ID
+	}
+	m.mode = ModeFilterByTag
+	m.statusMsg = "Select a tag"
+}
+
+// filterByTag narrows the list to the notes with tag and goes back to it
+func (m *Model) filterByTag(tag string) {
+	m.filter.tag = tag
+	m.refreshNoteList()
+	m.statusMsg = fmt.Sprintf("Notes filtered by tag: %s", tag)
+	m.mode = ModeList
+}
+
+// startEdit opens the selected note in the editor
+func (m *Model) startEdit() {
+	m.mode = ModeEdit
+	m.lastAutosave = time.Time{}
+	m.saveErr = nil
+	m.titleWarning = ""
+	m.titleInput.SetValue(m.selectedNote.Title)
+	m.setEditorContent(m.selectedNote.Content)
+	m.titleInput.Focus()
+}
+
+// stepNote opens the next (delta > 0) or previous (delta < 0) note in the
+// current list order and moves the list selection along with it
+func (m *Model) stepNote(delta int) {
+	items := m.noteList.VisibleItems()
+	if len(items) == 0 {
+		return
+	}
+
+	index := m.noteList.Index() + delta
+	if index < 0 || index >= len(items) {
+		if !m.config.View.WrapNavigation {
+			if delta > 0 {
+				m.statusMsg = "Last note"
+			} else {
+				m.statusMsg = "First note"
+			}
+			return
+		}
+		index = (index + len(items)) % len(items)
+	}
+
+	item, ok := items[index].(NoteItem)
+	if !ok {
+		return
+	}
+	m.noteList.Select(index)
+	m.statusMsg = ""
+	m.openNote(item.Note)
+}
+
+// openNote displays a note in view mode, restoring the scroll position it
+// had when it was last read
+func (m *Model) openNote(note *notes.Note) {
+	m.rememberPosition()
+	m.selectedNote = note
+	m.mode = ModeView
+	if err := m.notesManager.BackfillImageInfo(note); err != nil {
+		m.statusMsg = fmt.Sprintf("Error saving image details: %s", storeError(err))
+	}
+	m.showNoteBody()
+	m.viewport.SetYOffset(m.readingPos[note.ID])
+}
+
+// rememberPosition records the scroll position of the note being read
+func (m *Model) rememberPosition() {
+	if m.selectedNote == nil || m.mode != ModeView {
+		return
+	}
+	m.readingPos[m.selectedNote.ID] = m.viewport.YOffset
+}
+
+// noteItems converts notes to list items, hiding archived notes unless they
+// are shown explicitly and keeping pinned notes at the top
+func (m Model) noteItems(ns []*notes.Note) []list.Item {
+	pinned := []list.Item{}
+	others := []list.Item{}
+	for _, n := range ns {
+		if n.Archived && !m.showArchived {
+			continue
+		}
+		if n.Pinned {
+			pinned = append(pinned, NoteItem{Note: n, Marked: m.isMarked(n.ID)})
+		} else {
+			others = append(others, NoteItem{Note: n, Marked: m.isMarked(n.ID)})
+		}
+	}
+	return append(pinned, others...)
+}
+
+// resultItems returns the list items of search results, described by where
+// they matched
+func (m Model) resultItems(results []notes.SearchResult) []list.Item {
+	ns := make([]*notes.Note, len(results))
+	snippets := map[string]string{}
+	for i, result := range results {
+		ns[i] = result.Note
+		switch result.Field {
+		case notes.MatchContent:
+			snippets[result.Note.ID] = result.Snippet
+		case notes.MatchCaption, notes.MatchAltText:
+			snippets[result.Note.ID] = fmt.Sprintf("matched %s: %s", result.Field, result.Snippet)
+		}
+	}
+
+	items := m.noteItems(ns)
+	for i, item := range items {
+		if note, ok := item.(NoteItem); ok {
+			note.Snippet = snippets[note.ID]
+			items[i] = note
+		}
+	}
+	return items
+}
+
+// refreshNoteList rebuilds the list items from the notes the filter keeps,
+// reporting whether the highlighted note is still listed
+func (m *Model) refreshNoteList() bool {
+	return m.setNoteItems(m.filteredItems())
+}
+
+// listTags fills the list with every tag in the configured order, the cursor
+// at the top. It reports false, leaving the list as it is, when there is no
+// tag.
+func (m *Model) listTags() bool {
+	tags := m.notesManager.GetAllTags()
+	if m.config.Tags.Sort == config.TagSortFrequency {
+		tags = m.notesManager.GetAllTagsByFrequency()
+	}
+	if len(tags) == 0 {
+		return false
+	}
+
+	items := []list.Item{}
+	for _, tag := range tags {
+		items = append(items, TagItem{Tag: tag})
+	}
+	m.noteList.SetItems(items)
+	m.noteList.Select(0)
+	return true
+}
+
+// setNoteItems replaces the notes of the list, keeping the highlighted note
+// under the cursor. It reports false when that note isn't listed anymore,
+// the cursor going back to the top.
+func (m *Model) setNoteItems(items []list.Item) bool {
+	id := m.listedNote
+	if note := m.highlightedNote(); note != nil {
+		id = note.ID
+	}
+	m.listedNote = ""
+
+	m.noteList.SetItems(items)
+	if m.selectNote(id) {
+		return true
+	}
+	m.noteList.Select(0)
+	return false
+}
+
+// selectNote moves the cursor of the list to a note, reporting false when
+// the note isn't listed
+func (m *Model) selectNote(id string) bool {
+	for i, item := range m.noteList.VisibleItems() {
+		if note, ok := item.(NoteItem); ok && note.ID == id {
+			m.noteList.Select(i)
+			return true
+		}
+	}
+	return false
 }
 
 // saveNote saves the note being edited
 func (m Model) saveNote() (tea.Model, tea.Cmd) {
 	if m.mode == ModeNew {
+		// Looked up before the note exists, which would match itself
+		duplicate := m.notesManager.TitleExists(m.titleInput.Value())
 		note := m.notesManager.CreateNote(m.titleInput.Value())
-		note.Content = m.textArea.Value()
-		m.notesManager.UpdateNote(note)
+		note.Content = m.editorContent()
 		m.selectedNote = note
+		m.mode = ModeView
 
-		// Update the list
-		items := []list.Item{}
-		for _, n := range m.notesManager.Notes {
-			items = append(items, NoteItem{n})
+		if err := m.notesManager.UpdateNote(note); err != nil {
+			m.statusMsg = fmt.Sprintf("Error saving note: %s", storeError(err))
+		} else if duplicate {
+			m.statusMsg = "Note created, another note already has this title"
+		} else {
+			m.statusMsg = "Note created successfully"
 		}
-		m.noteList.SetItems(items)
-
-		m.mode = ModeView
-		m.statusMsg = "Note created successfully"
 	} else {
 		// Edit mode
-		m.selectedNote.Title = m.titleInput.Value()
-		m.selectedNote.Content = m.textArea.Value()
-		m.notesManager.UpdateNote(m.selectedNote)
-
-		// Update the list
-		items := []list.Item{}
-		for _, n := range m.notesManager.Notes {
-			items = append(items, NoteItem{n})
+		if !m.persistEdit() {
+			return m, nil
+		}
+
+		// With autosave the editor stays open, Ctrl+S only forces the save
+		if m.autosaveEnabled() {
+			m.statusMsg = "Note saved"
+			return m, nil
 		}
-		m.noteList.SetItems(items)
 
 		m.mode = ModeView
 		m.statusMsg = "Note updated successfully"
@@ -725,18 +1672,35 @@ func (m Model) saveNote() (tea.Model, tea.Cmd) {
 
 // View returns the user interface display
 func (m Model) View() string {
+	if m.locked {
+		return m.lockedView()
+	}
+	if m.tooSmall() {
+		return m.tooSmallView()
+	}
+	if m.loading {
+		return m.loadingView()
+	}
+
 	switch m.mode {
 	case ModeList:
-		return lipgloss.JoinVertical(
-			lipgloss.Left,
-			m.noteList.View(),
-			m.statusBar(),
-			m.helpView(),
-		)
+		return m.viewList()
 
 	case ModeView:
 		return m.viewNote()
 
+	case ModeNoteSearch:
+		return m.viewNoteSearch()
+
+	case ModeActivity:
+		return m.viewActivity()
+
+	case ModeReview:
+		return m.viewReview()
+
+	case ModeCalendar:
+		return m.viewCalendar()
+
 	case ModeViewImage:
 		return m.viewImage()
 
@@ -749,19 +1713,41 @@ func (m Model) View() string {
 			"Search:",
 			m.searchInput.View(),
 			m.statusBar(),
-			"Press Enter to search, Esc to cancel",
+			"Filters: tag:work created:>2024-01-01 modified:<7d",
+			m.helpView(),
 		)
 
 	case ModeAddImage:
 		return m.modeAddImage()
 
+	case ModeExportBook:
+		return m.viewExportBook()
+
+	case ModeExportImages:
+		return m.viewExportImages()
+
+	case ModeQuickNote:
+		return m.viewQuickNote()
+
+	case ModeRenameTag:
+		return m.viewRenameTag()
+
 	case ModeAddTag:
 		return lipgloss.JoinVertical(
 			lipgloss.Left,
 			"Add a tag:",
 			m.tagInput.View(),
 			m.statusBar(),
-			"Press Enter to add, Esc to cancel",
+			m.helpView(),
+		)
+
+	case ModeEditAliases:
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			"Aliases of the note:",
+			m.aliasInput.View(),
+			m.statusBar(),
+			m.helpView(),
 		)
 
 	case ModeFilterByTag:
@@ -770,7 +1756,22 @@ func (m Model) View() string {
 			"Filter by tag:",
 			m.noteList.View(),
 			m.statusBar(),
-			"Press Enter to filter, Esc to cancel",
+			m.helpView(),
+		)
+
+	case ModeHelp:
+		return m.viewHelp()
+
+	case ModeConflict:
+		return m.viewConflict()
+
+	case ModeWorkspaces:
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			"Switch workspace:",
+			m.noteList.View(),
+			m.statusBar(),
+			m.helpView(),
 		)
 
 	default:
@@ -783,21 +1784,35 @@ func (m Model) modeAddImage() string {
 	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFA500"))
 
+	// The completions of the path take the place of the examples
+	pathHelp := helpStyle.Render("Exemple: /home/user/images/photo.jpg\nPlusieurs images : a.png, b.png ou ~/captures/*.png")
+	if len(m.pathMatches) > 0 {
+		pathHelp = helpStyle.Render(ansi.Truncate(strings.Join(m.pathMatches, "  "), m.width, "…") + "\n")
+	}
+
+	altLabel := "Texte alternatif (recommandé) :"
+	if m.config.Accessibility.RequireAltText {
+		altLabel = "Texte alternatif (obligatoire) :"
+	}
+
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
 		titleStyle.Render("📷 Ajouter une image à la note"),
 		"",
 		"Chemin de l'image (chemin complet vers le fichier) :",
 		m.imagePath.View(),
-		helpStyle.Render("Exemple: /home/user/images/photo.jpg"),
+		pathHelp,
 		"",
 		"Légende (optionnelle) :",
 		m.imageCaption.View(),
 		"",
-		helpStyle.Render("Utilisez Tab pour naviguer entre les champs"),
-		helpStyle.Render("Entrée pour confirmer, Échap pour annuler"),
+		altLabel,
+		m.imageAlt.View(),
+		helpStyle.Render("Décrit l'image pour les lecteurs d'écran"),
+		"",
 		"",
 		m.statusBar(),
+		m.helpView(),
 	)
 }
 
@@ -807,15 +1822,61 @@ func (m Model) viewNote() string {
 		return "No note selected"
 	}
 
-	titleStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#FFA500")).
-		MarginBottom(1)
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.viewport.View(),
+		"",
+		m.statusBar(),
+		m.helpView(),
+	)
+}
+
+// noteBody renders the scrollable part of view mode: the note and its metadata
+func (m Model) noteBody() string {
+	if m.selectedNote == nil {
+		return ""
+	}
+	return m.renderNote(m.selectedNote, m.width)
+}
+
+// showNoteBody displays the selected note in the note view. The layout runs
+// after every message, so the note is only rendered again when bodyKey
+// changed.
+func (m *Model) showNoteBody() {
+	key := m.bodyKey(m.selectedNote, m.width)
+	if key == m.viewKey {
+		return
+	}
+	m.viewKey = key
+	m.viewport.SetContent(m.noteBody())
+}
+
+// bodyKey returns a hash of what renderNote draws a note from: the note, the
+// width, the matches of the search within it and the version of the store,
+// which the notes it embeds belong to
+func (m Model) bodyKey(note *notes.Note, width int) [sha256.Size]byte {
+	h := sha256.New()
+	if data, err := json.Marshal(note); err == nil {
+		h.Write(data)
+	}
+	fmt.Fprintf(h, "\x00%d %d %v", width, m.storeVersion, m.mode == ModeNoteSearch)
+	if m.mode == ModeNoteSearch {
+		fmt.Fprintf(h, " %v %d", m.noteMatches, m.currentMatch)
+	}
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+	return key
+}
 
+// renderNote renders a note and its metadata within width columns
+func (m Model) renderNote(note *notes.Note, width int) string {
+	// The note is wrapped to the reading width and centered, the metadata
+	// below uses the whole width
+	readingWidth := max(min(m.readingWidth(), width), 1)
 	contentStyle := lipgloss.NewStyle().
 		MarginTop(1).
 		MarginBottom(1).
-		Width(m.width)
+		Width(readingWidth)
 
 	metadataStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#888888")).
@@ -830,67 +1891,106 @@ func (m Model) viewNote() string {
 	warningStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#ff7700"))
 
-	title := titleStyle.Render(m.selectedNote.Title)
-	content := contentStyle.Render(m.selectedNote.Content)
-	created := metadataStyle.Render(fmt.Sprintf("Created on: %s", m.selectedNote.CreatedAt.Format("02/01/2006 15:04")))
-	updated := metadataStyle.Render(fmt.Sprintf("Updated on: %s", m.selectedNote.UpdatedAt.Format("02/01/2006 15:04")))
+	idStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#555555"))
+
+	// The search within the note runs on its own text, without colors,
+	// elsewhere the notes it embeds are displayed in it
+	content := m.highlightMatches(sanitizeTerminal(note.Content, false))
+	if m.mode != ModeNoteSearch {
+		content = expandEmbeds(note.Content, readingWidth, m.embedLookup(note.Content), []string{note.ID}, 0, func(text string, _ int) string {
+			return sanitizeTerminal(text, m.config.View.RenderANSI)
+		})
+	}
+	if notes.IsBlank(note.Content) {
+		content = emptyNoteStyle.Render("Nothing written yet, press e to write this note")
+	}
+	column := lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.titleStyle().Width(readingWidth).Render(sanitizeTerminal(note.Title, false)),
+		contentStyle.Render(content),
+	)
+	column = lipgloss.PlaceHorizontal(width, lipgloss.Center, column)
+	created := metadataStyle.Render(fmt.Sprintf("Created on: %s", note.CreatedAt.Format("02/01/2006 15:04")))
+	updated := metadataStyle.Render(fmt.Sprintf("Updated on: %s", note.UpdatedAt.Format("02/01/2006 15:04")))
+	noteID := idStyle.Render(fmt.Sprintf("ID: %s", note.ID))
+
+	shared := ""
+	if note.Gist != nil {
+		shared = metadataStyle.Render("Shared at: " + note.Gist.URL)
+	}
 
 	tags := ""
-	if len(m.selectedNote.Tags) > 0 {
-		tags = tagsStyle.Render("Tags: " + strings.Join(m.selectedNote.Tags, ", "))
+	if len(note.Tags) > 0 {
+		tags = tagsStyle.Render("Tags: " + strings.Join(note.Tags, ", "))
+	}
+
+	timeSpent := ""
+	if note.TimeSpent > 0 {
+		timeSpent = metadataStyle.Render("Time spent editing: " + formatTimeSpent(note.TimeSpent))
+	}
+
+	aliases := ""
+	if len(note.Aliases) > 0 {
+		aliases = metadataStyle.Render("Also known as: " + strings.Join(note.Aliases, ", "))
 	}
 
 	imagesSection := ""
-	if len(m.selectedNote.Images) > 0 {
-		imagesSection = imageStyle.Render("📷 Images attachées:\n")
+	if len(note.Images) > 0 {
+		blocks := []string{imageStyle.Render("📷 Images attachées:")}
 		validImagesCount := 0
 
-		for i, img := range m.selectedNote.Images {
-			caption := img.Caption
-			if caption == "" {
-				caption = "(aucune légende)"
-			}
-
+		for i, img := range note.Images {
 			// Vérifier si l'image existe toujours
-			if m.notesManager.ImageExists(img.Path) {
-				imagesSection += imageStyle.Render(fmt.Sprintf("%d. %s: %s\n", i+1, img.Path, caption))
+			missing := !m.notesManager.ImageExists(img.Path)
+			if !missing {
 				validImagesCount++
-			} else {
-				imagesSection += warningStyle.Render(fmt.Sprintf("%d. %s: %s (fichier manquant)\n", i+1, img.Path, caption))
 			}
+			blocks = append(blocks, imageBlock(i+1, img, missing, readingWidth))
 		}
 
-		if validImagesCount == 0 && len(m.selectedNote.Images) > 0 {
-			imagesSection += warningStyle.Render("⚠️ Aucune image n'a pu être trouvée. Les fichiers ont peut-être été déplacés ou supprimés.\n")
+		if validImagesCount == 0 {
+			blocks = append(blocks, warningStyle.Render("⚠️ Aucune image n'a pu être trouvée. Les fichiers ont peut-être été déplacés ou supprimés."))
 		}
+		imagesSection = lipgloss.JoinVertical(lipgloss.Left, blocks...) + "\n"
 	}
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
-		title,
-		content,
+		column,
 		imagesSection,
 		tags,
+		aliases,
 		created,
 		updated,
-		"",
-		m.statusBar(),
-		m.helpView(),
+		timeSpent,
+		shared,
+		noteID,
 	)
 }
 
+// titleStyle returns the style of the title in the note view
+func (m Model) titleStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FFA500")).
+		MarginBottom(1).
+		Width(m.readingWidth())
+}
+
 // viewEditor displays the note editor
 func (m Model) viewEditor() string {
 	modeText := "Editing"
 	if m.mode == ModeNew {
 		modeText = "New note"
+	} else if indicator := m.autosaveIndicator(); indicator != "" {
+		modeText += " " + indicator
 	}
 
 	// If preview is enabled, split the screen into two parts
 	if m.showPreview {
 		// Calculate widths for editor and preview
-		editorWidth := m.width / 2
-		previewWidth := m.width - editorWidth - 1
+		editorWidth, previewWidth := m.editorPanes()
 
 		// Create styles
 		editorStyle := lipgloss.NewStyle().Width(editorWidth)
@@ -906,12 +2006,13 @@ func (m Model) viewEditor() string {
 			modeText,
 			"Title:",
 			m.titleInput.View(),
+			m.titleInfoView(),
 			"Content:",
 			m.textArea.View(),
 		)
 
 		// Preview section
-		previewContent := m.renderMarkdown(m.textArea.Value())
+		previewContent := m.previewView()
 		previewTitle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFA500")).Render(m.titleInput.Value())
 
 		previewSection := lipgloss.JoinVertical(
@@ -934,129 +2035,24 @@ func (m Model) viewEditor() string {
 			lipgloss.Left,
 			content,
 			m.statusBar(),
-			"Ctrl+S to save, Esc to cancel, Ctrl+P to toggle preview",
+			m.helpView(),
 		)
 	}
 
 	// Normal display (without preview)
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
-		modeText+" (Tab to switch between title and content)",
+		modeText+" (Shift+Tab to switch between title and content, Tab to indent)",
 		"Title:",
 		m.titleInput.View(),
+		m.titleInfoView(),
 		"Content:",
 		m.textArea.View(),
 		m.statusBar(),
-		"Ctrl+S to save, Esc to cancel, Ctrl+P for preview",
+		m.helpView(),
 	)
 }
 
-// renderMarkdown renders Markdown content as formatted text
-func (m Model) renderMarkdown(content string) string {
-	if content == "" {
-		return ""
-	}
-
-	// Convert markdown to HTML
-	var htmlBuf strings.Builder
-	if err := m.markdown.Convert([]byte(content), &htmlBuf); err != nil {
-		return fmt.Sprintf("Error rendering Markdown: %s", err)
-	}
-
-	html := htmlBuf.String()
-
-	// Apply styles for common HTML elements
-	// Define styles
-	h1Style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF0000")).MarginBottom(1)
-	h2Style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF5500")).MarginBottom(1)
-	h3Style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFAA00"))
-	boldStyle := lipgloss.NewStyle().Bold(true)
-	italicStyle := lipgloss.NewStyle().Italic(true)
-	codeStyle := lipgloss.NewStyle().Background(lipgloss.Color("#333")).Foreground(lipgloss.Color("#FFF"))
-
-	// Replace HTML tags with formatted text
-	// Headings
-	h1Regex := regexp.MustCompile(`<h1[^>]*>(.*?)</h1>`)
-	html = h1Regex.ReplaceAllStringFunc(html, func(match string) string {
-		content := h1Regex.FindStringSubmatch(match)[1]
-		return h1Style.Render(content)
-	})
-
-	h2Regex := regexp.MustCompile(`<h2[^>]*>(.*?)</h2>`)
-	html = h2Regex.ReplaceAllStringFunc(html, func(match string) string {
-		content := h2Regex.FindStringSubmatch(match)[1]
-		return h2Style.Render(content)
-	})
-
-	h3Regex := regexp.MustCompile(`<h3[^>]*>(.*?)</h3>`)
-	html = h3Regex.ReplaceAllStringFunc(html, func(match string) string {
-		content := h3Regex.FindStringSubmatch(match)[1]
-		return h3Style.Render(content)
-	})
-
-	// Bold
-	boldRegex := regexp.MustCompile(`<(?:strong|b)[^>]*>(.*?)</(?:strong|b)>`)
-	html = boldRegex.ReplaceAllStringFunc(html, func(match string) string {
-		content := boldRegex.FindStringSubmatch(match)[1]
-		return boldStyle.Render(content)
-	})
-
-	// Italic
-	italicRegex := regexp.MustCompile(`<(?:em|i)[^>]*>(.*?)</(?:em|i)>`)
-	html = italicRegex.ReplaceAllStringFunc(html, func(match string) string {
-		content := italicRegex.FindStringSubmatch(match)[1]
-		return italicStyle.Render(content)
-	})
-
-	// Code
-	codeRegex := regexp.MustCompile(`<code[^>]*>(.*?)</code>`)
-	html = codeRegex.ReplaceAllStringFunc(html, func(match string) string {
-		content := codeRegex.FindStringSubmatch(match)[1]
-		return codeStyle.Render(content)
-	})
-
-	// Lists
-	html = strings.ReplaceAll(html, "<ul>", "")
-	html = strings.ReplaceAll(html, "</ul>", "\n")
-	html = strings.ReplaceAll(html, "<ol>", "")
-	html = strings.ReplaceAll(html, "</ol>", "\n")
-
-	// List items
-	liRegex := regexp.MustCompile(`<li[^>]*>(.*?)</li>`)
-	html = liRegex.ReplaceAllStringFunc(html, func(match string) string {
-		content := liRegex.FindStringSubmatch(match)[1]
-		return "• " + content + "\n"
-	})
-
-	// Paragraphs
-	html = strings.ReplaceAll(html, "<p>", "")
-	html = strings.ReplaceAll(html, "</p>", "\n\n")
-
-	// Links
-	linkRegex := regexp.MustCompile(`<a[^>]*href="([^"]*)"[^>]*>(.*?)</a>`)
-	html = linkRegex.ReplaceAllStringFunc(html, func(match string) string {
-		parts := linkRegex.FindStringSubmatch(match)
-		url := parts[1]
-		text := parts[2]
-		return fmt.Sprintf("%s (%s)", text, url)
-	})
-
-	// Clean up excessive line breaks
-	html = strings.ReplaceAll(html, "\n\n\n", "\n\n")
-
-	// Remove remaining HTML tags
-	cleanRegex := regexp.MustCompile("<[^>]*>")
-	html = cleanRegex.ReplaceAllString(html, "")
-
-	// Decode HTML entities
-	html = strings.ReplaceAll(html, "&lt;", "<")
-	html = strings.ReplaceAll(html, "&gt;", ">")
-	html = strings.ReplaceAll(html, "&amp;", "&")
-	html = strings.ReplaceAll(html, "&quot;", "\"")
-
-	return html
-}
-
 // viewImage displays an image in view mode
 func (m Model) viewImage() string {
 	if m.selectedNote == nil || len(m.selectedNote.Images) == 0 || m.selectedImage < 0 || m.selectedImage >= len(m.selectedNote.Images) {
@@ -1141,31 +2137,68 @@ func (m Model) viewImage() string {
 // statusBar displays the status bar at the bottom of the screen
 func (m Model) statusBar() string {
 	status := m.statusMsg
+	if m.mode == ModeEdit || m.mode == ModeNew {
+		if warning := contentSizeWarning(m.textArea.Length(), m.config.Editor); warning != "" && status != "" {
+			status += " · " + warning
+		} else if warning != "" {
+			status = warning
+		}
+	}
 	if status == "" {
 		status = "Ready"
 
 	}
 
-	return lipgloss.NewStyle().
+	// The status stays on one line, a long one such as quoting a title
+	// would otherwise push the screen up
+	badge := m.workspaceBadge() + m.saveModeBadge()
+	width := max(m.width-lipgloss.Width(badge), 0)
+	if m.width > 0 {
+		status = ansi.Truncate(strings.Join(strings.Fields(status), " "), max(width-2, 1), "…")
+	}
+	return badge + lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#FAFAFA")).
 		Background(lipgloss.Color("#555555")).
 		Padding(0, 1).
-		Width(m.width).
+		Width(width).
 		Render(status)
 }
 
-// helpView displays navigation help
+// helpView displays navigation help on a single line
 func (m Model) helpView() string {
+	help := m.shortHelpView()
+	// help.Model doesn't truncate when the ellipsis itself doesn't fit
+	if m.width > 0 {
+		help = ansi.Truncate(help, m.width, "…")
+	}
+	return help
+}
+
+// shortHelpView returns the bindings help of the current mode
+func (m Model) shortHelpView() string {
 	switch m.mode {
 	case ModeList:
-		return m.help.ShortHelpView([]key.Binding{
+		bindings := []key.Binding{}
+		if m.filter.active() {
+			bindings = append(bindings, relabel(m.keys.Back, "clear filters"))
+		}
+		return m.help.ShortHelpView(append(bindings,
 			m.keys.Up,
 			m.keys.Down,
 			m.keys.Enter,
 			m.keys.New,
 			m.keys.Search,
+			m.keys.Mark,
+			m.keys.ExportBook,
+			m.keys.Sync,
+			m.keys.Review,
+			m.keys.Calendar,
+			m.keys.Activity,
+			m.keys.DetailPane,
+			m.keys.Workspaces,
+			m.keys.ShowArchived,
 			m.keys.Quit,
-		})
+		))
 	case ModeView:
 		return m.help.ShortHelpView([]key.Binding{
 			m.keys.Back,
@@ -1173,7 +2206,45 @@ func (m Model) helpView() string {
 			m.keys.Delete,
 			m.keys.AddImage,
 			m.keys.AddTag,
+			m.keys.Aliases,
 			m.keys.ViewImage,
+			m.keys.Pin,
+			m.keys.Archive,
+			m.keys.PrevNote,
+			m.keys.NextNote,
+			m.keys.CopyID,
+			m.keys.Search,
+			m.keys.ReadingWidth,
+			m.keys.Share,
+			m.keys.Export,
+			m.keys.Quit,
+		})
+	case ModeCalendar:
+		return m.help.ShortHelpView([]key.Binding{
+			m.keys.Back,
+			m.keys.Enter,
+			m.keys.PrevDay,
+			m.keys.NextDay,
+			m.keys.Up,
+			m.keys.Down,
+			m.keys.PrevMonth,
+			m.keys.NextMonth,
+			m.keys.Quit,
+		})
+	case ModeReview:
+		return m.help.ShortHelpView([]key.Binding{
+			m.keys.Back,
+			m.keys.Keep,
+			m.keys.Edit,
+			m.keys.Archive,
+			m.keys.Delete,
+			m.keys.Quit,
+		})
+	case ModeActivity:
+		return m.help.ShortHelpView([]key.Binding{
+			m.keys.Back,
+			m.keys.Up,
+			m.keys.Down,
 			m.keys.Quit,
 		})
 	case ModeViewImage:
@@ -1184,19 +2255,159 @@ func (m Model) helpView() string {
 			m.keys.OpenImage,
 			m.keys.Quit,
 		})
+	case ModeEdit, ModeNew:
+		return m.help.ShortHelpView([]key.Binding{
+			m.keys.Save,
+			relabel(m.keys.Back, "cancel"),
+			m.keys.TogglePreview,
+			m.typingHelp(),
+			m.keys.SwitchFocus,
+			m.keys.Indent,
+			m.keys.MoveLineUp,
+			m.keys.MoveLineDown,
+			m.keys.DuplicateLine,
+			m.keys.JoinLines,
+			m.keys.SmartPaste,
+		})
+	case ModeSearch:
+		return m.help.ShortHelpView([]key.Binding{
+			relabel(m.keys.Enter, "search"),
+			relabel(m.keys.Back, "cancel"),
+			m.typingHelp(),
+		})
+	case ModeAddTag:
+		return m.help.ShortHelpView([]key.Binding{
+			relabel(m.keys.Enter, "add"),
+			relabel(m.keys.Back, "cancel"),
+			m.typingHelp(),
+		})
+	case ModeEditAliases:
+		return m.help.ShortHelpView([]key.Binding{
+			relabel(m.keys.Enter, "save"),
+			relabel(m.keys.Back, "cancel"),
+			m.typingHelp(),
+		})
+	case ModeExportBook, ModeExportImages:
+		return m.help.ShortHelpView([]key.Binding{
+			relabel(m.keys.Enter, "export"),
+			relabel(m.keys.Back, "cancel"),
+			m.typingHelp(),
+		})
+	case ModeQuickNote:
+		return m.help.ShortHelpView([]key.Binding{
+			relabel(m.keys.Enter, "save"),
+			relabel(m.keys.Back, "cancel"),
+			m.typingHelp(),
+		})
+	case ModeAddImage:
+		return m.help.ShortHelpView([]key.Binding{
+			relabel(m.keys.Enter, "confirmer"),
+			relabel(m.keys.Back, "annuler"),
+			relabel(m.keys.Indent, "compléter, champ suivant"),
+			m.typingHelp(),
+		})
+	case ModeNoteSearch:
+		return m.help.ShortHelpView([]key.Binding{
+			relabel(m.keys.Enter, "next match"),
+			key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "previous match")),
+			relabel(m.keys.Back, "close"),
+		})
+	case ModeFilterByTag:
+		return m.help.ShortHelpView([]key.Binding{
+			m.keys.Up,
+			m.keys.Down,
+			relabel(m.keys.Enter, "filter"),
+			m.keys.RenameTag,
+			relabel(m.keys.Back, "cancel"),
+			m.keys.Help,
+		})
+	case ModeRenameTag:
+		if m.renameNotes != nil {
+			return m.help.ShortHelpView([]key.Binding{
+				key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "apply")),
+				key.NewBinding(key.WithKeys("any"), key.WithHelp("any key", "edit the name")),
+				relabel(m.keys.Back, "cancel"),
+			})
+		}
+		return m.help.ShortHelpView([]key.Binding{
+			relabel(m.keys.Enter, "preview"),
+			relabel(m.keys.Back, "cancel"),
+			m.typingHelp(),
+		})
+	case ModeWorkspaces:
+		return m.help.ShortHelpView([]key.Binding{
+			m.keys.Up,
+			m.keys.Down,
+			relabel(m.keys.Enter, "open"),
+			relabel(m.keys.Back, "cancel"),
+			m.keys.Help,
+		})
+	case ModeHelp:
+		return m.help.ShortHelpView([]key.Binding{
+			m.keys.Up,
+			m.keys.Down,
+			relabel(m.keys.Back, "close"),
+			relabel(m.keys.Help, "close"),
+			relabel(m.keys.Export, "export keys"),
+		})
+	case ModeConflict:
+		return m.help.ShortHelpView([]key.Binding{
+			m.keys.KeepMine,
+			m.keys.TakeTheirs,
+			m.keys.MergeManually,
+			m.keys.Up,
+			m.keys.Down,
+		})
 	default:
 		return ""
 	}
 }
 
-// App launches the TUI application
-func App(storagePath string) error {
-	notesManager, err := notes.NewNotesManager(storagePath)
-	if err != nil {
-		return fmt.Errorf("error initializing notes manager: %w", err)
-	}
+// relabel returns a binding with another description, for modes where its
+// key does something else
+func relabel(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// typingHelp returns the help binding of text fields, where ? is typed and
+// only f1 opens the help screen
+func (m Model) typingHelp() key.Binding {
+	b := m.keys.Help
+	b.SetHelp("f1", b.Help().Desc)
+	return b
+}
 
-	p := tea.NewProgram(NewModel(notesManager), tea.WithAltScreen())
-	_, err = p.Run()
+// App launches the TUI application, on the note of start when it has one.
+// cfg was read from configPath over the settings of the storage folder.
+func App(storagePath, workspace, configPath string, cfg config.Config, start Start) error {
+	// The notes are loaded once the interface is displayed, and the
+	// directories of the store are only created when something is saved
+	model := NewModel(openManager(storagePath, cfg), cfg)
+	model.workspace = workspace
+	model.configPath = configPath
+	model.start = start
+	model.loading = true
+
+	// Signals are handled here rather than by tea so that the model can
+	// still save once the program stops
+	server := &instanceServer{stop: func() {}}
+	model.instance = server
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithoutSignalHandler())
+	server.program = p
+	stopSignals := handleSignals(p)
+	server.serve(storagePath)
+	final, err := p.Run()
+	stopSignals()
+	server.close()
+
+	if final, ok := final.(Model); ok {
+		if final.loadErr != nil {
+			return final.loadErr
+		}
+		if saveErr := final.shutdown(); saveErr != nil && err == nil {
+			err = fmt.Errorf("error saving the note being edited: %w", saveErr)
+		}
+	}
 	return err
 }
@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"datapad/internal/expand"
+	"datapad/internal/export"
+	"datapad/internal/notes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// isMarked reports whether a note is part of the multi-selection
+func (m Model) isMarked(id string) bool {
+	for _, marked := range m.marked {
+		if marked == id {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleMark adds the highlighted note to the multi-selection or removes it,
+// keeping the order in which notes were selected
+func (m *Model) toggleMark() {
+	item, ok := m.noteList.SelectedItem().(NoteItem)
+	if !ok {
+		return
+	}
+
+	if m.isMarked(item.Note.ID) {
+		for i, id := range m.marked {
+			if id == item.Note.ID {
+				m.marked = append(m.marked[:i], m.marked[i+1:]...)
+				break
+			}
+		}
+	} else {
+		m.marked = append(m.marked, item.Note.ID)
+	}
+
+	item.Marked = m.isMarked(item.Note.ID)
+	m.noteList.SetItem(m.noteList.GlobalIndex(), item)
+	m.statusMsg = fmt.Sprintf("%d note(s) selected", len(m.marked))
+}
+
+// markedNotes returns the selected notes in selection order, or the
+// highlighted note when nothing is selected
+func (m Model) markedNotes() []*notes.Note {
+	selection := []*notes.Note{}
+	for _, id := range m.marked {
+		if note, err := m.notesManager.GetNoteByID(id); err == nil {
+			selection = append(selection, note)
+		}
+	}
+	if len(selection) == 0 {
+		if item, ok := m.noteList.SelectedItem().(NoteItem); ok {
+			selection = append(selection, item.Note)
+		}
+	}
+	return selection
+}
+
+// startBookExport asks where to write the book of the selected notes
+func (m *Model) startBookExport() {
+	m.startExport(m.markedNotes(), "notes.html")
+}
+
+// startNoteExport asks where to write the open note, as a PDF by default
+func (m *Model) startNoteExport() {
+	m.startExport([]*notes.Note{m.selectedNote}, notes.Slugify(m.selectedNote.Title)+".pdf")
+}
+
+// startExport asks where to write the given notes, the prompt returning to
+// the current mode
+func (m *Model) startExport(selection []*notes.Note, path string) {
+	if len(selection) == 0 {
+		m.statusMsg = "No notes to export"
+		return
+	}
+	m.exporting = selection
+	m.exportFrom = m.mode
+	m.mode = ModeExportBook
+	m.bookPath.SetValue(path)
+	m.bookPath.CursorEnd()
+	m.bookPath.Focus()
+}
+
+// updateExportBookMode handles the prompt for the book file
+func (m Model) updateExportBookMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = m.exportFrom
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		path := expand.Path(strings.TrimSpace(m.bookPath.Value()))
+		if path == "" {
+			return m, nil
+		}
+		if err := export.ExportBook(m.notesManager, m.exporting, path, export.BookOptions{Extensions: m.config.Markdown.Extensions}); err != nil {
+			m.statusMsg = fmt.Sprintf("Export failed: %s", err)
+			return m, nil
+		}
+
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		m.statusMsg = fmt.Sprintf("%d note(s) exported to %s", len(m.exporting), path)
+		m.exporting = nil
+		m.mode = m.exportFrom
+		if m.mode == ModeList {
+			m.marked = nil
+			m.refreshNoteList()
+		}
+		return m, nil
+	}
+
+	m.bookPath, cmd = m.bookPath.Update(msg)
+	return m, cmd
+}
+
+// viewExportBook displays the prompt for the book file
+func (m Model) viewExportBook() string {
+	prompt := fmt.Sprintf("Export %d note(s) as a book (.html or .pdf):", len(m.exporting))
+	if m.exportFrom == ModeView {
+		prompt = "Export the note (.pdf or .html):"
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		prompt,
+		m.bookPath.View(),
+		m.statusBar(),
+		m.helpView(),
+	)
+}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// calendarCell is the width of a day in the month grid
+const calendarCell = 5
+
+// openCalendar displays the month of the selected day, today at first
+func (m *Model) openCalendar() {
+	if m.calendarDay.IsZero() {
+		now := time.Now()
+		m.calendarDay = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	}
+	m.mode = ModeCalendar
+	m.statusMsg = ""
+}
+
+// calendarNotes returns the notes of the displayed month by day, leaving out
+// archived notes unless they are shown
+func (m Model) calendarNotes() map[int][]*notes.Note {
+	days := m.notesManager.NotesByDay(m.calendarDay)
+	for day, ns := range days {
+		visible := []*notes.Note{}
+		for _, note := range ns {
+			if !note.Archived || m.showArchived {
+				visible = append(visible, note)
+			}
+		}
+		if len(visible) == 0 {
+			delete(days, day)
+		} else {
+			days[day] = visible
+		}
+	}
+	return days
+}
+
+// updateCalendarMode moves the selected day with the arrows and the months
+// with [ and ], Enter lists the notes of the selected day
+func (m Model) updateCalendarMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeList
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		ns := m.calendarNotes()[m.calendarDay.Day()]
+		if len(ns) == 0 {
+			m.statusMsg = "No notes on this day"
+			return m, nil
+		}
+		m.filter.day = m.calendarDay
+		m.refreshNoteList()
+		m.statusMsg = fmt.Sprintf("Notes created on %s", m.calendarDay.Format("02/01/2006"))
+		m.mode = ModeList
+		return m, nil
+
+	case key.Matches(msg, m.keys.PrevDay):
+		m.calendarDay = m.calendarDay.AddDate(0, 0, -1)
+	case key.Matches(msg, m.keys.NextDay):
+		m.calendarDay = m.calendarDay.AddDate(0, 0, 1)
+	case key.Matches(msg, m.keys.Up):
+		m.calendarDay = m.calendarDay.AddDate(0, 0, -7)
+	case key.Matches(msg, m.keys.Down):
+		m.calendarDay = m.calendarDay.AddDate(0, 0, 7)
+	case key.Matches(msg, m.keys.PrevMonth):
+		m.calendarDay = addMonths(m.calendarDay, -1)
+	case key.Matches(msg, m.keys.NextMonth):
+		m.calendarDay = addMonths(m.calendarDay, 1)
+	}
+	m.statusMsg = ""
+	return m, nil
+}
+
+// addMonths moves a day by whole months, staying on the last day of the
+// month when the target month is shorter
+func addMonths(day time.Time, months int) time.Time {
+	first := time.Date(day.Year(), day.Month()+time.Month(months), 1, 0, 0, 0, 0, day.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day.Day(), last)-1)
+}
+
+// viewCalendar displays the month grid, days with notes standing out
+func (m Model) viewCalendar() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFA500"))
+	headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Width(calendarCell).Align(lipgloss.Right)
+	dayStyle := lipgloss.NewStyle().Width(calendarCell).Align(lipgloss.Right)
+	notesStyle := dayStyle.Foreground(lipgloss.Color("#5f5")).Bold(true)
+	selectedStyle := lipgloss.NewStyle().Reverse(true)
+
+	days := m.calendarNotes()
+	first := time.Date(m.calendarDay.Year(), m.calendarDay.Month(), 1, 0, 0, 0, 0, m.calendarDay.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	today := time.Now().In(m.calendarDay.Location())
+
+	var grid strings.Builder
+	for _, name := range []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"} {
+		grid.WriteString(headerStyle.Render(name))
+	}
+	grid.WriteString("\n")
+
+	// Weeks start on Monday
+	offset := (int(first.Weekday()) + 6) % 7
+	grid.WriteString(strings.Repeat(" ", offset*calendarCell))
+	for day := 1; day <= last; day++ {
+		label := fmt.Sprint(day)
+		if first.Year() == today.Year() && first.Month() == today.Month() && day == today.Day() {
+			label = "•" + label
+		}
+		if day == m.calendarDay.Day() {
+			label = selectedStyle.Render(label)
+		}
+		style := dayStyle
+		if len(days[day]) > 0 {
+			style = notesStyle
+		}
+		grid.WriteString(style.Render(label))
+		if (offset+day)%7 == 0 && day < last {
+			grid.WriteString("\n")
+		}
+	}
+
+	count := len(days[m.calendarDay.Day()])
+	summary := fmt.Sprintf("%d note(s) created on %s", count, m.calendarDay.Format("02/01/2006"))
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render(m.calendarDay.Format("January 2006")),
+		"",
+		grid.String(),
+		"",
+		summary,
+		"",
+		m.statusBar(),
+		m.helpView(),
+	)
+}
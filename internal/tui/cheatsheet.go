@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// cheatSheetFile is the file the help screen writes the keys to, in the
+// current directory
+const cheatSheetFile = "datapad-keys.md"
+
+// ToMarkdown returns a cheat sheet of every enabled binding of the key map, in
+// the order of its fields, as a Markdown table of the keys really bound, which
+// stay right when bindings are changed, and of what they do
+func (k KeyMap) ToMarkdown() string {
+	var b strings.Builder
+	b.WriteString("# Datapad keys\n\n")
+	b.WriteString("| Keys | Action |\n")
+	b.WriteString("| --- | --- |\n")
+
+	v := reflect.ValueOf(k)
+	for i := 0; i < v.NumField(); i++ {
+		binding, ok := v.Field(i).Interface().(key.Binding)
+		if !ok || !binding.Enabled() {
+			continue
+		}
+		keys := []string{}
+		for _, name := range binding.Keys() {
+			keys = append(keys, "`"+strings.ReplaceAll(name, "|", `\|`)+"`")
+		}
+		action := binding.Help().Desc
+		if action == "" {
+			action = v.Type().Field(i).Name
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", strings.Join(keys, ", "), strings.ReplaceAll(action, "|", `\|`))
+	}
+	return b.String()
+}
+
+// exportCheatSheet writes the keys of the interface to cheatSheetFile
+func (m *Model) exportCheatSheet() {
+	path, err := filepath.Abs(cheatSheetFile)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Error exporting the keys: %s", err)
+		return
+	}
+	if err := os.WriteFile(path, []byte(m.keys.ToMarkdown()), 0644); err != nil {
+		m.statusMsg = fmt.Sprintf("Error exporting the keys: %s", err)
+		return
+	}
+	m.statusMsg = fmt.Sprintf("Keys written to %s", path)
+}
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestKeyMapToMarkdown(t *testing.T) {
+	keys := DefaultKeyMap()
+	keys.Save.SetKeys("ctrl+w", "f2")
+	keys.Delete.SetEnabled(false)
+	keys.Pin.SetHelp("p", "")
+	keys.Quit.SetHelp("q", "quit | leave")
+
+	sheet := keys.ToMarkdown()
+	if !strings.HasPrefix(sheet, "# Datapad keys\n\n| Keys | Action |\n| --- | --- |\n") {
+		t.Errorf("cheat sheet doesn't start with its table:\n%s", sheet)
+	}
+	for _, want := range []string{
+		// The keys bound rather than those of the help
+		"| `ctrl+w`, `f2` | save |\n",
+		// The field name when there is no description
+		"| `p` | Pin |\n",
+		// Pipes escaped in keys and descriptions
+		"| `\\|` | detail pane |\n",
+		"| `ctrl+c`, `q` | quit \\| leave |\n",
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("%q missing from the cheat sheet:\n%s", want, sheet)
+		}
+	}
+	if strings.Contains(sheet, "ctrl+s") || strings.Contains(sheet, "| delete |") {
+		t.Errorf("cheat sheet lists replaced or disabled bindings:\n%s", sheet)
+	}
+}
+
+func TestExportCheatSheet(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	m := newTestModel(t, config.Default(), "a")
+	m.keys.Edit.SetKeys("E")
+	m.openHelp()
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+
+	data, err := os.ReadFile(filepath.Join(dir, cheatSheetFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "| `E` | edit |") {
+		t.Errorf("cheat sheet doesn't reflect the key map:\n%s", data)
+	}
+	if m.mode != ModeHelp || !strings.HasPrefix(m.statusMsg, "Keys written to ") {
+		t.Errorf("mode %v with status %q after exporting the keys", m.mode, m.statusMsg)
+	}
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"os"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// Clipboard gives access to a clipboard so that it can be replaced in tests
+// or on systems without a clipboard utility
+type Clipboard interface {
+	ReadAll() (string, error)
+	WriteAll(text string) error
+}
+
+// systemClipboard uses the operating system clipboard
+type systemClipboard struct{}
+
+// ReadAll returns the content of the system clipboard
+func (systemClipboard) ReadAll() (string, error) {
+	return clipboard.ReadAll()
+}
+
+// WriteAll copies text to the system clipboard, falling back to an OSC 52
+// escape sequence so that copying also works over SSH
+func (systemClipboard) WriteAll(text string) error {
+	if err := clipboard.WriteAll(text); err == nil {
+		return nil
+	}
+	_, err := osc52.New(text).WriteTo(os.Stderr)
+	return err
+}
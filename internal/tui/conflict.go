@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"datapad/internal/diff"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// errNoteChanged pauses the saves of a note changed outside datapad until the
+// conflict is resolved
+var errNoteChanged = errors.New("the note was changed outside datapad, resolve the conflict to save it")
+
+// Labels of the two versions in the diff and the conflict markers
+const (
+	conflictMine   = "mine"
+	conflictTheirs = "on disk"
+)
+
+// detectConflict reports whether the note being edited was changed on disk
+// since it was loaded or last saved, in a way the editor doesn't already
+// hold, and opens the conflict screen if so
+func (m *Model) detectConflict() bool {
+	stored, err := m.notesManager.StoredNote(m.selectedNote.ID)
+	// A file that can't be read is reported by the save itself
+	if err != nil || stored == nil || stored.UpdatedAt.Equal(m.selectedNote.UpdatedAt) {
+		return false
+	}
+	if stored.Title == m.titleInput.Value() && m.notesManager.SavedContent(stored.Content) == m.notesManager.SavedContent(m.editorContent()) {
+		return false
+	}
+
+	m.conflict = stored
+	m.saveErr = errNoteChanged
+	m.mode = ModeConflict
+	m.conflictView.GotoTop()
+	m.statusMsg = "The note was changed outside datapad while you were editing it"
+	return true
+}
+
+// resolveConflict leaves the conflict screen for mode once the conflicting
+// version is dealt with
+func (m *Model) resolveConflict(mode Mode) {
+	m.conflict = nil
+	m.saveErr = nil
+	m.mode = mode
+}
+
+// updateConflictMode handles the choice between the two versions of a note
+func (m Model) updateConflictMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.KeepMine):
+		// The version on disk is overwritten knowingly
+		m.selectedNote.UpdatedAt = m.conflict.UpdatedAt
+		m.resolveConflict(ModeEdit)
+		if m.persistEdit() {
+			m.statusMsg = "Your version was saved over the one on disk"
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.TakeTheirs):
+		m.notesManager.RevertNote(m.selectedNote, m.conflict)
+		m.resolveConflict(ModeView)
+		m.refreshNoteList()
+		m.statusMsg = "Kept the version on disk, your changes were dropped"
+		return m, nil
+	case key.Matches(msg, m.keys.MergeManually):
+		merged := diff.Merge(diff.Texts(m.editorContent(), m.conflict.Content), conflictMine, conflictTheirs)
+		m.selectedNote.UpdatedAt = m.conflict.UpdatedAt
+		m.resolveConflict(ModeEdit)
+		m.setEditorContent(strings.Join(merged, "\n"))
+		m.statusMsg = "Both versions are in the editor between conflict markers, save once merged"
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.conflictView, cmd = m.conflictView.Update(msg)
+	return m, cmd
+}
+
+// conflictBody renders the differences from the editor to the version on
+// disk, the title included when it differs
+func (m Model) conflictBody() string {
+	if m.conflict == nil {
+		return ""
+	}
+	mineStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5f5f"))
+	theirsStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#5f5"))
+	sameStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+
+	lines := []string{}
+	if title := m.titleInput.Value(); title != m.conflict.Title {
+		lines = append(lines,
+			"Title:",
+			mineStyle.Render("- "+sanitizeTerminal(title, false)),
+			theirsStyle.Render("+ "+sanitizeTerminal(m.conflict.Title, false)),
+			"",
+		)
+	}
+	for _, edit := range diff.Texts(m.editorContent(), m.conflict.Content) {
+		line := sanitizeTerminal(edit.Line, false)
+		switch edit.Op {
+		case diff.Delete:
+			lines = append(lines, mineStyle.Render("- "+line))
+		case diff.Insert:
+			lines = append(lines, theirsStyle.Render("+ "+line))
+		default:
+			lines = append(lines, sameStyle.Render("  "+line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// viewConflict displays the differences between the two versions of the note
+func (m Model) viewConflict() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ff7700"))
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render(fmt.Sprintf("Conflict: - %s, + %s", conflictMine, conflictTheirs)),
+		m.conflictView.View(),
+		"",
+		m.statusBar(),
+		m.helpView(),
+	)
+}
@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"datapad/internal/notes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// fallbackDescriptionWidth is the width of a note description outside the list
+const fallbackDescriptionWidth = 50
+
+// noteDelegate renders the notes of the list with descriptions as wide as
+// the list and lines lines high. Other items, such as tags, are rendered by
+// the default delegate.
+type noteDelegate struct {
+	list.DefaultDelegate
+	lines  int
+	images bool // Descriptions count the images of the notes
+	ages   bool // Titles are tinted by how recently the notes were updated
+}
+
+// newNoteDelegate creates the delegate of the list for the descriptions set
+// up in the view settings
+func newNoteDelegate(view config.ViewConfig) noteDelegate {
+	lines := max(view.DescriptionLines, 1)
+	d := list.NewDefaultDelegate()
+	d.SetHeight(lines + 1)
+	return noteDelegate{DefaultDelegate: d, lines: lines, images: view.ImageCount, ages: view.AgeColors}
+}
+
+// describedNote is a note whose description was fitted to the list
+type describedNote struct {
+	NoteItem
+	description string
+}
+
+// Description returns the fitted description
+func (d describedNote) Description() string {
+	return d.description
+}
+
+// Render prints an item, the description of notes filling the width of the list
+func (d noteDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if note, ok := item.(NoteItem); ok {
+		width := m.Width() - d.Styles.NormalDesc.GetHorizontalFrameSize()
+		item = describedNote{note, note.describe(width, d.lines, d.images)}
+		if d.ages {
+			d.Styles.NormalTitle = d.Styles.NormalTitle.Foreground(ageColor(noteAge(note.Note.UpdatedAt, time.Now())))
+		}
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+// describe returns at most lines lines of width columns describing the note:
+// the search snippet or the beginning of its text, followed by the number of
+// its images when images is set and by its tags
+func (n NoteItem) describe(width, lines int, images bool) string {
+	if width < 1 {
+		width = fallbackDescriptionWidth
+	}
+	tags := ""
+	if len(n.Note.Tags) > 0 {
+		tags = "[" + strings.Join(n.Note.Tags, ", ") + "]"
+	}
+	count := ""
+	if images {
+		count = imageIndicator(len(n.Note.Images))
+	}
+	// The count and the tags end the description, the text making room for them
+	suffix := strings.TrimSpace(count + " " + tags)
+	styledSuffix := strings.TrimSpace(imageCountStyle.Render(count) + " " + tagStyle.Render(tags))
+
+	text := previewText(sanitizeTerminal(n.Note.Content, false))
+	if n.Snippet != "" {
+		text = strings.Join(strings.Fields(sanitizeTerminal(n.Snippet, false)), " ")
+	}
+	if notes.IsBlank(text) {
+		// The placeholder already tells the number of images
+		return strings.TrimSpace(emptyNoteStyle.Render(emptyNoteLabel(n.Note)) + " " + tagStyle.Render(tags))
+	}
+
+	wrapped := strings.Split(ansi.Wrap(text, width, ""), "\n")
+	if len(wrapped) > lines {
+		// The last line shown runs into the next one so that it's cut with an ellipsis
+		wrapped[lines-1] += " " + wrapped[lines]
+		wrapped = wrapped[:lines]
+	}
+	// The text leaves room for the suffix, keeping half the line at least
+	last := len(wrapped) - 1
+	room := width
+	if suffix != "" {
+		room = max(width-ansi.StringWidth(suffix)-1, width/2)
+	}
+	wrapped[last] = ansi.Truncate(strings.TrimSpace(wrapped[last]), room, "…")
+	if suffix != "" {
+		wrapped[last] += " " + styledSuffix
+	}
+	return strings.Join(wrapped, "\n")
+}
+
+// imageIndicator returns the marker of a note holding count images, empty
+// when it has none
+func imageIndicator(count int) string {
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("📎%d", count)
+}
+
+// tagStyle colors the tags of the notes in the list
+var tagStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#5f5"))
+
+// imageCountStyle colors the number of images of the notes in the list
+var imageCountStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+
+// previewText returns the text of a note from its first paragraph that isn't
+// a heading, headings being skipped, on a single line. A note made of headings
+// only shows them.
+func previewText(content string) string {
+	text := []string{}
+	headings := []string{}
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if isHeading(trimmed) {
+			headings = append(headings, strings.TrimSpace(strings.TrimLeft(trimmed, "#")))
+			continue
+		}
+		if trimmed != "" {
+			text = append(text, trimmed)
+		}
+	}
+	if len(text) == 0 {
+		text = headings
+	}
+	return strings.Join(strings.Fields(strings.Join(text, " ")), " ")
+}
+
+// isHeading reports whether a line is a Markdown ATX heading, as opposed to a
+// #tag
+func isHeading(line string) bool {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	return level >= 1 && level <= 6 && (level == len(line) || line[level] == ' ' || line[level] == '\t')
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestImageIndicator(t *testing.T) {
+	tests := []struct {
+		count int
+		want  string
+	}{
+		{0, ""},
+		{1, "📎1"},
+		{12, "📎12"},
+	}
+	for _, tt := range tests {
+		if got := imageIndicator(tt.count); got != tt.want {
+			t.Errorf("imageIndicator(%d) = %q, want %q", tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeImageCount(t *testing.T) {
+	note := notes.NewNote("Trip")
+	note.Content = "Photos of the beach"
+	note.Tags = []string{"travel"}
+	note.Images = []notes.Image{{Path: "a.png"}, {Path: "b.png"}}
+	item := NoteItem{Note: note}
+
+	if got := ansi.Strip(item.describe(40, 1, true)); got != "Photos of the beach 📎2 [travel]" {
+		t.Errorf("description %q, want the count before the tags", got)
+	}
+	if got := ansi.Strip(item.describe(40, 1, false)); strings.Contains(got, "📎") {
+		t.Errorf("description %q counts the images while disabled", got)
+	}
+
+	// The text is cut to keep the count in the line
+	if got := ansi.Strip(item.describe(30, 1, true)); !strings.HasSuffix(got, "… 📎2 [travel]") || ansi.StringWidth(got) != 30 {
+		t.Errorf("narrow description %q", got)
+	}
+
+	note.Images = nil
+	if got := ansi.Strip(item.describe(40, 1, true)); got != "Photos of the beach [travel]" {
+		t.Errorf("description %q of a note without images", got)
+	}
+}
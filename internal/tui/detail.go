@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// minDetailWidth is the terminal width below which the detail pane is not
+// displayed, the list keeping the whole width
+const minDetailWidth = 80
+
+// detailPane reports whether the note under the cursor is displayed next to
+// the list
+func (m Model) detailPane() bool {
+	return m.state.DetailPane && m.mode == ModeList && m.width >= minDetailWidth
+}
+
+// listPanes returns the width of the list and of the detail pane
+func (m Model) listPanes() (listWidth, detailWidth int) {
+	listWidth = m.width * 2 / 5
+	// The separator takes one column
+	detailWidth = max(m.width-listWidth-1, 0)
+	return listWidth, detailWidth
+}
+
+// toggleDetailPane shows or hides the detail pane and remembers the choice
+func (m *Model) toggleDetailPane() {
+	m.state.DetailPane = !m.state.DetailPane
+
+	m.statusMsg = "Detail pane hidden"
+	if m.state.DetailPane {
+		m.statusMsg = "Detail pane shown"
+		if m.width < minDetailWidth {
+			m.statusMsg = fmt.Sprintf("Detail pane shown from %d columns", minDetailWidth)
+		}
+	}
+	if err := m.state.save(m.notesManager.StoragePath); err != nil {
+		m.statusMsg += fmt.Sprintf(" (not saved: %s)", err)
+	}
+}
+
+// highlightedNote returns the note under the cursor of the list, nil when
+// the list is empty
+func (m Model) highlightedNote() *notes.Note {
+	item, ok := m.noteList.SelectedItem().(NoteItem)
+	if !ok {
+		return nil
+	}
+	return item.Note
+}
+
+// updateDetail renders the note under the cursor in the detail pane, back at
+// its top when the cursor moved to another note
+func (m *Model) updateDetail() {
+	_, width := m.listPanes()
+	m.detail.Width = width
+	m.detail.Height = max(m.height-statusBarHeight-helpHeight, 1)
+
+	note := m.highlightedNote()
+	id := ""
+	if key := m.bodyKey(note, width); key != m.detailKey {
+		m.detailKey = key
+		if note == nil {
+			m.detail.SetContent(lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("No note selected"))
+		} else {
+			m.detail.SetContent(m.renderNote(note, width))
+		}
+	}
+	if note != nil {
+		id = note.ID
+	}
+	if id != m.detailID {
+		m.detailID = id
+		m.detail.GotoTop()
+	}
+}
+
+// viewList displays the list of notes, next to the detail pane when shown
+func (m Model) viewList() string {
+	content := m.noteList.View()
+	if m.detailPane() {
+		separator := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#555555")).
+			Render(strings.TrimSuffix(strings.Repeat("│\n", m.detail.Height), "\n"))
+		listWidth, _ := m.listPanes()
+		content = lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			lipgloss.NewStyle().Width(listWidth).Render(content),
+			separator,
+			m.detail.View(),
+		)
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		content,
+		m.statusBar(),
+		m.helpView(),
+	)
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDetailPane(t *testing.T) {
+	m := newTestModel(t, config.Default(), "", "")
+	// The first note of the list is long enough to scroll
+	long, short := m.highlightedNote(), m.notesManager.Notes[0]
+	if short == long {
+		short = m.notesManager.Notes[1]
+	}
+	long.Content = "Long body\n\n" + strings.Repeat("line\n\n", 100)
+	short.Content = "Short body"
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'|'}})
+	m = model.(Model)
+	if !m.detailPane() || m.statusMsg != "Detail pane shown" {
+		t.Fatalf("| shows the pane: %v, status %q", m.detailPane(), m.statusMsg)
+	}
+	if !loadState(m.notesManager.StoragePath).DetailPane {
+		t.Error("shown pane not kept in the interface state")
+	}
+
+	// The pane follows the cursor of the list, back at the top for
+	// another note
+	steps := []struct {
+		key        tea.KeyType
+		want, gone string
+	}{
+		{tea.KeyCtrlD, "line", "Long body"},
+		{tea.KeyDown, "Short body", "line"},
+		{tea.KeyUp, "Long body", "Short body"},
+	}
+	for _, step := range steps {
+		model, _ = model.Update(tea.KeyMsg{Type: step.key})
+		m = model.(Model)
+		if pane := ansi.Strip(m.detail.View()); !strings.Contains(pane, step.want) || strings.Contains(pane, step.gone) {
+			t.Errorf("pane after %v doesn't show %q alone:\n%s", step.key, step.want, pane)
+		}
+	}
+	if m.highlightedNote() != long || m.detail.YOffset != 0 {
+		t.Errorf("pane at offset %d after coming back to the note, want the top", m.detail.YOffset)
+	}
+
+	// A narrow terminal gives the whole width to the list
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 60, Height: 30})
+	m = model.(Model)
+	if view := ansi.Strip(m.View()); m.detailPane() || strings.Contains(view, "Created on") {
+		t.Errorf("pane shown below the minimum width:\n%s", view)
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'|'}})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'|'}})
+	m = model.(Model)
+	if m.statusMsg != "Detail pane shown from 80 columns" {
+		t.Errorf("status %q when shown on a narrow terminal", m.statusMsg)
+	}
+
+	// Hidden again, the choice is kept
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'|'}})
+	m = model.(Model)
+	if m.state.DetailPane || loadState(m.notesManager.StoragePath).DetailPane {
+		t.Error("hidden pane still in the interface state")
+	}
+}
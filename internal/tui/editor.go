@@ -0,0 +1,346 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"datapad/internal/notes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// titleCheckDelay is how long the title must stay unchanged before looking
+// for notes with the same title, so that large stores aren't scanned per key
+const titleCheckDelay = 300 * time.Millisecond
+
+// quotedTitleWidth is the number of columns of the titles quoted by the
+// duplicate title warning
+const quotedTitleWidth = 40
+
+// titleCheckMsg triggers the duplicate title check once typing pauses
+type titleCheckMsg struct {
+	seq int
+}
+
+// autosaveMsg triggers an autosave once the editor has been idle long enough
+type autosaveMsg struct {
+	seq int
+}
+
+// flushMsg writes the saves held back by the manager once their delay is over
+type flushMsg struct{}
+
+// indentation returns the whitespace inserted by the Tab key when the cursor
+// is at the given column, padding up to the next tab stop
+func indentation(column, tabWidth int) string {
+	if tabWidth <= 0 {
+		tabWidth = 4
+	}
+	return strings.Repeat(" ", tabWidth-column%tabWidth)
+}
+
+// editedLines are the lines of a note the editor may show differently from
+// how they are stored, those holding tabs or indented with spaces. They are
+// listed in order by their form in the editor.
+type editedLines map[string][]string
+
+// expandTabs replaces tab characters with spaces up to the next tab stop.
+// The textarea cannot hold tabs, so content is expanded before editing. It
+// also returns the lines that restoreLines gives back as they were.
+func expandTabs(content string, tabWidth int) (string, editedLines) {
+	stored := strings.Split(content, "\n")
+	lines := make([]string, len(stored))
+	originals := editedLines{}
+	for i, line := range stored {
+		lines[i] = expandLine(line, tabWidth)
+		if strings.Contains(line, "\t") || strings.HasPrefix(line, " ") {
+			originals[lines[i]] = nil
+		}
+	}
+	// Lines without tabs are listed too when they look like one that has some
+	for i, line := range lines {
+		if list, ok := originals[line]; ok {
+			originals[line] = append(list, stored[i])
+		}
+	}
+	return strings.Join(lines, "\n"), originals
+}
+
+// expandLine replaces the tabs of a line with spaces up to the next tab stop
+func expandLine(line string, tabWidth int) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var b strings.Builder
+	column := 0
+	for _, r := range line {
+		if r == '\t' {
+			pad := indentation(column, tabWidth)
+			b.WriteString(pad)
+			column += len(pad)
+			continue
+		}
+		b.WriteRune(r)
+		column++
+	}
+	return b.String()
+}
+
+// restoreLines gives the lines of content left as expandTabs returned them
+// back their stored form, tabs and spaces included, the nth occurrence of a
+// line taking the nth original. Lines typed or changed in the editor have
+// their leading spaces turned into tabs when hardTabs is set, any remainder
+// that doesn't fill a whole tab stop staying spaces.
+func restoreLines(content string, originals editedLines, hardTabs bool, tabWidth int) string {
+	if tabWidth <= 0 {
+		tabWidth = 4
+	}
+
+	lines := strings.Split(content, "\n")
+	used := map[string]int{}
+	for i, line := range lines {
+		if stored, ok := originals[line]; ok {
+			lines[i] = stored[min(used[line], len(stored)-1)]
+			used[line]++
+			continue
+		}
+		trimmed := strings.TrimLeft(line, " ")
+		spaces := len(line) - len(trimmed)
+		if !hardTabs || spaces < tabWidth {
+			continue
+		}
+		lines[i] = strings.Repeat("\t", spaces/tabWidth) + strings.Repeat(" ", spaces%tabWidth) + trimmed
+	}
+	return strings.Join(lines, "\n")
+}
+
+// insertIndent inserts indentation at the cursor of the content editor
+func (m *Model) insertIndent() {
+	info := m.textArea.LineInfo()
+	column := info.StartColumn + info.ColumnOffset
+	m.textArea.InsertString(indentation(column, m.config.Editor.TabWidth))
+}
+
+// editorContent returns the content of the editor as it should be stored.
+// The lines of the note left untouched keep their tabs and spaces.
+func (m Model) editorContent() string {
+	return restoreLines(m.textArea.Value(), m.editorStored, !m.config.Editor.SoftTabs, m.config.Editor.TabWidth)
+}
+
+// setEditorContent loads note content into the editor. A note already over
+// the maximum size is loaded whole, the limit then being its current size.
+func (m *Model) setEditorContent(content string) {
+	content, m.editorStored = expandTabs(content, m.config.Editor.TabWidth)
+	m.textArea.CharLimit = m.config.Editor.MaxChars
+	// The editor counts line breaks as characters too
+	if length := utf8.RuneCountInString(content); m.textArea.CharLimit > 0 && length > m.textArea.CharLimit {
+		m.textArea.CharLimit = length
+	}
+	m.textArea.SetValue(content)
+	m.pasteUndo = nil
+	m.pastedImage = nil
+}
+
+// contentSizeWarning returns the warning about content of length characters
+// being large for the editor limits, empty when it isn't
+func contentSizeWarning(length int, editor config.EditorConfig) string {
+	switch {
+	case editor.MaxChars > 0 && length >= editor.MaxChars:
+		return fmt.Sprintf("Note at the maximum size of %d characters", editor.MaxChars)
+	case editor.WarnChars > 0 && length > editor.WarnChars:
+		return fmt.Sprintf("Large note (%d characters), editing may slow down", length)
+	}
+	return ""
+}
+
+// switchEditorFocus moves the focus between the title and the content
+func (m *Model) switchEditorFocus() {
+	if m.titleInput.Focused() {
+		m.titleInput.Blur()
+		m.textArea.Focus()
+	} else {
+		m.textArea.Blur()
+		m.titleInput.Focus()
+	}
+}
+
+// autosaveEnabled reports whether the note being edited is saved automatically.
+// New notes always require a first explicit save.
+func (m Model) autosaveEnabled() bool {
+	if m.mode != ModeEdit {
+		return false
+	}
+	_, timed := m.config.Editor.AutosaveDelay()
+	return timed || m.config.Editor.AutosaveOnChange() || m.config.Editor.AutosaveOnBlur()
+}
+
+// editorDirty reports whether the editor holds changes not yet saved. Both
+// sides are compared as they would be saved, so that a note stored before
+// its whitespace was tidied isn't dirty as soon as it is opened.
+func (m Model) editorDirty() bool {
+	if m.selectedNote == nil || m.mode != ModeEdit {
+		return false
+	}
+	saved := m.notesManager.SavedContent
+	return m.titleInput.Value() != m.selectedNote.Title ||
+		saved(m.editorContent()) != saved(m.selectedNote.Content)
+}
+
+// persistEdit copies the editor into the selected note and saves it.
+// It reports whether the save succeeded. When the note was changed outside
+// datapad meanwhile, nothing is saved and the conflict screen opens.
+func (m *Model) persistEdit() bool {
+	if m.detectConflict() {
+		return false
+	}
+	m.selectedNote.Title = m.titleInput.Value()
+	m.selectedNote.Content = m.editorContent()
+	if err := m.notesManager.UpdateNote(m.selectedNote); err != nil {
+		m.saveErr = err
+		m.statusMsg = fmt.Sprintf("Error saving note: %s (autosave paused)", storeError(err))
+		return false
+	}
+	m.saveErr = nil
+	return true
+}
+
+// autosave saves the note being edited if autosave is enabled and there are
+// pending changes. It is paused while a save error is outstanding.
+func (m *Model) autosave() {
+	if !m.autosaveEnabled() || m.saveErr != nil || !m.editorDirty() {
+		return
+	}
+	if m.persistEdit() {
+		m.lastAutosave = time.Now()
+	}
+}
+
+// scheduleFlush writes the saves the manager holds back once the configured
+// delay is over, the saves made meanwhile being written along
+func (m *Model) scheduleFlush() tea.Cmd {
+	if m.flushPending || !m.notesManager.Unwritten() {
+		return nil
+	}
+	m.flushPending = true
+	return tea.Tick(m.notesManager.SaveDelay, func(time.Time) tea.Msg {
+		return flushMsg{}
+	})
+}
+
+// flush writes the saves held back by the manager
+func (m *Model) flush() {
+	m.flushPending = false
+	if err := m.notesManager.Flush(); err != nil {
+		m.statusMsg = fmt.Sprintf("Error saving notes: %s", storeError(err))
+	}
+}
+
+// changeSaveDelay is the pause in typing after which the on-change autosave
+// saves the note, so that a burst of keys makes one save
+const changeSaveDelay = 500 * time.Millisecond
+
+// scheduleAutosave debounces the timed and on-change autosaves: each change
+// restarts the delay
+func (m *Model) scheduleAutosave() tea.Cmd {
+	delay, ok := m.config.Editor.AutosaveDelay()
+	if m.config.Editor.AutosaveOnChange() {
+		delay, ok = changeSaveDelay, true
+	}
+	if !ok || m.mode != ModeEdit || m.saveErr != nil || !m.editorDirty() {
+		return nil
+	}
+	m.autosaveSeq++
+	seq := m.autosaveSeq
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return autosaveMsg{seq: seq}
+	})
+}
+
+// autosaveIndicator returns the discreet autosave state shown in the editor
+func (m Model) autosaveIndicator() string {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	switch {
+	case !m.autosaveEnabled():
+		return ""
+	case m.saveErr != nil:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7700")).Render("· autosave paused")
+	case m.editorDirty():
+		return style.Render("· unsaved")
+	case !m.lastAutosave.IsZero():
+		return style.Render("· saved " + m.lastAutosave.Format("15:04:05"))
+	default:
+		return ""
+	}
+}
+
+// saveModeBadge renders how the note being edited is saved for the status
+// bar, nothing outside the editor
+func (m Model) saveModeBadge() string {
+	if m.mode != ModeEdit && m.mode != ModeNew {
+		return ""
+	}
+	label := m.keys.Save.Help().Key + " saves"
+	if m.autosaveEnabled() {
+		delay, timed := m.config.Editor.AutosaveDelay()
+		switch {
+		case m.config.Editor.AutosaveOnChange():
+			label = "autosave on change"
+		case timed:
+			label = "autosave after " + delay.String()
+		default:
+			label = "autosave on blur"
+		}
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#3C6E71")).
+		Padding(0, 1).
+		Render(label)
+}
+
+// scheduleTitleCheck debounces the duplicate title check
+func (m *Model) scheduleTitleCheck() tea.Cmd {
+	m.titleCheckSeq++
+	seq := m.titleCheckSeq
+	return tea.Tick(titleCheckDelay, func(time.Time) tea.Msg {
+		return titleCheckMsg{seq: seq}
+	})
+}
+
+// checkTitle looks for other notes with the same or a similar title. The
+// warning is informational, saving is still allowed.
+func (m *Model) checkTitle() {
+	excludeID := ""
+	if m.mode == ModeEdit && m.selectedNote != nil {
+		excludeID = m.selectedNote.ID
+	}
+
+	matches := m.notesManager.SimilarTitles(m.titleInput.Value(), excludeID)
+	switch {
+	case len(matches) == 0:
+		m.titleWarning = ""
+	case matches[0].Exact:
+		m.titleWarning = fmt.Sprintf("⚠ A note titled %q already exists", notes.ShortTitle(matches[0].Note.Title, quotedTitleWidth))
+	default:
+		m.titleWarning = fmt.Sprintf("⚠ Similar to the existing note %q", notes.ShortTitle(matches[0].Note.Title, quotedTitleWidth))
+	}
+}
+
+// titleInfoView displays the slug of the title and the duplicate warning
+func (m Model) titleInfoView() string {
+	slugStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7700"))
+
+	info := slugStyle.Render("→ " + notes.Slugify(m.titleInput.Value()))
+	if m.titleWarning != "" {
+		info += "  " + warningStyle.Render(m.titleWarning)
+	}
+	if m.width > 0 {
+		info = ansi.Truncate(info, m.width, "…")
+	}
+	return info
+}
@@ -0,0 +1,375 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"datapad/internal/notes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// newTestModel returns a model over a store holding a note of each content,
+// stored as is, with the list filled
+func newTestModel(t *testing.T, cfg config.Config, contents ...string) Model {
+	t.Helper()
+	manager := openManager(t.TempDir(), cfg)
+	ns := make([]*notes.Note, len(contents))
+	for i, content := range contents {
+		ns[i] = notes.NewNote("Note")
+		ns[i].Content = content
+	}
+	if _, err := manager.AddNotes(ns); err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(manager, cfg)
+	m.refreshNoteList()
+	return m
+}
+
+// editNote opens the note in the editor with the content focused
+func editNote(m *Model, note *notes.Note) {
+	m.selectedNote = note
+	m.startEdit()
+	m.switchEditorFocus()
+}
+
+// typeText sends text to the model one key at a time
+func typeText(m tea.Model, text string) tea.Model {
+	for _, r := range text {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m
+}
+
+func TestEditorDirty(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		trim    bool
+	}{
+		{"plain", "abc", false},
+		{"untidy note with tidying", "abc  \n\n\n", true},
+		{"no final newline with tidying", "abc", true},
+		{"untidy note without tidying", "abc  \n\n\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Editor.TrimSpace = tt.trim
+			m := newTestModel(t, cfg, tt.content)
+			editNote(&m, m.notesManager.Notes[0])
+			if m.editorDirty() {
+				t.Fatalf("note %q dirty as soon as it is opened", tt.content)
+			}
+
+			m = typeText(m, "x").(Model)
+			if !m.editorDirty() {
+				t.Fatal("editor not dirty after typing")
+			}
+			m.textArea.SetValue(tt.content)
+			if m.editorDirty() {
+				t.Error("editor dirty after restoring the content")
+			}
+		})
+	}
+}
+
+func TestEditorDirtyTitle(t *testing.T) {
+	m := newTestModel(t, config.Default(), "abc")
+	editNote(&m, m.notesManager.Notes[0])
+	m.titleInput.SetValue("Other")
+	if !m.editorDirty() {
+		t.Error("editor not dirty after renaming the note")
+	}
+}
+
+func TestIndentation(t *testing.T) {
+	tests := []struct {
+		column, width int
+		want          string
+	}{
+		{0, 4, "    "},
+		{1, 4, "   "},
+		{3, 4, " "},
+		{4, 4, "    "},
+		{0, 2, "  "},
+		{5, 2, " "},
+		{0, 8, "        "},
+		{2, 0, "  "}, // 0 falls back to 4
+	}
+	for _, tt := range tests {
+		if got := indentation(tt.column, tt.width); got != tt.want {
+			t.Errorf("indentation(%d, %d) = %q, want %q", tt.column, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestTabInsertsSpaces(t *testing.T) {
+	for _, width := range []int{2, 4, 8} {
+		for _, soft := range []bool{true, false} {
+			cfg := config.Default()
+			cfg.Editor.TabWidth = width
+			cfg.Editor.SoftTabs = soft
+			m := newTestModel(t, cfg, "")
+			editNote(&m, m.notesManager.Notes[0])
+
+			var model tea.Model = typeText(m, "a")
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+			m = typeText(model, "b").(Model)
+			if got, want := m.textArea.Value(), "a"+strings.Repeat(" ", width-1)+"b"; got != want {
+				t.Errorf("width %d soft %v: editor holds %q, want %q", width, soft, got, want)
+			}
+
+			m.textArea.SetValue("")
+			model, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+			m = typeText(model, "c").(Model)
+			want := strings.Repeat(" ", width) + "c"
+			if !soft {
+				want = "\tc"
+			}
+			if got := m.editorContent(); got != want {
+				t.Errorf("width %d soft %v: indented line stored as %q, want %q", width, soft, got, want)
+			}
+		}
+	}
+}
+
+func TestSwitchFocus(t *testing.T) {
+	for _, mode := range []Mode{ModeEdit, ModeNew} {
+		cfg := config.Default()
+		cfg.Editor.Autosave = config.AutosaveOnBlur
+		m := newTestModel(t, cfg, "abc")
+		note := m.notesManager.Notes[0]
+		if mode == ModeEdit {
+			m.selectedNote = note
+			m.startEdit()
+		} else {
+			m.startNew("")
+		}
+		if !m.titleInput.Focused() {
+			t.Fatalf("mode %v: editor opened on the content", mode)
+		}
+
+		shiftTab := tea.KeyMsg{Type: tea.KeyShiftTab}
+		m, _ = press(m, shiftTab)
+		if m.titleInput.Focused() || !m.textArea.Focused() {
+			t.Errorf("mode %v: shift+tab left the focus on the title", mode)
+		}
+		m = typeText(m, "x").(Model)
+		m, _ = press(m, shiftTab)
+		if !m.titleInput.Focused() || m.textArea.Focused() {
+			t.Errorf("mode %v: shift+tab left the focus on the content", mode)
+		}
+		m = typeText(m, "y").(Model)
+		if !strings.HasSuffix(m.titleInput.Value(), "y") || !strings.Contains(m.editorContent(), "x") {
+			t.Errorf("mode %v: keys typed in the wrong field, title %q and content %q", mode, m.titleInput.Value(), m.editorContent())
+		}
+
+		// Leaving the content is a blur that autosaves the notes being edited
+		if saved := strings.Contains(note.Content, "x"); saved != (mode == ModeEdit) {
+			t.Errorf("mode %v: note saved on blur: %v", mode, saved)
+		}
+	}
+}
+
+func TestEditorTabRoundTrip(t *testing.T) {
+	contents := []string{
+		"a\tb\n",
+		"- a\n    - nested\n",
+		"- a\n\t- nested\n",
+		"\tx\n    x\n\tx\n",
+		"code:\n\tif x {\n\t\treturn\t// done\n\t}\n",
+		"  two spaces\n   three\n",
+		"a   b\na\tb\n",
+	}
+	for _, content := range contents {
+		for _, soft := range []bool{true, false} {
+			cfg := config.Default()
+			cfg.Editor.SoftTabs = soft
+			m := newTestModel(t, cfg, content)
+			editNote(&m, m.notesManager.Notes[0])
+			if strings.Contains(m.textArea.Value(), "\t") {
+				t.Errorf("editor holds a tab: %q", m.textArea.Value())
+			}
+			if got := m.editorContent(); got != content {
+				t.Errorf("soft %v: %q comes back as %q", soft, content, got)
+			}
+			if m.editorDirty() {
+				t.Errorf("soft %v: %q dirty once opened", soft, content)
+			}
+		}
+	}
+}
+
+func TestEditorKeepsUntouchedLines(t *testing.T) {
+	cfg := config.Default()
+	cfg.Editor.SoftTabs = false
+	m := newTestModel(t, cfg, "\tfirst\n    second\n")
+	editNote(&m, m.notesManager.Notes[0])
+	m.textArea.SetValue(m.textArea.Value() + "        third")
+	if got, want := m.editorContent(), "\tfirst\n    second\n\t\tthird"; got != want {
+		t.Errorf("content %q, want %q", got, want)
+	}
+}
+
+func TestAutosaveOnChangeWaitsForAPause(t *testing.T) {
+	cfg := config.Default()
+	cfg.Editor.Autosave = config.AutosaveOnChange
+	m := newTestModel(t, cfg, "abc")
+	note := m.notesManager.Notes[0]
+	editNote(&m, note)
+
+	m = typeText(m, "xyz").(Model)
+	if note.Content != "abc" {
+		t.Fatalf("note saved while typing: %q", note.Content)
+	}
+	stale, _ := m.Update(autosaveMsg{seq: m.autosaveSeq - 1})
+	m = stale.(Model)
+	if note.Content != "abc" {
+		t.Fatalf("note saved by an outdated autosave: %q", note.Content)
+	}
+	saved, _ := m.Update(autosaveMsg{seq: m.autosaveSeq})
+	m = saved.(Model)
+	if note.Content != "abcxyz" {
+		t.Errorf("note holds %q after the pause, want %q", note.Content, "abcxyz")
+	}
+}
+
+func TestAutosaveOff(t *testing.T) {
+	cfg := config.Default()
+	cfg.Editor.Autosave = config.AutosaveOff
+	m := newTestModel(t, cfg, "abc")
+	note := m.notesManager.Notes[0]
+	editNote(&m, note)
+
+	m = typeText(m, "xyz").(Model)
+	if m.autosaveSeq != 0 {
+		t.Errorf("autosave scheduled with autosave off")
+	}
+	saved, _ := m.Update(autosaveMsg{seq: m.autosaveSeq})
+	m = saved.(Model)
+	if note.Content != "abc" {
+		t.Errorf("note saved with autosave off: %q", note.Content)
+	}
+}
+
+func TestContentSizeWarning(t *testing.T) {
+	tests := []struct {
+		length, warn, max int
+		want              string
+	}{
+		{10, 10, 0, ""},
+		{11, 10, 0, "Large note (11 characters), editing may slow down"},
+		{11, 0, 0, ""},
+		{19, 10, 20, "Large note (19 characters), editing may slow down"},
+		{20, 10, 20, "Note at the maximum size of 20 characters"},
+		{20, 0, 20, "Note at the maximum size of 20 characters"},
+	}
+	for _, tt := range tests {
+		editor := config.EditorConfig{WarnChars: tt.warn, MaxChars: tt.max}
+		if got := contentSizeWarning(tt.length, editor); got != tt.want {
+			t.Errorf("warning for %d characters with %d and %d: %q, want %q", tt.length, tt.warn, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestEditorMaxChars(t *testing.T) {
+	cfg := config.Default()
+	cfg.Editor.WarnChars = 4
+	cfg.Editor.MaxChars = 6
+	m := newTestModel(t, cfg, "abc", "abcdefghij")
+	var short, long *notes.Note
+	for _, note := range m.notesManager.Notes {
+		if note.Content == "abc" {
+			short = note
+		} else {
+			long = note
+		}
+	}
+
+	// Typing stops at the maximum, the status bar warning on the way
+	editNote(&m, short)
+	if status := m.statusBar(); strings.Contains(status, "characters") {
+		t.Errorf("status %q for a small note", status)
+	}
+	m = typeText(m, "de").(Model)
+	if status := m.statusBar(); !strings.Contains(status, "Large note (5 characters)") {
+		t.Errorf("status %q above the warning size", status)
+	}
+	m = typeText(m, "fgh").(Model)
+	if got := m.textArea.Value(); got != "abcdef" {
+		t.Errorf("content %q typed past the maximum, want abcdef", got)
+	}
+	if status := m.statusBar(); !strings.Contains(status, "Note at the maximum size of 6 characters") {
+		t.Errorf("status %q at the maximum size", status)
+	}
+
+	// A note already over the maximum is loaded whole and can't grow
+	editNote(&m, long)
+	if got := m.textArea.Value(); got != long.Content {
+		t.Fatalf("note over the maximum loaded as %q", got)
+	}
+	m = typeText(m, "k").(Model)
+	if got := m.textArea.Value(); got != long.Content {
+		t.Errorf("note over the maximum grew to %q", got)
+	}
+}
+
+func TestLongTitleOnOneLine(t *testing.T) {
+	title := strings.Repeat("会議メモ", 20)
+	m := newTestModel(t, config.Default(), "a")
+	note := m.notesManager.Notes[0]
+	note.Title = title
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 41, Height: 30})
+	m = model.(Model)
+
+	// The warning quotes the title within its width, the line within the screen
+	m.mode = ModeNew
+	m.titleInput.SetValue(title)
+	m.checkTitle()
+	if want := notes.ShortTitle(title, quotedTitleWidth); !strings.Contains(m.titleWarning, want) {
+		t.Errorf("warning %q doesn't quote %q", m.titleWarning, want)
+	}
+	if info := m.titleInfoView(); strings.Contains(info, "\n") || ansi.StringWidth(info) > m.width {
+		t.Errorf("title info takes %d columns, want at most %d: %q", ansi.StringWidth(info), m.width, info)
+	}
+
+	// The status bar keeps one line of the screen width, wide characters
+	// never cut in half
+	m.statusMsg = "Note saved: " + title
+	status := m.statusBar()
+	if strings.Contains(status, "\n") || ansi.StringWidth(status) != m.width {
+		t.Errorf("status bar takes %d columns, want one line of %d: %q", ansi.StringWidth(status), m.width, status)
+	}
+	if !strings.Contains(status, "Note saved: 会…") {
+		t.Errorf("status %q not cut after the start of the title", status)
+	}
+}
+
+func TestNewNoteDuplicateTitle(t *testing.T) {
+	m := newTestModel(t, config.Default(), "a")
+	for _, tt := range []struct {
+		title, status string
+	}{
+		{"Plan", "Note created successfully"},
+		{" plan ", "Note created, another note already has this title"},
+	} {
+		m.mode = ModeList
+		m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+		if m.mode != ModeNew {
+			t.Fatalf("mode %v after n, want a new note", m.mode)
+		}
+		m.titleInput.SetValue(tt.title)
+		m, _ = press(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+		if m.statusMsg != tt.status {
+			t.Errorf("status %q after saving %q, want %q", m.statusMsg, tt.title, tt.status)
+		}
+	}
+	// The note is created all the same
+	if n := len(m.notesManager.Notes); n != 3 {
+		t.Errorf("%d note(s), want 3", n)
+	}
+}
@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxEmbedDepth is how many levels of notes embedded in embedded notes are
+// expanded
+const maxEmbedDepth = 3
+
+// embeddedNote is a copy of a note embedded in the rendered one, so that the
+// preview can render it in the background
+type embeddedNote struct {
+	id      string
+	title   string
+	content string
+}
+
+// embedLookup returns copies of the notes content embeds, directly or through
+// other embedded notes, by the title they are embedded with. Embeds of notes
+// that don't exist are left out.
+func (m Model) embedLookup(content string) map[string]embeddedNote {
+	lookup := map[string]embeddedNote{}
+	queue := []string{content}
+	for depth := 0; depth < maxEmbedDepth && len(queue) > 0; depth++ {
+		next := []string{}
+		for _, c := range queue {
+			for _, target := range notes.EmbedTargets(c) {
+				if _, ok := lookup[target]; ok {
+					continue
+				}
+				note, err := m.notesManager.FindByTitle(target)
+				if err != nil {
+					continue
+				}
+				lookup[target] = embeddedNote{id: note.ID, title: sanitizeTerminal(note.Title, false), content: note.Content}
+				next = append(next, note.Content)
+			}
+		}
+		queue = next
+	}
+	return lookup
+}
+
+// expandEmbeds renders content with render, the notes it embeds being
+// rendered the same way in boxes titled with their source. seen holds the
+// IDs of the notes being expanded, an embed of one of them is a cycle, and
+// depth how many of them are embedded in one another.
+func expandEmbeds(content string, width int, lookup map[string]embeddedNote, seen []string, depth int, render func(content string, width int) string) string {
+	parts := notes.SplitEmbeds(content)
+	if len(parts) == 1 && parts[0].Target == "" {
+		return render(content, width)
+	}
+
+	warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7700"))
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFA500"))
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#555555")).
+		Padding(0, 1).
+		Width(max(width-2, 1))
+	// The border and the padding take two columns on each side
+	inner := max(width-4, 1)
+
+	rendered := []string{}
+	for _, part := range parts {
+		if part.Target == "" {
+			rendered = append(rendered, render(part.Text, width))
+			continue
+		}
+
+		note, ok := lookup[part.Target]
+		switch {
+		case depth >= maxEmbedDepth:
+			rendered = append(rendered, warningStyle.Render(fmt.Sprintf("⚠ %s is embedded too deeply to be expanded", sanitizeTerminal(part.Target, false))))
+		case !ok:
+			rendered = append(rendered, warningStyle.Render(fmt.Sprintf("⚠ Embedded note not found: %s", sanitizeTerminal(part.Target, false))))
+		case slices.Contains(seen, note.id):
+			rendered = append(rendered, warningStyle.Render(fmt.Sprintf("⚠ %s isn't expanded again, its embeds form a cycle", note.title)))
+		default:
+			body := expandEmbeds(note.content, inner, lookup, append(slices.Clone(seen), note.id), depth+1, render)
+			body = strings.TrimRight(body, "\n")
+			rendered = append(rendered, boxStyle.Render(titleStyle.Render(note.title)+"\n"+body))
+		}
+	}
+	return strings.Join(rendered, "\n")
+}
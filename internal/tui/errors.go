@@ -0,0 +1,24 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"errors"
+)
+
+// storeError words the failures of the notes manager for the status bar,
+// telling what went wrong rather than which file operation failed
+func storeError(err error) string {
+	switch {
+	case errors.Is(err, notes.ErrNoteNotFound):
+		return "the note no longer exists, it may have been deleted elsewhere"
+	case errors.Is(err, notes.ErrImageNotFound):
+		return "there is no image file at this path"
+	case errors.Is(err, notes.ErrReadOnly):
+		return "the storage folder is read-only, make it writable to save your changes"
+	case errors.Is(err, notes.ErrStoragePermission):
+		return "permission denied on the storage folder"
+	case errors.Is(err, notes.ErrDuplicateID):
+		return "the sync returned several notes with the same ID, nothing was merged"
+	}
+	return err.Error()
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// noteEventMsg carries a change of the notes made outside the update loop,
+// such as by a background task
+type noteEventMsg struct {
+	event notes.Event
+}
+
+// waitForNoteEvent waits for the next change of the notes. Once the
+// subscription is closed it returns nothing and isn't scheduled again.
+func waitForNoteEvent(events <-chan notes.Event) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-events
+		if !ok {
+			return nil
+		}
+		return noteEventMsg{event: event}
+	}
+}
+
+// pendingNoteEvents returns the changes of the notes waiting to be handled,
+// without waiting for more
+func (m Model) pendingNoteEvents() []notes.Event {
+	events := []notes.Event{}
+	for {
+		select {
+		case event, ok := <-m.noteEvents:
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+// notesChanged brings the list and the open note up to date after changes
+// of the notes, whether the interface, a sync or another part of the
+// program made them
+func (m *Model) notesChanged(events []notes.Event) {
+	if len(events) == 0 {
+		return
+	}
+	m.storeVersion++
+
+	// The open note may have been replaced by another version, as a sync
+	// does, or deleted. It is looked up whatever the events, some may have
+	// been dropped. The editor keeps its note, conflicts are dealt with when
+	// saving.
+	if m.selectedNote != nil && !editing(m.mode) {
+		if note, err := m.notesManager.GetNoteByID(m.selectedNote.ID); err == nil {
+			m.selectedNote = note
+		} else if m.mode == ModeView {
+			m.selectedNote = nil
+			m.mode = ModeList
+		}
+	}
+
+	// The list holds tags or workspaces while one is being picked
+	if m.mode == ModeFilterByTag || m.mode == ModeRenameTag || m.mode == ModeWorkspaces {
+		return
+	}
+	// A deleted note leaves the cursor on the note that followed it rather
+	// than at the top
+	index := m.noteList.Index()
+	if !m.refreshNoteList() {
+		m.noteList.Select(min(index, max(len(m.noteList.VisibleItems())-1, 0)))
+	}
+}
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// listTitleChrome is the number of columns the list draws around its title:
+// the padding of the title bar and of the title, and the gap before the
+// status message of the list
+const listTitleChrome = 6
+
+// listFilter is what the note list is narrowed to. Filters add up, and every
+// note is listed when it is zero.
+type listFilter struct {
+	tag    string    // Notes with this tag
+	day    time.Time // Notes created on this day, zero for any day
+	search string    // Search query as typed, see notes.ParseQuery
+}
+
+// active reports whether the filter leaves notes out
+func (f listFilter) active() bool {
+	return f != listFilter{}
+}
+
+// crumbs describes each part of the filter, in the order they apply
+func (f listFilter) crumbs() []string {
+	crumbs := []string{}
+	if f.tag != "" {
+		crumbs = append(crumbs, "tag:"+f.tag)
+	}
+	if !f.day.IsZero() {
+		crumbs = append(crumbs, "created:"+f.day.Format("2006-01-02"))
+	}
+	if f.search != "" {
+		crumbs = append(crumbs, fmt.Sprintf("search:%q", f.search))
+	}
+	return crumbs
+}
+
+// filteredItems returns the list items of the notes the filter keeps, search
+// results being described by where they matched
+func (m Model) filteredItems() []list.Item {
+	ns := m.notesManager.Notes
+	if m.filter.tag != "" {
+		ns = m.notesManager.FilterByTags([]string{m.filter.tag})
+	}
+	if !m.filter.day.IsZero() {
+		ns = slices.DeleteFunc(slices.Clone(ns), func(note *notes.Note) bool {
+			created := note.CreatedAt.In(m.filter.day.Location())
+			y, mo, d := created.Date()
+			fy, fmo, fd := m.filter.day.Date()
+			return y != fy || mo != fmo || d != fd
+		})
+	}
+	if m.filter.search == "" {
+		return m.noteItems(ns)
+	}
+
+	// The query was checked when it was entered, relative dates are
+	// computed again so that "modified:<1d" stays true to its word
+	query, err := notes.ParseQuery(m.filter.search, time.Now())
+	if err != nil {
+		return m.noteItems(ns)
+	}
+	results := slices.DeleteFunc(m.notesManager.SearchQuery(query), func(result notes.SearchResult) bool {
+		return !slices.Contains(ns, result.Note)
+	})
+	return m.resultItems(results)
+}
+
+// clearFilter lists every note again
+func (m *Model) clearFilter() {
+	m.filter = listFilter{}
+	m.refreshNoteList()
+}
+
+// listTitle returns the title of the note list within width columns: the
+// filter as a breadcrumb, the number of notes listed and the order when it
+// isn't the usual one. A narrow list drops the order, then shortens the
+// breadcrumb from its end, the count staying visible.
+func (m Model) listTitle(width int) string {
+	path := strings.Join(append([]string{"Notes"}, m.filter.crumbs()...), " › ")
+	if m.showArchived {
+		path += " › archived included"
+	}
+	count := fmt.Sprintf("(%d)", len(m.noteList.Items()))
+	order := ""
+	if m.notesManager.ManualOrder && m.filter.search == "" {
+		order = " — manual order"
+	}
+
+	title := path + " " + count + order
+	if width <= 0 || ansi.StringWidth(title) <= width {
+		return title
+	}
+	title = path + " " + count
+	if ansi.StringWidth(title) <= width {
+		return title
+	}
+	return ansi.Truncate(path, max(width-ansi.StringWidth(count)-1, 1), "…") + " " + count
+}
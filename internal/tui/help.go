@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpColumnSize is the number of bindings in each column of a help section
+const helpColumnSize = 6
+
+// helpSection groups the bindings of one context on the help screen
+type helpSection struct {
+	title    string
+	bindings []key.Binding
+}
+
+// helpSections returns every binding of the interface by context
+func (m Model) helpSections() []helpSection {
+	k := m.keys
+	return []helpSection{
+		{"Everywhere", []key.Binding{k.Help, k.Back, k.Quit, k.Suspend, k.QuickNote}},
+		{"Note list", []key.Binding{
+			k.Up, k.Down, k.Enter, k.New, k.Search, k.FilterByTag,
+			k.Mark, k.ExportBook, k.MoveUp, k.MoveDown, k.ShowArchived, k.DetailPane,
+			m.detail.KeyMap.HalfPageDown, m.detail.KeyMap.HalfPageUp, k.Workspaces, k.Review, k.Calendar, k.Activity,
+			k.Sync,
+		}},
+		{"Note view", []key.Binding{
+			m.viewport.KeyMap.HalfPageDown, m.viewport.KeyMap.HalfPageUp, k.Edit, k.Delete, k.AddImage, k.AddTag,
+			k.Aliases, k.ViewImage, k.Pin, k.Archive, k.PrevNote, k.NextNote,
+			k.CopyID, k.Search, k.ReadingWidth, k.Share, k.Unshare, k.Export,
+			k.ExportImages, k.TOC,
+		}},
+		{"Editor", []key.Binding{
+			k.Save, k.SwitchFocus, k.Indent, k.TogglePreview, k.ForcePreview, k.MoveLineUp,
+			k.MoveLineDown, k.DuplicateLine, k.JoinLines, k.SmartPaste, k.UndoPaste, k.TOC,
+		}},
+		{"Images", []key.Binding{k.NextImage, k.PrevImage, k.OpenImage}},
+		{"Review", []key.Binding{k.Keep, k.Edit, k.Archive, k.Delete}},
+		{"Calendar", []key.Binding{k.PrevDay, k.NextDay, k.PrevMonth, k.NextMonth}},
+		{"Tags", []key.Binding{k.RenameTag}},
+		{"Conflict", []key.Binding{k.KeepMine, k.TakeTheirs, k.MergeManually}},
+	}
+}
+
+// helpBody renders the sections of the help screen, their bindings in
+// columns of helpColumnSize
+func (m Model) helpBody() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFA500"))
+	sections := []string{}
+	for _, section := range m.helpSections() {
+		columns := [][]key.Binding{}
+		for bindings := section.bindings; len(bindings) > 0; {
+			n := min(helpColumnSize, len(bindings))
+			columns = append(columns, bindings[:n])
+			bindings = bindings[n:]
+		}
+		sections = append(sections, titleStyle.Render(section.title)+"\n"+m.help.FullHelpView(columns))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// typing reports whether a key press is text typed in a field, where ? and q
+// must be inserted rather than open the help screen or quit
+func (m Model) typing(msg tea.KeyMsg) bool {
+	if msg.Type != tea.KeyRunes {
+		return false
+	}
+	switch m.mode {
+	case ModeEdit, ModeNew, ModeSearch, ModeAddImage, ModeAddTag, ModeEditAliases, ModeNoteSearch, ModeExportBook, ModeExportImages, ModeQuickNote, ModeRenameTag:
+		return true
+	case ModeList, ModeFilterByTag, ModeWorkspaces:
+		return m.noteList.SettingFilter()
+	}
+	return false
+}
+
+// openHelp shows the help screen, which returns to the current mode
+func (m *Model) openHelp() {
+	m.helpFrom = m.mode
+	m.mode = ModeHelp
+	m.helpScreen.GotoTop()
+}
+
+// updateHelpMode scrolls the help screen, exports the keys to a cheat sheet
+// and closes it with Esc or ?
+func (m Model) updateHelpMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Help):
+		m.mode = m.helpFrom
+		return m, nil
+	case key.Matches(msg, m.keys.Export):
+		m.exportCheatSheet()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.helpScreen, cmd = m.helpScreen.Update(msg)
+	return m, cmd
+}
+
+// viewHelp displays the help screen
+func (m Model) viewHelp() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFA500"))
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render("Keys"),
+		m.helpScreen.View(),
+		m.statusBar(),
+		m.helpView(),
+	)
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// sized gives the model a window large enough to show the whole help screen
+func sized(m Model) Model {
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 200, Height: 200})
+	return updated.(Model)
+}
+
+func TestHelpListsBindingsByContext(t *testing.T) {
+	m := sized(newTestModel(t, config.Default(), "a"))
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	if m.mode != ModeHelp {
+		t.Fatalf("mode %v after ?, want the help screen", m.mode)
+	}
+	m = sized(m)
+	lines := strings.Split(ansi.Strip(m.View()), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+
+	// Each section is listed in order, followed by its own bindings
+	sections := m.helpSections()
+	starts := make([]int, len(sections)+1)
+	for i, section := range sections {
+		starts[i] = slices.Index(lines, section.title)
+		if starts[i] < 0 || (i > 0 && starts[i] < starts[i-1]) {
+			t.Fatalf("section %q missing or out of order in:\n%s", section.title, strings.Join(lines, "\n"))
+		}
+	}
+	starts[len(sections)] = len(lines)
+	for i, section := range sections {
+		body := strings.Join(lines[starts[i]:starts[i+1]], "\n")
+		for _, binding := range section.bindings {
+			if help := binding.Help(); !strings.Contains(body, help.Key) || !strings.Contains(body, help.Desc) {
+				t.Errorf("%s %s missing from section %q", help.Key, help.Desc, section.title)
+			}
+		}
+	}
+}
+
+func TestHelpReturnsToPreviousMode(t *testing.T) {
+	m := newTestModel(t, config.Default(), "a")
+	m.openNote(m.notesManager.Notes[0])
+	for _, closing := range []tea.KeyMsg{
+		{Type: tea.KeyEsc},
+		{Type: tea.KeyRunes, Runes: []rune{'?'}},
+	} {
+		opened, _ := press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+		if opened.mode != ModeHelp || opened.helpFrom != ModeView {
+			t.Fatalf("mode %v from %v after ?, want the help screen from the note view", opened.mode, opened.helpFrom)
+		}
+		if closed, _ := press(opened, closing); closed.mode != ModeView {
+			t.Errorf("mode %v after %s, want back to the note view", closed.mode, closing)
+		}
+	}
+}
+
+func TestFooterBindingsByMode(t *testing.T) {
+	tests := []struct {
+		mode   Mode
+		want   []string
+		typing bool
+	}{
+		{ModeEdit, []string{"ctrl+s save", "esc cancel", "ctrl+p toggle preview", "shift+tab switch title/content"}, true},
+		{ModeNew, []string{"ctrl+s save", "esc cancel"}, true},
+		{ModeSearch, []string{"enter search", "esc cancel"}, true},
+		{ModeAddTag, []string{"enter add", "esc cancel"}, true},
+		{ModeEditAliases, []string{"enter save", "esc cancel"}, true},
+		{ModeExportBook, []string{"enter export", "esc cancel"}, true},
+		{ModeAddImage, []string{"enter confirmer", "esc annuler", "tab compléter, champ suivant"}, true},
+		{ModeNoteSearch, []string{"enter next match", "↑ previous match", "esc close"}, false},
+		{ModeFilterByTag, []string{"↑/k up", "enter filter", "esc cancel", "?/f1 help"}, false},
+		{ModeWorkspaces, []string{"↑/k up", "enter open", "esc cancel", "?/f1 help"}, false},
+		{ModeHelp, []string{"↓/j down", "esc close", "?/f1 close"}, false},
+	}
+	m := newTestModel(t, config.Default(), "a")
+	for _, tt := range tests {
+		m.mode = tt.mode
+		footer := m.helpView()
+		for _, want := range tt.want {
+			if !strings.Contains(footer, want) {
+				t.Errorf("footer of mode %v misses %q: %s", tt.mode, want, footer)
+			}
+		}
+		// ? is typed in text fields, which show f1 only
+		if tt.typing && (!strings.Contains(footer, "f1 help") || strings.Contains(footer, "?")) {
+			t.Errorf("footer of mode %v doesn't show f1 alone for help: %s", tt.mode, footer)
+		}
+	}
+}
+
+func TestFooterShownInEditor(t *testing.T) {
+	m := sized(newTestModel(t, config.Default(), "a"))
+	editNote(&m, m.notesManager.Notes[0])
+	lines := strings.Split(ansi.Strip(m.View()), "\n")
+	if last := lines[len(lines)-1]; !strings.HasPrefix(last, "ctrl+s save • esc cancel") {
+		t.Errorf("editor ends with %q, want its bindings", last)
+	}
+}
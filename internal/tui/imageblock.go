@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// imageBlockStyle frames each image attached to a note in the note view
+var imageBlockStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("#3498db")).
+	Padding(0, 1)
+
+// imageCaptionStyle sets the caption of an image apart from its placeholder
+var imageCaptionStyle = lipgloss.NewStyle().
+	Italic(true).
+	Foreground(lipgloss.Color("#AAAAAA"))
+
+// imageBlockText returns the two lines describing the image number of a
+// note: the placeholder the preview uses for images, which holds its alt text
+// and stored name, followed by its dimensions or by the missing file, then
+// its caption
+func imageBlockText(number int, img notes.Image, missing bool) (head, caption string) {
+	head = fmt.Sprintf("%d. %s", number, imagePlaceholder(img.AltText, img.Path))
+	switch {
+	case missing:
+		head += " — fichier manquant"
+	case img.Info() != "":
+		head += " — " + img.Info()
+	}
+	caption = img.Caption
+	if caption == "" {
+		caption = "(aucune légende)"
+	}
+	return head, caption
+}
+
+// imageBlock renders an image attached to a note as a framed block width
+// columns wide, its caption below its placeholder
+func imageBlock(number int, img notes.Image, missing bool, width int) string {
+	head, caption := imageBlockText(number, img, missing)
+	headStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#3498db"))
+	if missing {
+		headStyle = headStyle.Foreground(lipgloss.Color("#ff7700"))
+	}
+	inner := max(width-imageBlockStyle.GetHorizontalFrameSize(), 1)
+	return imageBlockStyle.Width(width - imageBlockStyle.GetHorizontalBorderSize()).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		headStyle.Width(inner).Render(sanitizeTerminal(head, false)),
+		imageCaptionStyle.Width(inner).Render(sanitizeTerminal(caption, false)),
+	))
+}
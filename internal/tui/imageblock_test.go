@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestImageBlockText(t *testing.T) {
+	tests := []struct {
+		name    string
+		img     notes.Image
+		missing bool
+		head    string
+		caption string
+	}{
+		{
+			name:    "described image",
+			img:     notes.Image{Path: "beach.png", AltText: "Sand", Caption: "The beach", Width: 800, Height: 600, Size: 2048},
+			head:    "2. 🖼 Sand (beach.png) — 800×600, 2 KB",
+			caption: "The beach",
+		},
+		{
+			name:    "size only",
+			img:     notes.Image{Path: "scan.pdf", Size: 10},
+			head:    "2. 🖼 (scan.pdf) — 10 B",
+			caption: "(aucune légende)",
+		},
+		{
+			name:    "no metadata",
+			img:     notes.Image{Path: "old.png", Caption: "Old"},
+			head:    "2. 🖼 (old.png)",
+			caption: "Old",
+		},
+		{
+			name:    "missing file",
+			img:     notes.Image{Path: "gone.png", AltText: "Gone", Width: 10, Height: 10, Size: 100},
+			missing: true,
+			head:    "2. 🖼 Gone (gone.png) — fichier manquant",
+			caption: "(aucune légende)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, caption := imageBlockText(2, tt.img, tt.missing)
+			if head != tt.head || caption != tt.caption {
+				t.Errorf("imageBlockText = %q, %q, want %q, %q", head, caption, tt.head, tt.caption)
+			}
+		})
+	}
+}
+
+func TestImageBlock(t *testing.T) {
+	img := notes.Image{Path: "beach.png", AltText: "Sand\x1b[2J", Caption: "The beach"}
+	block := ansi.Strip(imageBlock(1, img, false, 30))
+	lines := strings.Split(block, "\n")
+	if len(lines) != 4 {
+		t.Fatalf("block of %d line(s), want 4:\n%s", len(lines), block)
+	}
+	for _, line := range lines {
+		if ansi.StringWidth(line) != 30 {
+			t.Errorf("line %q is %d columns wide, want 30", line, ansi.StringWidth(line))
+		}
+	}
+	if !strings.Contains(lines[1], "1. 🖼 Sand (beach.png)") || !strings.Contains(lines[2], "The beach") {
+		t.Errorf("block lost its text:\n%s", block)
+	}
+	if strings.Contains(imageBlock(1, img, false, 30), "\x1b[2J") {
+		t.Error("control sequence of the alt text written to the terminal")
+	}
+}
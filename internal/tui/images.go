@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"datapad/internal/expand"
+	"datapad/internal/export"
+	"datapad/internal/notes"
+	"datapad/internal/pathcomplete"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// startImagesExport asks which folder receives the images of the open note
+func (m *Model) startImagesExport() {
+	if len(m.selectedNote.Images) == 0 {
+		m.statusMsg = "Cette note ne contient pas d'images"
+		return
+	}
+	m.mode = ModeExportImages
+	m.imagesDir.SetValue(notes.Slugify(m.selectedNote.Title) + "-images")
+	m.imagesDir.CursorEnd()
+	m.imagesDir.Focus()
+}
+
+// updateExportImagesMode handles the prompt for the images folder
+func (m Model) updateExportImagesMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeView
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		dir := expand.Path(strings.TrimSpace(m.imagesDir.Value()))
+		if dir == "" {
+			return m, nil
+		}
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		report, err := export.ExportImages(m.notesManager, m.selectedNote, dir)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Export failed: %s", err)
+			return m, nil
+		}
+
+		m.mode = ModeView
+		m.statusMsg = imagesExportStatus(report, dir)
+		return m, nil
+	}
+
+	m.imagesDir, cmd = m.imagesDir.Update(msg)
+	return m, cmd
+}
+
+// imagesExportStatus lists the files an images export wrote and the images
+// it couldn't find
+func imagesExportStatus(report export.ImagesReport, dir string) string {
+	names := []string{}
+	for _, path := range report.Written {
+		names = append(names, filepath.Base(path))
+	}
+	status := fmt.Sprintf("No image exported to %s", dir)
+	if len(names) > 0 {
+		status = fmt.Sprintf("Wrote %s to %s", strings.Join(names, ", "), dir)
+	}
+	if len(report.Missing) > 0 {
+		status += fmt.Sprintf(", missing from the store: %s", strings.Join(report.Missing, ", "))
+	}
+	return status
+}
+
+// completeImagePath completes the last path of the image prompt. It lists
+// the candidates when there are several, and reports false once Tab has
+// nothing more to do there so that it moves to the next field.
+func (m *Model) completeImagePath() bool {
+	value := m.imagePath.Value()
+	head, path := "", value
+	if i := strings.LastIndex(value, ","); i >= 0 {
+		head, path = value[:i+1], value[i+1:]
+	}
+	trimmed := strings.TrimLeft(path, " ")
+	head += path[:len(path)-len(trimmed)]
+	if trimmed == "" {
+		return false
+	}
+
+	completion := pathcomplete.Complete(trimmed)
+	if completion.Value != trimmed {
+		m.imagePath.SetValue(head + completion.Value)
+		m.imagePath.CursorEnd()
+		m.pathMatches = nil
+		if len(completion.Candidates) > 1 {
+			m.pathMatches = completion.Candidates
+		}
+		return true
+	}
+	if len(completion.Candidates) > 1 && m.pathMatches == nil {
+		m.pathMatches = completion.Candidates
+		return true
+	}
+	return false
+}
+
+// addImages imports several images at once into the open note. The caption
+// and alt text fields describe a single image, so they must be left empty.
+func (m Model) addImages(paths []string) (tea.Model, tea.Cmd) {
+	if strings.TrimSpace(m.imageCaption.Value()) != "" || strings.TrimSpace(m.imageAlt.Value()) != "" {
+		m.statusMsg = "Caption and alt text describe a single image, clear them to add several"
+		return m, nil
+	}
+	if m.config.Accessibility.RequireAltText {
+		m.statusMsg = "Alt text is required, add the images one at a time"
+		return m, nil
+	}
+
+	results, err := m.notesManager.ImportImages(m.selectedNote.ID, paths)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Error: %s", storeError(err))
+		return m, nil
+	}
+
+	// The prompt stays open with the paths that failed, to fix them
+	failed := []string{}
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result.Path)
+		}
+	}
+	m.statusMsg = imagesAddedStatus(results)
+	if len(failed) > 0 {
+		m.imagePath.SetValue(strings.Join(failed, ", "))
+		m.imagePath.CursorEnd()
+		return m, nil
+	}
+	m.imagePath.Reset()
+	m.mode = ModeView
+	return m, nil
+}
+
+// imagesAddedStatus tells how many images a batch import added and why the
+// others failed
+func imagesAddedStatus(results []notes.ImageImport) string {
+	added := 0
+	failures := []string{}
+	for _, result := range results {
+		switch {
+		case result.Err == nil:
+			added++
+		case errors.Is(result.Err, notes.ErrImageNotFound):
+			failures = append(failures, filepath.Base(result.Path)+" (not found)")
+		case errors.Is(result.Err, fs.ErrPermission):
+			failures = append(failures, filepath.Base(result.Path)+" (permission denied)")
+		default:
+			failures = append(failures, fmt.Sprintf("%s (%s)", filepath.Base(result.Path), storeError(result.Err)))
+		}
+	}
+
+	status := fmt.Sprintf("%d image(s) added", added)
+	if len(failures) > 0 {
+		status += fmt.Sprintf(", %d failed: %s", len(failures), strings.Join(failures, ", "))
+	}
+	return status
+}
+
+// viewExportImages displays the prompt for the images folder
+func (m Model) viewExportImages() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		fmt.Sprintf("Export the %d image(s) of the note to the folder:", len(m.selectedNote.Images)),
+		m.imagesDir.View(),
+		m.statusBar(),
+		m.helpView(),
+	)
+}
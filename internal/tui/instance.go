@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"datapad/internal/instance"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// instanceRequestMsg is a request of a command run while the interface has
+// the store open, such as datapad add
+type instanceRequestMsg struct {
+	storagePath string // Store the server answers for
+	request     instance.Request
+	reply       chan<- instance.Response
+}
+
+// instanceServer serves the store the interface has open, following it from
+// workspace to workspace. It is shared by the models of the program.
+type instanceServer struct {
+	program *tea.Program
+	stop    func()
+}
+
+// serve stops serving the previous store and serves storagePath
+func (s *instanceServer) serve(storagePath string) {
+	s.stop()
+	s.stop = serveInstance(s.program, storagePath)
+}
+
+// close stops serving
+func (s *instanceServer) close() {
+	s.stop()
+	s.stop = func() {}
+}
+
+// serveInstance lets the commands run while the interface is open submit
+// their changes to it rather than write the store behind its back. When the
+// socket can't be opened, as when another interface serves the store, the
+// commands write the store themselves. The returned function stops serving.
+func serveInstance(p *tea.Program, storagePath string) func() {
+	server, err := instance.Listen(storagePath)
+	if err != nil {
+		return func() {}
+	}
+	go server.Serve(func(req instance.Request) instance.Response {
+		reply := make(chan instance.Response, 1)
+		p.Send(instanceRequestMsg{storagePath: storagePath, request: req, reply: reply})
+		select {
+		case resp := <-reply:
+			return resp
+		case <-server.Done():
+			return instance.Response{Error: "the interface was closed before making the change"}
+		}
+	})
+	return server.Close
+}
+
+// answerInstance makes the change a command asked for, once the notes are
+// loaded. The list shows it as any other change, through the events of the
+// manager.
+func (m *Model) answerInstance(msg instanceRequestMsg) {
+	if m.loading {
+		m.instanceQueue = append(m.instanceQueue, msg)
+		return
+	}
+	// A request sent before a workspace switch is left to the command
+	if m.notesManager.StoragePath != msg.storagePath {
+		msg.reply <- instance.Response{Elsewhere: true}
+		return
+	}
+	resp, err := instance.Apply(m.notesManager, msg.request)
+	if err != nil {
+		resp.Error = storeError(err)
+	} else if resp.Error == "" {
+		m.statusMsg = fmt.Sprintf("Note added from the command line: %s", resp.Title)
+	}
+	msg.reply <- resp
+}
+
+// answerQueuedInstance answers the requests received while the notes were
+// loading
+func (m *Model) answerQueuedInstance() {
+	queue := m.instanceQueue
+	m.instanceQueue = nil
+	for _, msg := range queue {
+		m.answerInstance(msg)
+	}
+}
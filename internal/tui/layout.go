@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Minimum terminal size below which the interface is not rendered
+const (
+	minWidth  = 40
+	minHeight = 10
+)
+
+// Number of lines used around the main component of each screen
+const (
+	statusBarHeight = 1
+	helpHeight      = 1
+	editorChrome    = 7 // Mode line, title label, input and slug, content label, status, help
+	viewChrome      = 3 // Blank line, status and help below the note
+	pickerChrome    = 3 // Header, status and help line
+)
+
+// tooSmall reports whether the terminal is below the minimum usable size.
+// Before the first WindowSizeMsg the size is unknown and assumed large enough.
+func (m Model) tooSmall() bool {
+	if m.width == 0 && m.height == 0 {
+		return false
+	}
+	return m.width < minWidth || m.height < minHeight
+}
+
+// tooSmallView is displayed instead of the current mode when the terminal is too small
+func (m Model) tooSmallView() string {
+	msg := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#ff7700")).
+		Render(fmt.Sprintf("Terminal too small (%dx%d)\nMinimum size is %dx%d", m.width, m.height, minWidth, minHeight))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, msg)
+}
+
+// editorPanes returns the width of the editor and of the preview content
+// when the preview is displayed next to the editor
+func (m Model) editorPanes() (editorWidth, previewWidth int) {
+	editorWidth = m.width / 2
+	// The separator takes one column and the preview border two more
+	previewWidth = max(m.width-editorWidth-3, 0)
+	return editorWidth, previewWidth
+}
+
+// layout recomputes the size of every component from the terminal size.
+// It runs after each update so that resizes and mode changes are both handled,
+// the notes it displays only being rendered again when they changed.
+func (m *Model) layout() {
+	if m.width == 0 || m.height == 0 {
+		return
+	}
+
+	// The quick note prompt is displayed over the screen of another mode
+	if m.mode == ModeQuickNote {
+		m.mode = m.quickFrom
+		defer func() { m.mode = ModeQuickNote }()
+	}
+
+	m.help.Width = m.width
+
+	// Lists (notes and tag picker)
+	listHeight := m.height - statusBarHeight - helpHeight
+	if m.mode == ModeFilterByTag || m.mode == ModeWorkspaces {
+		listHeight = m.height - pickerChrome
+	}
+	listWidth := m.width
+	if m.detailPane() {
+		listWidth, _ = m.listPanes()
+	}
+	m.noteList.SetSize(listWidth, max(listHeight, 1))
+	if m.mode == ModeFilterByTag || m.mode == ModeRenameTag || m.mode == ModeWorkspaces {
+		m.noteList.Title = "Notes"
+	} else {
+		m.noteList.Title = m.listTitle(listWidth - listTitleChrome)
+	}
+	if m.detailPane() {
+		m.updateDetail()
+	}
+
+	// Scrollable note view, its content depends on the width
+	m.viewport.Width = m.width
+	m.viewport.Height = max(m.height-viewChrome, 1)
+	switch m.mode {
+	case ModeView, ModeNoteSearch, ModeReview:
+		m.showNoteBody()
+	case ModeActivity:
+		m.viewport.SetContent(m.activityBody())
+		m.viewKey = [sha256.Size]byte{}
+	}
+	m.helpScreen.Width = m.width
+	m.helpScreen.Height = max(m.height-viewChrome, 1)
+	if m.mode == ModeHelp {
+		m.helpScreen.SetContent(m.helpBody())
+	}
+	m.conflictView.Width = m.width
+	m.conflictView.Height = max(m.height-viewChrome-1, 1)
+	if m.mode == ModeConflict {
+		m.conflictView.SetContent(m.conflictBody())
+	}
+
+	// Editor, split in two when the preview is shown
+	editorWidth := m.width
+	if m.showPreview {
+		editorWidth, _ = m.editorPanes()
+	}
+	m.textArea.SetWidth(editorWidth)
+	m.textArea.SetHeight(max(m.height-editorChrome, 1))
+
+	// Single line inputs leave room for the prompt and the cursor
+	inputWidth := max(editorWidth-3, 1)
+	m.titleInput.Width = inputWidth
+	m.imagePath.Width = max(m.width-3, 1)
+	m.imageCaption.Width = max(m.width-3, 1)
+	m.imageAlt.Width = max(m.width-3, 1)
+	m.searchInput.Width = max(m.width-3, 1)
+	m.tagInput.Width = max(m.width-3, 1)
+	m.aliasInput.Width = max(m.width-3, 1)
+	m.noteSearch.Width = max(m.width-5, 1)
+	m.bookPath.Width = max(m.width-3, 1)
+	m.imagesDir.Width = max(m.width-3, 1)
+	m.quickInput.Width = max(m.width-14, 1)
+}
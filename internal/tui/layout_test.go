@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const sentinel = "not rendered again"
+
+func TestLayoutKeepsRenderedNote(t *testing.T) {
+	m := newTestModel(t, config.Default(), strings.Repeat("line\n\n", 200))
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = model.(Model)
+	m.openNote(m.notesManager.Notes[0])
+
+	// Messages that change nothing the note is drawn from keep the view
+	m.viewport.SetContent(sentinel)
+	model = m
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(flushMsg{})
+	if view := model.(Model).viewport.View(); !strings.Contains(view, sentinel) {
+		t.Fatal("the note was rendered again by a message that changed nothing")
+	}
+
+	// A new width renders it again
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 70, Height: 24})
+	m = model.(Model)
+	if strings.Contains(m.viewport.View(), sentinel) {
+		t.Fatal("the note wasn't rendered again at a new width")
+	}
+
+	// So does a change of the note
+	m.viewport.SetContent(sentinel)
+	note := m.selectedNote
+	note.Content = "changed"
+	if err := m.notesManager.UpdateNote(note); err != nil {
+		t.Fatal(err)
+	}
+	model, _ = m.Update(flushMsg{})
+	if view := model.(Model).viewport.View(); !strings.Contains(view, "changed") {
+		t.Errorf("the changed note isn't displayed: %q", view)
+	}
+}
+
+func TestLayoutAfterActivity(t *testing.T) {
+	m := newTestModel(t, config.Default(), "the note")
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = model.(Model)
+	m.openNote(m.notesManager.Notes[0])
+	m.openActivity()
+	m.openNote(m.notesManager.Notes[0])
+	if view := m.viewport.View(); !strings.Contains(view, "the note") {
+		t.Errorf("the note view still shows the activity: %q", view)
+	}
+}
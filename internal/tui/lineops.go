@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// editorLines returns the lines of the content editor and the position of
+// the cursor, its column counted in characters
+func (m Model) editorLines() (lines []string, row, col int) {
+	info := m.textArea.LineInfo()
+	return strings.Split(m.textArea.Value(), "\n"), m.textArea.Line(), info.StartColumn + info.ColumnOffset
+}
+
+// setEditorLines replaces the content of the editor in one go and puts the
+// cursor back at row and col. It reports false, leaving the content as it
+// was, when the new content would be over the size limit of the editor.
+func (m *Model) setEditorLines(lines []string, row, col int) bool {
+	value := strings.Join(lines, "\n")
+	if limit := m.textArea.CharLimit; limit > 0 && utf8.RuneCountInString(value) > limit {
+		m.statusMsg = contentSizeWarning(limit, m.config.Editor)
+		return false
+	}
+
+	m.textArea.SetValue(value)
+	// SetValue leaves the cursor at the end, wrapped lines take several
+	// steps to go up
+	for m.textArea.Line() > row {
+		m.textArea.CursorUp()
+	}
+	m.textArea.SetCursor(col)
+	return true
+}
+
+// moveLine swaps the line of the cursor with the one above or below it, the
+// cursor following the line. Nothing moves past the first or last line.
+func (m *Model) moveLine(delta int) {
+	lines, row, col := m.editorLines()
+	target := row + delta
+	if target < 0 || target >= len(lines) {
+		return
+	}
+	lines[row], lines[target] = lines[target], lines[row]
+	m.setEditorLines(lines, target, col)
+}
+
+// duplicateLine inserts a copy of the line of the cursor below it and moves
+// the cursor to the copy
+func (m *Model) duplicateLine() {
+	lines, row, col := m.editorLines()
+	lines = slices.Insert(lines, row+1, lines[row])
+	m.setEditorLines(lines, row+1, col)
+}
+
+// joinLines appends the next line to the line of the cursor, separated by a
+// single space, and puts the cursor where they meet
+func (m *Model) joinLines() {
+	lines, row, _ := m.editorLines()
+	if row+1 >= len(lines) {
+		return
+	}
+
+	left := strings.TrimRightFunc(lines[row], unicode.IsSpace)
+	right := strings.TrimLeftFunc(lines[row+1], unicode.IsSpace)
+	if left != "" && right != "" {
+		left += " "
+	}
+	lines[row] = left + right
+	lines = slices.Delete(lines, row+1, row+2)
+	m.setEditorLines(lines, row, utf8.RuneCountInString(left))
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// notesLoadedMsg reports that the notes were read in the background
+type notesLoadedMsg struct {
+	archived int // Notes archived for being untouched too long
+	err      error
+}
+
+// loadNotes reads the store in the background so that the interface is
+// displayed right away. Keys are ignored until it is done, nothing else
+// uses the manager in the meantime.
+func (m Model) loadNotes() tea.Cmd {
+	manager := m.notesManager
+	days := m.config.Archive.AfterDays
+	return func() tea.Msg {
+		if err := manager.CheckStorage(); err != nil {
+			return notesLoadedMsg{err: err}
+		}
+		if err := manager.LoadNotes(); err != nil && !os.IsNotExist(err) {
+			return notesLoadedMsg{err: fmt.Errorf("error loading notes: %w", err)}
+		}
+
+		// Archive notes left untouched for too long
+		if days <= 0 {
+			return notesLoadedMsg{}
+		}
+		archived, err := manager.AutoArchive(time.Duration(days) * 24 * time.Hour)
+		if err != nil {
+			return notesLoadedMsg{err: fmt.Errorf("error archiving old notes: %w", err)}
+		}
+		return notesLoadedMsg{archived: archived}
+	}
+}
+
+// finishLoading fills the list once the notes are loaded, a failure stops
+// the program and is returned by App
+func (m *Model) finishLoading(msg notesLoadedMsg) tea.Cmd {
+	m.loading = false
+	if msg.err != nil {
+		m.loadErr = msg.err
+		return tea.Quit
+	}
+	m.refreshNoteList()
+	if msg.archived > 0 {
+		m.statusMsg = fmt.Sprintf("%d note(s) untouched for %d days archived", msg.archived, m.config.Archive.AfterDays)
+	}
+	m.answerQueuedInstance()
+	m.applyStart()
+	return nil
+}
+
+// loadingView is displayed until the notes are loaded
+func (m Model) loadingView() string {
+	msg := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#888888")).
+		Render("Loading notes…")
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, msg)
+}
@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"datapad/internal/passphrase"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// lockMsg locks the screen once no key has been pressed for the lock delay
+type lockMsg struct {
+	seq int
+}
+
+// unlockMsg reports the check of the passphrase entered on the lock screen
+type unlockMsg struct {
+	ok  bool
+	err error
+}
+
+// lockEnabled reports whether the screen locks when idle
+func (m Model) lockEnabled() bool {
+	_, ok := m.config.Security.LockDelay()
+	return ok
+}
+
+// scheduleLock restarts the idle delay after which the screen locks. Each key
+// press schedules a new tick and only the latest one can lock.
+func (m *Model) scheduleLock() tea.Cmd {
+	delay, ok := m.config.Security.LockDelay()
+	if !ok || m.locked {
+		return nil
+	}
+	m.lockSeq++
+	seq := m.lockSeq
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return lockMsg{seq: seq}
+	})
+}
+
+// lock hides the interface until it is unlocked
+func (m *Model) lock() {
+	m.locked = true
+	m.unlockInput.Reset()
+	m.unlockInput.Focus()
+	m.unlockErr = ""
+	m.unlocking = false
+}
+
+// checkPassphrase checks the passphrase in the background, the hash being
+// slow to compute on purpose
+func checkPassphrase(entered, hash string) tea.Cmd {
+	return func() tea.Msg {
+		ok, err := passphrase.Verify(entered, hash)
+		return unlockMsg{ok: ok, err: err}
+	}
+}
+
+// finishUnlock unlocks the screen once the passphrase is found right
+func (m *Model) finishUnlock(msg unlockMsg) tea.Cmd {
+	if !m.unlocking {
+		return nil
+	}
+	m.unlocking = false
+	switch {
+	case msg.err != nil:
+		m.unlockErr = fmt.Sprintf("Unable to check the passphrase: %s", msg.err)
+		return nil
+	case !msg.ok:
+		m.unlockErr = "Wrong passphrase"
+		return nil
+	}
+	return m.unlock()
+}
+
+// unlock shows the interface again and restarts the idle delay
+func (m *Model) unlock() tea.Cmd {
+	m.locked = false
+	m.unlockErr = ""
+	m.unlockInput.Blur()
+	return m.scheduleLock()
+}
+
+// updateLocked handles keys while the screen is locked: only unlocking and
+// quitting are possible
+func (m Model) updateLocked(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		m.unlockInput.Reset()
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Enter):
+		hash := m.config.Security.PassphraseHash
+		if hash == "" {
+			return m, m.unlock()
+		}
+		if m.unlocking {
+			return m, nil
+		}
+		entered := m.unlockInput.Value()
+		m.unlockInput.Reset()
+		m.unlockErr = ""
+		m.unlocking = true
+		return m, checkPassphrase(entered, hash)
+	}
+
+	m.unlockInput, cmd = m.unlockInput.Update(msg)
+	return m, cmd
+}
+
+// lockedView replaces the whole interface while the screen is locked
+func (m Model) lockedView() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFA500"))
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff7700"))
+
+	lines := []string{titleStyle.Render("🔒 Locked"), ""}
+	if m.config.Security.PassphraseHash != "" {
+		lines = append(lines, "Passphrase:", m.unlockInput.View())
+	} else {
+		lines = append(lines, helpStyle.Render("Press Enter to unlock"))
+	}
+	if m.unlocking {
+		lines = append(lines, "", helpStyle.Render("Checking…"))
+	} else if m.unlockErr != "" {
+		lines = append(lines, "", errorStyle.Render(m.unlockErr))
+	}
+	lines = append(lines, "", helpStyle.Render("ctrl+c to quit"))
+
+	box := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	if m.width == 0 || m.height == 0 {
+		return box
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"datapad/internal/passphrase"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newLockedModel returns a locked model whose passphrase is secret
+func newLockedModel(t *testing.T) Model {
+	t.Helper()
+	hash, err := passphrase.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.Security.LockAfter = "5m"
+	cfg.Security.PassphraseHash = hash
+	m := newTestModel(t, cfg)
+	m.lock()
+	return m
+}
+
+// enterPassphrase types the passphrase and presses Enter, returning the
+// model and the check it started
+func enterPassphrase(t *testing.T, m Model, entered string) (Model, tea.Cmd) {
+	t.Helper()
+	updated, check := typeText(m, entered).(Model).update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.locked || !m.unlocking || check == nil {
+		t.Fatalf("locked %v and checking %v right after Enter, want both", m.locked, m.unlocking)
+	}
+	if m.unlockInput.Value() != "" {
+		t.Error("passphrase left in the input")
+	}
+	if _, again := m.update(tea.KeyMsg{Type: tea.KeyEnter}); again != nil {
+		t.Error("Enter started another check while one is running")
+	}
+	return m, check
+}
+
+func TestUnlockChecksInBackground(t *testing.T) {
+	m, check := enterPassphrase(t, newLockedModel(t), "secret")
+	updated, _ := m.Update(check())
+	m = updated.(Model)
+	if m.locked || m.unlocking || m.unlockErr != "" {
+		t.Errorf("locked %v, checking %v, error %q after the right passphrase", m.locked, m.unlocking, m.unlockErr)
+	}
+}
+
+func TestUnlockWrongPassphrase(t *testing.T) {
+	m, check := enterPassphrase(t, newLockedModel(t), "guess")
+	updated, _ := m.Update(check())
+	m = updated.(Model)
+	if !m.locked || m.unlocking || m.unlockErr != "Wrong passphrase" {
+		t.Errorf("locked %v, checking %v, error %q after a wrong passphrase", m.locked, m.unlocking, m.unlockErr)
+	}
+}
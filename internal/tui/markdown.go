@@ -0,0 +1,354 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// HTML elements of the rendered Markdown, compiled once rather than for each
+// block of the preview
+var (
+	h1Regex          = regexp.MustCompile(`<h1[^>]*>(.*?)</h1>`)
+	h2Regex          = regexp.MustCompile(`<h2[^>]*>(.*?)</h2>`)
+	h3Regex          = regexp.MustCompile(`<h3[^>]*>(.*?)</h3>`)
+	boldRegex        = regexp.MustCompile(`<(?:strong|b)[^>]*>(.*?)</(?:strong|b)>`)
+	italicRegex      = regexp.MustCompile(`<(?:em|i)[^>]*>(.*?)</(?:em|i)>`)
+	codeRegex        = regexp.MustCompile(`<code[^>]*>(.*?)</code>`)
+	linkRegex        = regexp.MustCompile(`<a[^>]*href="([^"]*)"[^>]*>(.*?)</a>`)
+	tableRegex       = regexp.MustCompile(`(?s)<table>(.*?)</table>`)
+	tableRowRegex    = regexp.MustCompile(`(?s)<tr>(.*?)</tr>`)
+	tableCellRegex   = regexp.MustCompile(`(?s)<t([hd])([^>]*)>(.*?)</t[hd]>`)
+	strikeRegex      = regexp.MustCompile(`<(?:del|s)>(.*?)</(?:del|s)>`)
+	checkboxRegex    = regexp.MustCompile(`<input( checked="")? disabled="" type="checkbox"\s*/?>\s*`)
+	footnoteRefRegex = regexp.MustCompile(`<sup id="fnref:[^"]*"><a href="#fn:[^"]*"[^>]*>(.*?)</a></sup>`)
+	footnoteBackRef  = regexp.MustCompile(`(?:&#160;)?<a href="#fnref:[^"]*"[^>]*>.*?</a>`)
+	footnoteRegex    = regexp.MustCompile(`(?s)<li id="fn:([^"]*)">\s*(.*?)\s*</li>`)
+	termRegex        = regexp.MustCompile(`<dt>(.*?)</dt>`)
+	definitionRegex  = regexp.MustCompile(`(?s)<dd>(.*?)</dd>`)
+	listTagRegex     = regexp.MustCompile(`<(/?)(ul|ol|li)(\s[^>]*)?>`)
+	listStartRegex   = regexp.MustCompile(`start="(\d+)"`)
+	imgRegex         = regexp.MustCompile(`<img src="([^"]*)" alt="([^"]*)"[^>]*>`)
+	hrRegex          = regexp.MustCompile(`<hr\s*/?>\n?`)
+	tagRegex         = regexp.MustCompile("<[^>]*>")
+	blankLinesRegex  = regexp.MustCompile(`\n{3,}`)
+)
+
+// tablePlaceholder stands for a rendered table until tags are removed, so
+// that the borders aren't touched by the other replacements
+const tablePlaceholder = "\x00table%d\x00"
+
+// previewWidth returns the number of columns inside the preview pane
+func (m Model) previewWidth() int {
+	_, width := m.editorPanes()
+	// The pane has a column of padding on each side
+	return max(width-2, 1)
+}
+
+// renderMarkdown renders Markdown content as formatted text width columns wide
+func (m Model) renderMarkdown(content string, width int) string {
+	if content == "" {
+		return ""
+	}
+	content = sanitizeTerminal(content, m.config.View.RenderANSI)
+
+	// Convert markdown to HTML
+	var htmlBuf strings.Builder
+	if err := m.markdown.Convert([]byte(content), &htmlBuf); err != nil {
+		return fmt.Sprintf("Error rendering Markdown: %s", err)
+	}
+
+	// Goldmark decodes character references such as &#27; into the control
+	// characters removed above
+	rendered := sanitizeTerminal(htmlBuf.String(), m.config.View.RenderANSI)
+
+	// Tables are drawn first and put back once the other tags are gone
+	rendered, tables := renderTables(rendered)
+
+	// Apply styles for common HTML elements
+	// Define styles
+	h1Style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF0000")).MarginBottom(1)
+	h2Style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF5500")).MarginBottom(1)
+	h3Style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFAA00"))
+	boldStyle := lipgloss.NewStyle().Bold(true)
+	italicStyle := lipgloss.NewStyle().Italic(true)
+	strikeStyle := lipgloss.NewStyle().Strikethrough(true)
+	codeStyle := lipgloss.NewStyle().Background(lipgloss.Color("#333")).Foreground(lipgloss.Color("#FFF"))
+	ruleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	imageStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#3498db"))
+	linkStyle := lipgloss.NewStyle().Underline(true).Foreground(lipgloss.Color("#5fafff"))
+
+	// Replace HTML tags with formatted text
+	// Headings
+	rendered = h1Regex.ReplaceAllStringFunc(rendered, func(match string) string {
+		content := h1Regex.FindStringSubmatch(match)[1]
+		return h1Style.Render(content)
+	})
+
+	rendered = h2Regex.ReplaceAllStringFunc(rendered, func(match string) string {
+		content := h2Regex.FindStringSubmatch(match)[1]
+		return h2Style.Render(content)
+	})
+
+	rendered = h3Regex.ReplaceAllStringFunc(rendered, func(match string) string {
+		content := h3Regex.FindStringSubmatch(match)[1]
+		return h3Style.Render(content)
+	})
+
+	// Bold
+	rendered = boldRegex.ReplaceAllStringFunc(rendered, func(match string) string {
+		content := boldRegex.FindStringSubmatch(match)[1]
+		return boldStyle.Render(content)
+	})
+
+	// Italic
+	rendered = italicRegex.ReplaceAllStringFunc(rendered, func(match string) string {
+		content := italicRegex.FindStringSubmatch(match)[1]
+		return italicStyle.Render(content)
+	})
+
+	// Strikethrough
+	rendered = strikeRegex.ReplaceAllStringFunc(rendered, func(match string) string {
+		return strikeStyle.Render(strikeRegex.FindStringSubmatch(match)[1])
+	})
+
+	// Code
+	rendered = codeRegex.ReplaceAllStringFunc(rendered, func(match string) string {
+		content := codeRegex.FindStringSubmatch(match)[1]
+		return codeStyle.Render(content)
+	})
+
+	// Footnote references and the footnotes at the end of the note
+	rendered = footnoteRefRegex.ReplaceAllString(rendered, "[$1]")
+	rendered = footnoteBackRef.ReplaceAllString(rendered, "")
+	rendered = footnoteRegex.ReplaceAllStringFunc(rendered, func(match string) string {
+		parts := footnoteRegex.FindStringSubmatch(match)
+		return fmt.Sprintf("[%s] %s\n", parts[1], strings.TrimSpace(tagRegex.ReplaceAllString(parts[2], "")))
+	})
+
+	// Definition lists
+	rendered = termRegex.ReplaceAllStringFunc(rendered, func(match string) string {
+		return boldStyle.Render(termRegex.FindStringSubmatch(match)[1])
+	})
+	rendered = definitionRegex.ReplaceAllString(rendered, "  $1")
+
+	// Task list checkboxes
+	rendered = checkboxRegex.ReplaceAllStringFunc(rendered, func(match string) string {
+		if strings.Contains(match, "checked") {
+			return "☑ "
+		}
+		return "☐ "
+	})
+
+	// Lists, indented by nesting level
+	rendered = renderLists(rendered)
+
+	// Horizontal rules span the whole width
+	rendered = hrRegex.ReplaceAllString(rendered, ruleStyle.Render(strings.Repeat("─", width))+"\n\n")
+
+	// Paragraphs
+	rendered = strings.ReplaceAll(rendered, "<p>", "")
+	rendered = strings.ReplaceAll(rendered, "</p>", "\n\n")
+
+	// Inline images, before links since an image can be the text of a link
+	rendered = imgRegex.ReplaceAllStringFunc(rendered, func(match string) string {
+		parts := imgRegex.FindStringSubmatch(match)
+		return imageStyle.Render(imagePlaceholder(parts[2], parts[1]))
+	})
+
+	// Links
+	rendered = linkRegex.ReplaceAllStringFunc(rendered, func(match string) string {
+		parts := linkRegex.FindStringSubmatch(match)
+		url := parts[1]
+		// Bold text or an image within the link loses its style, which the
+		// link style would otherwise cut through rune by rune
+		text := linkStyle.Render(ansi.Strip(parts[2]))
+		// Terminals supporting hyperlinks open the URL on click, it
+		// doesn't need to be displayed
+		if m.hyperlinks {
+			return hyperlink(unescapeEntities(url), text)
+		}
+		if tagRegex.ReplaceAllString(parts[2], "") == url {
+			return text
+		}
+		return fmt.Sprintf("%s (%s)", text, url)
+	})
+
+	// Remove remaining HTML tags
+	rendered = tagRegex.ReplaceAllString(rendered, "")
+
+	// Clean up excessive line breaks
+	rendered = blankLinesRegex.ReplaceAllString(rendered, "\n\n")
+
+	// Decode HTML entities
+	rendered = unescapeEntities(rendered)
+
+	for i, t := range tables {
+		rendered = strings.Replace(rendered, fmt.Sprintf(tablePlaceholder, i), t, 1)
+	}
+	return rendered
+}
+
+// renderTables replaces the tables of html with placeholders and returns the
+// rendered tables, in order
+func renderTables(content string) (string, []string) {
+	tables := []string{}
+	content = tableRegex.ReplaceAllStringFunc(content, func(match string) string {
+		tables = append(tables, renderTable(tableRegex.FindStringSubmatch(match)[1]))
+		return fmt.Sprintf(tablePlaceholder, len(tables)-1)
+	})
+	return content, tables
+}
+
+// renderTable draws the rows of an HTML table with borders
+func renderTable(content string) string {
+	var headers []string
+	var rows [][]string
+	rightAligned := map[int]bool{}
+
+	for _, row := range tableRowRegex.FindAllStringSubmatch(content, -1) {
+		cells := []string{}
+		header := false
+		for i, cell := range tableCellRegex.FindAllStringSubmatch(row[1], -1) {
+			header = cell[1] == "h"
+			if strings.Contains(cell[2], "text-align:right") {
+				rightAligned[i] = true
+			}
+			cells = append(cells, unescapeEntities(tagRegex.ReplaceAllString(cell[3], "")))
+		}
+		if header && headers == nil {
+			headers = cells
+		} else {
+			rows = append(rows, cells)
+		}
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Padding(0, 1)
+	cellStyle := lipgloss.NewStyle().Padding(0, 1)
+	return table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))).
+		Headers(headers...).
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := cellStyle
+			if row == table.HeaderRow {
+				style = headerStyle
+			}
+			if rightAligned[col] {
+				style = style.Align(lipgloss.Right)
+			}
+			return style
+		}).
+		String()
+}
+
+// hyperlink makes text an OSC 8 hyperlink to url
+func hyperlink(url, text string) string {
+	return ansi.SetHyperlink(url) + text + ansi.ResetHyperlink()
+}
+
+// supportsHyperlinks guesses from the environment whether the terminal
+// handles OSC 8 hyperlinks, others could display the escape sequences
+func supportsHyperlinks() bool {
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper":
+		return true
+	}
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+	// VTE based terminals (GNOME Terminal, Tilix...) since 0.50
+	if version, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && version >= 5000 {
+		return true
+	}
+	term := os.Getenv("TERM")
+	return strings.HasPrefix(term, "xterm-kitty") || strings.HasPrefix(term, "foot") || term == "alacritty"
+}
+
+// imagePlaceholder describes an inline image, which the preview can't display
+func imagePlaceholder(alt, src string) string {
+	if alt == "" {
+		return fmt.Sprintf("🖼 (%s)", src)
+	}
+	return fmt.Sprintf("🖼 %s (%s)", alt, src)
+}
+
+// listLevel is a list being rendered and the number of its next item
+type listLevel struct {
+	ordered bool
+	number  int
+}
+
+// renderLists replaces list tags with bullets or numbers indented by the
+// nesting level of the list
+func renderLists(content string) string {
+	var out strings.Builder
+	var levels []listLevel
+	newLine := func() {
+		if out.Len() > 0 && !strings.HasSuffix(out.String(), "\n") {
+			out.WriteString("\n")
+		}
+	}
+
+	last := 0
+	for _, match := range listTagRegex.FindAllStringSubmatchIndex(content, -1) {
+		// Within lists only the text of the items is kept, not the line
+		// breaks between tags
+		text := content[last:match[0]]
+		if len(levels) > 0 {
+			text = strings.TrimSpace(text)
+		}
+		out.WriteString(text)
+		last = match[1]
+
+		closing := content[match[2]:match[3]] == "/"
+		switch tag := content[match[4]:match[5]]; {
+		case tag == "li" && closing:
+			newLine()
+		case tag == "li":
+			newLine()
+			if len(levels) == 0 {
+				continue
+			}
+			level := &levels[len(levels)-1]
+			out.WriteString(strings.Repeat("  ", len(levels)-1))
+			// Tasks show their checkbox instead of a bullet
+			rest := strings.TrimLeft(tagRegex.ReplaceAllString(content[last:min(last+16, len(content))], ""), " \n")
+			switch {
+			case strings.HasPrefix(rest, "☑") || strings.HasPrefix(rest, "☐"):
+			case level.ordered:
+				fmt.Fprintf(&out, "%d. ", level.number)
+			default:
+				out.WriteString("• ")
+			}
+			level.number++
+		case closing:
+			if len(levels) > 0 {
+				levels = levels[:len(levels)-1]
+			}
+			newLine()
+			if len(levels) == 0 {
+				out.WriteString("\n")
+			}
+		default:
+			newLine()
+			level := listLevel{ordered: tag == "ol", number: 1}
+			// Ordered lists can start at another number
+			if match[6] >= 0 {
+				if start := listStartRegex.FindStringSubmatch(content[match[6]:match[7]]); start != nil {
+					level.number, _ = strconv.Atoi(start[1])
+				}
+			}
+			levels = append(levels, level)
+		}
+	}
+	out.WriteString(content[last:])
+	return out.String()
+}
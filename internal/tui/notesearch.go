@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Highlighting of the matches, the current one standing out
+var (
+	matchStyle        = lipgloss.NewStyle().Background(lipgloss.Color("#5f5f00")).Foreground(lipgloss.Color("#ffffff"))
+	currentMatchStyle = lipgloss.NewStyle().Background(lipgloss.Color("#ffaf00")).Foreground(lipgloss.Color("#000000"))
+)
+
+// textMatch is an occurrence of the searched text, as byte offsets
+type textMatch struct {
+	start, end int
+}
+
+// findMatches returns the occurrences of query in content, ignoring case.
+// Occurrences don't overlap.
+func findMatches(content, query string) []textMatch {
+	if query == "" {
+		return nil
+	}
+	runes := utf8.RuneCountInString(query)
+	matches := []textMatch{}
+	for i := 0; i < len(content); {
+		// Case folding may change the byte length, the candidate is the
+		// same number of runes as the query
+		end := i
+		for n := 0; n < runes && end < len(content); n++ {
+			_, size := utf8.DecodeRuneInString(content[end:])
+			end += size
+		}
+		if strings.EqualFold(content[i:end], query) {
+			matches = append(matches, textMatch{i, end})
+			i = end
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(content[i:])
+		i += size
+	}
+	return matches
+}
+
+// startNoteSearch opens the search field of the note view
+func (m *Model) startNoteSearch() {
+	m.mode = ModeNoteSearch
+	m.noteSearch.Reset()
+	m.noteSearch.Focus()
+	m.noteMatches = nil
+	m.currentMatch = 0
+}
+
+// updateNoteSearchMode handles the search within the open note: matches are
+// highlighted while typing, Enter and the arrows move between them
+func (m Model) updateNoteSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeView
+		m.noteMatches = nil
+		m.statusMsg = ""
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter), msg.Type == tea.KeyDown:
+		m.stepMatch(1)
+		return m, nil
+
+	case msg.Type == tea.KeyUp:
+		m.stepMatch(-1)
+		return m, nil
+	}
+
+	query := m.noteSearch.Value()
+	m.noteSearch, cmd = m.noteSearch.Update(msg)
+	if m.noteSearch.Value() != query {
+		m.noteMatches = findMatches(sanitizeTerminal(m.selectedNote.Content, false), m.noteSearch.Value())
+		m.currentMatch = 0
+		m.showMatch()
+	}
+	return m, cmd
+}
+
+// stepMatch moves to the next (delta > 0) or previous (delta < 0) match,
+// wrapping around at the ends of the note
+func (m *Model) stepMatch(delta int) {
+	if len(m.noteMatches) == 0 {
+		return
+	}
+	m.currentMatch = (m.currentMatch + delta + len(m.noteMatches)) % len(m.noteMatches)
+	m.showMatch()
+}
+
+// showMatch scrolls the note to the current match and reports its position
+func (m *Model) showMatch() {
+	if m.noteSearch.Value() == "" {
+		m.statusMsg = ""
+		return
+	}
+	if len(m.noteMatches) == 0 {
+		m.statusMsg = "No match"
+		return
+	}
+	m.statusMsg = fmt.Sprintf("Match %d/%d", m.currentMatch+1, len(m.noteMatches))
+
+	// Keep the line of the match a few lines below the top of the view
+	m.showNoteBody()
+	m.viewport.SetYOffset(max(m.matchLine()-2, 0))
+}
+
+// matchLine returns the line of the note view holding the current match.
+// Word wrapping only depends on the text before the end of a line, so the
+// text up to the end of the match wraps as it does in the whole note.
+func (m Model) matchLine() int {
+	match := m.noteMatches[m.currentMatch]
+	title := lipgloss.Height(m.titleStyle().Render(sanitizeTerminal(m.selectedNote.Title, false)))
+	content := sanitizeTerminal(m.selectedNote.Content, false)
+	before := lipgloss.NewStyle().Width(m.readingWidth()).Render(content[:match.end])
+	// The content starts after the title and its top margin
+	return title + 1 + lipgloss.Height(before) - 1
+}
+
+// highlightMatches returns the content of the open note with the matches of
+// the search highlighted
+func (m Model) highlightMatches(content string) string {
+	if m.mode != ModeNoteSearch || len(m.noteMatches) == 0 {
+		return content
+	}
+	var b strings.Builder
+	last := 0
+	for i, match := range m.noteMatches {
+		b.WriteString(content[last:match.start])
+		style := matchStyle
+		if i == m.currentMatch {
+			style = currentMatchStyle
+		}
+		b.WriteString(style.Render(content[match.start:match.end]))
+		last = match.end
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// viewNoteSearch displays the note with the search field below it
+func (m Model) viewNoteSearch() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.viewport.View(),
+		m.noteSearch.View(),
+		m.statusBar(),
+		m.helpView(),
+	)
+}
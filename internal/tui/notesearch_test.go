@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFindMatches(t *testing.T) {
+	tests := []struct {
+		content string
+		query   string
+		want    []textMatch
+	}{
+		{"foo bar foo", "foo", []textMatch{{0, 3}, {8, 11}}},
+		{"Foo FOO fOo", "foo", []textMatch{{0, 3}, {4, 7}, {8, 11}}},
+		{"aaaa", "aa", []textMatch{{0, 2}, {2, 4}}},
+		{"aaa", "aa", []textMatch{{0, 2}}},
+		{"Été été", "ÉTÉ", []textMatch{{0, 5}, {6, 11}}},
+		{"日本語の本", "本", []textMatch{{3, 6}, {12, 15}}},
+		{"foo", "bar", []textMatch{}},
+		{"fo", "foo", []textMatch{}},
+		{"foo", "", nil},
+	}
+	for _, tt := range tests {
+		got := findMatches(tt.content, tt.query)
+		if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("findMatches(%q, %q) = %v, want %v", tt.content, tt.query, got, tt.want)
+		}
+		for _, match := range got {
+			if !strings.EqualFold(tt.content[match.start:match.end], tt.query) {
+				t.Errorf("match %v of %q is %q", match, tt.query, tt.content[match.start:match.end])
+			}
+		}
+	}
+}
+
+func TestNoteSearchSteps(t *testing.T) {
+	content := "needle\n" + strings.Repeat("hay\n\n", 100) + "Needle\n" + strings.Repeat("hay\n\n", 100) + "NEEDLE\n"
+	updated, _ := newTestModel(t, config.Default(), content).Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m := updated.(Model)
+	m.openNote(m.notesManager.Notes[0])
+	m.startNoteSearch()
+	m = typeText(m, "needle").(Model)
+
+	steps := []struct {
+		key    tea.KeyType
+		status string
+	}{
+		{tea.KeyEnter, "Match 2/3"},
+		{tea.KeyDown, "Match 3/3"},
+		{tea.KeyEnter, "Match 1/3"}, // Wraps around at the end
+		{tea.KeyUp, "Match 3/3"},
+		{tea.KeyUp, "Match 2/3"},
+	}
+	if m.statusMsg != "Match 1/3" {
+		t.Fatalf("status %q once typed, want the first match", m.statusMsg)
+	}
+	top := m.viewport.YOffset
+	offsets := []int{}
+	for _, step := range steps {
+		m, _ = press(m, tea.KeyMsg{Type: step.key})
+		if m.statusMsg != step.status {
+			t.Errorf("status %q after %v, want %q", m.statusMsg, step.key, step.status)
+		}
+		offsets = append(offsets, m.viewport.YOffset)
+	}
+	// The view follows the current match down the note
+	if !(offsets[0] > top && offsets[1] > offsets[0] && offsets[2] == top && offsets[4] == offsets[0]) {
+		t.Errorf("view scrolled to the lines %v from %d", offsets, top)
+	}
+
+	m = typeText(m, "s").(Model)
+	if m.statusMsg != "No match" || len(m.noteMatches) != 0 {
+		t.Errorf("status %q with %d match(es) for needles", m.statusMsg, len(m.noteMatches))
+	}
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != ModeView || m.noteMatches != nil || m.statusMsg != "" {
+		t.Errorf("Esc left mode %v with status %q", m.mode, m.statusMsg)
+	}
+}
@@ -0,0 +1,54 @@
+package tui
+
+import "fmt"
+
+// moveSelectedNote swaps the note under the cursor with the previous or next
+// note of the list. Pinned notes move among the pinned notes whatever the
+// order, the others in the manual order only. Pinned notes stay above the
+// others.
+func (m *Model) moveSelectedNote(delta int) {
+	items := m.noteList.Items()
+	index := m.noteList.Index()
+	if index < 0 || index >= len(items) {
+		return
+	}
+	selected, ok := items[index].(NoteItem)
+	if !ok {
+		return
+	}
+	if !selected.Pinned && !m.notesManager.ManualOrder {
+		m.statusMsg = `Notes can be moved once view.sort is set to "manual", pinned notes at any time`
+		return
+	}
+	if m.filter.search != "" {
+		m.statusMsg = "Search results are ordered by relevance, clear the search to move notes"
+		return
+	}
+
+	target := index + delta
+	if target < 0 || target >= len(items) {
+		return
+	}
+	other, ok := items[target].(NoteItem)
+	if !ok {
+		return
+	}
+	if selected.Pinned != other.Pinned {
+		m.statusMsg = "Pinned notes stay above the others"
+		return
+	}
+
+	swap := m.notesManager.SwapNotes
+	if selected.Pinned {
+		swap = m.notesManager.SwapPinned
+	}
+	if err := swap(selected.Note, other.Note); err != nil {
+		m.statusMsg = fmt.Sprintf("Error moving note: %s", storeError(err))
+		return
+	}
+	// The list may be filtered, only the two notes change places in it
+	items[index], items[target] = other, selected
+	m.noteList.SetItems(items)
+	m.noteList.Select(target)
+	m.statusMsg = ""
+}
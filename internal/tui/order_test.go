@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// listedIDs returns the IDs of the notes of the list in their order
+func listedIDs(m Model) []string {
+	ids := []string{}
+	for _, item := range m.noteList.Items() {
+		if note, ok := item.(NoteItem); ok {
+			ids = append(ids, note.ID)
+		}
+	}
+	return ids
+}
+
+func TestMoveNoteKeys(t *testing.T) {
+	cfg := config.Default()
+	cfg.View.Sort = config.NoteSortManual
+	m := newTestModel(t, cfg, "a", "b", "c")
+	before := listedIDs(m)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+	m = updated.(Model)
+	want := []string{before[1], before[0], before[2]}
+	if got := listedIDs(m); !slices.Equal(got, want) {
+		t.Fatalf("list %v after moving the first note down, want %v", got, want)
+	}
+	if m.noteList.Index() != 1 {
+		t.Errorf("cursor at %d, want on the moved note at 1", m.noteList.Index())
+	}
+	stored := []string{}
+	for _, note := range m.notesManager.Notes {
+		stored = append(stored, note.ID)
+	}
+	if !slices.Equal(stored, want) {
+		t.Errorf("store order %v, want %v", stored, want)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}})
+	m = updated.(Model)
+	if got := listedIDs(m); !slices.Equal(got, before) {
+		t.Errorf("list %v after moving it back up, want %v", got, before)
+	}
+}
+
+func TestMoveNoteKeysSortedByDate(t *testing.T) {
+	m := newTestModel(t, config.Default(), "a", "b")
+	before := listedIDs(m)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+	m = updated.(Model)
+	if got := listedIDs(m); !slices.Equal(got, before) {
+		t.Errorf("list %v changed while sorted by date, want %v", got, before)
+	}
+	if m.statusMsg == "" {
+		t.Error("no message telling how to move notes")
+	}
+}
@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// bulletMarkers are the list markers of text copied from web pages and word
+// processors, turned into Markdown bullets by the smart paste
+var bulletMarkers = []string{"•", "◦", "▪", "‣", "–", "—"}
+
+// pasteUndo holds the editor as it was before a smart paste
+type pasteUndo struct {
+	lines    []string
+	row, col int
+	after    string // Content right after the paste, undoing is refused once it changed
+}
+
+// convertPaste turns pasted text into Markdown: Windows line endings are
+// collapsed, tab separated rows become a table and lines starting with a
+// bullet character become "- " items. It also describes what was converted,
+// empty when the text is pasted as is.
+func convertPaste(text string) (string, string) {
+	changes := []string{}
+	if strings.Contains(text, "\r") {
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+		text = strings.ReplaceAll(text, "\r", "\n")
+		changes = append(changes, "collapsed Windows line endings")
+	}
+
+	// Trailing line breaks, such as the one ending a spreadsheet selection,
+	// are kept out of the conversion
+	body := strings.TrimRight(text, "\n")
+	trailing := text[len(body):]
+	lines := strings.Split(body, "\n")
+
+	if table, ok := tsvTable(lines); ok {
+		changes = append(changes, fmt.Sprintf("converted %d rows to table", len(lines)))
+		return strings.Join(table, "\n") + trailing, strings.Join(changes, ", ")
+	}
+
+	bullets := 0
+	for i, line := range lines {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		rest := line[len(indent):]
+		if item, ok := bulletItem(rest); ok {
+			lines[i] = indent + "- " + item
+			bullets++
+		}
+	}
+	if bullets > 0 {
+		changes = append(changes, fmt.Sprintf("converted %d lines to bullets", bullets))
+	}
+	return strings.Join(lines, "\n") + trailing, strings.Join(changes, ", ")
+}
+
+// bulletItem returns the text of a line starting with a bullet marker.
+// Dashes only count when a space follows, since they also open dialogue.
+func bulletItem(line string) (string, bool) {
+	for _, marker := range bulletMarkers {
+		item, ok := strings.CutPrefix(line, marker)
+		if !ok {
+			continue
+		}
+		spaced := item == "" || item[0] == ' ' || item[0] == '\t'
+		if !spaced && (marker == "–" || marker == "—") {
+			return "", false
+		}
+		return strings.TrimLeft(item, " \t"), true
+	}
+	return "", false
+}
+
+// tsvTable renders tab separated rows as a Markdown table, the first row as
+// its header. It reports false unless there are at least two rows and every
+// one of them holds a tab.
+func tsvTable(lines []string) ([]string, bool) {
+	if len(lines) < 2 {
+		return nil, false
+	}
+	rows := [][]string{}
+	columns := 0
+	for _, line := range lines {
+		if !strings.Contains(line, "\t") {
+			return nil, false
+		}
+		cells := strings.Split(line, "\t")
+		for i, cell := range cells {
+			cells[i] = strings.ReplaceAll(strings.TrimSpace(cell), "|", `\|`)
+		}
+		rows = append(rows, cells)
+		columns = max(columns, len(cells))
+	}
+
+	row := func(cells []string) string {
+		cells = append(cells, make([]string, columns-len(cells))...)
+		return "| " + strings.Join(cells, " | ") + " |"
+	}
+	table := []string{row(rows[0]), row(slices.Repeat([]string{"---"}, columns))}
+	for _, cells := range rows[1:] {
+		table = append(table, row(cells))
+	}
+	return table, true
+}
+
+// smartPaste inserts the clipboard at the cursor of the content editor,
+// converted to Markdown, as a single change that undoPaste reverts
+func (m *Model) smartPaste() {
+	text, err := m.clipboard.ReadAll()
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Unable to read the clipboard: %s", err)
+		return
+	}
+	if text == "" {
+		m.statusMsg = "The clipboard is empty"
+		return
+	}
+	converted, summary := convertPaste(text)
+	converted, _ = expandTabs(converted, m.config.Editor.TabWidth)
+
+	lines, row, col := m.editorLines()
+	before := slices.Clone(lines)
+	line := []rune(lines[row])
+	col = min(col, len(line))
+	pasted := strings.Split(string(line[:col])+converted, "\n")
+	endRow := row + len(pasted) - 1
+	endCol := utf8.RuneCountInString(pasted[len(pasted)-1])
+	pasted[len(pasted)-1] += string(line[col:])
+	lines = slices.Replace(lines, row, row+1, pasted...)
+	if !m.setEditorLines(lines, endRow, endCol) {
+		return
+	}
+
+	m.pasteUndo = &pasteUndo{lines: before, row: row, col: col, after: m.textArea.Value()}
+	if summary == "" {
+		summary = "nothing to convert, pasted as is"
+	}
+	m.statusMsg = fmt.Sprintf("%s%s — press %s to undo", strings.ToUpper(summary[:1]), summary[1:], m.keys.UndoPaste.Help().Key)
+	if endRow == row {
+		m.offerImageImport(row, col, endCol)
+	}
+}
+
+// undoPaste puts the editor back as it was before the last smart paste, as
+// long as nothing was changed since
+func (m *Model) undoPaste() {
+	switch {
+	case m.pasteUndo == nil:
+		m.statusMsg = "No paste to undo"
+	case m.textArea.Value() != m.pasteUndo.after:
+		m.statusMsg = "The note changed since the paste, it can't be undone"
+	case m.setEditorLines(m.pasteUndo.lines, m.pasteUndo.row, m.pasteUndo.col):
+		m.pasteUndo = nil
+		m.statusMsg = "Paste undone"
+	}
+}
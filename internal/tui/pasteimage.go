@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pastedImage is a pasted path to an image file, waiting for the user to
+// choose whether to import it
+type pastedImage struct {
+	file     string // Image file the path names
+	row      int    // Line of the pasted path
+	from, to int    // Columns of the pasted path in its line
+	after    string // Content right after the paste, the path is kept once it changed
+}
+
+// offerImageImport asks whether to import the image named by the text pasted
+// on row between columns from and to. Anything but a single line naming an
+// existing image file is left alone, so that other pastes go on unnoticed.
+func (m *Model) offerImageImport(row, from, to int) {
+	m.pastedImage = nil
+	if m.mode != ModeEdit || m.config.Accessibility.RequireAltText {
+		return
+	}
+	lines, _, _ := m.editorLines()
+	if row >= len(lines) {
+		return
+	}
+	line := []rune(lines[row])
+	if from < 0 || from >= to || to > len(line) {
+		return
+	}
+	file, ok := notes.PastedImagePath(string(line[from:to]))
+	if !ok {
+		return
+	}
+
+	m.pastedImage = &pastedImage{file: file, row: row, from: from, to: to, after: m.textArea.Value()}
+	m.statusMsg = fmt.Sprintf("Import %s into the note and link it? y/n", filepath.Base(file))
+}
+
+// answerImageImport handles the key pressed once an image path was pasted: y
+// imports the image, n or esc keeps the path as text, and any other key keeps
+// it too before being handled as usual. It reports whether the key was used.
+func (m *Model) answerImageImport(msg tea.KeyMsg) bool {
+	offer := m.pastedImage
+	m.pastedImage = nil
+	switch {
+	case msg.String() == "y":
+		m.importPastedImage(offer)
+		return true
+	case msg.String() == "n" || key.Matches(msg, m.keys.Back):
+		m.statusMsg = "Path kept as text"
+		return true
+	}
+	m.statusMsg = ""
+	return false
+}
+
+// importPastedImage imports the image of a pasted path into the note being
+// edited and replaces the path by a Markdown reference to the stored copy
+func (m *Model) importPastedImage(offer *pastedImage) {
+	if m.textArea.Value() != offer.after {
+		m.statusMsg = "The note changed since the paste, the path is kept as text"
+		return
+	}
+	stored, err := m.notesManager.ImportImage(m.selectedNote.ID, offer.file, "", "")
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Error importing the image: %s", storeError(err))
+		return
+	}
+
+	lines, _, _ := m.editorLines()
+	line := []rune(lines[offer.row])
+	ref := imageReference(offer.file, m.notesManager.GetImageFullPath(stored))
+	lines[offer.row] = string(line[:offer.from]) + ref + string(line[offer.to:])
+	if !m.setEditorLines(lines, offer.row, offer.from+len([]rune(ref))) {
+		return
+	}
+	m.statusMsg = "Image imported and linked"
+}
+
+// imageReference returns the Markdown image linking to a stored image, its
+// alt text being the name of the pasted file
+func imageReference(file, stored string) string {
+	alt := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	alt = strings.NewReplacer("[", `\[`, "]", `\]`).Replace(alt)
+	if strings.ContainsAny(stored, " ()") {
+		stored = "<" + stored + ">"
+	}
+	return fmt.Sprintf("![%s](%s)", alt, stored)
+}
@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// previewDelay is how long typing pauses before the preview renders the
+// changes
+const previewDelay = 100 * time.Millisecond
+
+// previewTickMsg renders the preview once typing paused for previewDelay
+type previewTickMsg struct {
+	seq int
+}
+
+// previewMsg carries a preview rendered in the background
+type previewMsg struct {
+	key      [sha256.Size]byte
+	rendered string
+}
+
+// schedulePreview renders the edited content in the background when it
+// changed since the last rendering, so that a slow rendering never delays
+// the keys. It waits for typing to pause, each change restarting the delay,
+// and renders again only the blocks of the content that changed. A single
+// rendering runs at a time, the content being checked again once it is done.
+func (m *Model) schedulePreview() tea.Cmd {
+	if !m.showPreview || (m.mode != ModeEdit && m.mode != ModeNew) {
+		return nil
+	}
+
+	content := m.textArea.Value()
+	limit := m.config.Markdown.PreviewLimit
+	m.previewSkip = limit > 0 && len(content) > limit && !m.previewForce
+	if m.previewSkip {
+		return nil
+	}
+
+	// Embedded notes are copied since the notes may change while the
+	// preview renders, and the rendering of the note embedding them
+	seen := []string{}
+	if m.mode == ModeEdit && m.selectedNote != nil {
+		seen = append(seen, m.selectedNote.ID)
+	}
+	lookup := m.embedLookup(content)
+
+	// Horizontal rules depend on the width, a resize renders again, and so
+	// does a change of an embedded note
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d\x00%s", m.width, content)
+	for _, target := range slices.Sorted(maps.Keys(lookup)) {
+		fmt.Fprintf(hash, "\x00%s\x00%s\x00%s", target, lookup[target].title, lookup[target].content)
+	}
+	var key [sha256.Size]byte
+	hash.Sum(key[:0])
+	if key == m.previewKey {
+		return nil
+	}
+	if key != m.previewWant {
+		m.previewWant = key
+		m.previewIdle = false
+		m.previewSeq++
+		seq := m.previewSeq
+		return tea.Tick(previewDelay, func(time.Time) tea.Msg {
+			return previewTickMsg{seq: seq}
+		})
+	}
+	if !m.previewIdle || m.previewBusy {
+		return nil
+	}
+
+	m.previewBusy = true
+	model := *m
+	cache := m.previewCache
+	return func() tea.Msg {
+		cache.start()
+		rendered := expandEmbeds(content, model.previewWidth(), lookup, seen, 0, func(text string, width int) string {
+			// Paragraphs end with a blank line, a single one is kept
+			// before an embedded note
+			return strings.TrimRight(cache.render(text, width, model.renderMarkdown), "\n") + "\n"
+		})
+		cache.finish()
+		return previewMsg{key: key, rendered: rendered}
+	}
+}
+
+// previewView returns the rendered preview, or why it isn't rendered
+func (m Model) previewView() string {
+	if m.previewSkip {
+		return lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#ff7700")).
+			Render(fmt.Sprintf("Preview disabled for large note (press %s to force)", m.keys.ForcePreview.Help().Key))
+	}
+	return m.previewOut
+}
@@ -0,0 +1,316 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// minifiedJSON returns a JSON document of about size bytes on a single line,
+// as pasting the output of an API would give
+func minifiedJSON(size int) string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i := 0; b.Len() < size; i++ {
+		fmt.Fprintf(&b, `"key_%d":[%d,"*_[x](y)_*",{"a":"<b>c</b>","d":null}],`, i, i)
+	}
+	b.WriteString(`"end":true}`)
+	return b.String()
+}
+
+// previewModel returns a model editing content with the preview shown
+func previewModel(tb testing.TB, cfg config.Config, content string) Model {
+	m := NewModel(openManager(tb.TempDir(), cfg), cfg)
+	m.mode = ModeEdit
+	m.showPreview = true
+	m.width, m.height = 120, 40
+	m.textArea.SetValue(content)
+	return m
+}
+
+func TestPreviewSkipsLargeNote(t *testing.T) {
+	cfg := config.Default()
+	m := previewModel(t, cfg, minifiedJSON(2<<20))
+	if cmd := m.schedulePreview(); cmd != nil || !m.previewSkip {
+		t.Fatalf("preview of a note over the limit scheduled")
+	}
+	if view := m.previewView(); !strings.Contains(view, "Preview disabled for large note") {
+		t.Errorf("preview shows %q, want why it isn't rendered", view)
+	}
+
+	m.previewForce = true
+	if cmd := m.schedulePreview(); cmd == nil || m.previewSkip {
+		t.Error("forced preview not scheduled")
+	}
+
+	small := previewModel(t, cfg, "# Title")
+	if cmd := small.schedulePreview(); cmd == nil || small.previewSkip {
+		t.Error("preview of a small note not scheduled")
+	}
+}
+
+// BenchmarkRenderMinifiedJSON renders 256 KB of minified JSON full of
+// emphasis delimiters, which goldmark takes a time growing faster than the
+// size to parse: 2 MB take tens of seconds. The preview limit keeps such
+// content from being rendered on each key.
+func BenchmarkRenderMinifiedJSON(b *testing.B) {
+	m := previewModel(b, config.Default(), "")
+	content := minifiedJSON(256 << 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.renderMarkdown(content, 80)
+	}
+}
+
+// BenchmarkPreviewKeyOverLimit measures what the preview costs each key in a
+// note holding 2 MB of minified JSON
+func BenchmarkPreviewKeyOverLimit(b *testing.B) {
+	m := previewModel(b, config.Default(), minifiedJSON(2<<20))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.schedulePreview()
+	}
+}
+
+// renderWith renders content 40 columns wide with the given Markdown
+// extensions, without the styles
+func renderWith(t *testing.T, extensions []string, content string) string {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Markdown.Extensions = extensions
+	m := previewModel(t, cfg, "")
+	return ansi.Strip(m.renderMarkdown(content, 40))
+}
+
+func TestRenderMarkdownExtensions(t *testing.T) {
+	const table = "| a | b |\n|---|---|\n| 1 | 2 |\n"
+	const tasks = "- [x] done\n- [ ] todo\n"
+	tests := []struct {
+		name       string
+		extensions []string
+		content    string
+		want       []string
+		unwanted   []string
+	}{
+		{"table as text without extensions", nil, table, []string{"| a | b |", "|---|---|"}, []string{"│"}},
+		{"table with gfm", []string{config.ExtensionGFM}, table, []string{"│ a │ b │", "│ 1 │ 2 │"}, []string{"|---|"}},
+		{"table alone", []string{config.ExtensionTable}, table, []string{"│ a │ b │"}, []string{"|---|"}},
+		{"task list as text without extensions", nil, tasks, []string{"[x] done", "[ ] todo"}, []string{"☑", "☐"}},
+		{"task list with gfm", []string{config.ExtensionGFM}, tasks, []string{"☑ done", "☐ todo"}, []string{"[x]", "[ ]"}},
+		{"task list alone", []string{config.ExtensionTaskList}, tasks, []string{"☑ done", "☐ todo"}, []string{"[x]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderWith(t, tt.extensions, tt.content)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("%q missing from:\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("%q found in:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+// styledModel returns a model rendering the styles as an ANSI terminal does,
+// the tests having no terminal to detect
+func styledModel(t *testing.T) Model {
+	t.Helper()
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+	return previewModel(t, config.Default(), "")
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		width    int      // 40 when zero
+		links    bool     // Terminal supporting hyperlinks
+		want     []string // In the text without the styles
+		unwanted []string
+		styles   []string // Escape sequences of the styled output
+	}{
+		{
+			name:     "strikethrough",
+			content:  "~~old~~ new",
+			want:     []string{"old new"},
+			unwanted: []string{"~"},
+			styles:   []string{"\x1b[9mo"},
+		},
+		{
+			name:    "strikethrough within emphasis",
+			content: "**~~gone~~** *~~too~~*",
+			want:    []string{"gone too"},
+			styles:  []string{"\x1b[1m\x1b[9mg", "\x1b[3m\x1b[9mt"},
+		},
+		{
+			name:     "single tildes",
+			content:  "~a~ and ~~~",
+			want:     []string{"a and ~~~"},
+			unwanted: []string{"~a~"},
+		},
+		{
+			name:     "horizontal rule",
+			content:  "above\n\n---\n\nbelow",
+			want:     []string{"above\n\n" + strings.Repeat("─", 40) + "\n\nbelow"},
+			unwanted: []string{strings.Repeat("─", 41), "<hr"},
+		},
+		{
+			name:     "horizontal rule of a narrow preview",
+			content:  "***\n___",
+			width:    12,
+			want:     []string{strings.Repeat("─", 12) + "\n\n" + strings.Repeat("─", 12)},
+			unwanted: []string{strings.Repeat("─", 13)},
+		},
+		{
+			name:    "bullet list",
+			content: "- a\n- b\n",
+			want:    []string{"• a\n• b\n"},
+		},
+		{
+			name:    "ordered list",
+			content: "1. a\n1. b\n1. c\n",
+			want:    []string{"1. a\n2. b\n3. c\n"},
+		},
+		{
+			name:    "nested lists",
+			content: "- a\n  - b\n    - c\n  - d\n- e\n",
+			want:    []string{"• a\n  • b\n    • c\n  • d\n• e\n"},
+		},
+		{
+			name:    "mixed lists",
+			content: "1. a\n   - b\n   - c\n2. d\n   1. e\n   2. f\n",
+			want:    []string{"1. a\n  • b\n  • c\n2. d\n  1. e\n  2. f\n"},
+		},
+		{
+			name:     "loose list keeping its items apart",
+			content:  "- a\n\n- b\n",
+			want:     []string{"• a\n\n• b"},
+			unwanted: []string{"<li>", "<ul>"},
+		},
+		{
+			name:    "lists apart",
+			content: "- a\n\ntext\n\n1. b\n",
+			want:    []string{"• a\n\ntext\n\n1. b\n"},
+		},
+		{
+			name:    "ordered list start",
+			content: "3. a\n4. b\n",
+			want:    []string{"3. a\n4. b\n"},
+		},
+		{
+			name:    "ordered list numbered from its first item only",
+			content: "7. a\n1. b\n1. c\n",
+			want:    []string{"7. a\n8. b\n9. c\n"},
+		},
+		{
+			name:    "ordered list start of zero",
+			content: "0. a\n1. b\n",
+			want:    []string{"0. a\n1. b\n"},
+		},
+		{
+			// A list starting at another number than 1 can't interrupt the
+			// text of an item
+			name:    "nested ordered list start",
+			content: "- a\n\n  5. b\n  6. c\n",
+			want:    []string{"  5. b\n  6. c\n"},
+		},
+		{
+			name:     "image",
+			content:  "![A cat](cat.png)",
+			want:     []string{"🖼 A cat (cat.png)"},
+			unwanted: []string{"!["},
+			styles:   []string{"\x1b[94m🖼 A cat (cat.png)\x1b[0m"},
+		},
+		{
+			name:    "image without alternative text",
+			content: "![](cat.png)",
+			want:    []string{"🖼 (cat.png)"},
+		},
+		{
+			name:    "image within text",
+			content: "before ![*big* cat](<my cat.png>) after",
+			want:    []string{"before 🖼 big cat (my%20cat.png) after"},
+		},
+		{
+			name:    "image reference",
+			content: "![dog][d]\n\n[d]: dog.png\n",
+			want:    []string{"🖼 dog (dog.png)"},
+		},
+		{
+			name:    "link",
+			content: "[site](https://example.com/a?b=1&c=2)",
+			want:    []string{"site (https://example.com/a?b=1&c=2)"},
+			styles:  []string{"\x1b[4;94;4ms\x1b[0m"},
+		},
+		{
+			name:     "autolink shown once",
+			content:  "<https://example.com>",
+			want:     []string{"https://example.com"},
+			unwanted: []string{"(https://example.com)"},
+		},
+		{
+			name:     "hyperlink",
+			content:  "[site](https://example.com/a?b=1&c=2) and <https://example.org>",
+			links:    true,
+			want:     []string{"site and https://example.org"},
+			unwanted: []string{"(https://"},
+			styles: []string{
+				"\x1b]8;;https://example.com/a?b=1&c=2\a\x1b[4;94;4ms",
+				"e\x1b[0m\x1b]8;;\a and \x1b]8;;https://example.org\a",
+			},
+		},
+		{
+			name:     "styled link text",
+			content:  "[**bold** `code`](https://example.com) [![logo](logo.png)](https://example.com)",
+			want:     []string{"bold code (https://example.com) 🖼 logo (logo.png) (https://example.com)"},
+			unwanted: []string{"[1m", "[94m"},
+		},
+		{
+			name:     "styled hyperlink text",
+			content:  "[![logo](logo.png)](https://example.com)",
+			links:    true,
+			want:     []string{"🖼 logo (logo.png)"},
+			unwanted: []string{"[94m", "(https://"},
+			styles:   []string{"\x1b]8;;https://example.com\a\x1b[4;94;4m🖼"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := styledModel(t)
+			m.hyperlinks = tt.links
+			width := tt.width
+			if width == 0 {
+				width = 40
+			}
+			styled := m.renderMarkdown(tt.content, width)
+			text := ansi.Strip(styled)
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("%q missing from:\n%s", want, text)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(text, unwanted) {
+					t.Errorf("%q found in:\n%s", unwanted, text)
+				}
+			}
+			for _, style := range tt.styles {
+				if !strings.Contains(styled, style) {
+					t.Errorf("%q missing from %q", style, styled)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Lines the cutting of the preview in blocks depends on
+var (
+	listItemRegex    = regexp.MustCompile(`^ {0,3}([-*+]|\d{1,9}[.)])([ \t]|$)`)
+	linkedBlockRegex = regexp.MustCompile(`(?m)^ {0,3}(\[[^\]]+\]:|: )`)
+)
+
+// previewBlocks cuts Markdown into its top-level blocks, at blank lines, so
+// that the preview renders again only the blocks that changed. Blank lines
+// within code fences, before an indented line, which continues a list item
+// or is code, and between the items of a loose list don't cut. Reference
+// links and footnotes are defined in one block and used in others, and the
+// terms of a definition list go on past blank lines: content holding any of
+// them is a single block.
+func previewBlocks(content string) []string {
+	// The regular expressions only run on the lines that may match, as the
+	// blocks are cut on each key
+	if (strings.Contains(content, "]:") || strings.Contains(content, ": ")) && linkedBlockRegex.MatchString(content) {
+		return []string{content}
+	}
+
+	blocks := []string{}
+	block := []string{}
+	fence := ""
+	blank := false  // A blank line ended the text of the block
+	inList := false // The block ends with a list
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence == "" {
+			if trimmed == "" {
+				blank = len(block) > 0
+				block = append(block, line)
+				continue
+			}
+			indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+			item := strings.ContainsAny(trimmed[:1], "-*+0123456789") && listItemRegex.MatchString(line)
+			if blank && !indented && !(item && inList) {
+				blocks = append(blocks, strings.Join(block, "\n"))
+				block = []string{}
+			}
+			if !indented {
+				inList = item
+			}
+		}
+		blank = false
+
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+		case fence != "" && strings.HasPrefix(trimmed, fence):
+			fence = ""
+		}
+		block = append(block, line)
+	}
+	return append(blocks, strings.Join(block, "\n"))
+}
+
+// blockKey identifies the rendering of a block at a width
+type blockKey [sha256.Size]byte
+
+// blockCache keeps the rendered blocks of the preview. Each preview keeps
+// the blocks it used and drops the others, so that the cache follows the
+// note. Previews are rendered one at a time, in the background, and only
+// them use the cache.
+type blockCache struct {
+	previous map[blockKey]string // Blocks of the last preview
+	current  map[blockKey]string // Blocks of the preview being rendered
+}
+
+// newBlockCache creates an empty cache
+func newBlockCache() *blockCache {
+	return &blockCache{previous: map[blockKey]string{}}
+}
+
+// start begins the rendering of a preview
+func (c *blockCache) start() {
+	c.current = map[blockKey]string{}
+}
+
+// finish ends the rendering of a preview, whose blocks replace the cached
+// ones
+func (c *blockCache) finish() {
+	c.previous = c.current
+	c.current = nil
+}
+
+// render renders content width columns wide, rendering with render only the
+// blocks missing from the cache. The blocks are put together as goldmark
+// puts their HTML together, the blank lines between them collapsed as the
+// rendering of the whole content would.
+func (c *blockCache) render(content string, width int, render func(content string, width int) string) string {
+	var rendered strings.Builder
+	for _, block := range previewBlocks(content) {
+		key := blockKey(sha256.Sum256(fmt.Appendf(nil, "%d\x00%s", width, block)))
+		out, ok := c.current[key]
+		if !ok {
+			out, ok = c.previous[key]
+		}
+		if !ok {
+			out = render(block, width)
+		}
+		c.current[key] = out
+		rendered.WriteString(out)
+	}
+	return blankLinesRegex.ReplaceAllString(rendered.String(), "\n\n")
+}
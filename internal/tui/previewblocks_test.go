@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// largeNote returns a note of about the given number of lines mixing
+// headings, paragraphs, lists, quotes and code blocks
+func largeNote(lines int) string {
+	var b strings.Builder
+	for i := 0; lines > 0; i++ {
+		fmt.Fprintf(&b, "## Section %d\n\n", i)
+		fmt.Fprintf(&b, "Paragraph %d with **bold**, `code` and a [link](https://example.com/%d).\nIt goes on over a second line.\n\n", i, i)
+		fmt.Fprintf(&b, "- item %d\n- item with *emphasis*\n  continued\n\n", i)
+		fmt.Fprintf(&b, "> Quote %d\n\n", i)
+		fmt.Fprintf(&b, "```go\nfunc f%d() int {\n\treturn %d\n}\n```\n\n", i, i)
+		lines -= 19
+	}
+	return b.String()
+}
+
+// typeInNote returns content with a character added to the paragraph of its
+// middle section, as typing in it would
+func typeInNote(content string, n int) string {
+	paragraph := strings.Index(content, fmt.Sprintf("Paragraph %d ", strings.Count(content, "## Section")/2))
+	end := paragraph + strings.Index(content[paragraph:], "\n\n")
+	return content[:end] + strings.Repeat("x", n) + content[end:]
+}
+
+func TestPreviewBlocks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"paragraphs", "one\n\ntwo\n\nthree", 3},
+		{"code fence with blank lines", "```\na\n\nb\n```\n\nafter", 2},
+		{"loose list", "- a\n\n- b\n\nafter", 2},
+		{"indented continuation", "- a\n\n  more of a\n\nafter", 2},
+		{"reference links", "see [x]\n\n[x]: https://example.com", 1},
+		{"footnotes", "text[^1]\n\n[^1]: note", 1},
+	}
+	for _, tt := range tests {
+		if got := previewBlocks(tt.content); len(got) != tt.want {
+			t.Errorf("%s: %d block(s) %q, want %d", tt.name, len(got), got, tt.want)
+		}
+	}
+}
+
+func TestBlockCacheRendersChangedBlocks(t *testing.T) {
+	m := newTestModel(t, config.Default())
+	content := largeNote(10000)
+	rendered := 0
+	render := func(content string, width int) string {
+		rendered++
+		return m.renderMarkdown(content, width)
+	}
+	cache := newBlockCache()
+	cache.start()
+	full := cache.render(content, 80, render)
+	cache.finish()
+	blocks := rendered
+	if blocks < 1000 {
+		t.Fatalf("note cut in %d block(s), want thousands", blocks)
+	}
+	if whole := blankLinesRegex.ReplaceAllString(m.renderMarkdown(content, 80), "\n\n"); full != whole {
+		t.Error("blocks put together differ from the note rendered whole")
+	}
+
+	rendered = 0
+	cache.start()
+	cache.render(typeInNote(content, 1), 80, render)
+	cache.finish()
+	if rendered != 1 {
+		t.Errorf("%d block(s) rendered again after typing a character, want 1 of %d", rendered, blocks)
+	}
+}
+
+// BenchmarkPreviewWhole renders a note of 10,000 lines whole, as the preview
+// did on each key
+func BenchmarkPreviewWhole(b *testing.B) {
+	m := NewModel(openManager(b.TempDir(), config.Default()), config.Default())
+	content := largeNote(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.renderMarkdown(typeInNote(content, i%2), 80)
+	}
+}
+
+// BenchmarkPreviewIncremental renders a note of 10,000 lines after a key is
+// typed in it, only the changed block being rendered again
+func BenchmarkPreviewIncremental(b *testing.B) {
+	m := NewModel(openManager(b.TempDir(), config.Default()), config.Default())
+	content := largeNote(10000)
+	cache := newBlockCache()
+	cache.start()
+	cache.render(content, 80, m.renderMarkdown)
+	cache.finish()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.start()
+		cache.render(typeInNote(content, i%2), 80, m.renderMarkdown)
+		cache.finish()
+	}
+}
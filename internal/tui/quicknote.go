@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// openQuickNote shows the quick note prompt over the current mode, which it
+// returns to once the note is captured
+func (m *Model) openQuickNote() {
+	m.quickFrom = m.mode
+	m.mode = ModeQuickNote
+	m.quickInput.Reset()
+	m.quickInput.Focus()
+}
+
+// closeQuickNote goes back to the mode the quick note was opened from, as
+// it was left
+func (m *Model) closeQuickNote() {
+	m.quickInput.Blur()
+	m.mode = m.quickFrom
+}
+
+// updateQuickNoteMode handles the quick note prompt
+func (m Model) updateQuickNoteMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.closeQuickNote()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		m.closeQuickNote()
+		title, content := notes.QuickNote(m.quickInput.Value())
+		if title == "" {
+			m.statusMsg = "Nothing to capture"
+			return m, nil
+		}
+
+		duplicate := m.notesManager.TitleExists(title)
+		note := m.notesManager.CreateNote(title)
+		note.Content = content
+		if err := m.notesManager.UpdateNote(note); err != nil {
+			m.statusMsg = fmt.Sprintf("Error saving note: %s", storeError(err))
+			return m, nil
+		}
+		m.statusMsg = fmt.Sprintf("Quick note saved: %s", title)
+		if duplicate {
+			m.statusMsg = fmt.Sprintf("Quick note saved, another note already has this title: %s", title)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.quickInput, cmd = m.quickInput.Update(msg)
+	return m, cmd
+}
+
+// viewQuickNote displays the quick note prompt in place of the status and
+// help lines of the mode it was opened from
+func (m Model) viewQuickNote() string {
+	behind := m
+	behind.mode = m.quickFrom
+	lines := strings.Split(behind.View(), "\n")
+	lines = lines[:max(len(lines)-statusBarHeight-helpHeight, 0)]
+
+	promptStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFA500"))
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		strings.Join(lines, "\n"),
+		promptStyle.Render("Quick note ")+m.quickInput.View(),
+		m.helpView(),
+	)
+}
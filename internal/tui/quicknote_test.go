@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestQuickNoteFromEditor(t *testing.T) {
+	var model tea.Model = dirtyEditor(t)
+	editing := model.(Model).selectedNote
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if mode := model.(Model).mode; mode != ModeQuickNote {
+		t.Fatalf("mode %v after ctrl+g, want the quick note prompt", mode)
+	}
+	model = typeText(model, "  Call   the garage ")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m := model.(Model)
+
+	// The editor is back as it was left
+	if m.mode != ModeEdit || m.selectedNote != editing || m.textArea.Value() != "abcxyz" || !m.unsavedEdits() {
+		t.Errorf("mode %v editing %q after the quick note, want the unsaved editor back", m.mode, m.textArea.Value())
+	}
+	if m.statusMsg != "Quick note saved: Call the garage" {
+		t.Errorf("status %q", m.statusMsg)
+	}
+	note, err := m.notesManager.FindByTitle("Call the garage")
+	if err != nil || note.Content != "" || note == editing {
+		t.Errorf("quick note not saved as its own note: %v", err)
+	}
+	if len(m.notesManager.Notes) != 2 {
+		t.Errorf("%d note(s) after the quick note, want 2", len(m.notesManager.Notes))
+	}
+}
+
+func TestQuickNoteCancelled(t *testing.T) {
+	m := newTestModel(t, config.Default(), "a")
+	m.openNote(m.notesManager.Notes[0])
+	for _, closing := range []tea.KeyType{tea.KeyEsc, tea.KeyEnter} {
+		var model tea.Model = m
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+		if closing == tea.KeyEsc {
+			model = typeText(model, "dropped")
+		}
+		model, _ = model.Update(tea.KeyMsg{Type: closing})
+		if mode := model.(Model).mode; mode != ModeView {
+			t.Errorf("mode %v after %v, want back to the note view", mode, closing)
+		}
+		if n := len(model.(Model).notesManager.Notes); n != 1 {
+			t.Errorf("%d note(s) after %v, want none added", n, closing)
+		}
+	}
+	// An empty prompt saves nothing
+	updated, _ := press(m, tea.KeyMsg{Type: tea.KeyCtrlG})
+	updated, _ = press(updated, tea.KeyMsg{Type: tea.KeyEnter})
+	if updated.statusMsg != "Nothing to capture" {
+		t.Errorf("status %q after an empty quick note", updated.statusMsg)
+	}
+}
+
+func TestQuickNoteDuplicateTitle(t *testing.T) {
+	m := newTestModel(t, config.Default(), "a")
+	var model tea.Model = m
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	model = typeText(model, "NOTE")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+	if want := "Quick note saved, another note already has this title: NOTE"; m.statusMsg != want {
+		t.Errorf("status %q, want %q", m.statusMsg, want)
+	}
+	if n := len(m.notesManager.Notes); n != 2 {
+		t.Errorf("%d note(s), want the quick note created all the same", n)
+	}
+}
@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quits reports whether cmd stops the program. Commands that don't answer
+// at once, like the cursor blink, don't quit.
+func quits(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	msgs := make(chan tea.Msg, 1)
+	go func() { msgs <- cmd() }()
+	select {
+	case msg := <-msgs:
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				if quits(c) {
+					return true
+				}
+			}
+			return false
+		}
+		_, ok := msg.(tea.QuitMsg)
+		return ok
+	case <-time.After(50 * time.Millisecond):
+		return false
+	}
+}
+
+// press sends one key to the model without the layout and the timers
+// Update schedules around it
+func press(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	return updated.(Model), cmd
+}
+
+func TestQuitKeyTypedInSearch(t *testing.T) {
+	m := newTestModel(t, config.Default(), "a")
+	m.openSearch("")
+	m, cmd := press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if quits(cmd) {
+		t.Fatal("q typed in the search prompt quit")
+	}
+	if m.mode != ModeSearch {
+		t.Errorf("mode %v after typing q, want the search prompt", m.mode)
+	}
+	if got := m.searchInput.Value(); got != "q" {
+		t.Errorf("search input %q, want %q", got, "q")
+	}
+
+	// ctrl+c isn't typed and still quits
+	if _, cmd := press(m, tea.KeyMsg{Type: tea.KeyCtrlC}); !quits(cmd) {
+		t.Error("ctrl+c in the search prompt didn't quit")
+	}
+}
+
+func TestQuitKeyInList(t *testing.T) {
+	m := newTestModel(t, config.Default(), "a")
+	if _, cmd := press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}}); !quits(cmd) {
+		t.Error("q in the list didn't quit")
+	}
+}
+
+// dirtyEditor returns a model editing a note with text typed and not saved
+func dirtyEditor(t *testing.T) Model {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Editor.Autosave = config.AutosaveOff
+	m := newTestModel(t, cfg, "abc")
+	editNote(&m, m.notesManager.Notes[0])
+	m = typeText(m, "xyz").(Model)
+	if !m.unsavedEdits() {
+		t.Fatal("typed text not counted as unsaved")
+	}
+	return m
+}
+
+func TestQuitAsksWithUnsavedEdits(t *testing.T) {
+	m, cmd := press(dirtyEditor(t), tea.KeyMsg{Type: tea.KeyCtrlC})
+	if quits(cmd) {
+		t.Fatal("quit with unsaved edits without asking")
+	}
+	if !m.quitPending || m.statusMsg != "Unsaved changes — quit anyway? y/n" {
+		t.Fatalf("pending %v with status %q, want the confirmation", m.quitPending, m.statusMsg)
+	}
+	if _, cmd := press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}); !quits(cmd) {
+		t.Error("y didn't quit")
+	}
+	if _, cmd := press(m, tea.KeyMsg{Type: tea.KeyCtrlC}); !quits(cmd) {
+		t.Error("ctrl+c again didn't quit")
+	}
+}
+
+func TestQuitCancelled(t *testing.T) {
+	m := dirtyEditor(t)
+	content := m.editorContent()
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyCtrlC})
+	m, cmd := press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if quits(cmd) {
+		t.Fatal("n quit")
+	}
+	if m.quitPending || m.statusMsg != "" || m.mode != ModeEdit {
+		t.Errorf("pending %v, status %q and mode %v after n, want back to the editor", m.quitPending, m.statusMsg, m.mode)
+	}
+	if got := m.editorContent(); got != content {
+		t.Errorf("answer typed in the note: %q, want %q", got, content)
+	}
+}
+
+func TestQuitKeyTypedInEditor(t *testing.T) {
+	m, cmd := press(dirtyEditor(t), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if quits(cmd) || m.quitPending {
+		t.Error("q typed in the editor tried to quit")
+	}
+}
+
+func TestQuitWithoutUnsavedEdits(t *testing.T) {
+	tests := []struct {
+		name     string
+		autosave string
+		typed    string
+	}{
+		{"clean editor", config.AutosaveOff, ""},
+		{"edits the autosave writes", "30s", "xyz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Editor.Autosave = tt.autosave
+			m := newTestModel(t, cfg, "abc")
+			editNote(&m, m.notesManager.Notes[0])
+			m = typeText(m, tt.typed).(Model)
+			if m.unsavedEdits() {
+				t.Error("counted as unsaved")
+			}
+			m, cmd := press(m, tea.KeyMsg{Type: tea.KeyCtrlC})
+			if !quits(cmd) || m.quitPending {
+				t.Errorf("quit %v and pending %v, want to quit at once", quits(cmd), m.quitPending)
+			}
+		})
+	}
+}
+
+func TestQuitNewNote(t *testing.T) {
+	m := newTestModel(t, config.Default())
+	m.startNew("")
+	if m.unsavedEdits() {
+		t.Error("blank new note counted as unsaved")
+	}
+	m.startNew("Draft")
+	if !m.unsavedEdits() {
+		t.Fatal("new note with a title not counted as unsaved")
+	}
+	if m, cmd := press(m, tea.KeyMsg{Type: tea.KeyCtrlC}); quits(cmd) || !m.quitPending {
+		t.Error("quit a new note with a title without asking")
+	}
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// reviewBatch is the number of notes of a review session
+const reviewBatch = 10
+
+// startReview steps through the notes that haven't been looked at for the
+// longest time, one at a time
+func (m *Model) startReview() {
+	m.review = m.notesManager.NotesForReview(reviewBatch)
+	if len(m.review) == 0 {
+		m.statusMsg = "No notes to review"
+		return
+	}
+	m.reviewIndex = 0
+	m.mode = ModeReview
+	m.statusMsg = ""
+	m.showReviewNote()
+}
+
+// showReviewNote displays the note being reviewed from its top
+func (m *Model) showReviewNote() {
+	m.selectedNote = m.review[m.reviewIndex]
+	m.showNoteBody()
+	m.viewport.GotoTop()
+}
+
+// nextReview moves to the next note, ending the session after the last one
+func (m *Model) nextReview() {
+	m.reviewIndex++
+	if m.reviewIndex < len(m.review) {
+		m.showReviewNote()
+		return
+	}
+	m.review = nil
+	m.mode = ModeList
+	m.refreshNoteList()
+	m.statusMsg = "Review done"
+}
+
+// updateReviewMode handles the review session: each note is kept for later,
+// archived, deleted or edited
+func (m Model) updateReviewMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	note := m.selectedNote
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.review = nil
+		m.mode = ModeList
+		m.refreshNoteList()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Keep):
+		if err := m.notesManager.MarkReviewed(note); err != nil {
+			m.statusMsg = fmt.Sprintf("Error saving note: %s", storeError(err))
+			return m, nil
+		}
+		m.statusMsg = "Note kept"
+		m.nextReview()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Archive):
+		note.SetArchived(true)
+		if err := m.notesManager.UpdateNote(note); err != nil {
+			m.statusMsg = fmt.Sprintf("Error saving note: %s", storeError(err))
+			return m, nil
+		}
+		m.statusMsg = "Note archived"
+		m.nextReview()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Delete):
+		if err := m.notesManager.DeleteNote(note.ID); err != nil {
+			m.statusMsg = fmt.Sprintf("Error deleting note: %s", storeError(err))
+			return m, nil
+		}
+		delete(m.readingPos, note.ID)
+		m.statusMsg = "Note deleted"
+		m.nextReview()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Edit):
+		// Editing ends the session, the note leaves the queue once saved
+		m.review = nil
+		m.refreshNoteList()
+		m.startEdit()
+		return m, nil
+	}
+
+	// Other keys scroll the note
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// viewReview displays the note being reviewed
+func (m Model) viewReview() string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFA500"))
+	header := headerStyle.Render(fmt.Sprintf("Review %d/%d", m.reviewIndex+1, len(m.review))) +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render(
+			fmt.Sprintf(" · last seen on %s", m.selectedNote.LastSeen().Format("02/01/2006")))
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		m.viewport.View(),
+		m.statusBar(),
+		m.helpView(),
+	)
+}
@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// entityRegex matches an HTML character reference
+var entityRegex = regexp.MustCompile(`&(?:#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);?`)
+
+// sanitizeTerminal removes the escape sequences and control characters of
+// note text before it is displayed, so that pasted terminal output can't
+// move the cursor, change the window title or hide text. Line breaks and
+// tabs are kept, and so are color sequences when keepColors is set.
+func sanitizeTerminal(text string, keepColors bool) string {
+	if !strings.ContainsFunc(text, isTerminalControl) {
+		return text
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '\x1b':
+			size = escapeLength(text[i:])
+			if keepColors && isColorSequence(text[i:i+size]) {
+				b.WriteString(text[i : i+size])
+			}
+		case !isTerminalControl(r):
+			b.WriteString(text[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// isTerminalControl reports whether r is a control character other than a
+// line break or a tab. C1 controls can start sequences like ESC does.
+func isTerminalControl(r rune) bool {
+	return (r < 0x20 && r != '\n' && r != '\t') || (r >= 0x7f && r <= 0x9f)
+}
+
+// escapeLength returns the length of the escape sequence at the start of s,
+// which starts with ESC. Unterminated sequences run to the end of s.
+func escapeLength(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+
+	switch s[1] {
+	case '[':
+		// CSI: parameters and intermediate bytes up to a final byte
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+			if s[i] < 0x20 || s[i] > 0x3f {
+				return i
+			}
+		}
+		return len(s)
+	case ']', 'P', 'X', '^', '_':
+		// OSC and other strings, ended by BEL or ST (ESC \)
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	default:
+		// Other sequences, such as ESC c which resets the terminal:
+		// intermediate bytes then a final byte
+		for i := 1; i < len(s); i++ {
+			if s[i] >= 0x30 && s[i] <= 0x7e {
+				return i + 1
+			}
+			if s[i] < 0x20 || s[i] > 0x2f {
+				return i
+			}
+		}
+		return len(s)
+	}
+}
+
+// isColorSequence reports whether an escape sequence only sets colors or
+// text attributes (SGR)
+func isColorSequence(seq string) bool {
+	return len(seq) > 2 && seq[1] == '[' && seq[len(seq)-1] == 'm'
+}
+
+// unescapeEntities decodes the HTML character references of text as
+// html.UnescapeString does, but drops the control characters they stand for,
+// so that &#27; can't bring back a sequence sanitizeTerminal removed
+func unescapeEntities(text string) string {
+	if !strings.Contains(text, "&") {
+		return text
+	}
+	return entityRegex.ReplaceAllStringFunc(text, func(entity string) string {
+		return strings.Map(func(r rune) rune {
+			if isTerminalControl(r) {
+				return -1
+			}
+			return r
+		}, html.UnescapeString(entity))
+	})
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeTerminal(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		want       string
+		wantColors string // With keepColors, the same as want when empty
+	}{
+		{"plain", "hello\n\tworld", "hello\n\tworld", ""},
+		{"CSI color", "\x1b[31mred\x1b[0m", "red", "\x1b[31mred\x1b[0m"},
+		{"CSI cursor move", "a\x1b[2;5Hb", "ab", ""},
+		{"CSI screen clear", "a\x1b[2Jb", "ab", ""},
+		{"CSI private mode", "a\x1b[?1049hb", "ab", ""},
+		{"OSC title ended by BEL", "a\x1b]0;pwned\ab", "ab", ""},
+		{"OSC title ended by ST", "a\x1b]2;pwned\x1b\\b", "ab", ""},
+		{"OSC hyperlink", "\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\", "link", ""},
+		{"unterminated OSC", "a\x1b]0;pwned", "a", ""},
+		{"bare ESC", "a\x1bcb", "ab", ""},
+		{"ESC at the end", "a\x1b", "a", ""},
+		{"ESC with intermediate byte", "a\x1b(Bb", "ab", ""},
+		{"C1 CSI", "a\u009b2Jb", "a2Jb", ""},
+		{"bell and backspace", "a\a\bb", "ab", ""},
+		{"carriage return", "a\r\nb", "a\nb", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeTerminal(tt.in, false); got != tt.want {
+				t.Errorf("sanitizeTerminal(%q, false) = %q, want %q", tt.in, got, tt.want)
+			}
+			want := tt.wantColors
+			if want == "" {
+				want = tt.want
+			}
+			if got := sanitizeTerminal(tt.in, true); got != want {
+				t.Errorf("sanitizeTerminal(%q, true) = %q, want %q", tt.in, got, want)
+			}
+		})
+	}
+}
+
+func TestUnescapeEntities(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"a &amp; b", "a & b"},
+		{"&lt;b&gt; &quot;x&quot;", `<b> "x"`},
+		{"&eacute;t&#233; &#x263A;", "été ☺"},
+		{"&#27;]0;pwned&#7;", "]0;pwned"},
+		{"&#x1b;[2J", "[2J"},
+		{"&#X1B;[2J", "[2J"},
+		{"&#0027;c", "c"},
+		{"&#155;2J &#x9b;2J", "›2J ›2J"}, // Windows-1252 as HTML decodes them
+		{"&#27[2J", "[2J"},
+		{"&amp;#27;", "&#27;"},
+		{"\x1b[1mkept\x1b[0m", "\x1b[1mkept\x1b[0m"},
+	}
+	for _, tt := range tests {
+		if got := unescapeEntities(tt.in); got != tt.want {
+			t.Errorf("unescapeEntities(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// escapes returns the escape sequences of rendered that aren't colors
+func escapes(rendered string) []string {
+	var found []string
+	for i := 0; i < len(rendered); i++ {
+		if rendered[i] != '\x1b' {
+			continue
+		}
+		seq := rendered[i : i+escapeLength(rendered[i:])]
+		if !isColorSequence(seq) {
+			found = append(found, seq)
+		}
+	}
+	return found
+}
+
+func TestRenderMarkdownControlSequences(t *testing.T) {
+	inputs := []string{
+		"raw \x1b]0;pwned\a and \x1b[2J",
+		"entities &#27;]0;pwned&#7; and &#x1b;[2J",
+		"uppercase &#X1B;c and C1 &#x9b;2J",
+		"# Title &#27;[2J\n\n**bold &#27;c**",
+		"[link](http://x/&#27;]0;y&#7;)",
+		"<div>&#27;]0;pwned&#7;</div>",
+		"| a | b |\n|---|---|\n| &#27;[2J | &#x1b;]0;t&#7; |",
+	}
+	for _, renderANSI := range []bool{false, true} {
+		cfg := config.Default()
+		cfg.View.RenderANSI = renderANSI
+		m := newTestModel(t, cfg)
+		m.hyperlinks = false
+		for _, in := range inputs {
+			out := m.renderMarkdown(in, 80)
+			if seqs := escapes(out); len(seqs) > 0 {
+				t.Errorf("render ANSI %v: %q renders the sequences %q", renderANSI, in, seqs)
+			}
+			if strings.ContainsFunc(out, func(r rune) bool { return isTerminalControl(r) && r != '\x1b' }) {
+				t.Errorf("render ANSI %v: %q renders control characters: %q", renderANSI, in, out)
+			}
+		}
+	}
+}
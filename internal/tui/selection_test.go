@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"datapad/internal/notes"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// highlightedID returns the ID of the note under the cursor, empty when none
+func highlightedID(m tea.Model) string {
+	if note := m.(Model).highlightedNote(); note != nil {
+		return note.ID
+	}
+	return ""
+}
+
+func TestListKeepsSelectedNote(t *testing.T) {
+	m := newTestModel(t, config.Default(), "a", "b", "c")
+	ids := listedIDs(m)
+	m.noteList.Select(1)
+	for _, id := range ids[1:] {
+		note, _ := m.notesManager.GetNoteByID(id)
+		note.Tags = []string{"work"}
+	}
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+
+	// A note added elsewhere refreshes the list around the cursor
+	added := m.notesManager.CreateNote("Added")
+	if err := m.notesManager.UpdateNote(added); err != nil {
+		t.Fatal(err)
+	}
+	model, _ = model.Update(flushMsg{})
+	if got := highlightedID(model); got != ids[1] || len(listedIDs(model.(Model))) != 4 {
+		t.Errorf("cursor on %q after a note was added, want %q", got, ids[1])
+	}
+
+	// Picking a tag, or leaving the tags, comes back to the note
+	for _, closing := range []tea.KeyType{tea.KeyEsc, tea.KeyEnter} {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+		if mode := model.(Model).mode; mode != ModeFilterByTag {
+			t.Fatalf("mode %v after f, want the tags", mode)
+		}
+		model, _ = model.Update(tea.KeyMsg{Type: closing})
+		if got := highlightedID(model); model.(Model).mode != ModeList || got != ids[1] {
+			t.Errorf("cursor on %q after %v in the tags, want %q", got, closing, ids[1])
+		}
+	}
+	if got := listedIDs(model.(Model)); len(got) != 2 {
+		t.Errorf("%d note(s) listed with the tag work, want 2", len(got))
+	}
+
+	// A list without the note goes back to the top
+	m = model.(Model)
+	first, _ := m.notesManager.GetNoteByID(ids[0])
+	if m.setNoteItems(m.noteItems([]*notes.Note{added, first})) || m.noteList.Index() != 0 {
+		t.Errorf("cursor at %d in a list without the note, want the top", m.noteList.Index())
+	}
+}
+
+func TestListAfterDeletedNote(t *testing.T) {
+	m := newTestModel(t, config.Default(), "a", "b", "c")
+	ids := listedIDs(m)
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+
+	// The cursor stays on the note that followed the deleted one, or on the
+	// last note when the deleted one was last
+	steps := []struct {
+		selected int
+		deleted  string
+		want     string
+	}{
+		{1, ids[1], ids[2]},
+		{1, ids[2], ids[0]},
+	}
+	for _, step := range steps {
+		m = model.(Model)
+		m.noteList.Select(step.selected)
+		if err := m.notesManager.DeleteNote(step.deleted); err != nil {
+			t.Fatal(err)
+		}
+		model, _ = m.Update(flushMsg{})
+		if got := highlightedID(model); got != step.want {
+			t.Errorf("cursor on %q after deleting %q, want %q", got, step.deleted, step.want)
+		}
+	}
+}
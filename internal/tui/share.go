@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"datapad/internal/share"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// gistMsg reports the end of a share or unshare request
+type gistMsg struct {
+	noteID   string
+	ref      *notes.GistRef // New gist of the note, nil once unshared
+	unshared bool
+	err      error
+}
+
+// shareNote shares the selected note as a secret gist in the background
+func (m *Model) shareNote() tea.Cmd {
+	note := *m.selectedNote
+	client := share.NewGistClient(m.config.Share.Token())
+	m.statusMsg = "Sharing note..."
+	return func() tea.Msg {
+		ref, err := client.Share(&note, false)
+		return gistMsg{noteID: note.ID, ref: ref, err: err}
+	}
+}
+
+// unshareNote deletes the gist of the selected note in the background
+func (m *Model) unshareNote() tea.Cmd {
+	if m.selectedNote.Gist == nil {
+		m.statusMsg = "This note isn't shared"
+		return nil
+	}
+	ref := *m.selectedNote.Gist
+	noteID := m.selectedNote.ID
+	client := share.NewGistClient(m.config.Share.Token())
+	m.statusMsg = "Deleting gist..."
+	return func() tea.Msg {
+		return gistMsg{noteID: noteID, unshared: true, err: client.Unshare(&ref)}
+	}
+}
+
+// handleGist records the result of a share request in the note and copies
+// the URL of a new share to the clipboard
+func (m *Model) handleGist(msg gistMsg) {
+	if msg.err != nil {
+		m.statusMsg = fmt.Sprintf("Sharing failed: %s", msg.err)
+		return
+	}
+
+	note, err := m.notesManager.GetNoteByID(msg.noteID)
+	if err != nil {
+		m.statusMsg = "The shared note no longer exists"
+		return
+	}
+	note.Gist = msg.ref
+	if err := m.notesManager.UpdateNote(note); err != nil {
+		m.statusMsg = fmt.Sprintf("Error saving note: %s", storeError(err))
+		return
+	}
+
+	switch {
+	case msg.unshared:
+		m.statusMsg = "Gist deleted"
+	case m.clipboard.WriteAll(msg.ref.URL) != nil:
+		m.statusMsg = "Shared at " + msg.ref.URL
+	default:
+		m.statusMsg = "Shared at " + msg.ref.URL + " (copied)"
+	}
+}
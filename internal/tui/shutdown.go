@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// shutdown runs once the program has stopped, whether the user quit or a
+// signal stopped it, and saves the edits the autosave would have written
+// along with the saves the manager holds back. An unresolved conflict is
+// reported since the editor can't be saved.
+func (m *Model) shutdown() error {
+	if m.mode == ModeConflict {
+		// The version on disk stays, it was never chosen against
+		m.notesManager.RevertNote(m.selectedNote, m.conflict)
+		if err := m.notesManager.Flush(); err != nil {
+			return err
+		}
+		return m.saveErr
+	}
+	if m.autosaveEnabled() && m.editorDirty() && !m.persistEdit() {
+		return m.saveErr
+	}
+	// The time spent on a note left in the editor counts too, unless it's
+	// a new note that was never saved
+	if m.mode == ModeEdit && m.config.Editor.TrackTime {
+		m.countEditTime(time.Now())
+		m.saveEditTime()
+	}
+	return m.notesManager.Flush()
+}
+
+// unsavedEdits reports whether quitting would lose text typed in the editor.
+// Edits the autosave would write are saved by the shutdown.
+func (m Model) unsavedEdits() bool {
+	switch m.mode {
+	case ModeNew:
+		return !notes.IsBlank(m.titleInput.Value()) || !notes.IsBlank(m.editorContent())
+	case ModeEdit:
+		return m.editorDirty() && !m.autosaveEnabled()
+	case ModeConflict:
+		return true
+	}
+	return false
+}
+
+// confirmQuit answers the confirmation asked before quitting with unsaved
+// changes: y, or ctrl+c again, quits and any other key goes back to the editor
+func (m Model) confirmQuit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.quitPending = false
+	if msg.String() == "y" || msg.Type == tea.KeyCtrlC {
+		return m, tea.Quit
+	}
+	m.statusMsg = ""
+	return m, nil
+}
+
+// handleSignals quits the program when the process is interrupted, asked to
+// stop or loses its terminal, so that it goes through the same shutdown as
+// the quit key instead of dying in the middle of a save. The returned
+// function stops listening.
+func handleSignals(p *tea.Program) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			p.Quit()
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"datapad/internal/notes"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSignalSavesEdits(t *testing.T) {
+	cfg := config.Default()
+	cfg.Editor.Autosave = config.AutosaveOnBlur
+	m := newTestModel(t, cfg, "abc")
+	// Saves are held back until the manager is flushed
+	m.notesManager.SaveDelay = time.Hour
+	note := m.notesManager.Notes[0]
+	editNote(&m, note)
+	m = typeText(m, "xyz").(Model)
+
+	p := tea.NewProgram(m, tea.WithInput(nil), tea.WithOutput(io.Discard), tea.WithoutRenderer(), tea.WithoutSignalHandler())
+	stop := handleSignals(p)
+	defer stop()
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan tea.Model, 1)
+	go func() {
+		final, err := p.Run()
+		if err != nil {
+			t.Error(err)
+		}
+		done <- final
+	}()
+	var final tea.Model
+	select {
+	case final = <-done:
+	case <-time.After(5 * time.Second):
+		p.Kill()
+		t.Fatal("SIGTERM didn't stop the program")
+	}
+
+	stopped := final.(Model)
+	if saved, err := notes.NewNotesManager(m.notesManager.StoragePath); err == nil && saved.Notes[0].Content != "abc" {
+		t.Fatal("edit saved before the shutdown")
+	}
+	if err := stopped.shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := notes.NewNotesManager(m.notesManager.StoragePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := reloaded.GetNoteByID(note.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(saved.Content, "xyz") {
+		t.Errorf("edit lost on SIGTERM, note saved as %q", saved.Content)
+	}
+}
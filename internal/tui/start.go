@@ -0,0 +1,56 @@
+package tui
+
+import "fmt"
+
+// Start is where the interface starts once the notes are loaded, the list
+// when it is zero
+type Start struct {
+	Mode   Mode   // ModeList, ModeView, ModeEdit, ModeNew, ModeSearch or ModeFilterByTag
+	NoteID string // Note to read or edit with ModeView and ModeEdit
+	Seed   string // Title of the new note, search query or tag; the prompt opens when empty
+}
+
+// applyStart puts the interface where the program was started on. The note
+// opened is selected in the list that Esc goes back to, and the search or
+// tag filter narrows the list as if it had been typed.
+func (m *Model) applyStart() {
+	switch m.start.Mode {
+	case ModeView, ModeEdit:
+		if m.start.NoteID != "" {
+			m.openStartNote()
+		}
+	case ModeNew:
+		m.startNew(m.start.Seed)
+	case ModeSearch:
+		if m.start.Seed == "" {
+			m.openSearch("")
+		} else {
+			m.applySearch(m.start.Seed)
+		}
+	case ModeFilterByTag:
+		if m.start.Seed == "" {
+			m.openTagFilter()
+		} else {
+			m.filterByTag(m.start.Seed)
+		}
+	}
+}
+
+// openStartNote opens the note the program was started on, in the editor
+// when asked
+func (m *Model) openStartNote() {
+	note, err := m.notesManager.GetNoteByID(m.start.NoteID)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Note %s not found", m.start.NoteID)
+		return
+	}
+	for i, item := range m.noteList.Items() {
+		if item, ok := item.(NoteItem); ok && item.Note == note {
+			m.noteList.Select(i)
+		}
+	}
+	m.openNote(note)
+	if m.start.Mode == ModeEdit {
+		m.startEdit()
+	}
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"slices"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		start  Start // NoteID holds the title of the note to open
+		mode   Mode
+		listed []string // Titles left in the list, nil when not checked
+		check  func(t *testing.T, m Model)
+	}{
+		{"list", Start{}, ModeList, []string{"Plan", "Trip"}, nil},
+		{"view", Start{Mode: ModeView, NoteID: "Trip"}, ModeView, []string{"Plan", "Trip"}, func(t *testing.T, m Model) {
+			if m.selectedNote == nil || m.selectedNote.Title != "Trip" || m.highlightedNote() != m.selectedNote {
+				t.Error("Trip not opened and selected in the list")
+			}
+		}},
+		{"edit", Start{Mode: ModeEdit, NoteID: "Plan"}, ModeEdit, []string{"Plan", "Trip"}, func(t *testing.T, m Model) {
+			if m.titleInput.Value() != "Plan" || m.editorContent() != "Packing list" {
+				t.Errorf("editor holds %q and %q, want Plan", m.titleInput.Value(), m.editorContent())
+			}
+		}},
+		{"missing note", Start{Mode: ModeView, NoteID: "missing"}, ModeList, []string{"Plan", "Trip"}, func(t *testing.T, m Model) {
+			if m.statusMsg != "Note missing not found" {
+				t.Errorf("status %q", m.statusMsg)
+			}
+		}},
+		{"new", Start{Mode: ModeNew, Seed: "Groceries"}, ModeNew, []string{"Plan", "Trip"}, func(t *testing.T, m Model) {
+			if m.titleInput.Value() != "Groceries" || m.textArea.Value() != "" {
+				t.Errorf("new note titled %q", m.titleInput.Value())
+			}
+		}},
+		{"search prompt", Start{Mode: ModeSearch}, ModeSearch, []string{"Plan", "Trip"}, nil},
+		{"search", Start{Mode: ModeSearch, Seed: "packing"}, ModeList, []string{"Plan"}, nil},
+		{"tag list", Start{Mode: ModeFilterByTag}, ModeFilterByTag, nil, nil},
+		{"tag", Start{Mode: ModeFilterByTag, Seed: "travel"}, ModeList, []string{"Trip"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, config.Default(), "Packing list", "Flights")
+			for _, note := range m.notesManager.Notes {
+				if note.Content == "Packing list" {
+					note.Title = "Plan"
+				} else {
+					note.Title = "Trip"
+					note.Tags = []string{"travel"}
+				}
+				if note.Title == tt.start.NoteID {
+					tt.start.NoteID = note.ID
+				}
+			}
+
+			m.start = tt.start
+			m.loading = true
+			if cmd := m.finishLoading(notesLoadedMsg{}); cmd != nil {
+				t.Fatal("loading stopped the program")
+			}
+			if m.mode != tt.mode {
+				t.Errorf("started in mode %v, want %v", m.mode, tt.mode)
+			}
+			if tt.listed != nil {
+				titles := []string{}
+				for _, item := range m.noteList.Items() {
+					titles = append(titles, item.(NoteItem).Note.Title)
+				}
+				slices.Sort(titles)
+				if !slices.Equal(titles, tt.listed) {
+					t.Errorf("list holds %q, want %q", titles, tt.listed)
+				}
+			}
+			if tt.check != nil {
+				tt.check(t, m)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// stateFile holds the interface settings changed from the interface itself
+const stateFile = "ui-state.json"
+
+// uiState holds the interface settings kept between sessions
+type uiState struct {
+	ReadingWidth string `json:"reading_width"` // One of the reading widths of the view mode
+	DetailPane   bool   `json:"detail_pane"`   // The selected note is displayed next to the list
+}
+
+// Widths of the note column in view mode
+const (
+	readingNarrow = "narrow"
+	readingWide   = "wide"
+	readingFull   = "full"
+)
+
+// narrowReadingWidth is the width of the narrow column, the wide one is configured
+const narrowReadingWidth = 72
+
+// loadState reads the interface settings of the store, a missing or
+// unreadable file giving the defaults
+func loadState(storagePath string) uiState {
+	state := uiState{ReadingWidth: readingWide}
+	data, err := os.ReadFile(filepath.Join(storagePath, stateFile))
+	if err != nil {
+		return state
+	}
+	json.Unmarshal(data, &state)
+	return state
+}
+
+// save writes the interface settings to the store
+func (s uiState) save(storagePath string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(storagePath, 0755); err != nil {
+		return fmt.Errorf("unable to create storage directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(storagePath, stateFile), data, 0644); err != nil {
+		return fmt.Errorf("error writing interface state: %w", err)
+	}
+	return nil
+}
+
+// readingWidth returns the width of the note column in view mode
+func (m Model) readingWidth() int {
+	width := m.width
+	switch m.state.ReadingWidth {
+	case readingNarrow:
+		width = narrowReadingWidth
+	case readingFull:
+	default:
+		if m.config.View.MaxWidth > 0 {
+			width = m.config.View.MaxWidth
+		}
+	}
+	return max(min(width, m.width), 1)
+}
+
+// cycleReadingWidth switches between the narrow, wide and full columns and
+// remembers the choice
+func (m *Model) cycleReadingWidth() {
+	switch m.state.ReadingWidth {
+	case readingNarrow:
+		m.state.ReadingWidth = readingWide
+	case readingWide:
+		m.state.ReadingWidth = readingFull
+	default:
+		m.state.ReadingWidth = readingNarrow
+	}
+
+	m.statusMsg = fmt.Sprintf("Reading width: %s", m.state.ReadingWidth)
+	if err := m.state.save(m.notesManager.StoragePath); err != nil {
+		m.statusMsg = fmt.Sprintf("Reading width: %s (not saved: %s)", m.state.ReadingWidth, err)
+	}
+}
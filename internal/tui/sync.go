@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"datapad/internal/remote"
+	"fmt"
+	"maps"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// syncProgressMsg reports the progress of a running sync
+type syncProgressMsg struct {
+	progress remote.Progress
+}
+
+// syncDoneMsg reports the end of a sync
+type syncDoneMsg struct {
+	result remote.Result
+	err    error
+}
+
+// startSync synchronizes the notes with the configured WebDAV server in the
+// background. The sync works on copies of the notes and its changes are
+// applied once it is done.
+func (m *Model) startSync() tea.Cmd {
+	if m.syncEvents != nil {
+		m.statusMsg = "A sync is already running"
+		return nil
+	}
+	url := m.config.Sync.WebDAVURL
+	if url == "" {
+		m.statusMsg = "No WebDAV server, set sync.webdav_url in the config"
+		return nil
+	}
+
+	copies := make([]*notes.Note, 0, len(m.notesManager.Notes))
+	for _, note := range m.notesManager.Notes {
+		copies = append(copies, note.Clone())
+	}
+
+	events := make(chan tea.Msg, 16)
+	opts := remote.NewOptions(url, m.config.Sync)
+	opts.Progress = func(p remote.Progress) {
+		events <- syncProgressMsg{progress: p}
+	}
+	storagePath := m.notesManager.StoragePath
+	go func() {
+		result, err := remote.Sync(storagePath, copies, opts)
+		events <- syncDoneMsg{result: result, err: err}
+		close(events)
+	}()
+
+	m.syncEvents = events
+	m.statusMsg = "Syncing..."
+	return waitForSync(events)
+}
+
+// waitForSync waits for the next message of a running sync
+func waitForSync(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+// syncProgress displays the current step of the sync
+func (m *Model) syncProgress(p remote.Progress) tea.Cmd {
+	if p.Total > 0 {
+		m.statusMsg = fmt.Sprintf("Syncing: %s (%d/%d)", p.Step, p.Done, p.Total)
+	} else {
+		m.statusMsg = "Syncing: " + p.Step
+	}
+	return waitForSync(m.syncEvents)
+}
+
+// finishSync applies the changes brought by the sync
+func (m *Model) finishSync(msg syncDoneMsg) {
+	m.syncEvents = nil
+	if msg.err != nil {
+		m.statusMsg = fmt.Sprintf("Sync failed: %s", msg.err)
+		return
+	}
+
+	// Unsaved changes in the editor count as a change made during the sync,
+	// and the note being edited isn't deleted under the editor
+	r := msg.result
+	base := maps.Clone(r.Base)
+	dirty := m.editorDirty()
+	if m.mode == ModeEdit && (dirty || slices.Contains(r.Deleted, m.selectedNote.ID)) {
+		delete(base, m.selectedNote.ID)
+	}
+	skipped, err := m.notesManager.MergeNotes(r.Notes, r.Deleted, base)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Error saving synced notes: %s", storeError(err))
+		return
+	}
+	if err := r.SaveState(m.notesManager.StoragePath, skipped); err != nil {
+		m.statusMsg = fmt.Sprintf("Error saving the sync state: %s", err)
+		return
+	}
+	if !dirty {
+		m.reloadEditor()
+	}
+
+	m.statusMsg = fmt.Sprintf("Synced: %d sent, %d received, %d deleted", r.Uploaded, r.Downloaded, r.DeletedLocal+r.DeletedRemote)
+	if r.Conflicts > 0 {
+		m.statusMsg += fmt.Sprintf(", %d conflict(s) kept as copies", r.Conflicts)
+	}
+	if len(skipped) > 0 {
+		m.statusMsg += fmt.Sprintf(", %d note(s) changed meanwhile left for the next sync", len(skipped))
+	}
+}
+
+// reloadEditor shows the note being edited as the sync left it. The editor
+// must hold no unsaved changes.
+func (m *Model) reloadEditor() {
+	if m.mode != ModeEdit || m.selectedNote == nil {
+		return
+	}
+	if m.titleInput.Value() != m.selectedNote.Title {
+		m.titleInput.SetValue(m.selectedNote.Title)
+	}
+	if m.editorContent() != m.selectedNote.Content {
+		m.setEditorContent(m.selectedNote.Content)
+	}
+}
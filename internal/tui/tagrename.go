@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renamePreviewTitles is the number of notes listed by name before a tag
+// rename is applied, the others being counted
+const renamePreviewTitles = 5
+
+// openRenameTag asks for the new name of a tag picked in the tag list
+func (m *Model) openRenameTag(tag string) {
+	m.mode = ModeRenameTag
+	m.renamedTag = tag
+	m.renameNotes = nil
+	m.tagInput.SetValue(tag)
+	m.tagInput.CursorEnd()
+	m.tagInput.Focus()
+	m.statusMsg = ""
+}
+
+// updateRenameTagMode handles the new name of a tag, then the confirmation
+// of the notes it changes: y applies the rename and any other key goes back
+// to the name
+func (m Model) updateRenameTagMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.Back) {
+		m.tagInput.Blur()
+		m.mode = ModeFilterByTag
+		m.statusMsg = "Select a tag"
+		return m, nil
+	}
+
+	if m.renameNotes != nil {
+		if msg.String() == "y" {
+			return m.renameTag()
+		}
+		m.renameNotes = nil
+		m.statusMsg = ""
+		return m, nil
+	}
+
+	if key.Matches(msg, m.keys.Enter) {
+		changed, err := m.notesManager.RenameTag(m.renamedTag, m.tagInput.Value(), true)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Cannot rename the tag: %v", err)
+			return m, nil
+		}
+		if len(changed) == 0 {
+			m.statusMsg = "No note would change"
+			return m, nil
+		}
+		m.renameNotes = changed
+		m.statusMsg = ""
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	return m, cmd
+}
+
+// renameTag applies the previewed rename and goes back to the tag list,
+// the new tag under the cursor
+func (m Model) renameTag() (tea.Model, tea.Cmd) {
+	target := strings.TrimSpace(m.tagInput.Value())
+	merge := m.mergesTag(target)
+	changed, err := m.notesManager.RenameTag(m.renamedTag, target, false)
+	m.renameNotes = nil
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Error renaming the tag: %s", storeError(err))
+		return m, nil
+	}
+
+	m.tagInput.Blur()
+	m.mode = ModeFilterByTag
+	m.listTags()
+	for i, item := range m.noteList.Items() {
+		if item.(TagItem).Tag == target {
+			m.noteList.Select(i)
+		}
+	}
+	verb := "renamed to"
+	if merge {
+		verb = "merged into"
+	}
+	m.statusMsg = fmt.Sprintf("Tag %s %s %s in %d note(s)", m.renamedTag, verb, target, len(changed))
+	return m, nil
+}
+
+// mergesTag reports whether renaming the tag to target merges it with
+// another tag already in use, rather than only changing its spelling
+func (m Model) mergesTag(target string) bool {
+	return !strings.EqualFold(strings.TrimSpace(m.renamedTag), target) && m.notesManager.CountTagged(target) > 0
+}
+
+// renamePreview describes the notes the rename changes, the first of them by
+// title
+func (m Model) renamePreview() string {
+	target := strings.TrimSpace(m.tagInput.Value())
+	count := len(m.renameNotes)
+	lines := []string{
+		fmt.Sprintf("Rename %s to %s:", m.renamedTag, target),
+		fmt.Sprintf("%d note(s) will change", count),
+	}
+	if m.mergesTag(target) {
+		combined := m.notesManager.CountTagged(m.renamedTag, target)
+		lines = []string{
+			fmt.Sprintf("%s already exists, the two tags will be merged:", target),
+			fmt.Sprintf("%d note(s) will change, %d tagged %s in all", count, combined, target),
+		}
+	}
+	for _, note := range m.renameNotes[:min(count, renamePreviewTitles)] {
+		lines = append(lines, "  • "+notes.ShortTitle(note.Title, max(m.width-4, 1)))
+	}
+	if count > renamePreviewTitles {
+		lines = append(lines, fmt.Sprintf("  … and %d more", count-renamePreviewTitles))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// viewRenameTag displays the new name of the tag and, once previewed, the
+// notes it changes
+func (m Model) viewRenameTag() string {
+	parts := []string{fmt.Sprintf("Rename the tag %s:", m.renamedTag), m.tagInput.View()}
+	if m.renameNotes != nil {
+		confirmStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFA500"))
+		parts = append(parts, "", m.renamePreview(), "", confirmStyle.Render("Apply? (y/n)"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, append(parts, m.statusBar(), m.helpView())...)
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limits of the editing time counted. The time between two key presses
+// counts for at most editIdleCap, so that a terminal left open or suspended
+// adds little, and a single editor session for at most editSessionCap.
+const (
+	editIdleCap    = 2 * time.Minute
+	editSessionCap = 4 * time.Hour
+)
+
+// editing reports whether the mode is one of the editor modes. The conflict
+// screen belongs to the session, saving the time there would overwrite the
+// version on disk.
+func editing(mode Mode) bool {
+	return mode == ModeEdit || mode == ModeNew || mode == ModeConflict
+}
+
+// trackEditTime follows the editor sessions across an update: it starts
+// counting when the editor opens, counts the key presses in between and adds
+// the time to the note when the editor closes
+func (m *Model) trackEditTime(before Mode, keyPress bool) {
+	if !m.config.Editor.TrackTime {
+		return
+	}
+	now := time.Now()
+	switch {
+	case !editing(before) && editing(m.mode):
+		m.editSpent = 0
+		m.editLast = now
+	case editing(before) && keyPress:
+		m.countEditTime(now)
+	}
+	if editing(before) && !editing(m.mode) {
+		// A new note discarded without saving has no time to keep
+		if before == ModeNew && m.mode != ModeView {
+			return
+		}
+		m.saveEditTime()
+	}
+}
+
+// countEditTime adds the time since the last key press, capped
+func (m *Model) countEditTime(now time.Time) {
+	m.editSpent += min(now.Sub(m.editLast), editIdleCap)
+	m.editLast = now
+}
+
+// saveEditTime adds the time of the editor session to the selected note
+func (m *Model) saveEditTime() {
+	spent := min(m.editSpent, editSessionCap)
+	m.editSpent = 0
+	if m.selectedNote == nil || spent <= 0 {
+		return
+	}
+	if err := m.notesManager.AddTimeSpent(m.selectedNote, spent); err != nil {
+		m.statusMsg = fmt.Sprintf("Error saving the time spent: %s", storeError(err))
+	}
+}
+
+// formatTimeSpent describes a duration in hours and minutes
+func formatTimeSpent(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d < time.Minute:
+		return "less than a minute"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
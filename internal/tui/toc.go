@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"datapad/internal/notes"
+	"fmt"
+	"strings"
+)
+
+// editorTableOfContents refreshes the table of contents of the note being
+// edited or, without one, inserts it at the line of the cursor
+func (m *Model) editorTableOfContents() {
+	lines, row, _ := m.editorLines()
+	content := strings.Join(lines, "\n")
+	toc := notes.TableOfContents(content, m.config.Markdown.TOCDepth)
+	if toc == nil {
+		m.statusMsg = "No headings to list in a table of contents"
+		return
+	}
+	updated, end, refreshed := notes.SetTableOfContents(content, toc, row)
+	if updated == content {
+		m.statusMsg = "Table of contents up to date"
+		return
+	}
+	if !m.setEditorLines(strings.Split(updated, "\n"), end, 0) {
+		return
+	}
+	m.statusMsg = tocStatus(refreshed)
+}
+
+// noteTableOfContents refreshes the table of contents of the open note or,
+// without one, inserts it at its top, and saves the note
+func (m *Model) noteTableOfContents() {
+	note := m.selectedNote
+	toc := notes.TableOfContents(note.Content, m.config.Markdown.TOCDepth)
+	if toc == nil {
+		m.statusMsg = "No headings to list in a table of contents"
+		return
+	}
+	updated, _, refreshed := notes.SetTableOfContents(note.Content, toc, 0)
+	if updated == note.Content {
+		m.statusMsg = "Table of contents up to date"
+		return
+	}
+	note.Content = updated
+	if err := m.notesManager.UpdateNote(note); err != nil {
+		m.statusMsg = fmt.Sprintf("Error saving note: %s", storeError(err))
+		return
+	}
+	m.showNoteBody()
+	m.statusMsg = tocStatus(refreshed)
+}
+
+// tocStatus tells whether the table of contents was inserted or refreshed
+func tocStatus(refreshed bool) string {
+	if refreshed {
+		return "Table of contents refreshed"
+	}
+	return "Table of contents inserted"
+}
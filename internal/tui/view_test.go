@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// fakeClipboard holds the text copied in tests, failing when err is set
+type fakeClipboard struct {
+	text string
+	err  error
+}
+
+// ReadAll returns the text copied last
+func (c *fakeClipboard) ReadAll() (string, error) {
+	return c.text, c.err
+}
+
+// WriteAll copies text unless the clipboard fails
+func (c *fakeClipboard) WriteAll(text string) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.text = text
+	return nil
+}
+
+func TestNoteMetadata(t *testing.T) {
+	m := sized(newTestModel(t, config.Default(), "Body"))
+	note := m.notesManager.Notes[0]
+	note.Tags = []string{"work", "plan"}
+	note.CreatedAt = time.Date(2024, 3, 1, 9, 5, 0, 0, time.Local)
+	note.UpdatedAt = time.Date(2024, 3, 2, 18, 30, 0, 0, time.Local)
+
+	want := []string{
+		"Tags: work, plan",
+		"Created on: 01/03/2024 09:05",
+		"Updated on: 02/03/2024 18:30",
+		"ID: " + note.ID,
+	}
+	// The metadata follows the note, the ID last
+	lines := []string{}
+	for _, line := range strings.Split(ansi.Strip(m.renderNote(note, 80)), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if got := lines[max(len(lines)-len(want), 0):]; !slices.Equal(got, want) {
+		t.Errorf("metadata %q, want %q", got, want)
+	}
+}
+
+func TestCopyNoteID(t *testing.T) {
+	m := sized(newTestModel(t, config.Default(), "Body"))
+	clipboard := &fakeClipboard{}
+	m.clipboard = clipboard
+	note := m.notesManager.Notes[0]
+	m.openNote(note)
+
+	y := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}
+	m, _ = press(m, y)
+	if clipboard.text != note.ID || m.statusMsg != "Note ID copied to clipboard" {
+		t.Errorf("clipboard holds %q with status %q, want the ID", clipboard.text, m.statusMsg)
+	}
+
+	clipboard.err = errors.New("no clipboard")
+	m, _ = press(m, y)
+	if m.statusMsg != "Unable to copy note ID: no clipboard" {
+		t.Errorf("status %q after a failed copy", m.statusMsg)
+	}
+}
+
+func TestReadingPositionRestored(t *testing.T) {
+	long := strings.Repeat("line\n\n", 200)
+	updated, _ := newTestModel(t, config.Default(), long, long).Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m := updated.(Model)
+	first, second := m.notesManager.Notes[0], m.notesManager.Notes[1]
+	esc := tea.KeyMsg{Type: tea.KeyEsc}
+	scroll := tea.KeyMsg{Type: tea.KeyCtrlD}
+
+	m.openNote(first)
+	m, _ = press(m, scroll)
+	m, _ = press(m, scroll)
+	offset := m.viewport.YOffset
+	if offset == 0 {
+		t.Fatal("ctrl+d didn't scroll the note")
+	}
+	m, _ = press(m, esc)
+
+	// Each note has its own position
+	m.openNote(second)
+	if m.viewport.YOffset != 0 {
+		t.Errorf("note never read opened at line %d", m.viewport.YOffset)
+	}
+	m, _ = press(m, scroll)
+	secondOffset := m.viewport.YOffset
+	m, _ = press(m, esc)
+
+	m.openNote(first)
+	if m.viewport.YOffset != offset {
+		t.Errorf("note reopened at line %d, want %d", m.viewport.YOffset, offset)
+	}
+
+	// Opening another note from the one being read remembers it too
+	m.openNote(second)
+	if m.viewport.YOffset != secondOffset {
+		t.Errorf("second note reopened at line %d, want %d", m.viewport.YOffset, secondOffset)
+	}
+	m.openNote(first)
+	if m.viewport.YOffset != offset {
+		t.Errorf("note reopened at line %d after switching notes, want %d", m.viewport.YOffset, offset)
+	}
+}
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"datapad/internal/notes"
+	"fmt"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultWorkspace names the store opened outside the configured workspaces
+const defaultWorkspace = "default"
+
+// WorkspaceItem is a workspace of the switcher
+type WorkspaceItem struct {
+	Name   string
+	Path   string
+	Active bool
+}
+
+// Title returns the name of the workspace, marked when it is open
+func (w WorkspaceItem) Title() string {
+	if w.Active {
+		return "● " + w.Name
+	}
+	return w.Name
+}
+
+// Description returns the storage folder of the workspace
+func (w WorkspaceItem) Description() string {
+	return w.Path
+}
+
+// FilterValue returns the value to use for filtering workspaces
+func (w WorkspaceItem) FilterValue() string {
+	return w.Name
+}
+
+// openManager creates the manager of a store with the settings of the config,
+// the notes being loaded afterwards
+func openManager(storagePath string, cfg config.Config) *notes.NotesManager {
+	manager := notes.OpenNotesManager(storagePath)
+	manager.DefaultTags = cfg.Tags.Defaults
+	manager.Snapshots = notes.SnapshotPolicy(cfg.Snapshots)
+	manager.ManualOrder = cfg.View.Sort == config.NoteSortManual
+	manager.TidyContent = cfg.Editor.TrimSpace
+	manager.SaveDelay, _ = cfg.Editor.SaveDelay()
+	return manager
+}
+
+// workspaceName returns the name displayed for the active workspace
+func (m Model) workspaceName() string {
+	if m.workspace == "" {
+		return defaultWorkspace
+	}
+	return m.workspace
+}
+
+// openWorkspaces lists the workspaces of the config in the switcher
+func (m *Model) openWorkspaces() {
+	names := m.config.WorkspaceNames()
+	if len(names) == 0 {
+		m.statusMsg = "No workspaces, add them to the workspaces section of the config file"
+		return
+	}
+
+	items := []list.Item{}
+	selected := 0
+	for i, name := range names {
+		path, err := m.config.WorkspacePath(name)
+		if err != nil {
+			path = err.Error()
+		}
+		items = append(items, WorkspaceItem{Name: name, Path: path, Active: name == m.workspace})
+		if name == m.workspace {
+			selected = i
+		}
+	}
+	if note := m.highlightedNote(); note != nil {
+		m.listedNote = note.ID
+	}
+	m.noteList.SetItems(items)
+	m.noteList.Select(selected)
+	m.mode = ModeWorkspaces
+	m.statusMsg = "Select a workspace"
+}
+
+// updateWorkspacesMode handles the keys of the workspace switcher
+func (m Model) updateWorkspacesMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeList
+		m.statusMsg = ""
+		m.refreshNoteList()
+		return m, nil
+	case key.Matches(msg, m.keys.Enter):
+		item, ok := m.noteList.SelectedItem().(WorkspaceItem)
+		if !ok {
+			return m, nil
+		}
+		return m.switchWorkspace(item.Name)
+	}
+
+	var cmd tea.Cmd
+	m.noteList, cmd = m.noteList.Update(msg)
+	return m, cmd
+}
+
+// switchWorkspace closes the current store, saving what is pending, and
+// loads the store of another workspace in a fresh interface. The current
+// workspace stays open when the other one can't be used.
+func (m Model) switchWorkspace(name string) (tea.Model, tea.Cmd) {
+	if name == m.workspace {
+		m.mode = ModeList
+		m.statusMsg = ""
+		m.refreshNoteList()
+		return m, nil
+	}
+	if m.syncEvents != nil {
+		m.statusMsg = "Wait for the sync to finish before switching workspaces"
+		return m, nil
+	}
+
+	path, err := m.config.WorkspacePath(name)
+	if err != nil {
+		m.statusMsg = err.Error()
+		return m, nil
+	}
+	// The settings kept in the workspace lie beneath the config file, as
+	// they do for the workspace the interface started on
+	cfg, err := config.LoadWithSettings(m.configPath, filepath.Join(path, config.SettingsFile))
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Can't open workspace %s: %s", name, err)
+		return m, nil
+	}
+	manager := openManager(path, cfg)
+	if err := manager.CheckStorage(); err != nil {
+		m.statusMsg = fmt.Sprintf("Can't open workspace %s: %s", name, err)
+		return m, nil
+	}
+	if err := m.shutdown(); err != nil {
+		m.statusMsg = fmt.Sprintf("Error saving before switching workspaces: %s", storeError(err))
+		return m, nil
+	}
+	m.notesManager.Unsubscribe(m.noteEvents)
+
+	next := NewModel(manager, cfg)
+	next.workspace = name
+	next.configPath = m.configPath
+	next.instance = m.instance
+	if next.instance != nil {
+		next.instance.serve(path)
+	}
+	next.width, next.height = m.width, m.height
+	// The autosave and title checks scheduled in the previous workspace must
+	// not run in this one, the idle delay of the lock carries on
+	next.autosaveSeq = m.autosaveSeq
+	next.titleCheckSeq = m.titleCheckSeq
+	next.lockSeq = m.lockSeq
+	next.loading = true
+	next.statusMsg = fmt.Sprintf("Workspace: %s", name)
+	return next, tea.Batch(next.loadNotes(), waitForNoteEvent(next.noteEvents))
+}
+
+// workspaceBadge renders the name of the active workspace for the status bar
+func (m Model) workspaceBadge() string {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#7D56F4")).
+		Padding(0, 1).
+		Render(m.workspaceName())
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"datapad/internal/config"
+	"datapad/internal/instance"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newWorkspaceModel returns a model open on the workspace "a" of a config
+// file holding the workspaces "a" and "b", and their storage folders
+func newWorkspaceModel(t *testing.T) (m Model, a, b string) {
+	t.Helper()
+	root := t.TempDir()
+	a, b = filepath.Join(root, "a"), filepath.Join(root, "b")
+	configPath := filepath.Join(root, "config.json")
+	data, _ := json.Marshal(map[string]any{"workspaces": map[string]string{"a": a, "b": b}})
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadWithSettings(configPath, filepath.Join(a, config.SettingsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m = NewModel(openManager(a, cfg), cfg)
+	m.workspace = "a"
+	m.configPath = configPath
+	return m, a, b
+}
+
+func TestSwitchWorkspaceReadsItsSettings(t *testing.T) {
+	m, _, b := newWorkspaceModel(t)
+	if err := os.MkdirAll(b, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"version": 1, "editor": {"tab_width": 2}, "view": {"sort": "manual"}}`
+	if err := os.WriteFile(filepath.Join(b, config.SettingsFile), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	model, _ := m.switchWorkspace("b")
+	next := model.(Model)
+	if next.config.Editor.TabWidth != 2 {
+		t.Errorf("tab width %d, the settings of the workspace weren't read", next.config.Editor.TabWidth)
+	}
+	if !next.notesManager.ManualOrder {
+		t.Error("the manager doesn't follow the settings of the workspace")
+	}
+	if len(next.config.Workspaces) != 2 {
+		t.Error("the workspaces of the config file were lost")
+	}
+
+	// Back to a workspace without settings, the defaults apply again
+	model, _ = next.switchWorkspace("a")
+	if tab := model.(Model).config.Editor.TabWidth; tab != config.Default().Editor.TabWidth {
+		t.Errorf("tab width %d kept from the previous workspace", tab)
+	}
+}
+
+func TestSwitchWorkspaceServesNewStore(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	m, a, b := newWorkspaceModel(t)
+	m.instance = &instanceServer{stop: func() {}}
+	m.instance.serve(a)
+	defer m.instance.close()
+
+	next, _ := m.switchWorkspace("b")
+	if next.(Model).notesManager.StoragePath != b {
+		t.Fatal("the workspace wasn't switched")
+	}
+	// The interface answers for b, a is left to the commands
+	if _, err := instance.Listen(b); err == nil {
+		t.Error("nothing serves the new workspace")
+	}
+	server, err := instance.Listen(a)
+	if err != nil {
+		t.Fatalf("the previous workspace is still served: %v", err)
+	}
+	server.Close()
+}